@@ -0,0 +1,87 @@
+// Package main generates terraform import blocks and resource configuration
+// for the objects of an existing Okta org.
+//
+// The org is configured with the same environment variables as the provider,
+// e.g. OKTA_ORG_NAME, OKTA_BASE_URL and OKTA_API_TOKEN. The generated file is
+// meant to be used with terraform 1.5 or later:
+//
+//	go run ./cmd/okta-import -resources okta_group,okta_app_saml > imports.tf
+//	terraform plan
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/okta"
+)
+
+func main() {
+	resources := flag.String("resources", "", "comma separated resource types to import, the importable resource types that aren't opt-in by default")
+	q := flag.String("query", "", "only import objects whose name or label starts with this value")
+	out := flag.String("out", "", "file to write the generated configuration to, standard output by default")
+	list := flag.Bool("list", false, "list the importable resource types and exit, opt-in resource types are followed by the reason they aren't imported by default")
+	flag.Parse()
+
+	if *list {
+		for _, resourceType := range okta.ImportableResourceTypes() {
+			if reason := okta.ImportOptInReason(resourceType); reason != "" {
+				fmt.Printf("%s (opt-in: %s)\n", resourceType, reason)
+				continue
+			}
+			fmt.Println(resourceType)
+		}
+		return
+	}
+
+	resourceTypes := okta.DefaultImportResourceTypes()
+	if *resources != "" {
+		resourceTypes = strings.Split(*resources, ",")
+		for i := range resourceTypes {
+			resourceTypes[i] = strings.TrimSpace(resourceTypes[i])
+		}
+	}
+
+	ctx := context.Background()
+	provider := okta.Provider()
+	if diags := provider.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{})); diags.HasError() {
+		for _, d := range diags {
+			log.Printf("%s: %s", d.Summary, d.Detail)
+		}
+		os.Exit(1)
+	}
+
+	var w io.Writer = os.Stdout
+	var f *os.File
+	if *out != "" {
+		var err error
+		f, err = os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	err := okta.NewImporter(provider, *q).Generate(ctx, resourceTypes, bw)
+	flushErr := bw.Flush()
+	// log.Fatal exits without running deferred calls, close the file first
+	if f != nil {
+		if closeErr := f.Close(); closeErr != nil && flushErr == nil {
+			flushErr = closeErr
+		}
+	}
+	if flushErr != nil {
+		log.Fatal(flushErr)
+	}
+	if err != nil {
+		// a partial configuration has been written, report what was skipped
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,8 @@
+data "okta_apps" "saml" {
+  sign_on_mode = "SAML_2_0"
+  status       = "ACTIVE"
+}
+
+output "saml_app_ids" {
+  value = [for app in data.okta_apps.saml.apps : app.id]
+}
@@ -0,0 +1,3 @@
+data "okta_auth_server_keys" "test" {
+  auth_server_id = "default"
+}
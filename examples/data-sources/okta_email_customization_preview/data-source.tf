@@ -0,0 +1,9 @@
+data "okta_email_customization_preview" "user_activation_fr" {
+  template_name = "UserActivation"
+  language      = "fr"
+  subject       = "Bienvenue chez $${org.name}"
+  body          = "#if($${user.profile.locale} == \"fr\")Bonjour#{else}Hello#end $${user.profile.firstName}, activez votre compte : $${activationLink}"
+  context = {
+    "user.profile.firstName" = "Marie"
+  }
+}
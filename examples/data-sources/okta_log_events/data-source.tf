@@ -0,0 +1,18 @@
+# Refuse to apply when the break-glass account signed in during the last hour.
+# plantimestamp() is known at plan time so the events are read during the
+# plan, with timeadd(timestamp(), "-1h") since would be unknown until apply
+# and the read, and the gate, would be deferred to the apply.
+data "okta_log_events" "break_glass_logins" {
+  filter      = "eventType eq \"user.session.start\" and actor.alternateId eq \"break-glass@example.com\" and outcome.result eq \"SUCCESS\""
+  since       = timeadd(plantimestamp(), "-1h")
+  max_results = 1
+}
+
+resource "terraform_data" "break_glass_gate" {
+  lifecycle {
+    precondition {
+      condition     = length(data.okta_log_events.break_glass_logins.events) == 0
+      error_message = "The break-glass account signed in during the last hour, review the session before applying."
+    }
+  }
+}
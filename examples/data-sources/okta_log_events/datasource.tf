@@ -0,0 +1,5 @@
+data "okta_log_events" "test" {
+  filter      = "eventType eq \"user.session.start\""
+  sort_order  = "DESCENDING"
+  max_results = 5
+}
@@ -0,0 +1,6 @@
+resource "okta_group_rule" "department" {
+  name              = "Department ${var.department}"
+  group_assignments = [okta_group.department.id]
+  expression_type   = "urn:okta:expression:1.0"
+  expression_value  = "user.department == ${provider::okta::el_string_literal(var.department)}"
+}
@@ -0,0 +1,17 @@
+locals {
+  jwk = provider::okta::jwk_from_pem(file("public.pem"))
+}
+
+resource "okta_app_oauth" "example" {
+  label                      = "example"
+  type                       = "service"
+  grant_types                = ["client_credentials"]
+  token_endpoint_auth_method = "private_key_jwt"
+
+  jwks {
+    kid = local.jwk.kid
+    kty = local.jwk.kty
+    e   = local.jwk.e
+    n   = local.jwk.n
+  }
+}
@@ -0,0 +1,4 @@
+locals {
+  user_ids  = [for id in var.assignees : id if provider::okta::okta_id_type(id) == "user"]
+  group_ids = [for id in var.assignees : id if provider::okta::okta_id_type(id) == "group"]
+}
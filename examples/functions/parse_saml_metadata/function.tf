@@ -0,0 +1,20 @@
+locals {
+  partner = provider::okta::parse_saml_metadata(file("partner-metadata.xml"))
+}
+
+resource "okta_idp_saml_key" "partner" {
+  x5c = [local.partner.signing_certificate]
+}
+
+resource "okta_idp_saml" "partner" {
+  name                     = "Partner"
+  acs_type                 = "INSTANCE"
+  sso_url                  = local.partner.http_post_binding
+  sso_destination          = local.partner.http_post_binding
+  sso_binding              = "HTTP-POST"
+  username_template        = "idpuser.email"
+  kid                      = okta_idp_saml_key.partner.id
+  issuer                   = local.partner.entity_id
+  request_signature_scope  = "REQUEST"
+  response_signature_scope = "ANY"
+}
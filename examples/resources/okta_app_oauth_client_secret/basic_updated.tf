@@ -0,0 +1,12 @@
+resource "okta_app_oauth" "test" {
+  label          = "testAcc_replace_with_uuid"
+  type           = "service"
+  response_types = ["token"]
+  grant_types    = ["client_credentials"]
+  omit_secret    = true
+}
+
+resource "okta_app_oauth_client_secret" "test" {
+  app_id = okta_app_oauth.test.id
+  status = "INACTIVE"
+}
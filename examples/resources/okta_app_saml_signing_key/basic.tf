@@ -0,0 +1,22 @@
+resource "okta_app_saml" "test" {
+  label                    = "testAcc_replace_with_uuid"
+  sso_url                  = "https://example.com"
+  recipient                = "https://example.com"
+  destination              = "https://example.com"
+  audience                 = "https://example.com/audience"
+  subject_name_id_template = "$${user.userName}"
+  subject_name_id_format   = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+  response_signed          = true
+  signature_algorithm      = "RSA_SHA256"
+  digest_algorithm         = "SHA256"
+  authn_context_class_ref  = "urn:oasis:names:tc:SAML:2.0:ac:classes:PasswordProtectedTransport"
+
+  lifecycle {
+    ignore_changes = [key_id]
+  }
+}
+
+resource "okta_app_saml_signing_key" "test" {
+  app_id      = okta_app_saml.test.id
+  years_valid = 2
+}
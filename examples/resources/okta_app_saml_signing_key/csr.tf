@@ -0,0 +1,13 @@
+resource "okta_app_saml_signing_key" "example" {
+  app_id = okta_app_saml.example.id
+
+  csr {
+    common_name       = "sso.example.com"
+    organization_name = "Example"
+    country_name      = "US"
+  }
+
+  # PEM encoded certificate signed by the CA for csr_pem
+  certificate = file("signed.pem")
+  active      = false
+}
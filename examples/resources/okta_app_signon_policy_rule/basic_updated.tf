@@ -141,22 +141,20 @@ resource "okta_app_signon_policy_rule" "test" {
     okta_user.test[0].id,
     okta_user.test[1].id
   ]
-  constraints = [
-    jsonencode({
-      "knowledge" : {
-        "reauthenticateIn" : "PT2H",
-        "types" : ["password"]
-      },
-      "possession" : {
-        "deviceBound" : "REQUIRED"
-      }
-    }),
-    jsonencode({
-      "possession" : {
-        "deviceBound" : "REQUIRED",
-        "hardwareProtection" : "REQUIRED",
-        "userPresence" : "OPTIONAL"
-      }
-    })
-  ]
+  constraint {
+    knowledge {
+      reauthenticate_in = "PT2H"
+      types             = ["password"]
+    }
+    possession {
+      device_bound = "REQUIRED"
+    }
+  }
+  constraint {
+    possession {
+      device_bound        = "REQUIRED"
+      hardware_protection = "REQUIRED"
+      user_presence       = "OPTIONAL"
+    }
+  }
 }
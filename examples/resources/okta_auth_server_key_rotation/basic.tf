@@ -0,0 +1,11 @@
+resource "okta_auth_server" "test" {
+  audiences                 = ["whatever.rise.zone"]
+  credentials_rotation_mode = "MANUAL"
+  description               = "test"
+  name                      = "testAcc_replace_with_uuid"
+}
+
+resource "okta_auth_server_key_rotation" "test" {
+  auth_server_id = okta_auth_server.test.id
+  rotate_trigger = "2024-01"
+}
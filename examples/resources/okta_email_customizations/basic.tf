@@ -0,0 +1,20 @@
+data "okta_brands" "test" {
+}
+
+resource "okta_email_customizations" "test" {
+  brand_id         = tolist(data.okta_brands.test.brands)[0].id
+  template_name    = "ForgotPassword"
+  default_language = "en"
+
+  customization {
+    language = "en"
+    subject  = "Forgot Password"
+    body     = "Hi $${user.profile.firstName},<br/><br/>Click this link to reset your password: $${resetPasswordLink}"
+  }
+
+  customization {
+    language = "es"
+    subject  = "Has olvidado tu contraseña"
+    body     = "Hola $${user.profile.firstName},<br/><br/>Haga clic en este enlace para restablecer tu contraseña: $${resetPasswordLink}"
+  }
+}
@@ -0,0 +1,20 @@
+data "okta_brands" "test" {
+}
+
+resource "okta_email_customizations" "test" {
+  brand_id         = tolist(data.okta_brands.test.brands)[0].id
+  template_name    = "ForgotPassword"
+  default_language = "fr"
+
+  customization {
+    language = "en"
+    subject  = "Forgot your password?"
+    body     = "Hello $${user.profile.firstName},<br/><br/>Click this link to reset your password: $${resetPasswordLink}"
+  }
+
+  customization {
+    language = "fr"
+    subject  = "Mot de passe oublié"
+    body     = "Bonjour $${user.profile.firstName},<br/><br/>Cliquez sur ce lien pour réinitialiser votre mot de passe : $${resetPasswordLink}"
+  }
+}
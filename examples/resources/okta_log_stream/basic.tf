@@ -2,9 +2,9 @@ resource "okta_log_stream" "eventbridge" {
   name     = "testAcc_replace_with_uuid EventBridge"
   type     = "aws_eventbridge"
   status   = "ACTIVE"
-  settings {
-    account_id = "123456789012"
-    region     = "eu-west-3"
+  aws_eventbridge {
+    account_id        = "123456789012"
+    region            = "eu-west-3"
     event_source_name = "testAcc_replace_with_uuid"
   }
 }
@@ -0,0 +1,22 @@
+resource "okta_group" "test" {
+  count = 2
+  name  = "testAcc_${count.index}_replace_with_uuid"
+}
+
+resource "okta_policy_password" "test" {
+  count           = 2
+  name            = "testAcc_${count.index}_replace_with_uuid"
+  groups_included = [okta_group.test[count.index].id]
+
+  lifecycle {
+    ignore_changes = [priority]
+  }
+}
+
+resource "okta_policy_order" "test" {
+  type = "PASSWORD"
+  policy_ids = [
+    okta_policy_password.test[1].id,
+    okta_policy_password.test[0].id,
+  ]
+}
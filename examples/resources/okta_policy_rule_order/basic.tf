@@ -0,0 +1,30 @@
+data "okta_group" "all" {
+  name = "Everyone"
+}
+
+resource "okta_policy_signon" "test" {
+  name            = "testAcc_replace_with_uuid"
+  status          = "ACTIVE"
+  description     = "Terraform Acceptance Test SignOn Policy"
+  groups_included = [data.okta_group.all.id]
+}
+
+resource "okta_policy_rule_signon" "test" {
+  count     = 3
+  policy_id = okta_policy_signon.test.id
+  name      = "testAcc_${count.index}_replace_with_uuid"
+  status    = "ACTIVE"
+
+  lifecycle {
+    ignore_changes = [priority]
+  }
+}
+
+resource "okta_policy_rule_order" "test" {
+  policy_id = okta_policy_signon.test.id
+  rule_ids = [
+    okta_policy_rule_signon.test[2].id,
+    okta_policy_rule_signon.test[0].id,
+    okta_policy_rule_signon.test[1].id,
+  ]
+}
@@ -0,0 +1,8 @@
+resource "okta_user" "test" {
+  first_name              = "TestAcc"
+  last_name               = "Smith"
+  login                   = "testAcc-replace_with_uuid@example.com"
+  email                   = "testAcc-replace_with_uuid@example.com"
+  password                = "Abcd1234"
+  expire_password_trigger = "1"
+}
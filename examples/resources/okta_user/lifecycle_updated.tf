@@ -0,0 +1,13 @@
+resource "okta_user" "test" {
+  first_name              = "TestAcc"
+  last_name               = "Smith"
+  login                   = "testAcc-replace_with_uuid@example.com"
+  email                   = "testAcc-replace_with_uuid@example.com"
+  password                = "Abcd1234"
+  expire_password_trigger = "1"
+  reset_factors_trigger   = "1"
+
+  suspend_window {
+    start = "2020-01-01T00:00:00Z"
+  }
+}
@@ -56,6 +56,10 @@ func main() {
 		muxServer.ProviderServer,
 		serveOpts...,
 	)
+	// terraform shuts the provider down once it's done with it
+	if err := okta.WriteAPIMetrics(); err != nil {
+		log.Printf("[ERROR] failed to write the API usage metrics: %v", err)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
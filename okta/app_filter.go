@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/okta/terraform-provider-okta/sdk"
@@ -15,6 +16,12 @@ type appFilters struct {
 	ID          string
 	Label       string
 	LabelPrefix string
+	LabelRegex  *regexp.Regexp
+	Name        string
+	SignOnMode  string
+	UserID      string
+	GroupID     string
+	Expand      string
 }
 
 // Grabs application q query param
@@ -25,6 +32,41 @@ func (f *appFilters) getQ() string {
 	return f.LabelPrefix
 }
 
+// Grabs application filter query param. The list applications API only
+// supports a single "eq" expression, so the most selective filter is sent to
+// Okta and the others are applied by matches.
+func (f *appFilters) getFilter() string {
+	switch {
+	case f.UserID != "":
+		return fmt.Sprintf(`user.id eq "%s"`, f.UserID)
+	case f.GroupID != "":
+		return fmt.Sprintf(`group.id eq "%s"`, f.GroupID)
+	case f.Name != "":
+		return fmt.Sprintf(`name eq "%s"`, f.Name)
+	case f.Status != "":
+		return fmt.Sprintf(`status eq "%s"`, f.Status)
+	}
+	return ""
+}
+
+// matches reports whether the application satisfies the filters that can be
+// evaluated client side. Assignment filters are only ever applied by Okta.
+func (f *appFilters) matches(app *sdk.Application) bool {
+	if f.Status != "" && app.Status != f.Status {
+		return false
+	}
+	if f.Name != "" && app.Name != f.Name {
+		return false
+	}
+	if f.SignOnMode != "" && app.SignOnMode != f.SignOnMode {
+		return false
+	}
+	if f.LabelRegex != nil && !f.LabelRegex.MatchString(app.Label) {
+		return false
+	}
+	return true
+}
+
 func (f *appFilters) String() string {
 	return fmt.Sprintf(`id: "%s", label: "%s", label_prefix: "%s"`, f.ID, f.Label, f.LabelPrefix)
 }
@@ -32,8 +74,9 @@ func (f *appFilters) String() string {
 func listApps(ctx context.Context, client *sdk.Client, filters *appFilters, limit int64) ([]*sdk.Application, error) {
 	params := &query.Params{Limit: limit}
 	if filters != nil {
-		params.Filter = filters.Status
+		params.Filter = filters.getFilter()
 		params.Q = filters.getQ()
+		params.Expand = filters.Expand
 	}
 	apps, resp, err := client.Application.ListApplications(ctx, params)
 	if err != nil {
@@ -51,7 +94,16 @@ func listApps(ctx context.Context, client *sdk.Client, filters *appFilters, limi
 		}
 		resultingApps = append(resultingApps, nextApps...)
 	}
-	return resultingApps, nil
+	if filters == nil {
+		return resultingApps, nil
+	}
+	matchingApps := make([]*sdk.Application, 0, len(resultingApps))
+	for _, app := range resultingApps {
+		if filters.matches(app) {
+			matchingApps = append(matchingApps, app)
+		}
+	}
+	return matchingApps, nil
 }
 
 func getAppFilters(d *schema.ResourceData) (*appFilters, error) {
@@ -60,7 +112,7 @@ func getAppFilters(d *schema.ResourceData) (*appFilters, error) {
 	labelPrefix := d.Get("label_prefix").(string)
 	filters := &appFilters{ID: id, Label: label, LabelPrefix: labelPrefix}
 	if d.Get("active_only").(bool) {
-		filters.Status = fmt.Sprintf(`status eq "%s"`, statusActive)
+		filters.Status = statusActive
 	}
 	if id == "" && label == "" && labelPrefix == "" {
 		return nil, errors.New("you must provide either a 'label_prefix', 'id', or 'label' for application search")
@@ -7,8 +7,10 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
@@ -20,6 +22,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/okta/okta-sdk-golang/v3/okta"
 	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
+	"github.com/okta/terraform-provider-okta/okta/internal/filecache"
+	"github.com/okta/terraform-provider-okta/okta/internal/metrics"
+	"github.com/okta/terraform-provider-okta/okta/internal/permissions"
 	"github.com/okta/terraform-provider-okta/okta/internal/transport"
 	"github.com/okta/terraform-provider-okta/sdk"
 )
@@ -56,7 +61,21 @@ type (
 		maxWait                 int
 		logLevel                int
 		requestTimeout          int
-		maxAPICapacity          int // experimental
+		maxAPICapacity          int  // experimental
+		cacheEnabled            bool // experimental
+		cacheDir                string
+		cacheTTL                int
+		cache                   *filecache.FileCache
+		readOnly                bool
+		rateLimitFile           string
+		metricsFile             string
+		metricsFormat           string
+		apiTokenRole            string
+		apiTokenRoleOnce        sync.Once
+		userProfileOnce         sync.Once
+		userProfileProperties   map[string]bool
+		appUserProfileMu        sync.Mutex
+		appUserProfiles         map[string]map[string]bool
 		oktaSDKClientV2         *sdk.Client
 		oktaSDKClientV3         *okta.APIClient
 		oktaSDKsupplementClient *sdk.APISupplement
@@ -78,6 +97,8 @@ func NewConfig(d *schema.ResourceData) *Config {
 		logLevel:       int(hclog.Error),
 		requestTimeout: 0,
 		maxAPICapacity: 100,
+		cacheTTL:       300,
+		metricsFormat:  metrics.FormatOpenMetrics,
 	}
 	logLevel := hclog.Level(config.logLevel)
 	if os.Getenv("TF_LOG") != "" {
@@ -188,6 +209,68 @@ func NewConfig(d *schema.ResourceData) *Config {
 		}
 	}
 
+	if val, ok := d.GetOk("rate_limit_coordination_file"); ok {
+		config.rateLimitFile = val.(string)
+	}
+	if config.rateLimitFile == "" && os.Getenv("OKTA_RATE_LIMIT_COORDINATION_FILE") != "" {
+		config.rateLimitFile = os.Getenv("OKTA_RATE_LIMIT_COORDINATION_FILE")
+	}
+
+	if val, ok := d.GetOk("cache_enabled"); ok {
+		config.cacheEnabled = val.(bool)
+	}
+	if !config.cacheEnabled && os.Getenv("OKTA_CACHE_ENABLED") != "" {
+		enabled, err := strconv.ParseBool(os.Getenv("OKTA_CACHE_ENABLED"))
+		if err != nil {
+			config.logger.Error("error with cache_enabled value", err)
+		} else {
+			config.cacheEnabled = enabled
+		}
+	}
+
+	if val, ok := d.GetOk("cache_dir"); ok {
+		config.cacheDir = val.(string)
+	}
+	if config.cacheDir == "" && os.Getenv("OKTA_CACHE_DIR") != "" {
+		config.cacheDir = os.Getenv("OKTA_CACHE_DIR")
+	}
+
+	if val, ok := d.GetOk("cache_ttl_seconds"); ok {
+		config.cacheTTL = val.(int)
+	}
+
+	if val, ok := d.GetOk("api_token_role"); ok {
+		config.apiTokenRole = val.(string)
+	}
+	if config.apiTokenRole == "" && os.Getenv("OKTA_API_TOKEN_ROLE") != "" {
+		config.apiTokenRole = os.Getenv("OKTA_API_TOKEN_ROLE")
+	}
+
+	if val, ok := d.GetOk("read_only"); ok {
+		config.readOnly = val.(bool)
+	}
+	if !config.readOnly && os.Getenv("OKTA_READ_ONLY") != "" {
+		readOnly, err := strconv.ParseBool(os.Getenv("OKTA_READ_ONLY"))
+		if err != nil {
+			config.logger.Error("error with read_only value", err)
+		} else {
+			config.readOnly = readOnly
+		}
+	}
+
+	if val, ok := d.GetOk("metrics_file"); ok {
+		config.metricsFile = val.(string)
+	}
+	if config.metricsFile == "" && os.Getenv("OKTA_METRICS_FILE") != "" {
+		config.metricsFile = os.Getenv("OKTA_METRICS_FILE")
+	}
+
+	if val, ok := d.GetOk("metrics_format"); ok {
+		config.metricsFormat = val.(string)
+	} else if os.Getenv("OKTA_METRICS_FORMAT") != "" {
+		config.metricsFormat = os.Getenv("OKTA_METRICS_FORMAT")
+	}
+
 	if httpProxy, ok := d.Get("http_proxy").(string); ok {
 		config.httpProxy = httpProxy
 	}
@@ -220,6 +303,85 @@ func (c *Config) IsClassicOrg(ctx context.Context) bool {
 	return c.classicOrg
 }
 
+// APITokenRole returns the admin role of the provider's credentials. It is
+// either the api_token_role setting or, for an SSWS API token, the most
+// privileged role assigned to the token's admin. Does lazy evaluation of the
+// token admin's roles. Unknown if neither is available.
+func (c *Config) APITokenRole(ctx context.Context) permissions.Role {
+	c.apiTokenRoleOnce.Do(func() {
+		if c.apiTokenRole != "" || c.apiToken == "" {
+			return
+		}
+		me, _, err := c.oktaSDKClientV2.User.GetUser(ctx, "me")
+		if err != nil {
+			c.logger.Warn("unable to detect api_token_role, error querying GET /api/v1/users/me", "error", err)
+			return
+		}
+		roles, _, err := c.oktaSDKClientV2.User.ListAssignedRolesForUser(ctx, me.Id, nil)
+		if err != nil {
+			c.logger.Warn("unable to detect api_token_role, error querying the token admin's roles", "error", err)
+			return
+		}
+		roleTypes := make([]string, len(roles))
+		for i, role := range roles {
+			roleTypes[i] = role.Type
+		}
+		c.apiTokenRole = string(permissions.MostPrivileged(roleTypes))
+		c.logger.Info(fmt.Sprintf("detected api_token_role %q", c.apiTokenRole))
+	})
+
+	return permissions.Role(c.apiTokenRole)
+}
+
+// UserProfileProperties returns the names of the properties of every user
+// type's profile. The user schemas are read once, by the first caller, and
+// concurrent callers wait for them. Nil if the schemas can't be read.
+func (c *Config) UserProfileProperties(ctx context.Context) map[string]bool {
+	c.userProfileOnce.Do(func() {
+		userTypes, _, err := c.oktaSDKClientV2.UserType.ListUserTypes(ctx)
+		if err != nil {
+			c.logger.Warn("unable to read the user profile properties, error querying GET /api/v1/meta/types/user", "error", err)
+			return
+		}
+		properties := make(map[string]bool)
+		for _, userType := range userTypes {
+			us, _, err := c.oktaSDKClientV2.UserSchema.GetUserSchema(ctx, userTypeSchemaID(userType))
+			if err != nil {
+				c.logger.Warn("unable to read the user profile properties, error querying the user type's schema", "user_type", userType.Name, "error", err)
+				return
+			}
+			for name := range userSchemaProperties(us) {
+				properties[name] = true
+			}
+		}
+		c.userProfileProperties = properties
+	})
+	return c.userProfileProperties
+}
+
+// AppUserProfileProperties returns the names of the properties of the app's
+// user profile, or nil if they can't be read. Each app's schema is read once,
+// however many expressions reference it.
+func (c *Config) AppUserProfileProperties(ctx context.Context, appID string) map[string]bool {
+	c.appUserProfileMu.Lock()
+	defer c.appUserProfileMu.Unlock()
+	if properties, ok := c.appUserProfiles[appID]; ok {
+		return properties
+	}
+	var properties map[string]bool
+	us, _, err := c.oktaSDKClientV2.UserSchema.GetApplicationUserSchema(ctx, appID)
+	if err != nil {
+		c.logger.Warn("unable to read the app user profile properties", "app_id", appID, "error", err)
+	} else {
+		properties = userSchemaProperties(us)
+	}
+	if c.appUserProfiles == nil {
+		c.appUserProfiles = make(map[string]map[string]bool)
+	}
+	c.appUserProfiles[appID] = properties
+	return properties
+}
+
 func (c *Config) IsOAuth20Auth() bool {
 	return c.privateKey != "" || c.accessToken != ""
 }
@@ -243,6 +405,10 @@ func (c *Config) resetHttpTransport(transport *http.RoundTripper) {
 
 // loadClients initializes the Okta SDK clients
 func (c *Config) loadClients(ctx context.Context) error {
+	if err := c.loadCache(); err != nil {
+		return err
+	}
+
 	v3Client, err := oktaV3SDKClient(c)
 	if err != nil {
 		return err
@@ -272,6 +438,29 @@ func (c *Config) loadClients(ctx context.Context) error {
 	return nil
 }
 
+// loadCache initializes the experimental on-disk response cache shared by the
+// v2 and v3 SDK clients when it is enabled
+func (c *Config) loadCache() error {
+	if !c.cacheEnabled {
+		return nil
+	}
+	dir := c.cacheDir
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return fmt.Errorf("unable to determine default cache_dir, set cache_dir explicitly: %v", err)
+		}
+		dir = filepath.Join(userCacheDir, "terraform-provider-okta")
+	}
+	cache, err := filecache.NewFileCache(dir, time.Duration(c.cacheTTL)*time.Second)
+	if err != nil {
+		return err
+	}
+	c.cache = cache
+	c.logger.Info(fmt.Sprintf("running with experimental response cache in %q, ttl %d seconds", dir, c.cacheTTL))
+	return nil
+}
+
 func (c *Config) verifyCredentials(ctx context.Context) error {
 	// NOTE: validate credentials during initial config with a call to
 	// GET /api/v1/users/me
@@ -338,6 +527,42 @@ func (c *Config) handleFrameworkDefaults(ctx context.Context, data *FrameworkPro
 			data.MaxAPICapacity = types.Int64Value(100)
 		}
 	}
+	if data.CacheEnabled.IsNull() && os.Getenv("OKTA_CACHE_ENABLED") != "" {
+		enabled, err := strconv.ParseBool(os.Getenv("OKTA_CACHE_ENABLED"))
+		if err != nil {
+			return err
+		}
+		data.CacheEnabled = types.BoolValue(enabled)
+	}
+	if data.CacheDir.IsNull() && os.Getenv("OKTA_CACHE_DIR") != "" {
+		data.CacheDir = types.StringValue(os.Getenv("OKTA_CACHE_DIR"))
+	}
+	if data.APITokenRole.IsNull() && os.Getenv("OKTA_API_TOKEN_ROLE") != "" {
+		data.APITokenRole = types.StringValue(os.Getenv("OKTA_API_TOKEN_ROLE"))
+	}
+	if data.ReadOnly.IsNull() && os.Getenv("OKTA_READ_ONLY") != "" {
+		readOnly, err := strconv.ParseBool(os.Getenv("OKTA_READ_ONLY"))
+		if err != nil {
+			return err
+		}
+		data.ReadOnly = types.BoolValue(readOnly)
+	}
+	if data.RateLimitFile.IsNull() && os.Getenv("OKTA_RATE_LIMIT_COORDINATION_FILE") != "" {
+		data.RateLimitFile = types.StringValue(os.Getenv("OKTA_RATE_LIMIT_COORDINATION_FILE"))
+	}
+	if data.MetricsFile.IsNull() && os.Getenv("OKTA_METRICS_FILE") != "" {
+		data.MetricsFile = types.StringValue(os.Getenv("OKTA_METRICS_FILE"))
+	}
+	if data.MetricsFormat.IsNull() {
+		if os.Getenv("OKTA_METRICS_FORMAT") != "" {
+			data.MetricsFormat = types.StringValue(os.Getenv("OKTA_METRICS_FORMAT"))
+		} else {
+			data.MetricsFormat = types.StringValue(metrics.FormatOpenMetrics)
+		}
+	}
+	if data.CacheTTL.IsNull() {
+		data.CacheTTL = types.Int64Value(300)
+	}
 	data.Backoff = types.BoolValue(true)
 	data.MinWaitSeconds = types.Int64Value(30)
 	data.MaxWaitSeconds = types.Int64Value(300)
@@ -357,18 +582,25 @@ func (c *Config) handleFrameworkDefaults(ctx context.Context, data *FrameworkPro
 	return err
 }
 
+// orgURL is the URL of the Okta org the clients call. It is the http_proxy
+// value if set, the base_url value if that is a full URL, e.g. of a fake Okta
+// API in tests, and the org_name subdomain of base_url otherwise.
+func (c *Config) orgURL() string {
+	if c.httpProxy != "" {
+		return strings.TrimSuffix(c.httpProxy, "/")
+	}
+	if strings.Contains(c.domain, "://") {
+		return strings.TrimSuffix(c.domain, "/")
+	}
+	return fmt.Sprintf("https://%v.%v", c.orgName, c.domain)
+}
+
 // oktaSDKClient should be called with a primary http client that is utilized
 // throughout the provider
 func oktaSDKClient(c *Config) (client *sdk.Client, err error) {
 	httpClient := c.oktaSDKClientV3.GetConfig().HTTPClient
-	var orgUrl string
-	var disableHTTPS bool
-	if c.httpProxy != "" {
-		orgUrl = strings.TrimSuffix(c.httpProxy, "/")
-		disableHTTPS = strings.HasPrefix(orgUrl, "http://")
-	} else {
-		orgUrl = fmt.Sprintf("https://%v.%v", c.orgName, c.domain)
-	}
+	orgUrl := c.orgURL()
+	disableHTTPS := strings.HasPrefix(orgUrl, "http://")
 	_, err = url.Parse(orgUrl)
 	if err != nil {
 		return nil, fmt.Errorf("malformed Okta API URL (org_name+base_url value, or http_proxy value): %+v", err)
@@ -376,13 +608,16 @@ func oktaSDKClient(c *Config) (client *sdk.Client, err error) {
 
 	setters := []sdk.ConfigSetter{
 		sdk.WithOrgUrl(orgUrl),
-		sdk.WithCache(false),
+		sdk.WithCache(c.cache != nil),
 		sdk.WithHttpClientPtr(httpClient),
 		sdk.WithRateLimitMaxBackOff(int64(c.maxWait)),
 		sdk.WithRequestTimeout(int64(c.requestTimeout)),
 		sdk.WithRateLimitMaxRetries(int32(c.retryCount)),
 		sdk.WithUserAgentExtra(OktaTerraformProviderUserAgent),
 	}
+	if c.cache != nil {
+		setters = append(setters, sdk.WithCacheManager(c.cache))
+	}
 
 	switch {
 	case c.accessToken != "":
@@ -432,6 +667,15 @@ func oktaV3SDKClient(c *Config) (client *okta.APIClient, err error) {
 		} else {
 			retryableClient.HTTPClient.Transport = logging.NewSubsystemLoggingHTTPTransport("Okta", retryableClient.HTTPClient.Transport)
 		}
+		if c.metricsFile != "" {
+			// every attempt of a request goes through the inner transport
+			retryableClient.HTTPClient.Transport = metrics.NewTransport(retryableClient.HTTPClient.Transport, apiMetrics)
+			retryableClient.RequestLogHook = func(_ retryablehttp.Logger, req *http.Request, attempt int) {
+				if attempt > 0 {
+					apiMetrics.RecordRetry(req.Context(), req.Method, req.URL.Path)
+				}
+			}
+		}
 		retryableClient.ErrorHandler = errHandler
 		retryableClient.CheckRetry = checkRetry
 		httpClient = retryableClient.StandardClient()
@@ -445,6 +689,9 @@ func oktaV3SDKClient(c *Config) (client *okta.APIClient, err error) {
 		} else {
 			httpClient.Transport = logging.NewSubsystemLoggingHTTPTransport("Okta", httpClient.Transport)
 		}
+		if c.metricsFile != "" {
+			httpClient.Transport = metrics.NewTransport(httpClient.Transport, apiMetrics)
+		}
 		c.logger.Info("running with default http client")
 	}
 
@@ -455,36 +702,54 @@ func oktaV3SDKClient(c *Config) (client *okta.APIClient, err error) {
 		if err != nil {
 			return nil, err
 		}
-		httpClient.Transport = transport.NewGovernedTransport(httpClient.Transport, apiMutex, c.logger)
+		if c.rateLimitFile != "" {
+			c.logger.Info(fmt.Sprintf("sharing max_api_capacity rate limit status with other processes in %q", c.rateLimitFile))
+			apiMutex.SetBackend(apimutex.NewFileBackend(c.rateLimitFile))
+		}
+		var recorder *metrics.Recorder
+		if c.metricsFile != "" {
+			recorder = apiMetrics
+		}
+		httpClient.Transport = transport.NewGovernedTransport(httpClient.Transport, apiMutex, recorder, c.logger)
 	}
-	var orgUrl string
-	var disableHTTPS bool
-	if c.httpProxy != "" {
-		orgUrl = strings.TrimSuffix(c.httpProxy, "/")
-		disableHTTPS = strings.HasPrefix(orgUrl, "http://")
-	} else {
-		orgUrl = fmt.Sprintf("https://%v.%v", c.orgName, c.domain)
+	if c.metricsFile != "" {
+		if !contains(metrics.Formats(), c.metricsFormat) {
+			return nil, fmt.Errorf("unknown metrics_format %q, expected one of %s", c.metricsFormat, strings.Join(metrics.Formats(), ", "))
+		}
+		c.logger.Info(fmt.Sprintf("recording API usage metrics to %q in %s format", c.metricsFile, c.metricsFormat))
+		setAPIMetricsOutput(c.metricsFile, c.metricsFormat)
 	}
-	_, err = url.Parse(orgUrl)
+	if c.rateLimitFile != "" && (c.maxAPICapacity <= 0 || c.maxAPICapacity >= 100) {
+		c.logger.Warn("rate_limit_coordination_file is ignored, max_api_capacity isn't set below 100")
+	}
+	// refuses mutating requests before they reach the retryable or default
+	// client; the v2 sdk and supplement clients share this http client
+	if c.readOnly {
+		c.logger.Info("running in read_only mode, requests that would modify the org are refused")
+		httpClient.Transport = transport.NewReadOnlyTransport(httpClient.Transport)
+	}
+	orgUrl := c.orgURL()
+	disableHTTPS := strings.HasPrefix(orgUrl, "http://")
+	_url, err := url.Parse(orgUrl)
 	if err != nil {
 		return nil, fmt.Errorf("malformed Okta API URL (org_name+base_url value, or http_proxy value): %+v", err)
 	}
 
 	setters := []okta.ConfigSetter{
 		okta.WithOrgUrl(orgUrl),
-		okta.WithCache(false),
+		okta.WithCache(c.cache != nil),
 		okta.WithHttpClientPtr(httpClient),
 		okta.WithRateLimitMaxBackOff(int64(c.maxWait)),
 		okta.WithRequestTimeout(int64(c.requestTimeout)),
 		okta.WithRateLimitMaxRetries(int32(c.retryCount)),
 		okta.WithUserAgentExtra(OktaTerraformProviderUserAgent),
 	}
-	// v3 client also needs http proxy explicitly set
-	if c.httpProxy != "" {
-		_url, err := url.Parse(c.httpProxy)
-		if err != nil {
-			return nil, err
-		}
+	if c.cache != nil {
+		setters = append(setters, okta.WithCacheManager(c.cache))
+	}
+	// v3 client also needs http proxy explicitly set, as does a base_url
+	// naming a port as the v3 client drops the org URL's port
+	if c.httpProxy != "" || _url.Port() != "" {
 		host := okta.WithProxyHost(_url.Hostname())
 		setters = append(setters, host)
 
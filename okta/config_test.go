@@ -3,9 +3,12 @@ package okta
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/okta/terraform-provider-okta/okta/internal/fakeokta"
+	"github.com/okta/terraform-provider-okta/okta/internal/permissions"
 )
 
 func TestConfigLoadAndValidate(t *testing.T) {
@@ -55,3 +58,55 @@ func TestConfigLoadAndValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestConfigFakeOktaBaseURL(t *testing.T) {
+	server := fakeokta.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	config := Config{
+		domain:   server.URL,
+		apiToken: fakeokta.DefaultToken,
+		logLevel: int(hclog.Warn),
+		logger:   hclog.NewNullLogger(),
+	}
+	if got := config.orgURL(); got != server.URL {
+		t.Fatalf("expected a full base_url to be the org URL, got %q", got)
+	}
+	if err := config.loadClients(ctx); err != nil {
+		t.Fatalf("failed to load clients: %v", err)
+	}
+	if err := config.verifyCredentials(ctx); err != nil {
+		t.Fatalf("failed to verify credentials with the fake Okta API: %v", err)
+	}
+	if role := config.APITokenRole(ctx); role != permissions.SuperAdmin {
+		t.Errorf("expected the fake's token to be a super admin's, got %q", role)
+	}
+	if properties := config.UserProfileProperties(ctx); !properties["login"] {
+		t.Errorf("expected the user profile properties from the fake's schema, got %v", properties)
+	}
+
+	config.domain = "okta.com"
+	config.orgName = "test"
+	if got := config.orgURL(); got != "https://test.okta.com" {
+		t.Errorf("expected a domain base_url to be the org's domain, got %q", got)
+	}
+}
+
+// TestConfigUserProfilePropertiesConcurrent checks concurrent callers all get
+// the properties once they are read.
+func TestConfigUserProfilePropertiesConcurrent(t *testing.T) {
+	_, config := newFakeOktaConfig(t)
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if properties := config.UserProfileProperties(ctx); !properties["login"] {
+				t.Errorf("expected the user profile properties once they are read, got %v", properties)
+			}
+		}()
+	}
+	wg.Wait()
+}
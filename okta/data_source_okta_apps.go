@@ -0,0 +1,155 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceApps() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAppsRead,
+		Schema: map[string]*schema.Schema{
+			"status": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: stringInSlice([]string{statusActive, statusInactive}),
+				Description:      "Filter applications by status, `ACTIVE` or `INACTIVE`.",
+			},
+			"sign_on_mode": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filter applications by sign on mode, e.g. `SAML_2_0`, `OPENID_CONNECT` or `BOOKMARK`.",
+			},
+			"label_prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Searches for applications whose label or name starts with this value.",
+			},
+			"label_regex": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: stringIsRegexp,
+				Description:      "Filter applications whose label matches this regular expression.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filter applications by name, the key of the application in the Okta Integration Network catalog, e.g. `okta_org2org`.",
+			},
+			"user_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"group_id"},
+				Description:   "Filter applications assigned to this user.",
+			},
+			"group_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"user_id"},
+				Description:   "Filter applications assigned to this group.",
+			},
+			"expand": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Embeds related resources in each application's `embedded` attribute, e.g. `user/<user_id>` together with `user_id` embeds the user's application assignment.",
+			},
+			"apps": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Application ID.",
+						},
+						"label": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Application label.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Application name.",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Application status.",
+						},
+						"sign_on_mode": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Application sign on mode.",
+						},
+						"links": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Generic JSON containing discoverable resources related to the app.",
+						},
+						"embedded": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Generic JSON containing the resources embedded by `expand`.",
+						},
+					},
+				},
+			},
+		},
+		Description: "Get a list of applications from Okta.",
+	}
+}
+
+func dataSourceAppsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	filters := &appFilters{
+		Status:      d.Get("status").(string),
+		LabelPrefix: d.Get("label_prefix").(string),
+		Name:        d.Get("name").(string),
+		SignOnMode:  d.Get("sign_on_mode").(string),
+		UserID:      d.Get("user_id").(string),
+		GroupID:     d.Get("group_id").(string),
+		Expand:      d.Get("expand").(string),
+	}
+	labelRegex := d.Get("label_regex").(string)
+	if labelRegex != "" {
+		re, err := regexp.Compile(labelRegex)
+		if err != nil {
+			return diag.Errorf("invalid label_regex %q: %v", labelRegex, err)
+		}
+		filters.LabelRegex = re
+	}
+	apps, err := listApps(ctx, getOktaClientFromMetadata(m), filters, defaultPaginationLimit)
+	if err != nil {
+		return diag.Errorf("failed to list apps: %v", err)
+	}
+	id := fmt.Sprintf("%s/%s/%s/%s/%s/%s/%s/%s", filters.Status, filters.LabelPrefix, labelRegex, filters.Name,
+		filters.SignOnMode, filters.UserID, filters.GroupID, filters.Expand)
+	d.SetId(fmt.Sprintf("%d", crc32.ChecksumIEEE([]byte(id))))
+	arr := make([]map[string]interface{}, len(apps))
+	for i := range apps {
+		links, _ := json.Marshal(apps[i].Links)
+		var embedded string
+		if apps[i].Embedded != nil {
+			e, _ := json.Marshal(apps[i].Embedded)
+			embedded = string(e)
+		}
+		arr[i] = map[string]interface{}{
+			"id":           apps[i].Id,
+			"label":        apps[i].Label,
+			"name":         apps[i].Name,
+			"status":       apps[i].Status,
+			"sign_on_mode": apps[i].SignOnMode,
+			"links":        string(links),
+			"embedded":     embedded,
+		}
+	}
+	_ = d.Set("apps", arr)
+	return nil
+}
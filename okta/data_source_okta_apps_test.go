@@ -0,0 +1,99 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceOktaApps_read(t *testing.T) {
+	mgr := newFixtureManager("data-sources", apps, t.Name())
+	config := mgr.GetFixtures("datasource.tf", t)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.okta_apps.test", "apps.#", "1"),
+					resource.TestCheckResourceAttrPair("data.okta_apps.test", "apps.0.id", "okta_app_saml.test", "id"),
+					resource.TestCheckResourceAttr("data.okta_apps.test", "apps.0.label", buildResourceName(mgr.Seed)),
+					resource.TestCheckResourceAttr("data.okta_apps.test", "apps.0.sign_on_mode", "SAML_2_0"),
+					resource.TestCheckResourceAttr("data.okta_apps.test", "apps.0.status", statusActive),
+				),
+			},
+		},
+	})
+}
+
+func TestListAppsFilters(t *testing.T) {
+	server, config := newFakeOktaConfig(t)
+	ctx := context.TODO()
+	client := config.oktaSDKClientV2
+	re := getRequestExecutor(config)
+
+	var ids []string
+	for _, app := range []struct {
+		name, label, signOnMode string
+		activate                bool
+	}{
+		{"my_saml", "Payroll", "SAML_2_0", true},
+		{"my_saml", "Payroll Sandbox", "SAML_2_0", false},
+		{"oidc_client", "Payroll API", "OPENID_CONNECT", true},
+	} {
+		var created sdk.Application
+		req, err := re.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/apps?activate=%t", app.activate), map[string]interface{}{"name": app.name, "label": app.label, "signOnMode": app.signOnMode})
+		require.NoError(t, err)
+		_, err = re.Do(ctx, req, &created)
+		require.NoError(t, err)
+		ids = append(ids, created.Id)
+	}
+	group, _, err := client.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: "Payroll"}})
+	require.NoError(t, err)
+	for _, id := range ids[:2] {
+		_, _, err = client.Application.CreateApplicationGroupAssignment(ctx, id, group.Id, sdk.ApplicationGroupAssignment{})
+		require.NoError(t, err)
+	}
+	for _, id := range ids {
+		_, _, err = client.Application.AssignUserToApplication(ctx, id, sdk.AppUser{Id: server.AdminID()})
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		filters  *appFilters
+		expected []string
+	}{
+		{nil, ids},
+		{&appFilters{Status: statusActive}, []string{ids[0], ids[2]}},
+		{&appFilters{SignOnMode: "SAML_2_0"}, ids[:2]},
+		{&appFilters{Name: "my_saml", Status: statusActive}, ids[:1]},
+		{&appFilters{GroupID: group.Id, Status: statusInactive}, ids[1:2]},
+		{&appFilters{UserID: server.AdminID(), LabelRegex: regexp.MustCompile(`^Payroll( API)?$`)}, []string{ids[0], ids[2]}},
+	}
+	for _, test := range tests {
+		result, err := listApps(ctx, client, test.filters, defaultPaginationLimit)
+		require.NoError(t, err)
+		resultIDs := make([]string, len(result))
+		for i := range result {
+			resultIDs[i] = result[i].Id
+		}
+		require.Equal(t, test.expected, resultIDs)
+	}
+}
+
+func TestDataSourceAppsReadInvalidLabelRegex(t *testing.T) {
+	d := dataSourceApps().TestResourceData()
+	require.NoError(t, d.Set("label_regex", "^Payroll("))
+	diags := dataSourceAppsRead(context.TODO(), d, nil)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Summary, `invalid label_regex "^Payroll("`)
+}
@@ -0,0 +1,131 @@
+package okta
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func dataSourceAuthServerKeys() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAuthServerKeysRead,
+		Schema: map[string]*schema.Schema{
+			"auth_server_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Auth server ID",
+			},
+			"keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Collection of authorization server signing keys retrieved from Okta with the following properties.",
+				Elem:        authServerKeyResource,
+			},
+		},
+		Description: "Get the signing keys of an authorization server from Okta.",
+	}
+}
+
+var authServerKeyResource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"kid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Key ID.",
+		},
+		"status": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Status of the key: `ACTIVE`, `NEXT` or `EXPIRED`.",
+		},
+		"alg": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Algorithm used with the key.",
+		},
+		"kty": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Cryptographic algorithm family of the key.",
+		},
+		"use": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Intended use of the key.",
+		},
+		"e": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "RSA key value (exponent) for key binding.",
+		},
+		"n": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "RSA key value (modulus) for key binding.",
+		},
+		"x5t_s256": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Base64url-encoded SHA-256 thumbprint of the certificate.",
+		},
+		"created": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Timestamp when the key was created.",
+		},
+		"expires_at": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Timestamp when the key expires.",
+		},
+	},
+}
+
+func dataSourceAuthServerKeysRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	authServerID := d.Get("auth_server_id").(string)
+	keys, _, err := getOktaClientFromMetadata(m).AuthorizationServer.ListAuthorizationServerKeys(ctx, authServerID)
+	if err != nil {
+		return diag.Errorf("failed to list authorization server keys: %v", err)
+	}
+	_ = d.Set("keys", flattenAuthServerKeys(keys))
+	d.SetId(authServerID)
+	return nil
+}
+
+func flattenAuthServerKeys(keys []*sdk.JsonWebKey) []interface{} {
+	arr := make([]interface{}, len(keys))
+	for i, key := range keys {
+		m := map[string]interface{}{
+			"kid":      key.Kid,
+			"status":   key.Status,
+			"alg":      key.Alg,
+			"kty":      key.Kty,
+			"use":      key.Use,
+			"e":        key.E,
+			"n":        key.N,
+			"x5t_s256": key.X5tS256,
+		}
+		if key.Created != nil {
+			m["created"] = key.Created.Format(time.RFC3339)
+		}
+		if key.ExpiresAt != nil {
+			m["expires_at"] = key.ExpiresAt.Format(time.RFC3339)
+		}
+		arr[i] = m
+	}
+	return arr
+}
+
+// activeAuthServerKeyID returns the kid of the key currently used to sign
+// tokens, empty when there is none.
+func activeAuthServerKeyID(keys []*sdk.JsonWebKey) string {
+	for _, key := range keys {
+		if key.Status == statusActive {
+			return key.Kid
+		}
+	}
+	return ""
+}
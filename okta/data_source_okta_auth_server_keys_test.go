@@ -0,0 +1,28 @@
+package okta
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceOktaAuthServerKeys(t *testing.T) {
+	mgr := newFixtureManager("data-sources", authServerKeys, t.Name())
+	config := mgr.GetFixtures("datasource.tf", t)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.okta_auth_server_keys.test", "keys.#"),
+					resource.TestCheckResourceAttrSet("data.okta_auth_server_keys.test", "keys.0.kid"),
+					resource.TestCheckResourceAttrSet("data.okta_auth_server_keys.test", "keys.0.status"),
+				),
+			},
+		},
+	})
+}
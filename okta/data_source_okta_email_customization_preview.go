@@ -0,0 +1,99 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/internal/velocity"
+)
+
+func dataSourceEmailCustomizationPreview() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceEmailCustomizationPreviewRead,
+		Schema: map[string]*schema.Schema{
+			"template_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Template Name",
+			},
+			"language": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "en",
+				Description: "The language of the customization, used as the sample user's locale",
+			},
+			"subject": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The subject of the customization",
+			},
+			"body": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The body of the customization",
+			},
+			"context": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Sample values of the template variables keyed by variable name, e.g. `user.profile.firstName`, replacing the provider's sample values",
+			},
+			"rendered_subject": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The subject rendered with the sample values",
+			},
+			"rendered_body": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The body rendered with the sample values",
+			},
+		},
+		Description: "Render an email customization locally with sample values, without sending a test email.",
+	}
+}
+
+func dataSourceEmailCustomizationPreviewRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	templateName := d.Get("template_name").(string)
+	language := d.Get("language").(string)
+	overrides := make(map[string]interface{})
+	for name, v := range d.Get("context").(map[string]interface{}) {
+		s := v.(string)
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil && strconv.FormatInt(n, 10) == s {
+			overrides[name] = n
+			continue
+		}
+		overrides[name] = s
+	}
+	sample := emailSampleContext(language, overrides)
+
+	var diags diag.Diagnostics
+	for _, k := range []string{"subject", "body"} {
+		text := d.Get(k).(string)
+		tmpl, err := velocity.Parse(text)
+		if err != nil {
+			return diag.Errorf("%s is not a valid email template: %v", k, err)
+		}
+		warnings, err := checkEmailTemplate(ctx, m, k, templateName, text, k == "body")
+		for _, warning := range warnings {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Email customization references an unknown variable",
+				Detail:   warning,
+			})
+		}
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Email customization would fail validation",
+				Detail:   err.Error(),
+			})
+		}
+		_ = d.Set("rendered_"+k, tmpl.Render(sample))
+	}
+	d.SetId(fmt.Sprintf("email_customization_preview-%s-%s", templateName, language))
+	return diags
+}
@@ -0,0 +1,27 @@
+package okta
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceOktaEmailCustomizationPreview_read(t *testing.T) {
+	mgr := newFixtureManager("data-sources", emailCustomizationPreview, t.Name())
+	config := mgr.GetFixtures("datasource.tf", t)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.okta_email_customization_preview.user_activation_fr", "rendered_subject", "Bienvenue chez Example"),
+					resource.TestCheckResourceAttr("data.okta_email_customization_preview.user_activation_fr", "rendered_body", "Bonjour Marie, activez votre compte : https://example.okta.com/welcome/XE6wE17zmphl3KqAPFxO"),
+				),
+			},
+		},
+	})
+}
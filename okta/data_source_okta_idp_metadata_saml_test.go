@@ -3,6 +3,7 @@ package okta
 import (
 	"testing"
 
+	"github.com/crewjam/saml"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
@@ -29,3 +30,24 @@ func TestAccDataSourceOktaIdpMetadataSaml_read(t *testing.T) {
 		},
 	})
 }
+
+func TestSyncSamlCertificates(t *testing.T) {
+	descriptor := func(use, cert string) saml.KeyDescriptor {
+		desc := saml.KeyDescriptor{Use: use}
+		desc.KeyInfo.X509Data.X509Certificates = []saml.X509Certificate{{Data: cert}}
+		return desc
+	}
+
+	// a descriptor without use doesn't set the certificates
+	d := dataSourceIdpMetadataSaml().TestResourceData()
+	syncSamlCertificates(d, []saml.KeyDescriptor{descriptor("", "both")})
+	if d.Get("signing_certificate") != "" || d.Get("encryption_certificate") != "" {
+		t.Errorf("expected no certificates, got signing %q and encryption %q", d.Get("signing_certificate"), d.Get("encryption_certificate"))
+	}
+
+	d = dataSourceIdpMetadataSaml().TestResourceData()
+	syncSamlCertificates(d, []saml.KeyDescriptor{descriptor("signing", "sig"), descriptor("", "both"), descriptor("encryption", "enc")})
+	if d.Get("signing_certificate") != "sig" || d.Get("encryption_certificate") != "enc" {
+		t.Errorf("expected signing %q and encryption %q, got %q and %q", "sig", "enc", d.Get("signing_certificate"), d.Get("encryption_certificate"))
+	}
+}
@@ -0,0 +1,320 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+// logEventsPageLimit is the largest page of the System Log API.
+const logEventsPageLimit = 1000
+
+var logEventObjectSchema = map[string]*schema.Schema{
+	"id": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "ID of the object",
+	},
+	"type": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Type of the object, e.g. `User` or `AppInstance`",
+	},
+	"alternate_id": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Alternative ID of the object, e.g. the login of a user",
+	},
+	"display_name": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Display name of the object",
+	},
+}
+
+func dataSourceLogEvents() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLogEventsRead,
+		Schema: map[string]*schema.Schema{
+			"filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "SCIM filter expression the events must match, e.g. `eventType eq \"user.session.start\"`",
+			},
+			"q": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Keywords the events must contain",
+			},
+			"since": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: stringIsRFC3339,
+				Description:      "Only events published at or after this RFC 3339 time are returned, Okta defaults to 7 days ago",
+			},
+			"until": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: stringIsRFC3339,
+				Description:      "Only events published before this RFC 3339 time are returned, defaults to the time of the read",
+			},
+			"sort_order": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "ASCENDING",
+				ValidateDiagFunc: stringInSlice([]string{"ASCENDING", "DESCENDING"}),
+				Description:      "Order of the events by publication time, `ASCENDING` or `DESCENDING`",
+			},
+			"max_results": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          100,
+				ValidateDiagFunc: intBetween(1, 10000),
+				Description:      "Maximum number of events returned, the events are read page by page until it is reached",
+			},
+			"events": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The events matching the query",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Unique ID of the event",
+						},
+						"published": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "RFC 3339 time the event was published",
+						},
+						"event_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Type of the event, e.g. `user.session.start`",
+						},
+						"legacy_event_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Type of the event in the legacy Events API",
+						},
+						"display_message": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Human readable description of the event",
+						},
+						"severity": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Severity of the event, `DEBUG`, `INFO`, `WARN` or `ERROR`",
+						},
+						"version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Version of the event",
+						},
+						"outcome_result": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Result of the action, e.g. `SUCCESS`, `FAILURE` or `DENY`",
+						},
+						"outcome_reason": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Reason of the result of the action",
+						},
+						"transaction_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the transaction, shared by the events of a same request",
+						},
+						"actor": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The user, app or client that performed the action",
+							Elem: &schema.Resource{
+								Schema: logEventObjectSchema,
+							},
+						},
+						"target": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The objects the action was performed on",
+							Elem: &schema.Resource{
+								Schema: logEventObjectSchema,
+							},
+						},
+						"client": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The client the action was performed from",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"ip_address": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "IP address of the client",
+									},
+									"device": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "Type of device of the client, e.g. `Computer`",
+									},
+									"zone": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "Name of the network zone the client is in",
+									},
+									"user_agent": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "User agent of the client",
+									},
+									"city": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "City of the client",
+									},
+									"state": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "State of the client",
+									},
+									"country": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "Country of the client",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Description: "Query the System Log of the Okta organization.",
+	}
+}
+
+// dataSourceLogEventsRead reads the events until the time of the read when
+// until isn't set. A query without until is a polling query, Okta returns a
+// next page even when there are no more events and the read would take an
+// extra request to find out, while a bounded query ends with its last page.
+// The ID is the hash of the query, so it changes with the time of the read.
+func dataSourceLogEventsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	maxResults := d.Get("max_results").(int)
+	until := d.Get("until").(string)
+	if until == "" {
+		until = time.Now().UTC().Format(time.RFC3339)
+	}
+	qp := &query.Params{
+		Filter:    d.Get("filter").(string),
+		Q:         d.Get("q").(string),
+		Since:     d.Get("since").(string),
+		Until:     until,
+		SortOrder: d.Get("sort_order").(string),
+		Limit:     int64(min(maxResults, logEventsPageLimit)),
+	}
+	events, err := collectLogEvents(ctx, getOktaClientFromMetadata(m), qp, maxResults)
+	if err != nil {
+		return diag.Errorf("failed to list log events: %v", err)
+	}
+	d.SetId(fmt.Sprintf("%d", crc32.ChecksumIEEE([]byte(fmt.Sprintf("%s&max_results=%d", qp.String(), maxResults)))))
+	arr := make([]interface{}, len(events))
+	for i, event := range events {
+		arr[i] = flattenLogEvent(event)
+	}
+	err = d.Set("events", arr)
+	if err != nil {
+		return diag.Errorf("failed to set log events: %v", err)
+	}
+	return nil
+}
+
+// collectLogEvents reads the events page by page until max events are read or
+// there is no next page, or no events in the page as Okta links polling
+// queries to a next page.
+func collectLogEvents(ctx context.Context, client *sdk.Client, qp *query.Params, max int) ([]*sdk.LogEvent, error) {
+	events, resp, err := client.LogEvent.GetLogs(ctx, qp)
+	if err != nil {
+		return nil, err
+	}
+	page := events
+	for len(page) > 0 && len(events) < max && resp.HasNextPage() {
+		page = nil
+		resp, err = resp.Next(ctx, &page)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page...)
+	}
+	if len(events) > max {
+		events = events[:max]
+	}
+	return events, nil
+}
+
+func flattenLogEvent(event *sdk.LogEvent) map[string]interface{} {
+	attrs := map[string]interface{}{
+		"uuid":              event.Uuid,
+		"event_type":        event.EventType,
+		"legacy_event_type": event.LegacyEventType,
+		"display_message":   event.DisplayMessage,
+		"severity":          event.Severity,
+		"version":           event.Version,
+	}
+	if event.Published != nil {
+		attrs["published"] = event.Published.Format(time.RFC3339Nano)
+	}
+	if event.Outcome != nil {
+		attrs["outcome_result"] = event.Outcome.Result
+		attrs["outcome_reason"] = event.Outcome.Reason
+	}
+	if event.Transaction != nil {
+		attrs["transaction_id"] = event.Transaction.Id
+	}
+	if event.Actor != nil {
+		attrs["actor"] = []interface{}{map[string]interface{}{
+			"id":           event.Actor.Id,
+			"type":         event.Actor.Type,
+			"alternate_id": event.Actor.AlternateId,
+			"display_name": event.Actor.DisplayName,
+		}}
+	}
+	targets := make([]interface{}, 0, len(event.Target))
+	for _, target := range event.Target {
+		if target == nil {
+			continue
+		}
+		targets = append(targets, map[string]interface{}{
+			"id":           target.Id,
+			"type":         target.Type,
+			"alternate_id": target.AlternateId,
+			"display_name": target.DisplayName,
+		})
+	}
+	attrs["target"] = targets
+	if event.Client != nil {
+		client := map[string]interface{}{
+			"ip_address": event.Client.IpAddress,
+			"device":     event.Client.Device,
+			"zone":       event.Client.Zone,
+		}
+		if event.Client.UserAgent != nil {
+			client["user_agent"] = event.Client.UserAgent.RawUserAgent
+		}
+		if geo := event.Client.GeographicalContext; geo != nil {
+			client["city"] = geo.City
+			client["state"] = geo.State
+			client["country"] = geo.Country
+		}
+		attrs["client"] = []interface{}{client}
+	}
+	return attrs
+}
@@ -0,0 +1,116 @@
+package okta
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/internal/fakeokta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceOktaLogEvents_read(t *testing.T) {
+	mgr := newFixtureManager("data-sources", logEvents, t.Name())
+	config := mgr.GetFixtures("datasource.tf", t)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.okta_log_events.test", "id"),
+					resource.TestCheckResourceAttrSet("data.okta_log_events.test", "events.#"),
+				),
+			},
+		},
+	})
+}
+
+// testLoginEvent is a login of the break-glass user published at published.
+func testLoginEvent(published time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"published":      published.UTC().Format(time.RFC3339),
+		"eventType":      "user.session.start",
+		"displayMessage": "User login to Okta",
+		"severity":       "INFO",
+		"actor":          map[string]interface{}{"id": "00u1", "type": "User", "alternateId": "break-glass@example.com"},
+		"client": map[string]interface{}{
+			"ipAddress":           "203.0.113.7",
+			"userAgent":           map[string]interface{}{"rawUserAgent": "curl/8.0"},
+			"geographicalContext": map[string]interface{}{"country": "France", "city": "Paris"},
+		},
+		"outcome":     map[string]interface{}{"result": "SUCCESS"},
+		"target":      []interface{}{map[string]interface{}{"id": "0oa1", "type": "AppInstance", "displayName": "Okta Admin Console"}},
+		"transaction": map[string]interface{}{"id": "txn1"},
+	}
+}
+
+func TestDataSourceLogEventsRead(t *testing.T) {
+	since := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name       string
+		count      int
+		maxResults int
+		events     int
+		requests   int
+	}{
+		{"stops at the last page", 5, 100, 5, 1},
+		{"follows pages up to max_results", 50, 22, 22, 3},
+		{"caps the events of a page", 10, 3, 3, 1},
+		{"no events", 0, 100, 0, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server, config := newFakeOktaConfig(t, fakeokta.WithPageLimit(10))
+			for i := 0; i < test.count; i++ {
+				server.AddLogEvents(testLoginEvent(since.Add(time.Duration(i+1) * time.Second)))
+			}
+			server.AddLogEvents(map[string]interface{}{"eventType": "user.session.end"})
+			d := schema.TestResourceDataRaw(t, dataSourceLogEvents().Schema, map[string]interface{}{
+				"filter":      `eventType eq "user.session.start"`,
+				"since":       since.UTC().Format(time.RFC3339),
+				"max_results": test.maxResults,
+			})
+			requests := server.Requests()
+			diags := dataSourceLogEventsRead(context.TODO(), d, config)
+			require.False(t, diags.HasError())
+			require.Len(t, d.Get("events").([]interface{}), test.events)
+			require.Equal(t, test.requests, server.Requests()-requests)
+			require.NotEmpty(t, d.Id())
+		})
+	}
+}
+
+func TestDataSourceLogEventsReadUntil(t *testing.T) {
+	// The clock of the org is ahead so that, without until, it would return
+	// the event published after the read.
+	now := time.Now()
+	server, config := newFakeOktaConfig(t, fakeokta.WithClock(func() time.Time { return now.Add(time.Hour) }))
+	server.AddLogEvents(testLoginEvent(now.Add(-time.Minute)), testLoginEvent(now.Add(30*time.Minute)))
+
+	d := schema.TestResourceDataRaw(t, dataSourceLogEvents().Schema, map[string]interface{}{})
+	require.False(t, dataSourceLogEventsRead(context.TODO(), d, config).HasError())
+	require.Len(t, d.Get("events").([]interface{}), 1)
+	require.Equal(t, "user.session.start", d.Get("events.0.event_type"))
+	require.Equal(t, now.Add(-time.Minute).UTC().Format(time.RFC3339), d.Get("events.0.published"))
+	require.Equal(t, "SUCCESS", d.Get("events.0.outcome_result"))
+	require.Equal(t, "break-glass@example.com", d.Get("events.0.actor.0.alternate_id"))
+	require.Equal(t, "Okta Admin Console", d.Get("events.0.target.0.display_name"))
+	require.Equal(t, "Paris", d.Get("events.0.client.0.city"))
+	require.Equal(t, "curl/8.0", d.Get("events.0.client.0.user_agent"))
+	require.Equal(t, "txn1", d.Get("events.0.transaction_id"))
+
+	// The ID hashes the query, until included, so it changes between reads.
+	id := d.Id()
+	d = schema.TestResourceDataRaw(t, dataSourceLogEvents().Schema, map[string]interface{}{
+		"until": now.Add(time.Hour).UTC().Format(time.RFC3339),
+	})
+	require.False(t, dataSourceLogEventsRead(context.TODO(), d, config).HasError())
+	require.Len(t, d.Get("events").([]interface{}), 2)
+	require.NotEqual(t, id, d.Id())
+}
@@ -28,6 +28,7 @@ func TestAccDataSourceOktaLogStream_read(t *testing.T) {
 					resource.TestCheckResourceAttr(awsDataSource, "settings.account_id", "123456789012"),
 					resource.TestCheckResourceAttr(awsDataSource, "settings.region", "eu-west-3"),
 					resource.TestCheckResourceAttr(awsDataSource, "settings.event_source_name", fmt.Sprintf("%s_AWS", buildResourceName(mgr.Seed))),
+					resource.TestCheckResourceAttr(awsDataSource, "aws_eventbridge.account_id", "123456789012"),
 
 					//resource.TestCheckResourceAttrSet(splunkDataSource, "id"),
 					resource.TestCheckResourceAttr(splunkDataSource, "name", fmt.Sprintf("%s Splunk", buildResourceName(mgr.Seed))),
@@ -35,6 +36,7 @@ func TestAccDataSourceOktaLogStream_read(t *testing.T) {
 					resource.TestCheckResourceAttr(splunkDataSource, "status", "ACTIVE"),
 					resource.TestCheckResourceAttr(splunkDataSource, "settings.host", "acme.splunkcloud.com"),
 					resource.TestCheckResourceAttr(splunkDataSource, "settings.edition", "aws"),
+					resource.TestCheckResourceAttr(splunkDataSource, "splunk_cloud_logstreaming.host", "acme.splunkcloud.com"),
 				),
 			},
 		},
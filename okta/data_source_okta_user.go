@@ -128,15 +128,20 @@ func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, m interface
 	if err != nil {
 		return diag.Errorf("failed to set user's properties: %v", err)
 	}
+	var diags diag.Diagnostics
 	if val := d.Get("skip_roles"); val != nil {
 		if skip, ok := val.(bool); ok && !skip {
-			err = setAdminRoles(ctx, d, m)
+			diags, err = setAdminRoles(ctx, d, m)
 			if err != nil {
 				return diag.Errorf("failed to set user's admin roles: %v", err)
 			}
-			err = setRoles(ctx, d, m)
-			if err != nil {
-				return diag.Errorf("failed to set user's roles: %v", err)
+			// roles are listed from the same endpoint as admin roles, there
+			// is no need to warn twice
+			if diags == nil {
+				diags, err = setRoles(ctx, d, m)
+				if err != nil {
+					return diag.Errorf("failed to set user's roles: %v", err)
+				}
 			}
 		}
 	}
@@ -150,7 +155,7 @@ func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, m interface
 		}
 	}
 
-	return nil
+	return diags
 }
 
 func getSearchCriteria(d *schema.ResourceData) string {
@@ -2,6 +2,7 @@ package okta
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -130,3 +131,19 @@ func hashEmailCustomization(v interface{}) int {
 	))
 	return schema.HashString(buf.String())
 }
+
+func collectEmailCustomizations(ctx context.Context, client *okta.APIClient, brandID, templateName string) ([]okta.EmailCustomization, error) {
+	customizations, resp, err := client.CustomizationAPI.ListEmailCustomizations(ctx, brandID, templateName).Limit(int32(defaultPaginationLimit)).Execute()
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextCustomizations []okta.EmailCustomization
+		resp, err = resp.Next(&nextCustomizations)
+		if err != nil {
+			return nil, err
+		}
+		customizations = append(customizations, nextCustomizations...)
+	}
+	return customizations, nil
+}
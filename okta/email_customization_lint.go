@@ -0,0 +1,274 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/internal/velocity"
+)
+
+// emailTemplateVariables are the variables of an Okta email template besides
+// the variables of every template.
+type emailTemplateVariables struct {
+	variables []string
+	// required are the groups of variables of which the body must reference
+	// at least one, e.g. the activation link or token
+	required [][]string
+}
+
+// emailVariables are the sample values of the email template variables
+// previews are rendered with. User profile properties are referenced as
+// ${user.profile.<property>}, or ${user.<property>} in legacy templates.
+var emailVariables = map[string]interface{}{
+	"baseURL":                            "https://example.okta.com",
+	"org.name":                           "Example",
+	"org.locale":                         "en",
+	"org.subDomain":                      "example",
+	"app.id":                             "0oa1gjh63g214q0Hq0g4",
+	"app.name":                           "example_app",
+	"app.label":                          "Example App",
+	"user.groups.names":                  "Everyone",
+	"user.groups.ids":                    "00g1emaKYZTWRYYRRTSK",
+	"brand.theme.logo":                   "https://example.okta.com/assets/img/logo.png",
+	"brand.theme.primaryColor":           "#1662dd",
+	"brand.theme.secondaryColor":         "#ebebed",
+	"org.activationTokenExpirationHours": int64(168),
+	"activationLink":                     "https://example.okta.com/welcome/XE6wE17zmphl3KqAPFxO",
+	"activationToken":                    "XE6wE17zmphl3KqAPFxO",
+	"registrationActivationLink":         "https://example.okta.com/signin/register-activation/XE6wE17zmphl3KqAPFxO",
+	"registrationActivationToken":        "XE6wE17zmphl3KqAPFxO",
+	"verificationLink":                   "https://example.okta.com/signin/verify/XE6wE17zmphl3KqAPFxO",
+	"verificationToken":                  "XE6wE17zmphl3KqAPFxO",
+	"resetPasswordLink":                  "https://example.okta.com/signin/reset-password/XE6wE17zmphl3KqAPFxO",
+	"recoveryToken":                      "XE6wE17zmphl3KqAPFxO",
+	"unlockAccountLink":                  "https://example.okta.com/signin/unlock/XE6wE17zmphl3KqAPFxO",
+	"oneTimePassword":                    "123456",
+	"pushVerifyActivationLink":           "https://example.okta.com/signin/okta-verify/XE6wE17zmphl3KqAPFxO",
+	"newEmail":                           "jane.doe@example.org",
+	"oldEmail":                           "jane.doe@example.com",
+	"request.browser":                    "Chrome",
+	"request.date":                       "January 1, 2024",
+	"request.time":                       "9:00 AM UTC",
+	"request.location":                   "San Francisco, CA, USA",
+	"request.ipAddress":                  "203.0.113.10",
+}
+
+// commonEmailVariables are the variables every email template can reference.
+var commonEmailVariables = []string{
+	"app.id",
+	"app.label",
+	"app.name",
+	"baseURL",
+	"brand.theme.logo",
+	"brand.theme.primaryColor",
+	"brand.theme.secondaryColor",
+	"org.locale",
+	"org.name",
+	"org.subDomain",
+	"user.groups.ids",
+	"user.groups.names",
+}
+
+var (
+	activationEmailTemplate = emailTemplateVariables{
+		variables: []string{"activationLink", "activationToken", "org.activationTokenExpirationHours"},
+		required:  [][]string{{"activationLink", "activationToken"}},
+	}
+	forgotPasswordEmailTemplate = emailTemplateVariables{
+		variables: []string{"resetPasswordLink", "oneTimePassword", "recoveryToken"},
+		required:  [][]string{{"resetPasswordLink", "oneTimePassword", "recoveryToken"}},
+	}
+	selfServiceUnlockEmailTemplate = emailTemplateVariables{
+		variables: []string{"unlockAccountLink", "oneTimePassword", "recoveryToken"},
+		required:  [][]string{{"unlockAccountLink", "oneTimePassword", "recoveryToken"}},
+	}
+	requestEmailVariables = []string{
+		"request.browser", "request.date", "request.time", "request.location", "request.ipAddress",
+	}
+)
+
+// knownEmailTemplates are the variables of the Okta email templates by name,
+// see https://developer.okta.com/docs/guides/custom-email/main/#use-vtl-variables
+// Templates missing from the list are only checked for syntax and the
+// variables they reference.
+var knownEmailTemplates = map[string]emailTemplateVariables{
+	"AccountLockout":                     {variables: append([]string{"unlockAccountLink"}, requestEmailVariables...)},
+	"ADForgotPassword":                   forgotPasswordEmailTemplate,
+	"ADForgotPasswordDenied":             {},
+	"ADSelfServiceUnlock":                selfServiceUnlockEmailTemplate,
+	"ADUserActivation":                   activationEmailTemplate,
+	"ChangeEmailConfirmation":            {variables: []string{"verificationLink", "verificationToken", "newEmail", "oldEmail"}, required: [][]string{{"verificationLink", "verificationToken"}}},
+	"EmailChallenge":                     {variables: append([]string{"verificationLink", "verificationToken", "oneTimePassword"}, requestEmailVariables...), required: [][]string{{"verificationLink", "verificationToken", "oneTimePassword"}}},
+	"EmailChangeConfirmation":            {variables: []string{"verificationLink", "verificationToken", "newEmail", "oldEmail"}, required: [][]string{{"verificationLink", "verificationToken"}}},
+	"EmailChangeNotification":            {variables: []string{"newEmail", "oldEmail"}},
+	"EmailFactorVerification":            {variables: []string{"verificationLink", "verificationToken", "oneTimePassword"}, required: [][]string{{"verificationLink", "verificationToken", "oneTimePassword"}}},
+	"ForgotPassword":                     forgotPasswordEmailTemplate,
+	"ForgotPasswordDenied":               {},
+	"LDAPForgotPassword":                 forgotPasswordEmailTemplate,
+	"LDAPForgotPasswordDenied":           {},
+	"LDAPSelfServiceUnlock":              selfServiceUnlockEmailTemplate,
+	"LDAPUserActivation":                 activationEmailTemplate,
+	"NewSignOnNotification":              {variables: requestEmailVariables},
+	"OktaVerifyActivation":               {variables: []string{"pushVerifyActivationLink"}, required: [][]string{{"pushVerifyActivationLink"}}},
+	"PasswordChanged":                    {variables: requestEmailVariables},
+	"PasswordResetByAdmin":               {variables: []string{"resetPasswordLink", "recoveryToken"}, required: [][]string{{"resetPasswordLink", "recoveryToken"}}},
+	"PendingEmailChange":                 {variables: []string{"newEmail", "oldEmail"}},
+	"RegistrationActivation":             {variables: []string{"registrationActivationLink", "registrationActivationToken"}, required: [][]string{{"registrationActivationLink", "registrationActivationToken"}}},
+	"RegistrationEmailVerification":      {variables: []string{"verificationLink", "verificationToken", "oneTimePassword"}, required: [][]string{{"verificationLink", "verificationToken", "oneTimePassword"}}},
+	"SelfServiceUnlock":                  selfServiceUnlockEmailTemplate,
+	"SelfServiceUnlockOnUnlockedAccount": {},
+	"UserActivation":                     activationEmailTemplate,
+}
+
+// checkEmailTemplate validates the subject or body of a customization of the
+// email template. When required is set the body is checked to reference the
+// template's required links. The variables it references that aren't
+// variables of the template and the user properties that aren't properties of
+// a user type's profile are returned as warnings rather than errors, Okta
+// adds variables the provider doesn't know about. Templates unknown to the
+// provider are only checked for syntax and user profile properties.
+func checkEmailTemplate(ctx context.Context, m interface{}, k, templateName, text string, required bool) ([]string, error) {
+	refs, err := velocity.Validate(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid email template: %v", k, err)
+	}
+	var warnings []string
+	tmpl, known := knownEmailTemplates[templateName]
+	variables := make(map[string]bool)
+	for _, v := range append(commonEmailVariables, tmpl.variables...) {
+		variables[v] = true
+	}
+	referenced := make(map[string]bool)
+	var userProperties map[string]bool
+	for _, ref := range refs {
+		referenced[ref.Name] = true
+		if variables[ref.Name] {
+			continue
+		}
+		if ref.Name == "user" || ref.Name == "user.profile" {
+			warnings = append(warnings, fmt.Sprintf("%s references ${%s} at line %d, column %d, reference a user profile property instead, e.g. ${user.profile.firstName}", k, ref.Name, ref.Line, ref.Column))
+			continue
+		}
+		if strings.HasPrefix(ref.Name, "user.") {
+			// ${user.<property>} is the legacy form of ${user.profile.<property>}
+			property := strings.TrimPrefix(strings.TrimPrefix(ref.Name, "user."), "profile.")
+			if userProperties == nil && m != nil {
+				userProperties = m.(*Config).UserProfileProperties(ctx)
+			}
+			if userProperties != nil && !userProperties[property] {
+				warnings = append(warnings, fmt.Sprintf("%s references ${%s} at line %d, column %d, %s is not a property of any user type's profile", k, ref.Name, ref.Line, ref.Column, property))
+			}
+			continue
+		}
+		if known {
+			warnings = append(warnings, fmt.Sprintf("%s references ${%s} at line %d, column %d which is not a variable of the %s template, its variables are: %s",
+				k, ref.Name, ref.Line, ref.Column, templateName, strings.Join(sortedVariables(variables), ", ")))
+		}
+	}
+	if !required {
+		return warnings, nil
+	}
+	for _, group := range tmpl.required {
+		found := false
+		for _, v := range group {
+			found = found || referenced[v]
+		}
+		if !found {
+			links := make([]string, len(group))
+			for i, v := range group {
+				links[i] = "${" + v + "}"
+			}
+			return warnings, fmt.Errorf("%s of a %s email must include %s", k, templateName, strings.Join(links, " or "))
+		}
+	}
+	return warnings, nil
+}
+
+func sortedVariables(variables map[string]bool) []string {
+	names := make([]string, 0, len(variables)+1)
+	for v := range variables {
+		names = append(names, "${"+v+"}")
+	}
+	sort.Strings(names)
+	return append(names, "${user.profile.<property>}")
+}
+
+// emailCustomizationDiff checks the subject and body of an email
+// customization when they change and are known at plan time. A plan can't
+// carry warnings, they are logged.
+func emailCustomizationDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("template_name") {
+		return nil
+	}
+	templateName := d.Get("template_name").(string)
+	for _, k := range []string{"subject", "body"} {
+		if !d.HasChanges("template_name", k) || !d.NewValueKnown(k) || d.Get(k).(string) == "" {
+			continue
+		}
+		warnings, err := checkEmailTemplate(ctx, m, k, templateName, d.Get(k).(string), k == "body")
+		logEmailTemplateWarnings(warnings)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func logEmailTemplateWarnings(warnings []string) {
+	for _, warning := range warnings {
+		log.Printf("[WARN] %s", warning)
+	}
+}
+
+// emailSampleContext returns the sample context email templates are rendered
+// with: the sample values of the variables, a sample user whose locale is the
+// language, and the values of the overrides keyed by variable name, e.g.
+// user.profile.firstName. The user profile is also set as the legacy
+// ${user.<property>} variables.
+func emailSampleContext(language string, overrides map[string]interface{}) map[string]interface{} {
+	context := make(map[string]interface{})
+	for name, v := range emailVariables {
+		setEmailVariable(context, name, v)
+	}
+	sampleUser := map[string]interface{}{
+		"user.profile.login":       "jane.doe@example.com",
+		"user.profile.email":       "jane.doe@example.com",
+		"user.profile.firstName":   "Jane",
+		"user.profile.lastName":    "Doe",
+		"user.profile.displayName": "Jane Doe",
+		"user.profile.locale":      language,
+	}
+	for name, v := range sampleUser {
+		setEmailVariable(context, name, v)
+	}
+	for name, v := range overrides {
+		setEmailVariable(context, name, v)
+	}
+	user, _ := context["user"].(map[string]interface{})
+	profile, _ := user["profile"].(map[string]interface{})
+	for name, v := range profile {
+		if _, ok := user[name]; !ok {
+			user[name] = v
+		}
+	}
+	return context
+}
+
+// setEmailVariable sets the value of a dotted variable name, e.g.
+// user.profile.firstName, in a context.
+func setEmailVariable(context map[string]interface{}, name string, v interface{}) {
+	keys := strings.Split(name, ".")
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := context[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			context[key] = next
+		}
+		context = next
+	}
+	context[keys[len(keys)-1]] = v
+}
@@ -0,0 +1,80 @@
+package okta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckEmailTemplate(t *testing.T) {
+	_, config := newFakeOktaConfig(t)
+
+	tests := []struct {
+		templateName string
+		text         string
+		required     bool
+		warning      string
+		err          string
+	}{
+		{"UserActivation", `Hi ${user.profile.firstName}, <a href="${activationLink}">activate</a> within ${f.formatTimeDiffHoursNowInUserLocale(${org.activationTokenExpirationHours})}`, true, "", ""},
+		{"UserActivation", `${app.label} (${app.name}) for ${user.groups.names}: ${activationLink}`, true, "", ""},
+		{"ForgotPassword", `Hi $$user.firstName,<br/><br/>Click this link to reset your password: $$resetPasswordLink`, true, "", ""},
+		{"ForgotPassword", `#set($code = $oneTimePassword)Your code is $code`, true, "", ""},
+		{"ForgotPassword", `Reset your ${org.name} password`, false, "", ""},
+		{"SomeFutureTemplate", `${someNewVariable}`, true, "", ""},
+		{"UserActivation", `Welcome ${user.profile.firstname}! ${activationLink}`, true,
+			"body references ${user.profile.firstname} at line 1, column 9, firstname is not a property of any user type's profile", ""},
+		{"UserActivation", "Welcome!\n${resetPasswordLink}", true,
+			"body references ${resetPasswordLink} at line 2, column 1 which is not a variable of the UserActivation template, its variables are: ${activationLink}, ${activationToken}, ${app.id}, ${app.label}, ${app.name}, ${baseURL}, ${brand.theme.logo}, ${brand.theme.primaryColor}, ${brand.theme.secondaryColor}, ${org.activationTokenExpirationHours}, ${org.locale}, ${org.name}, ${org.subDomain}, ${user.groups.ids}, ${user.groups.names}, ${user.profile.<property>}",
+			"body of a UserActivation email must include ${activationLink} or ${activationToken}"},
+		{"UserActivation", `Welcome ${user.profile.firstName}!`, true, "",
+			"body of a UserActivation email must include ${activationLink} or ${activationToken}"},
+		{"UserActivation", `#if($user.profile.locale == "fr")Bienvenue ${activationLink}`, true, "",
+			"body is not a valid email template: #if without #end at line 1, column 1"},
+		{"UserActivation", `${user.profile}`, false,
+			"body references ${user.profile} at line 1, column 1, reference a user profile property instead, e.g. ${user.profile.firstName}", ""},
+	}
+	for _, test := range tests {
+		warnings, err := checkEmailTemplate(context.TODO(), config, "body", test.templateName, test.text, test.required)
+		if test.warning == "" {
+			require.Empty(t, warnings, test.text)
+		} else {
+			require.Equal(t, []string{test.warning}, warnings, test.text)
+		}
+		if test.err == "" {
+			require.NoError(t, err, test.text)
+			continue
+		}
+		require.EqualError(t, err, test.err, test.text)
+	}
+}
+
+func TestDataSourceEmailCustomizationPreviewRead(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceEmailCustomizationPreview().Schema, map[string]interface{}{
+		"template_name": "UserActivation",
+		"language":      "fr",
+		"subject":       "Bienvenue chez ${org.name}",
+		"body":          `#if(${user.profile.locale} == "fr")Bonjour#{else}Hello#end $user.firstName, expire dans ${f.formatTimeDiffHoursNow(${org.activationTokenExpirationHours})}`,
+		"context": map[string]interface{}{
+			"user.profile.firstName":             "Marie",
+			"org.activationTokenExpirationHours": "48",
+		},
+	})
+	diags := dataSourceEmailCustomizationPreviewRead(context.TODO(), d, nil)
+	require.Len(t, diags, 1)
+	require.Equal(t, "body of a UserActivation email must include ${activationLink} or ${activationToken}", diags[0].Detail)
+	require.Equal(t, "Bienvenue chez Example", d.Get("rendered_subject"))
+	require.Equal(t, "Bonjour Marie, expire dans 2 days", d.Get("rendered_body"))
+	require.Equal(t, "email_customization_preview-UserActivation-fr", d.Id())
+
+	d = schema.TestResourceDataRaw(t, dataSourceEmailCustomizationPreview().Schema, map[string]interface{}{
+		"template_name": "UserActivation",
+		"subject":       "Welcome",
+		"body":          "#foreach($a in $b)",
+	})
+	diags = dataSourceEmailCustomizationPreviewRead(context.TODO(), d, nil)
+	require.True(t, diags.HasError())
+	require.Equal(t, "body is not a valid email template: #foreach without #end at line 1, column 1", diags[0].Summary)
+}
@@ -0,0 +1,141 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ ephemeral.EphemeralResourceWithConfigure = &appOAuthClientSecretEphemeralResource{}
+
+func NewAppOAuthClientSecretEphemeralResource() ephemeral.EphemeralResource {
+	return &appOAuthClientSecretEphemeralResource{}
+}
+
+type appOAuthClientSecretEphemeralResource struct {
+	*Config
+}
+
+type appOAuthClientSecretEphemeralResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	AppID        types.String `tfsdk:"app_id"`
+	ClientSecret types.String `tfsdk:"client_secret"`
+	SecretHash   types.String `tfsdk:"secret_hash"`
+	Status       types.String `tfsdk:"status"`
+	Created      types.String `tfsdk:"created"`
+}
+
+func (e *appOAuthClientSecretEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_oauth_client_secret"
+}
+
+func (e *appOAuthClientSecretEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	e.Config = ephemeralResourceConfiguration(req, resp)
+}
+
+func (e *appOAuthClientSecretEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Gets a client secret of an OAuth application without storing it in the state. The newest active secret is used unless `id` is set, a secret is generated when the application has no active secret.",
+		MarkdownDescription: "Gets a client secret of an OAuth application without storing it in the state. The newest active secret is used unless `id` is set, a secret is generated when the application has no active secret.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:         "ID of the client secret.",
+				MarkdownDescription: "ID of the client secret.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"app_id": schema.StringAttribute{
+				Description:         "ID of the OAuth application.",
+				MarkdownDescription: "ID of the OAuth application.",
+				Required:            true,
+			},
+			"client_secret": schema.StringAttribute{
+				Description:         "Value of the client secret.",
+				MarkdownDescription: "Value of the client secret.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"secret_hash": schema.StringAttribute{
+				Description:         "Hash of the client secret.",
+				MarkdownDescription: "Hash of the client secret.",
+				Computed:            true,
+			},
+			"status": schema.StringAttribute{
+				Description:         "Status of the client secret: ACTIVE or INACTIVE.",
+				MarkdownDescription: "Status of the client secret: ACTIVE or INACTIVE.",
+				Computed:            true,
+			},
+			"created": schema.StringAttribute{
+				Description:         "Creation timestamp of the client secret.",
+				MarkdownDescription: "Creation timestamp of the client secret.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (e *appOAuthClientSecretEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data appOAuthClientSecretEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appID := data.AppID.ValueString()
+	secrets, err := listAppOAuthClientSecrets(ctx, e.Config, appID)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to list client secrets of OAuth application", err.Error())
+		return
+	}
+	var secret *oauthClientSecret
+	if !data.ID.IsNull() {
+		for _, s := range secrets {
+			if s.ID == data.ID.ValueString() {
+				secret = s
+				break
+			}
+		}
+		if secret == nil {
+			resp.Diagnostics.AddError("client secret not found",
+				fmt.Sprintf("OAuth application '%s' has no client secret with ID '%s'", appID, data.ID.ValueString()))
+			return
+		}
+	} else {
+		secret = newestActiveAppOAuthClientSecret(secrets)
+		if secret == nil {
+			secret, err = createAppOAuthClientSecret(ctx, e.Config, appID, &oauthClientSecret{})
+			if err != nil {
+				resp.Diagnostics.AddError("failed to generate client secret of OAuth application", err.Error())
+				return
+			}
+		}
+	}
+
+	data.ID = types.StringValue(secret.ID)
+	data.ClientSecret = types.StringValue(secret.ClientSecret)
+	data.SecretHash = types.StringValue(secret.SecretHash)
+	data.Status = types.StringValue(secret.Status)
+	data.Created = types.StringValue(secret.Created)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// newestActiveAppOAuthClientSecret returns the most recently created active
+// secret, nil when there is none.
+func newestActiveAppOAuthClientSecret(secrets []*oauthClientSecret) *oauthClientSecret {
+	var newest *oauthClientSecret
+	var newestCreated time.Time
+	for _, s := range secrets {
+		if s.Status != statusActive {
+			continue
+		}
+		created, _ := time.Parse(time.RFC3339, s.Created)
+		if newest == nil || created.After(newestCreated) {
+			newest, newestCreated = s, created
+		}
+	}
+	return newest
+}
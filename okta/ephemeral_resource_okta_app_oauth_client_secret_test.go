@@ -0,0 +1,94 @@
+package okta
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/okta/terraform-provider-okta/okta/internal/fakeokta"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppOAuthClientSecretEphemeralResource(t *testing.T) {
+	// every request is a second later, so that the secrets are ordered
+	now := time.Now().Add(-time.Hour)
+	_, config := newFakeOktaConfig(t, fakeokta.WithClock(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}))
+	ctx := context.TODO()
+	createApp := func(authMethod string) string {
+		t.Helper()
+		app := sdk.NewOpenIdConnectApplication()
+		app.Label = "My App " + authMethod
+		app.Credentials = &sdk.OAuthApplicationCredentials{OauthClient: &sdk.ApplicationCredentialsOAuthClient{TokenEndpointAuthMethod: authMethod}}
+		_, _, err := config.oktaSDKClientV2.Application.CreateApplication(ctx, app, nil)
+		require.NoError(t, err)
+		return app.Id
+	}
+	appID := createApp("client_secret_basic")
+	secrets, err := listAppOAuthClientSecrets(ctx, config, appID)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	old := secrets[0]
+	current, err := createAppOAuthClientSecret(ctx, config, appID, &oauthClientSecret{})
+	require.NoError(t, err)
+
+	e := &appOAuthClientSecretEphemeralResource{Config: config}
+	var schemaResp ephemeral.SchemaResponse
+	e.Schema(context.TODO(), ephemeral.SchemaRequest{}, &schemaResp)
+	typ := schemaResp.Schema.Type().TerraformType(context.TODO())
+
+	open := func(id interface{}) (*ephemeral.OpenResponse, appOAuthClientSecretEphemeralResourceModel) {
+		req := ephemeral.OpenRequest{Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw: tftypes.NewValue(typ, map[string]tftypes.Value{
+				"id":            tftypes.NewValue(tftypes.String, id),
+				"app_id":        tftypes.NewValue(tftypes.String, appID),
+				"client_secret": tftypes.NewValue(tftypes.String, nil),
+				"secret_hash":   tftypes.NewValue(tftypes.String, nil),
+				"status":        tftypes.NewValue(tftypes.String, nil),
+				"created":       tftypes.NewValue(tftypes.String, nil),
+			}),
+		}}
+		resp := &ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(typ, nil),
+		}}
+		e.Open(context.TODO(), req, resp)
+		var data appOAuthClientSecretEphemeralResourceModel
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.Result.Get(context.TODO(), &data)...)
+		}
+		return resp, data
+	}
+
+	resp, data := open(nil)
+	require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	require.Equal(t, current.ID, data.ID.ValueString())
+	require.Equal(t, current.ClientSecret, data.ClientSecret.ValueString())
+
+	resp, data = open(old.ID)
+	require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	require.Equal(t, old.ClientSecret, data.ClientSecret.ValueString())
+
+	resp, _ = open("ocs9")
+	require.True(t, resp.Diagnostics.HasError())
+
+	// a secret is generated when the application has no active secret
+	appID = createApp("private_key_jwt")
+	inactive, err := createAppOAuthClientSecret(ctx, config, appID, &oauthClientSecret{Status: statusInactive})
+	require.NoError(t, err)
+	resp, data = open(nil)
+	require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	require.NotEqual(t, inactive.ID, data.ID.ValueString())
+	require.Equal(t, statusActive, data.Status.ValueString())
+	require.NotEmpty(t, data.ClientSecret.ValueString())
+	secrets, err = listAppOAuthClientSecrets(ctx, config, appID)
+	require.NoError(t, err)
+	require.Len(t, secrets, 2)
+}
@@ -0,0 +1,83 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+// checkExpression validates the Okta Expression Language expression and
+// checks that the user properties it references are properties of a user
+// type's profile. When appID is set appuser properties are checked against the
+// app's user profile. The schemas are only read when the expression
+// references their properties, and are read once per provider run. Schemas
+// that can't be read aren't checked; the expression is then validated by Okta
+// on apply.
+func checkExpression(ctx context.Context, m interface{}, k, expr, appID string) error {
+	refs, err := expression.Validate(expr)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid Okta Expression Language expression: %v", k, err)
+	}
+	var userProperties, appUserProperties map[string]bool
+	for _, ref := range refs {
+		switch ref.Root {
+		case "user":
+			if userProperties == nil {
+				if userProperties = m.(*Config).UserProfileProperties(ctx); userProperties == nil {
+					continue
+				}
+			}
+			if !userProperties[ref.Property] {
+				return fmt.Errorf("%s references user.%s which is not a property of any user type's profile", k, ref.Property)
+			}
+		case "appuser":
+			if appID == "" {
+				continue
+			}
+			if appUserProperties == nil {
+				if appUserProperties = m.(*Config).AppUserProfileProperties(ctx, appID); appUserProperties == nil {
+					appID = ""
+					continue
+				}
+			}
+			if !appUserProperties[ref.Property] {
+				return fmt.Errorf("%s references appuser.%s which is not a property of the app's user profile", k, ref.Property)
+			}
+		}
+	}
+	return nil
+}
+
+// userSchemaProperties returns the names of the base and custom properties of
+// a user or app user schema.
+func userSchemaProperties(us *sdk.UserSchema) map[string]bool {
+	properties := make(map[string]bool)
+	if us == nil || us.Definitions == nil {
+		return properties
+	}
+	if us.Definitions.Base != nil {
+		for name := range us.Definitions.Base.Properties {
+			properties[name] = true
+		}
+	}
+	if us.Definitions.Custom != nil {
+		for name := range us.Definitions.Custom.Properties {
+			properties[name] = true
+		}
+	}
+	return properties
+}
+
+// expressionDiff returns a CustomizeDiffFunc checking the expression of the
+// attribute when its value changes and is known at plan time.
+func expressionDiff(k string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if !d.HasChange(k) || !d.NewValueKnown(k) {
+			return nil
+		}
+		return checkExpression(ctx, m, k, d.Get(k).(string), "")
+	}
+}
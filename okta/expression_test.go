@@ -0,0 +1,72 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckExpression(t *testing.T) {
+	server, config := newFakeOktaConfig(t)
+	ctx := context.TODO()
+	re := getRequestExecutor(config)
+	post := func(path string, body interface{}) map[string]interface{} {
+		t.Helper()
+		var result map[string]interface{}
+		req, err := re.NewRequest(http.MethodPost, path, body)
+		require.NoError(t, err)
+		_, err = re.Do(ctx, req, &result)
+		require.NoError(t, err)
+		return result
+	}
+	customProperty := func(name string) map[string]interface{} {
+		return map[string]interface{}{"definitions": map[string]interface{}{"custom": map[string]interface{}{
+			"properties": map[string]interface{}{name: map[string]interface{}{"title": name, "type": "string"}},
+		}}}
+	}
+	contractor := post("/api/v1/meta/types/user", map[string]interface{}{"name": "contractor", "displayName": "Contractor"})
+	schemaHref := contractor["_links"].(map[string]interface{})["schema"].(map[string]interface{})["href"].(string)
+	post(strings.TrimPrefix(schemaHref, server.URL), customProperty("agency"))
+	appID := post("/api/v1/apps", map[string]interface{}{"label": "Payroll", "signOnMode": "BOOKMARK"})["id"].(string)
+	post("/api/v1/meta/schemas/apps/"+appID+"/default", customProperty("role"))
+
+	tests := []struct {
+		expr  string
+		appID string
+		err   string
+	}{
+		{`String.startsWith(user.firstName, "andy")`, "", ""},
+		{`user.costCenter == "R&D" or user.agency == "acme"`, "", ""},
+		{`appuser.role ?: appuser.userName`, appID, ""},
+		{`appuser.anything`, "", ""},
+		{`appuser.anything`, "0oa404", ""},
+		{`user.costcenter`, "", "expression_value references user.costcenter which is not a property of any user type's profile"},
+		{`appuser.nickName`, appID, "expression_value references appuser.nickName which is not a property of the app's user profile"},
+		{`String.toLowercase(user.firstName)`, "", "expression_value is not a valid Okta Expression Language expression: unknown function String.toLowercase"},
+	}
+	for _, test := range tests {
+		err := checkExpression(ctx, config, "expression_value", test.expr, test.appID)
+		if test.err == "" {
+			require.NoError(t, err, test.expr)
+			continue
+		}
+		require.ErrorContains(t, err, test.err, test.expr)
+	}
+	// the schemas are read once, whatever the number of expressions checked
+	requests := server.Requests()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, checkExpression(ctx, config, "mappings", `appuser.role`, appID))
+		require.NoError(t, checkExpression(ctx, config, "mappings", `appuser.role`, "0oa404"))
+	}
+	require.NoError(t, checkExpression(ctx, config, "mappings", `user.agency`, ""))
+	require.Equal(t, requests, server.Requests())
+
+	// expressions without profile references don't read any schema
+	server, config = newFakeOktaConfig(t)
+	requests = server.Requests()
+	require.NoError(t, checkExpression(ctx, config, "value", `"engineering"`, appID))
+	require.Equal(t, requests, server.Requests())
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -15,11 +17,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/internal/metrics"
+	"github.com/okta/terraform-provider-okta/okta/internal/permissions"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ provider.Provider = &FrameworkProvider{}
+	_ provider.Provider                       = &FrameworkProvider{}
+	_ provider.ProviderWithEphemeralResources = &FrameworkProvider{}
+	_ provider.ProviderWithFunctions          = &FrameworkProvider{}
 )
 
 // NewFrameworkProvider is a helper function to simplify provider server and
@@ -53,7 +59,15 @@ type FrameworkProviderData struct {
 	Parallelism    types.Int64  `tfsdk:"parallelism"`
 	LogLevel       types.Int64  `tfsdk:"log_level"`
 	MaxAPICapacity types.Int64  `tfsdk:"max_api_capacity"`
+	RateLimitFile  types.String `tfsdk:"rate_limit_coordination_file"`
 	RequestTimeout types.Int64  `tfsdk:"request_timeout"`
+	APITokenRole   types.String `tfsdk:"api_token_role"`
+	ReadOnly       types.Bool   `tfsdk:"read_only"`
+	MetricsFile    types.String `tfsdk:"metrics_file"`
+	MetricsFormat  types.String `tfsdk:"metrics_format"`
+	CacheEnabled   types.Bool   `tfsdk:"cache_enabled"`
+	CacheDir       types.String `tfsdk:"cache_dir"`
+	CacheTTL       types.Int64  `tfsdk:"cache_ttl_seconds"`
 }
 
 // Metadata returns the provider type name.
@@ -136,7 +150,7 @@ func (p *FrameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 			},
 			"base_url": schema.StringAttribute{
 				Optional:    true,
-				Description: "The Okta url. (Use 'oktapreview.com' for Okta testing)",
+				Description: "The Okta url. (Use 'oktapreview.com' for Okta testing, or the full URL of a fake Okta API, e.g. 'http://127.0.0.1:8080')",
 			},
 			"http_proxy": schema.StringAttribute{
 				Optional:    true,
@@ -191,6 +205,56 @@ func (p *FrameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 					int64validator.AtMost(300),
 				},
 			},
+			"api_token_role": schema.StringAttribute{
+				Optional: true,
+				Description: "The admin role of the API token or OAuth 2.0 service app, one of the standard role types such as `SUPER_ADMIN` or `ORG_ADMIN`. " +
+					"When a request to an endpoint the role isn't known to have access to responds 401 Unauthorized or 403 Forbidden the provider warns and leaves the attributes depending on it unset instead of erroring. " +
+					"Detected from the token admin's roles for `api_token` when not set. Can also be sourced from the `OKTA_API_TOKEN_ROLE` environment variable.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(permissions.Roles()...),
+				},
+			},
+			"read_only": schema.BoolAttribute{
+				Optional: true,
+				Description: "Refuse every request to the Okta API that would modify the org, for instance to run `terraform plan` or `terraform refresh` with a token that may not be scoped read-only. " +
+					"A mutating request fails with an error naming the resource that made it, its method and endpoint. Can also be sourced from the `OKTA_READ_ONLY` environment variable.",
+			},
+			"metrics_file": schema.StringAttribute{
+				Optional: true,
+				Description: "File the provider writes its Okta API usage to once a terraform run ends: request counts, latencies, 429s, retries and throttle sleeps " +
+					"per endpoint class and per resource type. The usage of the provider processes started by the same terraform run is added up. " +
+					"Can also be sourced from the `OKTA_METRICS_FILE` environment variable.",
+			},
+			"metrics_format": schema.StringAttribute{
+				Optional:    true,
+				Description: "Format of the `metrics_file`, `openmetrics` (the default) or `json`. Can also be sourced from the `OKTA_METRICS_FORMAT` environment variable.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(metrics.Formats()...),
+				},
+			},
+			"rate_limit_coordination_file": schema.StringAttribute{
+				Optional: true,
+				Description: "(Experimental) file on a volume shared by the terraform runs against the org, for instance parallel workspaces, through which the providers share the rate limit status of the Okta API so `max_api_capacity` is enforced across processes. " +
+					"Only used when `max_api_capacity` is set below 100. Can also be sourced from the `OKTA_RATE_LIMIT_COORDINATION_FILE` environment variable.",
+			},
+			"cache_enabled": schema.BoolAttribute{
+				Optional: true,
+				Description: "(Experimental) cache responses of GET requests on disk so they can be reused across terraform runs, " +
+					"for instance a plan followed by an apply. A cached path, the paths beneath it, and the collections above it are invalidated " +
+					"whenever the provider makes a POST, PUT, or DELETE request to it. Can also be sourced from the `OKTA_CACHE_ENABLED` environment variable.",
+			},
+			"cache_dir": schema.StringAttribute{
+				Optional:    true,
+				Description: "Directory of the response cache when `cache_enabled` is set, the default is a `terraform-provider-okta` directory in the user's cache directory. Can also be sourced from the `OKTA_CACHE_DIR` environment variable.",
+			},
+			"cache_ttl_seconds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Time to live (in seconds) of a cached response when `cache_enabled` is set, the default is `300`.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+					int64validator.AtMost(86400),
+				},
+			},
 		},
 	}
 }
@@ -233,6 +297,14 @@ func (p *FrameworkProvider) Configure(ctx context.Context, req provider.Configur
 	if !data.HTTPProxy.IsNull() {
 		p.httpProxy = data.HTTPProxy.ValueString()
 	}
+	p.apiTokenRole = data.APITokenRole.ValueString()
+	p.readOnly = data.ReadOnly.ValueBool()
+	p.metricsFile = data.MetricsFile.ValueString()
+	p.rateLimitFile = data.RateLimitFile.ValueString()
+	p.metricsFormat = data.MetricsFormat.ValueString()
+	p.cacheEnabled = data.CacheEnabled.ValueBool()
+	p.cacheDir = data.CacheDir.ValueString()
+	p.cacheTTL = int(data.CacheTTL.ValueInt64())
 
 	if err := p.loadClients(ctx); err != nil {
 		resp.Diagnostics.AddError("failed to load default value to provider", err.Error())
@@ -242,6 +314,7 @@ func (p *FrameworkProvider) Configure(ctx context.Context, req provider.Configur
 
 	resp.DataSourceData = &p.Config
 	resp.ResourceData = &p.Config
+	resp.EphemeralResourceData = &p.Config
 }
 
 // DataSources defines the data sources implemented in the provider.
@@ -257,6 +330,7 @@ func (p *FrameworkProvider) DataSources(_ context.Context) []func() datasource.D
 func (p *FrameworkProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewAppAccessPolicyAssignmentResource,
+		NewAppOAuthClientSecretResource,
 		NewAppOAuthRoleAssignmentResource,
 		NewBrandResource,
 		NewLogStreamResource,
@@ -270,6 +344,24 @@ func (p *FrameworkProvider) Resources(_ context.Context) []func() resource.Resou
 	}
 }
 
+// EphemeralResources defines the ephemeral resources implemented in the
+// provider.
+func (p *FrameworkProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewAppOAuthClientSecretEphemeralResource,
+	}
+}
+
+// Functions defines the functions implemented in the provider.
+func (p *FrameworkProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewElStringLiteralFunction,
+		NewJwkFromPemFunction,
+		NewOktaIDTypeFunction,
+		NewParseSamlMetadataFunction,
+	}
+}
+
 func dataSourceConfiguration(req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) *Config {
 	if req.ProviderData == nil {
 		return nil
@@ -303,3 +395,20 @@ func resourceConfiguration(req resource.ConfigureRequest, resp *resource.Configu
 
 	return p
 }
+
+func ephemeralResourceConfiguration(req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) *Config {
+	if req.ProviderData == nil {
+		return nil
+	}
+
+	p, ok := req.ProviderData.(*Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return nil
+	}
+
+	return p
+}
@@ -0,0 +1,49 @@
+package okta
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &elStringLiteralFunction{}
+
+func NewElStringLiteralFunction() function.Function {
+	return &elStringLiteralFunction{}
+}
+
+type elStringLiteralFunction struct{}
+
+func (f *elStringLiteralFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "el_string_literal"
+}
+
+func (f *elStringLiteralFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Quotes a string as an Okta Expression Language literal.",
+		Description:         "Quotes a string as a literal of the Okta Expression Language, so that it can be safely inserted into an expression, e.g. the expression of a group rule or of a claim. The string is enclosed in single quotes and its single quotes are doubled.",
+		MarkdownDescription: "Quotes a string as a literal of the Okta Expression Language, so that it can be safely inserted into an expression, e.g. the `expression_value` of `okta_group_rule` or the `value` of `okta_auth_server_claim`. The string is enclosed in single quotes and its single quotes are doubled.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "value",
+				Description:         "String to quote.",
+				MarkdownDescription: "String to quote.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *elStringLiteralFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value string
+	resp.Error = req.Arguments.Get(ctx, &value)
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, elStringLiteral(value))
+}
+
+func elStringLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,23 @@
+package okta
+
+import (
+	"testing"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElStringLiteral(t *testing.T) {
+	require.Equal(t, "''", elStringLiteral(""))
+	require.Equal(t, "'Engineering'", elStringLiteral("Engineering"))
+	require.Equal(t, "'O''Brien'", elStringLiteral("O'Brien"))
+	require.Equal(t, "''' or true or '''", elStringLiteral("' or true or '"))
+}
+
+func TestElStringLiteralParses(t *testing.T) {
+	for _, value := range []string{"", "O'Brien", "C:\\", `\'`, `say "hi"`, "''"} {
+		expr, err := expression.Parse(elStringLiteral(value))
+		require.NoError(t, err, value)
+		require.Equal(t, value, expr.(*expression.Literal).Value, value)
+	}
+}
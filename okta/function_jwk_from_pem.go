@@ -0,0 +1,149 @@
+package okta
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &jwkFromPemFunction{}
+
+func NewJwkFromPemFunction() function.Function {
+	return &jwkFromPemFunction{}
+}
+
+type jwkFromPemFunction struct{}
+
+type jwkModel struct {
+	Kid types.String `tfsdk:"kid"`
+	Kty types.String `tfsdk:"kty"`
+	E   types.String `tfsdk:"e"`
+	N   types.String `tfsdk:"n"`
+	X   types.String `tfsdk:"x"`
+	Y   types.String `tfsdk:"y"`
+}
+
+func (f *jwkFromPemFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "jwk_from_pem"
+}
+
+func (f *jwkFromPemFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Converts a PEM encoded public key to a JSON Web Key.",
+		Description:         "Converts a PEM encoded RSA or EC public key, or the public key of a PEM encoded certificate, to a JSON Web Key with the attributes of the jwks block of okta_app_oauth. The key ID is the RFC 7638 thumbprint of the key. The attributes that do not apply to the key type are empty.",
+		MarkdownDescription: "Converts a PEM encoded RSA or EC public key, or the public key of a PEM encoded certificate, to a JSON Web Key with the attributes of the `jwks` block of `okta_app_oauth`. The key ID is the [RFC 7638](https://www.rfc-editor.org/rfc/rfc7638) thumbprint of the key. The attributes that do not apply to the key type are empty.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "pem",
+				Description:         "PEM encoded public key or certificate.",
+				MarkdownDescription: "PEM encoded public key or certificate.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"kid": types.StringType,
+				"kty": types.StringType,
+				"e":   types.StringType,
+				"n":   types.StringType,
+				"x":   types.StringType,
+				"y":   types.StringType,
+			},
+		},
+	}
+}
+
+func (f *jwkFromPemFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var data string
+	resp.Error = req.Arguments.Get(ctx, &data)
+	if resp.Error != nil {
+		return
+	}
+	result, err := jwkFromPem([]byte(data))
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, result)
+}
+
+func jwkFromPem(data []byte) (*jwkModel, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block '%s', expected a public key or a certificate", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", block.Type, err)
+	}
+
+	// the members of the thumbprint are in lexicographic order, see RFC 7638
+	var thumbprint interface{}
+	result := &jwkModel{
+		E: types.StringValue(""),
+		N: types.StringValue(""),
+		X: types.StringValue(""),
+		Y: types.StringValue(""),
+	}
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+		n := base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+		result.Kty = types.StringValue("RSA")
+		result.E = types.StringValue(e)
+		result.N = types.StringValue(n)
+		thumbprint = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{e, "RSA", n}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		x := base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, size)))
+		y := base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, size)))
+		result.Kty = types.StringValue("EC")
+		result.X = types.StringValue(x)
+		result.Y = types.StringValue(y)
+		thumbprint = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{k.Curve.Params().Name, "EC", x, y}
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T, expected an RSA or EC key", key)
+	}
+	raw, err := json.Marshal(thumbprint)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(raw)
+	result.Kid = types.StringValue(base64.RawURLEncoding.EncodeToString(sum[:]))
+	return result, nil
+}
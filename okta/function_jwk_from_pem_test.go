@@ -0,0 +1,56 @@
+package okta
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJwkFromPem(t *testing.T) {
+	// the example key of RFC 7638
+	n := "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"
+	modulus, err := base64.RawURLEncoding.DecodeString(n)
+	require.NoError(t, err)
+	rsaKey := &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: 65537}
+
+	der, err := x509.MarshalPKIXPublicKey(rsaKey)
+	require.NoError(t, err)
+	jwk, err := jwkFromPem(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	require.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", jwk.Kid.ValueString())
+	require.Equal(t, "RSA", jwk.Kty.ValueString())
+	require.Equal(t, "AQAB", jwk.E.ValueString())
+	require.Equal(t, n, jwk.N.ValueString())
+	require.Equal(t, "", jwk.X.ValueString())
+
+	pkcs1, err := jwkFromPem(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(rsaKey)}))
+	require.NoError(t, err)
+	require.Equal(t, jwk, pkcs1)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	require.NoError(t, err)
+	jwk, err = jwkFromPem(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	require.Equal(t, "EC", jwk.Kty.ValueString())
+	require.Len(t, jwk.X.ValueString(), 43)
+	require.Len(t, jwk.Y.ValueString(), 43)
+	require.Equal(t, "", jwk.N.ValueString())
+
+	der, err = x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+	_, err = jwkFromPem(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	require.ErrorContains(t, err, "unsupported PEM block 'PRIVATE KEY'")
+
+	_, err = jwkFromPem([]byte("not a PEM"))
+	require.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package okta
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &oktaIDTypeFunction{}
+
+func NewOktaIDTypeFunction() function.Function {
+	return &oktaIDTypeFunction{}
+}
+
+type oktaIDTypeFunction struct{}
+
+// oktaIDPrefixes maps the prefixes of Okta object IDs to the object types.
+// Okta does not document the prefixes, the list holds the well-known ones.
+var oktaIDPrefixes = map[string]string{
+	"00u": "user",
+	"00g": "group",
+	"0oa": "app",
+	"00p": "policy",
+	"0pr": "rule",
+	"aus": "auth_server",
+	"aut": "authenticator",
+	"nzo": "network_zone",
+}
+
+func (f *oktaIDTypeFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "okta_id_type"
+}
+
+func (f *oktaIDTypeFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Classifies an Okta object ID.",
+		Description:         "Returns the type of the object an Okta ID belongs to by its prefix: user (00u), group (00g), app (0oa), policy (00p), rule (0pr), auth_server (aus), authenticator (aut), network_zone (nzo) or unknown. Applications and identity providers share the 0oa prefix, policy rules and group rules share the 0pr prefix.",
+		MarkdownDescription: "Returns the type of the object an Okta ID belongs to by its prefix: `user` (`00u`), `group` (`00g`), `app` (`0oa`), `policy` (`00p`), `rule` (`0pr`), `auth_server` (`aus`), `authenticator` (`aut`), `network_zone` (`nzo`) or `unknown`. Applications and identity providers share the `0oa` prefix, policy rules and group rules share the `0pr` prefix.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "id",
+				Description:         "Okta object ID.",
+				MarkdownDescription: "Okta object ID.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *oktaIDTypeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var id string
+	resp.Error = req.Arguments.Get(ctx, &id)
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, oktaIDType(id))
+}
+
+func oktaIDType(id string) string {
+	if len(id) > 3 {
+		if typ, ok := oktaIDPrefixes[strings.ToLower(id[:3])]; ok {
+			return typ
+		}
+	}
+	return "unknown"
+}
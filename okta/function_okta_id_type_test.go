@@ -0,0 +1,22 @@
+package okta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOktaIDType(t *testing.T) {
+	tests := map[string]string{
+		"00u1abcdefghijklmno1": "user",
+		"00g1abcdefghijklmno1": "group",
+		"0oa1abcdefghijklmno1": "app",
+		"aus1abcdefghijklmno1": "auth_server",
+		"default":              "unknown",
+		"00u":                  "unknown",
+		"":                     "unknown",
+	}
+	for id, typ := range tests {
+		require.Equal(t, typ, oktaIDType(id), id)
+	}
+}
@@ -0,0 +1,108 @@
+package okta
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+
+	"github.com/crewjam/saml"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &parseSamlMetadataFunction{}
+
+func NewParseSamlMetadataFunction() function.Function {
+	return &parseSamlMetadataFunction{}
+}
+
+type parseSamlMetadataFunction struct{}
+
+type samlMetadataModel struct {
+	EntityID                types.String `tfsdk:"entity_id"`
+	HTTPPostBinding         types.String `tfsdk:"http_post_binding"`
+	HTTPRedirectBinding     types.String `tfsdk:"http_redirect_binding"`
+	SigningCertificate      types.String `tfsdk:"signing_certificate"`
+	EncryptionCertificate   types.String `tfsdk:"encryption_certificate"`
+	WantAuthnRequestsSigned types.Bool   `tfsdk:"want_authn_requests_signed"`
+	AuthnRequestsSigned     types.Bool   `tfsdk:"authn_requests_signed"`
+	WantAssertionsSigned    types.Bool   `tfsdk:"want_assertions_signed"`
+}
+
+func (f *parseSamlMetadataFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_saml_metadata"
+}
+
+func (f *parseSamlMetadataFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Parses SAML metadata.",
+		Description:         "Parses the XML metadata of a SAML identity provider or service provider, for example the metadata of an Okta SAML application or of a SAML identity provider to add to Okta. The bindings are the single sign-on services of an identity provider and the assertion consumer services of a service provider.",
+		MarkdownDescription: "Parses the XML metadata of a SAML identity provider or service provider, for example the metadata of an Okta SAML application or of a SAML identity provider to add to Okta. The bindings are the single sign-on services of an identity provider and the assertion consumer services of a service provider.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "metadata",
+				Description:         "XML metadata of the entity.",
+				MarkdownDescription: "XML metadata of the entity.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"entity_id":                  types.StringType,
+				"http_post_binding":          types.StringType,
+				"http_redirect_binding":      types.StringType,
+				"signing_certificate":        types.StringType,
+				"encryption_certificate":     types.StringType,
+				"want_authn_requests_signed": types.BoolType,
+				"authn_requests_signed":      types.BoolType,
+				"want_assertions_signed":     types.BoolType,
+			},
+		},
+	}
+}
+
+func (f *parseSamlMetadataFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var metadata string
+	resp.Error = req.Arguments.Get(ctx, &metadata)
+	if resp.Error != nil {
+		return
+	}
+	result, err := parseSamlMetadata([]byte(metadata))
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, result)
+}
+
+func parseSamlMetadata(metadata []byte) (*samlMetadataModel, error) {
+	var root saml.EntityDescriptor
+	if err := xml.Unmarshal(metadata, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse SAML metadata: %v", err)
+	}
+	if root.EntityID == "" {
+		return nil, fmt.Errorf("SAML metadata has no entity ID")
+	}
+	result := &samlMetadataModel{EntityID: types.StringValue(root.EntityID)}
+	var post, redirect, signing, encryption string
+	switch {
+	case len(root.IDPSSODescriptors) > 0:
+		desc := root.IDPSSODescriptors[0]
+		post, redirect = samlEndpointLocations(desc.SingleSignOnServices)
+		signing, encryption = samlCertificates(desc.KeyDescriptors)
+		result.WantAuthnRequestsSigned = types.BoolPointerValue(desc.WantAuthnRequestsSigned)
+	case len(root.SPSSODescriptors) > 0:
+		desc := root.SPSSODescriptors[0]
+		post, redirect = samlEndpointLocations(samlEndpoints(desc.AssertionConsumerServices))
+		signing, encryption = samlCertificates(desc.KeyDescriptors)
+		result.AuthnRequestsSigned = types.BoolPointerValue(desc.AuthnRequestsSigned)
+		result.WantAssertionsSigned = types.BoolPointerValue(desc.WantAssertionsSigned)
+	default:
+		return nil, fmt.Errorf("SAML metadata of '%s' has neither an IdP nor an SP descriptor", root.EntityID)
+	}
+	result.HTTPPostBinding = types.StringValue(post)
+	result.HTTPRedirectBinding = types.StringValue(redirect)
+	result.SigningCertificate = types.StringValue(signing)
+	result.EncryptionCertificate = types.StringValue(encryption)
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package okta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/stretchr/testify/require"
+)
+
+const testIdpSamlMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="http://www.okta.com/exk1">
+  <md:IDPSSODescriptor WantAuthnRequestsSigned="false" protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#"><ds:X509Data><ds:X509Certificate>MIIDsigning</ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://example.okta.com/app/exk1/sso/saml"/>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://example.okta.com/app/exk1/sso/saml?redirect"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>`
+
+const testSpSamlMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://sp.example.com">
+  <md:SPSSODescriptor AuthnRequestsSigned="true" WantAssertionsSigned="true" protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor>
+      <ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#"><ds:X509Data><ds:X509Certificate>MIIDboth</ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:AssertionConsumerService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://sp.example.com/acs" index="0"/>
+  </md:SPSSODescriptor>
+</md:EntityDescriptor>`
+
+func TestParseSamlMetadata(t *testing.T) {
+	idp, err := parseSamlMetadata([]byte(testIdpSamlMetadata))
+	require.NoError(t, err)
+	require.Equal(t, "http://www.okta.com/exk1", idp.EntityID.ValueString())
+	require.Equal(t, "https://example.okta.com/app/exk1/sso/saml", idp.HTTPPostBinding.ValueString())
+	require.Equal(t, "https://example.okta.com/app/exk1/sso/saml?redirect", idp.HTTPRedirectBinding.ValueString())
+	require.Equal(t, "MIIDsigning", idp.SigningCertificate.ValueString())
+	require.Equal(t, "", idp.EncryptionCertificate.ValueString())
+	require.False(t, idp.WantAuthnRequestsSigned.ValueBool())
+	require.True(t, idp.AuthnRequestsSigned.IsNull())
+
+	sp, err := parseSamlMetadata([]byte(testSpSamlMetadata))
+	require.NoError(t, err)
+	require.Equal(t, "https://sp.example.com/acs", sp.HTTPPostBinding.ValueString())
+	require.Equal(t, "", sp.HTTPRedirectBinding.ValueString())
+	require.Equal(t, "MIIDboth", sp.SigningCertificate.ValueString())
+	require.Equal(t, "MIIDboth", sp.EncryptionCertificate.ValueString())
+	require.True(t, sp.WantAssertionsSigned.ValueBool())
+	require.True(t, sp.WantAuthnRequestsSigned.IsNull())
+
+	_, err = parseSamlMetadata([]byte("<html></html>"))
+	require.Error(t, err)
+}
+
+func TestParseSamlMetadataFunctionRun(t *testing.T) {
+	f := NewParseSamlMetadataFunction()
+	var definition function.DefinitionResponse
+	f.Definition(context.TODO(), function.DefinitionRequest{}, &definition)
+	typ := definition.Definition.Return.GetType().(basetypes.ObjectType)
+
+	resp := &function.RunResponse{Result: function.NewResultData(types.ObjectUnknown(typ.AttrTypes))}
+	f.Run(context.TODO(), function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(testIdpSamlMetadata)}),
+	}, resp)
+	require.Nil(t, resp.Error)
+	result := resp.Result.Value().(basetypes.ObjectValue)
+	require.Equal(t, types.StringValue("http://www.okta.com/exk1"), result.Attributes()["entity_id"])
+
+	resp = &function.RunResponse{Result: function.NewResultData(types.ObjectUnknown(typ.AttrTypes))}
+	f.Run(context.TODO(), function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("not xml")}),
+	}, resp)
+	require.NotNil(t, resp.Error)
+	require.Equal(t, int64(0), *resp.Error.FunctionArgument)
+}
@@ -63,8 +63,12 @@ func listGroups(ctx context.Context, client *sdk.Client, qp *query.Params) ([]*s
 }
 
 // Group Primary Key Operations (Use when # groups < # users in operations)
-func addGroupMembers(ctx context.Context, client *sdk.Client, groupId string, users []string) error {
-	for _, user := range users {
+
+// addGroupMembers adds the users to the group making up to parallelism
+// requests concurrently. Every user is attempted; the users successfully added
+// are returned along with an error aggregating each user that failed.
+func addGroupMembers(ctx context.Context, client *sdk.Client, groupId string, users []string, parallelism int) ([]string, error) {
+	return concurrentlyEach(users, parallelism, func(user string) error {
 		resp, err := client.Group.AddUserToGroup(ctx, groupId, user)
 		if err != nil {
 			return fmt.Errorf("failed to add user (%s) to group (%s): %w", user, groupId, err)
@@ -73,19 +77,22 @@ func addGroupMembers(ctx context.Context, client *sdk.Client, groupId string, us
 		if !exists {
 			return fmt.Errorf("targeted object does not exist: %s", err)
 		}
-	}
-	return nil
+		return nil
+	})
 }
 
-func removeGroupMembers(ctx context.Context, client *sdk.Client, groupId string, users []string) error {
-	for _, user := range users {
+// removeGroupMembers removes the users from the group making up to parallelism
+// requests concurrently. Every user is attempted; the users successfully
+// removed are returned along with an error aggregating each user that failed.
+func removeGroupMembers(ctx context.Context, client *sdk.Client, groupId string, users []string, parallelism int) ([]string, error) {
+	return concurrentlyEach(users, parallelism, func(user string) error {
 		resp, err := client.Group.RemoveUserFromGroup(ctx, groupId, user)
 		err = suppressErrorOn404(resp, err)
 		if err != nil {
 			return fmt.Errorf("failed to remove user (%s) from group (%s): %v", user, groupId, err)
 		}
-	}
-	return nil
+		return nil
+	})
 }
 
 // User Primary Key Operations (use when # users < # groups in operations)
@@ -0,0 +1,1005 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/okta/okta-sdk-golang/v3/okta"
+	"github.com/okta/terraform-provider-okta/okta/internal/permissions"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+// appendImportTarget appends the object to targets when its name starts with
+// q.
+func appendImportTarget(targets []importTarget, q, id, name string) []importTarget {
+	if !strings.HasPrefix(name, q) {
+		return targets
+	}
+	return append(targets, importTarget{ID: id, Name: name})
+}
+
+// childImportLister lists the children of the objects listed by parent. The
+// query only applies to the parents, the children are named after their
+// parent so that their labels are grouped together.
+func childImportLister(parent importLister, children func(ctx context.Context, m interface{}, parent importTarget) ([]importTarget, error)) importLister {
+	return func(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+		parents, err := parent(ctx, m, q)
+		if err != nil {
+			return nil, err
+		}
+		var targets []importTarget
+		for _, p := range parents {
+			childTargets, err := children(ctx, m, p)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list the children of %q: %w", p.ID, err)
+			}
+			for _, target := range childTargets {
+				target.Name = strings.TrimSpace(p.Name + " " + target.Name)
+				targets = append(targets, target)
+			}
+		}
+		return targets, nil
+	}
+}
+
+// singletonImportLister lists the single object of the org wide settings
+// resources, which are imported with a fixed ID.
+func singletonImportLister(id string) importLister {
+	return func(_ context.Context, _ interface{}, q string) ([]importTarget, error) {
+		return appendImportTarget(nil, q, id, id), nil
+	}
+}
+
+func appImportLister(signOnMode string) importLister {
+	return func(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+		apps, err := listApps(ctx, getOktaClientFromMetadata(m), &appFilters{LabelPrefix: q, SignOnMode: signOnMode}, defaultPaginationLimit)
+		if err != nil {
+			return nil, err
+		}
+		var targets []importTarget
+		for _, app := range apps {
+			targets = appendImportTarget(targets, q, app.Id, app.Label)
+		}
+		return targets, nil
+	}
+}
+
+// swaImportLister lists the browser plugin apps of one of the SWA resource
+// types. The API only tells them apart by the app's name and credentials
+// scheme, which aren't part of the generic application model, so the apps are
+// listed as browser plugin apps.
+func swaImportLister(resourceType string) importLister {
+	return func(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+		re := getRequestExecutor(m)
+		qp := &query.Params{Limit: defaultPaginationLimit, Q: q}
+		req, err := re.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/apps%s", qp.String()), nil)
+		if err != nil {
+			return nil, err
+		}
+		var apps []*sdk.BrowserPluginApplication
+		resp, err := re.Do(ctx, req, &apps)
+		if err != nil {
+			return nil, err
+		}
+		for resp.HasNextPage() {
+			var nextApps []*sdk.BrowserPluginApplication
+			resp, err = resp.Next(ctx, &nextApps)
+			if err != nil {
+				return nil, err
+			}
+			apps = append(apps, nextApps...)
+		}
+		var targets []importTarget
+		for _, app := range apps {
+			if app.SignOnMode != "BROWSER_PLUGIN" || swaResourceType(app) != resourceType {
+				continue
+			}
+			targets = appendImportTarget(targets, q, app.Id, app.Label)
+		}
+		return targets, nil
+	}
+}
+
+func swaResourceType(app *sdk.BrowserPluginApplication) string {
+	switch {
+	case app.Name == "template_swa3field":
+		return appThreeField
+	case app.Credentials != nil && app.Credentials.Scheme == "SHARED_USERNAME_AND_PASSWORD":
+		return appSharedCredentials
+	}
+	return appSwa
+}
+
+func listAppGroupAssignmentImportTargets(ctx context.Context, m interface{}, app importTarget) ([]importTarget, error) {
+	client := getOktaClientFromMetadata(m)
+	assignments, resp, err := client.Application.ListApplicationGroupAssignments(ctx, app.ID, &query.Params{Limit: defaultPaginationLimit})
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextAssignments []*sdk.ApplicationGroupAssignment
+		resp, err = resp.Next(ctx, &nextAssignments)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, nextAssignments...)
+	}
+	targets := make([]importTarget, len(assignments))
+	for i, assignment := range assignments {
+		targets[i] = importTarget{ID: app.ID + "/" + assignment.Id, Name: assignment.Id}
+	}
+	return targets, nil
+}
+
+func listAppGroupAssignmentsImportTargets(ctx context.Context, m interface{}, app importTarget) ([]importTarget, error) {
+	assignments, _, err := getOktaClientFromMetadata(m).Application.ListApplicationGroupAssignments(ctx, app.ID, &query.Params{Limit: 1})
+	if err != nil || len(assignments) == 0 {
+		return nil, err
+	}
+	return []importTarget{{ID: app.ID}}, nil
+}
+
+// listAppUserImportTargets lists the users assigned to the app directly,
+// users assigned through a group can't be managed by okta_app_user.
+func listAppUserImportTargets(ctx context.Context, m interface{}, app importTarget) ([]importTarget, error) {
+	client := getOktaClientFromMetadata(m)
+	appUsers, resp, err := client.Application.ListApplicationUsers(ctx, app.ID, &query.Params{Limit: defaultPaginationLimit})
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextAppUsers []*sdk.AppUser
+		resp, err = resp.Next(ctx, &nextAppUsers)
+		if err != nil {
+			return nil, err
+		}
+		appUsers = append(appUsers, nextAppUsers...)
+	}
+	var targets []importTarget
+	for _, appUser := range appUsers {
+		if appUser.Scope != "USER" {
+			continue
+		}
+		name := appUser.Id
+		if appUser.Credentials != nil && appUser.Credentials.UserName != "" {
+			name = appUser.Credentials.UserName
+		}
+		targets = append(targets, importTarget{ID: app.ID + "/" + appUser.Id, Name: name})
+	}
+	return targets, nil
+}
+
+func listAppOAuthAPIScopeImportTargets(ctx context.Context, m interface{}, app importTarget) ([]importTarget, error) {
+	grants, _, err := getOktaClientFromMetadata(m).Application.ListScopeConsentGrants(ctx, app.ID, nil)
+	if err != nil || len(grants) == 0 {
+		return nil, err
+	}
+	return []importTarget{{ID: app.ID}}, nil
+}
+
+func listAppSamlSigningKeyImportTargets(ctx context.Context, m interface{}, app importTarget) ([]importTarget, error) {
+	keys, _, err := getOktaClientFromMetadata(m).Application.ListApplicationKeys(ctx, app.ID)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]importTarget, len(keys))
+	for i, key := range keys {
+		targets[i] = importTarget{ID: app.ID + "/" + key.Kid, Name: key.Kid}
+	}
+	return targets, nil
+}
+
+// appUserSchemaPropertyImportLister lists the base or custom properties of
+// the apps' user schemas.
+func appUserSchemaPropertyImportLister(base bool) importLister {
+	return childImportLister(appImportLister(""), func(ctx context.Context, m interface{}, app importTarget) ([]importTarget, error) {
+		us, _, err := getOktaClientFromMetadata(m).UserSchema.GetApplicationUserSchema(ctx, app.ID)
+		if err != nil {
+			return nil, err
+		}
+		var targets []importTarget
+		for _, index := range userSchemaPropertyIndexes(us, base) {
+			targets = append(targets, importTarget{ID: app.ID + "/" + index, Name: index})
+		}
+		return targets, nil
+	})
+}
+
+// userSchemaPropertyImportLister lists the base or custom properties of the
+// default user type and of the user types. The properties of a user type
+// other than the default one are imported as <user_type_id>.<index>.
+func userSchemaPropertyImportLister(base bool) importLister {
+	return func(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+		client := getOktaClientFromMetadata(m)
+		us, _, err := client.UserSchema.GetUserSchema(ctx, "default")
+		if err != nil {
+			return nil, err
+		}
+		var targets []importTarget
+		for _, index := range userSchemaPropertyIndexes(us, base) {
+			targets = appendImportTarget(targets, q, index, index)
+		}
+		userTypes, _, err := client.UserType.ListUserTypes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, userType := range userTypes {
+			if userType.Default != nil && *userType.Default {
+				continue
+			}
+			us, _, err := client.UserSchema.GetUserSchema(ctx, userTypeSchemaID(userType))
+			if err != nil {
+				return nil, err
+			}
+			for _, index := range userSchemaPropertyIndexes(us, base) {
+				targets = appendImportTarget(targets, q, userType.Id+"."+index, userType.Name+" "+index)
+			}
+		}
+		return targets, nil
+	}
+}
+
+func userSchemaPropertyIndexes(us *sdk.UserSchema, base bool) []string {
+	if us == nil || us.Definitions == nil {
+		return nil
+	}
+	var indexes []string
+	switch {
+	case base && us.Definitions.Base != nil:
+		for index := range us.Definitions.Base.Properties {
+			indexes = append(indexes, index)
+		}
+	case !base && us.Definitions.Custom != nil:
+		for index := range us.Definitions.Custom.Properties {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Strings(indexes)
+	return indexes
+}
+
+func listGroupSchemaPropertyImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	s, _, err := getOktaClientFromMetadata(m).GroupSchema.GetGroupSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil || s.Definitions == nil || s.Definitions.Custom == nil {
+		return nil, nil
+	}
+	var targets []importTarget
+	for index := range s.Definitions.Custom.Properties {
+		targets = appendImportTarget(targets, q, index, index)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].ID < targets[j].ID })
+	return targets, nil
+}
+
+func listGroupImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	groups, err := listGroups(ctx, getOktaClientFromMetadata(m), &query.Params{Limit: defaultPaginationLimit, Q: q})
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, group := range groups {
+		// only groups mastered in Okta can be managed by terraform
+		if group.Type != "OKTA_GROUP" {
+			continue
+		}
+		targets = append(targets, importTarget{ID: group.Id, Name: group.Profile.Name})
+	}
+	return targets, nil
+}
+
+func listGroupMembershipsImportTargets(ctx context.Context, m interface{}, group importTarget) ([]importTarget, error) {
+	users, _, err := getOktaClientFromMetadata(m).Group.ListGroupUsers(ctx, group.ID, &query.Params{Limit: 1})
+	if err != nil || len(users) == 0 {
+		return nil, err
+	}
+	return []importTarget{{ID: group.ID}}, nil
+}
+
+func listGroupRoleImportTargets(ctx context.Context, m interface{}, group importTarget) ([]importTarget, error) {
+	roles, _, err := getOktaClientFromMetadata(m).Group.ListGroupAssignedRoles(ctx, group.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]importTarget, len(roles))
+	for i, role := range roles {
+		targets[i] = importTarget{ID: group.ID + "/" + role.Id, Name: role.Type}
+	}
+	return targets, nil
+}
+
+func listGroupRuleImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	qp := &query.Params{Limit: defaultPaginationLimit}
+	rules, resp, err := getOktaClientFromMetadata(m).Group.ListGroupRules(ctx, qp)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextRules []*sdk.GroupRule
+		resp, err = resp.Next(ctx, &nextRules)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, nextRules...)
+	}
+	var targets []importTarget
+	for _, rule := range rules {
+		targets = appendImportTarget(targets, q, rule.Id, rule.Name)
+	}
+	return targets, nil
+}
+
+func listUserImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	qp := &query.Params{Limit: defaultPaginationLimit, Q: q}
+	users, resp, err := getOktaClientFromMetadata(m).User.ListUsers(ctx, qp)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextUsers []*sdk.User
+		resp, err = resp.Next(ctx, &nextUsers)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, nextUsers...)
+	}
+	targets := make([]importTarget, len(users))
+	for i, user := range users {
+		targets[i] = importTarget{ID: user.Id}
+		if user.Profile != nil {
+			targets[i].Name, _ = (*user.Profile)["login"].(string)
+		}
+	}
+	return targets, nil
+}
+
+// userRoleImportLister lists the users that are assigned an admin role, for
+// which the import ID is built by id.
+func userRoleImportLister(id func(user importTarget, roles []*sdk.Role) []importTarget) importLister {
+	return childImportLister(listUserImportTargets, func(ctx context.Context, m interface{}, user importTarget) ([]importTarget, error) {
+		roles, _, err := getOktaClientFromMetadata(m).User.ListAssignedRolesForUser(ctx, user.ID, nil)
+		if err != nil || len(roles) == 0 {
+			return nil, err
+		}
+		return id(user, roles), nil
+	})
+}
+
+func userAdminRolesImportTargets(user importTarget, _ []*sdk.Role) []importTarget {
+	return []importTarget{{ID: user.ID}}
+}
+
+func adminRoleTargetsImportTargets(user importTarget, roles []*sdk.Role) []importTarget {
+	var targets []importTarget
+	for _, role := range roles {
+		if contains(rolesWithTargets, role.Type) {
+			targets = append(targets, importTarget{ID: user.ID + "/" + role.Type, Name: role.Type})
+		}
+	}
+	return targets
+}
+
+func listUserFactorQuestionImportTargets(ctx context.Context, m interface{}, user importTarget) ([]importTarget, error) {
+	factors, _, err := getOktaClientFromMetadata(m).UserFactor.ListFactors(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, f := range factors {
+		factor, ok := f.(*sdk.UserFactor)
+		if ok && factor.FactorType == "question" {
+			targets = append(targets, importTarget{ID: user.ID + "/" + factor.Id})
+		}
+	}
+	return targets, nil
+}
+
+func listLinkValueImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	client := getOktaClientFromMetadata(m)
+	definitions, _, err := client.LinkedObject.ListLinkedObjectDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := listUserImportTargets(ctx, m, q)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, definition := range definitions {
+		if definition.Primary == nil || definition.Associated == nil {
+			continue
+		}
+		for _, user := range users {
+			associated, _, err := client.User.GetLinkedObjectsForUser(ctx, user.ID, definition.Associated.Name, nil)
+			if err != nil {
+				return nil, err
+			}
+			if len(associated) > 0 {
+				targets = append(targets, importTarget{ID: definition.Primary.Name + "/" + user.ID, Name: user.Name + " " + definition.Primary.Name})
+			}
+		}
+	}
+	return targets, nil
+}
+
+func listLinkDefinitionImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	definitions, _, err := getOktaClientFromMetadata(m).LinkedObject.ListLinkedObjectDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, definition := range definitions {
+		if definition.Primary != nil {
+			targets = appendImportTarget(targets, q, definition.Primary.Name, definition.Primary.Name)
+		}
+	}
+	return targets, nil
+}
+
+func listUserTypeImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	userTypes, _, err := getOktaClientFromMetadata(m).UserType.ListUserTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, userType := range userTypes {
+		// the default user type can't be managed by terraform
+		if userType.Default != nil && *userType.Default {
+			continue
+		}
+		targets = appendImportTarget(targets, q, userType.Id, userType.Name)
+	}
+	return targets, nil
+}
+
+func listNetworkZoneImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	zones, resp, err := getOktaClientFromMetadata(m).NetworkZone.ListNetworkZones(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextZones []*sdk.NetworkZone
+		resp, err = resp.Next(ctx, &nextZones)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, nextZones...)
+	}
+	var targets []importTarget
+	for _, zone := range zones {
+		targets = appendImportTarget(targets, q, zone.Id, zone.Name)
+	}
+	return targets, nil
+}
+
+func listTrustedOriginImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	origins, resp, err := getOktaClientFromMetadata(m).TrustedOrigin.ListOrigins(ctx, &query.Params{Limit: defaultPaginationLimit})
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextOrigins []*sdk.TrustedOrigin
+		resp, err = resp.Next(ctx, &nextOrigins)
+		if err != nil {
+			return nil, err
+		}
+		origins = append(origins, nextOrigins...)
+	}
+	var targets []importTarget
+	for _, origin := range origins {
+		targets = appendImportTarget(targets, q, origin.Id, origin.Name)
+	}
+	return targets, nil
+}
+
+func listInlineHookImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	hooks, _, err := getOktaClientFromMetadata(m).InlineHook.ListInlineHooks(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, hook := range hooks {
+		targets = appendImportTarget(targets, q, hook.Id, hook.Name)
+	}
+	return targets, nil
+}
+
+func listEventHookImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	hooks, _, err := getOktaClientFromMetadata(m).EventHook.ListEventHooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, hook := range hooks {
+		targets = appendImportTarget(targets, q, hook.Id, hook.Name)
+	}
+	return targets, nil
+}
+
+func listAuthenticatorImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	authenticators, _, err := getOktaClientFromMetadata(m).Authenticator.ListAuthenticators(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, authenticator := range authenticators {
+		targets = appendImportTarget(targets, q, authenticator.Id, authenticator.Name)
+	}
+	return targets, nil
+}
+
+func listBehaviorImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	behaviors, _, err := getAPISupplementFromMetadata(m).ListBehaviors(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, behavior := range behaviors {
+		targets = appendImportTarget(targets, q, behavior.ID, behavior.Name)
+	}
+	return targets, nil
+}
+
+func listSmsTemplateImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	templates, _, err := getOktaClientFromMetadata(m).SmsTemplate.ListSmsTemplates(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, template := range templates {
+		targets = appendImportTarget(targets, q, template.Id, template.Name)
+	}
+	return targets, nil
+}
+
+func listFactorImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	re := getAPISupplementFromMetadata(m).RequestExecutor
+	req, err := re.NewRequest(http.MethodGet, "/api/v1/org/factors", nil)
+	if err != nil {
+		return nil, err
+	}
+	var factors []*sdk.OrgFactor
+	if _, err = re.Do(ctx, req, &factors); err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, factor := range factors {
+		targets = appendImportTarget(targets, q, factor.Id, factor.Id)
+	}
+	return targets, nil
+}
+
+func listFactorTotpImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	re := getAPISupplementFromMetadata(m).RequestExecutor
+	req, err := re.NewRequest(http.MethodGet, "/api/v1/org/factors/hotp/profiles", nil)
+	if err != nil {
+		return nil, err
+	}
+	var profiles []*sdk.HotpFactorProfile
+	if _, err = re.Do(ctx, req, &profiles); err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, profile := range profiles {
+		targets = appendImportTarget(targets, q, profile.ID, profile.Name)
+	}
+	return targets, nil
+}
+
+// idpImportLister lists the identity providers of the type, any type other
+// than OIDC and SAML2 is a social identity provider.
+func idpImportLister(idpType string) importLister {
+	return func(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+		client := getOktaClientFromMetadata(m)
+		idps, resp, err := client.IdentityProvider.ListIdentityProviders(ctx, &query.Params{Limit: defaultPaginationLimit, Q: q})
+		if err != nil {
+			return nil, err
+		}
+		for resp.HasNextPage() {
+			var nextIdps []*sdk.IdentityProvider
+			resp, err = resp.Next(ctx, &nextIdps)
+			if err != nil {
+				return nil, err
+			}
+			idps = append(idps, nextIdps...)
+		}
+		var targets []importTarget
+		for _, idp := range idps {
+			social := idp.Type != oidcIdp && idp.Type != saml2Idp
+			if idp.Type == idpType || (idpType == "" && social) {
+				targets = appendImportTarget(targets, q, idp.Id, idp.Name)
+			}
+		}
+		return targets, nil
+	}
+}
+
+func listIdpSamlKeyImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	keys, _, err := getOktaClientFromMetadata(m).IdentityProvider.ListIdentityProviderKeys(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, key := range keys {
+		targets = appendImportTarget(targets, q, key.Kid, key.Kid)
+	}
+	return targets, nil
+}
+
+func listAdminRoleCustomImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	roles, _, err := getAPISupplementFromMetadata(m).ListCustomRoles(ctx, &query.Params{Limit: defaultPaginationLimit})
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, role := range roles.Roles {
+		targets = appendImportTarget(targets, q, role.Id, role.Label)
+	}
+	return targets, nil
+}
+
+func listResourceSetImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	resourceSets, _, err := getAPISupplementFromMetadata(m).ListResourceSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, resourceSet := range resourceSets.ResourceSets {
+		targets = appendImportTarget(targets, q, resourceSet.Id, resourceSet.Label)
+	}
+	return targets, nil
+}
+
+func listAdminRoleCustomAssignmentsImportTargets(ctx context.Context, m interface{}, resourceSet importTarget) ([]importTarget, error) {
+	bindings, _, err := getOktaV3ClientFromMetadata(m).ResourceSetAPI.ListBindings(ctx, resourceSet.ID).Execute()
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, role := range bindings.Roles {
+		targets = append(targets, importTarget{ID: resourceSet.ID + "/" + role.GetId(), Name: role.GetId()})
+	}
+	return targets, nil
+}
+
+// listRoleSubscriptionImportTargets lists the subscriptions of the standard
+// admin roles.
+func listRoleSubscriptionImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	var targets []importTarget
+	for _, role := range permissions.Roles() {
+		subscriptions, _, err := getOktaClientFromMetadata(m).Subscription.ListRoleSubscriptions(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+		for _, subscription := range subscriptions {
+			targets = appendImportTarget(targets, q, role+"/"+subscription.NotificationType, role+" "+subscription.NotificationType)
+		}
+	}
+	return targets, nil
+}
+
+func listOrgConfigurationImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	settings, _, err := getOktaClientFromMetadata(m).OrgSetting.GetOrgSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return appendImportTarget(nil, q, settings.Id, settings.CompanyName), nil
+}
+
+func listCaptchaImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	captchas, _, err := getOktaV3ClientFromMetadata(m).CAPTCHAAPI.ListCaptchaInstances(ctx).Execute()
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, captcha := range captchas {
+		targets = appendImportTarget(targets, q, captcha.GetId(), captcha.GetName())
+	}
+	return targets, nil
+}
+
+func listDomainImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	domains, _, err := getOktaV3ClientFromMetadata(m).CustomDomainAPI.ListCustomDomains(ctx).Execute()
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, domain := range domains.Domains {
+		targets = appendImportTarget(targets, q, domain.GetId(), domain.GetDomain())
+	}
+	return targets, nil
+}
+
+func listEmailDomainImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	domains, _, err := getOktaV3ClientFromMetadata(m).EmailDomainAPI.ListEmailDomains(ctx).Execute()
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, domain := range domains {
+		targets = appendImportTarget(targets, q, domain.GetId(), domain.GetDomain())
+	}
+	return targets, nil
+}
+
+func listBrandImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	brands, _, err := getOktaV3ClientFromMetadata(m).CustomizationAPI.ListBrands(ctx).Execute()
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, brand := range brands {
+		targets = appendImportTarget(targets, q, brand.GetId(), brand.GetName())
+	}
+	return targets, nil
+}
+
+func listThemeImportTargets(ctx context.Context, m interface{}, brand importTarget) ([]importTarget, error) {
+	themes, _, err := getOktaV3ClientFromMetadata(m).CustomizationAPI.ListBrandThemes(ctx, brand.ID).Execute()
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]importTarget, len(themes))
+	for i, theme := range themes {
+		targets[i] = importTarget{ID: brand.ID + "/" + theme.GetId(), Name: "theme"}
+	}
+	return targets, nil
+}
+
+// emailCustomizationImportLister lists the email templates of the brands that
+// are customized. When each is true a target is listed for each
+// customization, otherwise one is listed for each template.
+func emailCustomizationImportLister(each bool) importLister {
+	return childImportLister(listBrandImportTargets, func(ctx context.Context, m interface{}, brand importTarget) ([]importTarget, error) {
+		client := getOktaV3ClientFromMetadata(m)
+		templates, err := collectEmailTempates(ctx, client, &okta.BrandWithEmbedded{Id: &brand.ID})
+		if err != nil {
+			return nil, err
+		}
+		var targets []importTarget
+		for _, template := range templates {
+			customizations, err := collectEmailCustomizations(ctx, client, brand.ID, template.GetName())
+			if err != nil {
+				return nil, err
+			}
+			if len(customizations) == 0 {
+				continue
+			}
+			if !each {
+				targets = append(targets, importTarget{ID: brand.ID + "/" + template.GetName(), Name: template.GetName()})
+				continue
+			}
+			for _, customization := range customizations {
+				targets = append(targets, importTarget{
+					ID:   customization.GetId() + "/" + brand.ID + "/" + template.GetName(),
+					Name: template.GetName() + " " + customization.GetLanguage(),
+				})
+			}
+		}
+		return targets, nil
+	})
+}
+
+func listAuthServers(ctx context.Context, m interface{}, q string) ([]*sdk.AuthorizationServer, error) {
+	servers, resp, err := getOktaClientFromMetadata(m).AuthorizationServer.ListAuthorizationServers(ctx, &query.Params{Limit: defaultPaginationLimit, Q: q})
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextServers []*sdk.AuthorizationServer
+		resp, err = resp.Next(ctx, &nextServers)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, nextServers...)
+	}
+	return servers, nil
+}
+
+// authServerImportLister lists the default authorization server, which is
+// managed by okta_auth_server_default, or the other authorization servers.
+func authServerImportLister(isDefault bool) importLister {
+	return func(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+		servers, err := listAuthServers(ctx, m, q)
+		if err != nil {
+			return nil, err
+		}
+		var targets []importTarget
+		for _, server := range servers {
+			if (server.Default != nil && *server.Default) != isDefault {
+				continue
+			}
+			targets = appendImportTarget(targets, q, server.Id, server.Name)
+		}
+		return targets, nil
+	}
+}
+
+func listAllAuthServerImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	servers, err := listAuthServers(ctx, m, q)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, server := range servers {
+		targets = appendImportTarget(targets, q, server.Id, server.Name)
+	}
+	return targets, nil
+}
+
+func listAuthServerKeyRotationImportTargets(_ context.Context, _ interface{}, server importTarget) ([]importTarget, error) {
+	return []importTarget{{ID: server.ID}}, nil
+}
+
+// authServerClaimImportLister lists the system claims, which are managed by
+// okta_auth_server_claim_default, or the custom claims.
+func authServerClaimImportLister(system bool) importLister {
+	return childImportLister(listAllAuthServerImportTargets, func(ctx context.Context, m interface{}, server importTarget) ([]importTarget, error) {
+		claims, _, err := getOktaClientFromMetadata(m).AuthorizationServer.ListOAuth2Claims(ctx, server.ID)
+		if err != nil {
+			return nil, err
+		}
+		var targets []importTarget
+		for _, claim := range claims {
+			if (claim.System != nil && *claim.System) == system {
+				targets = append(targets, importTarget{ID: server.ID + "/" + claim.Id, Name: claim.Name})
+			}
+		}
+		return targets, nil
+	})
+}
+
+// listAuthServerScopeImportTargets lists the custom scopes, system scopes
+// can't be managed by terraform.
+func listAuthServerScopeImportTargets(ctx context.Context, m interface{}, server importTarget) ([]importTarget, error) {
+	scopes, _, err := getOktaClientFromMetadata(m).AuthorizationServer.ListOAuth2Scopes(ctx, server.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, scope := range scopes {
+		if scope.System == nil || !*scope.System {
+			targets = append(targets, importTarget{ID: server.ID + "/" + scope.Id, Name: scope.Name})
+		}
+	}
+	return targets, nil
+}
+
+func listAuthServerPolicyImportTargets(ctx context.Context, m interface{}, server importTarget) ([]importTarget, error) {
+	policies, _, err := getOktaClientFromMetadata(m).AuthorizationServer.ListAuthorizationServerPolicies(ctx, server.ID)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]importTarget, len(policies))
+	for i, policy := range policies {
+		targets[i] = importTarget{ID: server.ID + "/" + policy.Id, Name: policy.Name}
+	}
+	return targets, nil
+}
+
+func listAuthServerPolicyRuleImportTargets(ctx context.Context, m interface{}, policy importTarget) ([]importTarget, error) {
+	serverID, policyID, _ := strings.Cut(policy.ID, "/")
+	rules, _, err := getOktaClientFromMetadata(m).AuthorizationServer.ListAuthorizationServerPolicyRules(ctx, serverID, policyID)
+	if err != nil {
+		return nil, err
+	}
+	var targets []importTarget
+	for _, rule := range rules {
+		if rule.System == nil || !*rule.System {
+			targets = append(targets, importTarget{ID: policy.ID + "/" + rule.Id, Name: rule.Name})
+		}
+	}
+	return targets, nil
+}
+
+func listPoliciesOfType(ctx context.Context, m interface{}, policyType string) ([]*sdk.Policy, error) {
+	policies, resp, err := getOktaClientFromMetadata(m).Policy.ListPolicies(ctx, &query.Params{Type: policyType})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*sdk.Policy, len(policies))
+	for i, p := range policies {
+		result[i] = p.(*sdk.Policy)
+	}
+	for resp.HasNextPage() {
+		var nextPolicies []*sdk.Policy
+		resp, err = resp.Next(ctx, &nextPolicies)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, nextPolicies...)
+	}
+	return result, nil
+}
+
+// policyImportLister lists the policies of the type. System policies are
+// managed by the okta_policy_*_default resources, they are only listed when
+// system is true.
+func policyImportLister(policyType string, system bool) importLister {
+	return func(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+		policies, err := listPoliciesOfType(ctx, m, policyType)
+		if err != nil {
+			return nil, err
+		}
+		var targets []importTarget
+		for _, policy := range policies {
+			if (policy.System != nil && *policy.System) == system {
+				targets = appendImportTarget(targets, q, policy.Id, policy.Name)
+			}
+		}
+		return targets, nil
+	}
+}
+
+func listAllPolicyImportTargets(policyType string) importLister {
+	return func(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+		policies, err := listPoliciesOfType(ctx, m, policyType)
+		if err != nil {
+			return nil, err
+		}
+		var targets []importTarget
+		for _, policy := range policies {
+			targets = appendImportTarget(targets, q, policy.Id, policy.Name)
+		}
+		return targets, nil
+	}
+}
+
+// policyRuleImportLister lists the rules of the policies of the type, system
+// rules included in every policy can't be managed by terraform.
+func policyRuleImportLister(policyType string) importLister {
+	return childImportLister(listAllPolicyImportTargets(policyType), func(ctx context.Context, m interface{}, policy importTarget) ([]importTarget, error) {
+		rules, _, err := getAPISupplementFromMetadata(m).ListPolicyRules(ctx, policy.ID)
+		if err != nil {
+			return nil, err
+		}
+		var targets []importTarget
+		for _, rule := range rules {
+			if rule.System == nil || !*rule.System {
+				targets = append(targets, importTarget{ID: policy.ID + "/" + rule.Id, Name: rule.Name})
+			}
+		}
+		return targets, nil
+	})
+}
+
+// listPolicyRuleOrderImportTargets lists the policies with ordered rules, those
+// of authorization servers are imported as <auth_server_id>/<policy_id>.
+func listPolicyRuleOrderImportTargets(ctx context.Context, m interface{}, q string) ([]importTarget, error) {
+	var targets []importTarget
+	policyTypes := append([]string{sdk.AccessPolicyType}, orderedPolicyTypes...)
+	for _, policyType := range policyTypes {
+		policies, err := listAllPolicyImportTargets(policyType)(ctx, m, q)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, policies...)
+	}
+	serverPolicies, err := childImportLister(listAllAuthServerImportTargets, listAuthServerPolicyImportTargets)(ctx, m, q)
+	if err != nil {
+		return nil, err
+	}
+	return append(targets, serverPolicies...), nil
+}
+
+func listPolicyOrderImportTargets(_ context.Context, _ interface{}, q string) ([]importTarget, error) {
+	var targets []importTarget
+	for _, policyType := range orderedPolicyTypes {
+		targets = appendImportTarget(targets, q, policyType, policyType)
+	}
+	return targets, nil
+}
+
+func listPolicyProfileEnrollmentAppsImportTargets(ctx context.Context, m interface{}, policy importTarget) ([]importTarget, error) {
+	apps, _, err := getAPISupplementFromMetadata(m).ListEnrollmentPolicyApps(ctx, policy.ID, &query.Params{Limit: 1})
+	if err != nil || len(apps) == 0 {
+		return nil, err
+	}
+	return []importTarget{{ID: policy.ID}}, nil
+}
@@ -0,0 +1,518 @@
+package okta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// importTarget is an existing Okta object that can be imported as a
+// terraform resource.
+type importTarget struct {
+	ID   string
+	Name string
+}
+
+// importLister lists the objects of one resource type in the org. When q is
+// not empty only objects whose name starts with q are listed.
+type importLister func(ctx context.Context, m interface{}, q string) ([]importTarget, error)
+
+// importListers are the resource types the importer can enumerate.
+var importListers = map[string]importLister{
+	adminRoleCustom:             listAdminRoleCustomImportTargets,
+	adminRoleCustomAssignments:  childImportLister(listResourceSetImportTargets, listAdminRoleCustomAssignmentsImportTargets),
+	adminRoleTargets:            userRoleImportLister(adminRoleTargetsImportTargets),
+	appAutoLogin:                appImportLister("AUTO_LOGIN"),
+	appBasicAuth:                appImportLister("BASIC_AUTH"),
+	appBookmark:                 appImportLister("BOOKMARK"),
+	appGroupAssignment:          childImportLister(appImportLister(""), listAppGroupAssignmentImportTargets),
+	appGroupAssignments:         childImportLister(appImportLister(""), listAppGroupAssignmentsImportTargets),
+	appOAuth:                    appImportLister("OPENID_CONNECT"),
+	appOAuthAPIScope:            childImportLister(appImportLister("OPENID_CONNECT"), listAppOAuthAPIScopeImportTargets),
+	appSaml:                     appImportLister("SAML_2_0"),
+	appSamlSigningKey:           childImportLister(appImportLister("SAML_2_0"), listAppSamlSigningKeyImportTargets),
+	appSecurePasswordStore:      appImportLister("SECURE_PASSWORD_STORE"),
+	appSharedCredentials:        swaImportLister(appSharedCredentials),
+	appSignOnPolicy:             policyImportLister(sdk.AccessPolicyType, false),
+	appSignOnPolicyRule:         policyRuleImportLister(sdk.AccessPolicyType),
+	appSwa:                      swaImportLister(appSwa),
+	appThreeField:               swaImportLister(appThreeField),
+	appUser:                     childImportLister(appImportLister(""), listAppUserImportTargets),
+	appUserBaseSchemaProperty:   appUserSchemaPropertyImportLister(true),
+	appUserSchemaProperty:       appUserSchemaPropertyImportLister(false),
+	authenticator:               listAuthenticatorImportTargets,
+	authServer:                  authServerImportLister(false),
+	authServerClaim:             authServerClaimImportLister(false),
+	authServerClaimDefault:      authServerClaimImportLister(true),
+	authServerDefault:           authServerImportLister(true),
+	authServerKeyRotation:       childImportLister(listAllAuthServerImportTargets, listAuthServerKeyRotationImportTargets),
+	authServerPolicy:            childImportLister(listAllAuthServerImportTargets, listAuthServerPolicyImportTargets),
+	authServerPolicyRule:        childImportLister(childImportLister(listAllAuthServerImportTargets, listAuthServerPolicyImportTargets), listAuthServerPolicyRuleImportTargets),
+	authServerScope:             childImportLister(listAllAuthServerImportTargets, listAuthServerScopeImportTargets),
+	behavior:                    listBehaviorImportTargets,
+	captcha:                     listCaptchaImportTargets,
+	captchaOrgWideSettings:      singletonImportLister("org_wide_captcha"),
+	domain:                      listDomainImportTargets,
+	emailCustomization:          emailCustomizationImportLister(true),
+	emailCustomizations:         emailCustomizationImportLister(false),
+	emailDomain:                 listEmailDomainImportTargets,
+	eventHook:                   listEventHookImportTargets,
+	factor:                      listFactorImportTargets,
+	factorTotp:                  listFactorTotpImportTargets,
+	group:                       listGroupImportTargets,
+	groupMemberships:            childImportLister(listGroupImportTargets, listGroupMembershipsImportTargets),
+	groupRole:                   childImportLister(listGroupImportTargets, listGroupRoleImportTargets),
+	groupRule:                   listGroupRuleImportTargets,
+	groupSchemaProperty:         listGroupSchemaPropertyImportTargets,
+	idpOidc:                     idpImportLister(oidcIdp),
+	idpSaml:                     idpImportLister(saml2Idp),
+	idpSamlKey:                  listIdpSamlKeyImportTargets,
+	idpSocial:                   idpImportLister(""),
+	inlineHook:                  listInlineHookImportTargets,
+	linkDefinition:              listLinkDefinitionImportTargets,
+	linkValue:                   listLinkValueImportTargets,
+	networkZone:                 listNetworkZoneImportTargets,
+	orgConfiguration:            listOrgConfigurationImportTargets,
+	policyMfa:                   policyImportLister(sdk.MfaPolicyType, false),
+	policyMfaDefault:            policyImportLister(sdk.MfaPolicyType, true),
+	policyOrder:                 listPolicyOrderImportTargets,
+	policyPassword:              policyImportLister(sdk.PasswordPolicyType, false),
+	policyPasswordDefault:       policyImportLister(sdk.PasswordPolicyType, true),
+	policyProfileEnrollment:     policyImportLister(sdk.ProfileEnrollmentPolicyType, false),
+	policyProfileEnrollmentApps: childImportLister(listAllPolicyImportTargets(sdk.ProfileEnrollmentPolicyType), listPolicyProfileEnrollmentAppsImportTargets),
+	policyRuleIdpDiscovery:      policyRuleImportLister(sdk.IdpDiscoveryType),
+	policyRuleMfa:               policyRuleImportLister(sdk.MfaPolicyType),
+	policyRuleOrder:             listPolicyRuleOrderImportTargets,
+	policyRulePassword:          policyRuleImportLister(sdk.PasswordPolicyType),
+	policyRuleProfileEnrollment: policyRuleImportLister(sdk.ProfileEnrollmentPolicyType),
+	policyRuleSignOn:            policyRuleImportLister(sdk.SignOnPolicyType),
+	policySignOn:                policyImportLister(sdk.SignOnPolicyType, false),
+	rateLimiting:                singletonImportLister("rate_limiting"),
+	resourceSet:                 listResourceSetImportTargets,
+	roleSubscription:            listRoleSubscriptionImportTargets,
+	securityNotificationEmails:  singletonImportLister("security_notification_emails"),
+	templateSms:                 listSmsTemplateImportTargets,
+	theme:                       childImportLister(listBrandImportTargets, listThemeImportTargets),
+	threatInsightSettings:       singletonImportLister("threat_insight_settings"),
+	trustedOrigin:               listTrustedOriginImportTargets,
+	user:                        listUserImportTargets,
+	userAdminRoles:              userRoleImportLister(userAdminRolesImportTargets),
+	userBaseSchemaProperty:      userSchemaPropertyImportLister(true),
+	userFactorQuestion:          childImportLister(listUserImportTargets, listUserFactorQuestionImportTargets),
+	userSchemaProperty:          userSchemaPropertyImportLister(false),
+	userType:                    listUserTypeImportTargets,
+}
+
+// importOptIn are the importable resource types that are only imported when
+// they are asked for, because the objects they manage are also managed by
+// another resource type, or because listing them reads every user or app.
+var importOptIn = map[string]string{
+	adminRoleTargets:          "lists the roles of every user",
+	appGroupAssignment:        "the assignments are also managed by okta_app_group_assignments",
+	appSamlSigningKey:         "every SAML app has signing keys generated by Okta",
+	appUser:                   "lists the users of every app",
+	appUserBaseSchemaProperty: "every app has base user schema properties",
+	authServerKeyRotation:     "the key rotation is also managed by okta_auth_server",
+	emailCustomization:        "the customizations are also managed by okta_email_customizations",
+	groupMemberships:          "the memberships are also managed by okta_user_group_memberships and okta_user",
+	linkValue:                 "lists the linked objects of every user",
+	policyOrder:               "the priorities are also managed by the okta_policy_* resources",
+	policyRuleOrder:           "the priorities are also managed by the policy rule resources",
+	roleSubscription:          "every admin role has subscriptions by default",
+	userAdminRoles:            "lists the roles of every user",
+	userBaseSchemaProperty:    "every org has base user schema properties",
+	userFactorQuestion:        "lists the factors of every user",
+}
+
+// importUnsupported are the resource types the importer can't enumerate, with
+// the reason why.
+var importUnsupported = map[string]string{
+	appOAuthPostLogoutRedirectURI: "the URIs are managed by okta_app_oauth",
+	appOAuthRedirectURI:           "the URIs are managed by okta_app_oauth",
+	appSamlAppSettings:            "the settings are managed by okta_app_saml",
+	domainCertificate:             "it uploads a certificate and can't be imported",
+	domainVerification:            "it is an action and can't be imported",
+	emailDomainVerification:       "it is an action and can't be imported",
+	emailSender:                   "the API can't list custom email senders",
+	emailSenderVerification:       "it is an action and can't be imported",
+	eventHookVerification:         "it is an action and can't be imported",
+	orgSupport:                    "it is an action and can't be imported",
+	profileMapping:                "it can't be imported",
+	userGroupMemberships:          "it can't be imported, use okta_group_memberships",
+}
+
+// ImportableResourceTypes returns the resource types the Importer can
+// generate configuration for.
+func ImportableResourceTypes() []string {
+	types := make([]string, 0, len(importListers))
+	for resourceType := range importListers {
+		types = append(types, resourceType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// DefaultImportResourceTypes returns the importable resource types that are
+// imported when none are asked for. The others are reported along with the
+// reason they are left out by ImportOptInReason.
+func DefaultImportResourceTypes() []string {
+	var types []string
+	for _, resourceType := range ImportableResourceTypes() {
+		if _, ok := importOptIn[resourceType]; !ok {
+			types = append(types, resourceType)
+		}
+	}
+	return types
+}
+
+// ImportOptInReason returns why an importable resource type is only imported
+// when it is asked for, an empty string when it is imported by default.
+func ImportOptInReason(resourceType string) string {
+	return importOptIn[resourceType]
+}
+
+// Importer walks an existing Okta org and generates terraform import blocks
+// along with the configuration of each imported resource. Resources are read
+// with their own importer and read functions so the generated configuration
+// plans with no changes.
+type Importer struct {
+	provider *schema.Provider
+	meta     interface{}
+	query    string
+	labels   map[string]bool
+}
+
+// NewImporter returns an Importer for the configured provider. When query is
+// not empty only objects whose name starts with query are imported.
+func NewImporter(provider *schema.Provider, query string) *Importer {
+	return &Importer{
+		provider: provider,
+		meta:     provider.Meta(),
+		query:    query,
+		labels:   make(map[string]bool),
+	}
+}
+
+// Generate writes import blocks and resource configuration for every object
+// of the resource types to w. Objects that fail to be read are skipped, their
+// errors are returned together once all the resource types are generated.
+func (i *Importer) Generate(ctx context.Context, resourceTypes []string, w io.Writer) error {
+	var errs []error
+	for _, resourceType := range resourceTypes {
+		if reason, unsupported := importUnsupported[resourceType]; unsupported {
+			return fmt.Errorf("resource type %q can not be imported: %s", resourceType, reason)
+		}
+		lister, ok := importListers[resourceType]
+		if !ok {
+			return fmt.Errorf("resource type %q can not be imported, supported types are: %s", resourceType, strings.Join(ImportableResourceTypes(), ", "))
+		}
+		targets, err := lister(ctx, i.meta, i.query)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list %s: %w", resourceType, err))
+			continue
+		}
+		for _, target := range targets {
+			f, err := i.generate(ctx, resourceType, target)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to import %s %q: %w", resourceType, target.ID, err))
+				continue
+			}
+			if f == nil {
+				continue
+			}
+			if _, err := f.WriteTo(w); err != nil {
+				return err
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (i *Importer) generate(ctx context.Context, resourceType string, target importTarget) (*hclwrite.File, error) {
+	res := i.provider.ResourcesMap[resourceType]
+	d := res.Data(nil)
+	d.SetId(target.ID)
+	if res.Importer != nil && res.Importer.StateContext != nil {
+		imported, err := res.Importer.StateContext(ctx, d, i.meta)
+		if err != nil {
+			return nil, err
+		}
+		if len(imported) == 0 {
+			return nil, nil
+		}
+		d = imported[0]
+	}
+	for _, diagnostic := range res.ReadContext(ctx, d, i.meta) {
+		// warnings, e.g. attributes the token isn't permitted to read, don't
+		// prevent the object from being imported
+		if diagnostic.Severity == diag.Error {
+			return nil, fmt.Errorf("%s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+	if d.Id() == "" {
+		// the object was removed since it was listed
+		return nil, nil
+	}
+
+	label := i.resourceLabel(resourceType, target.Name)
+	f := hclwrite.NewEmptyFile()
+	importBody := f.Body().AppendNewBlock("import", nil).Body()
+	importBody.SetAttributeTraversal("to", hclTraversal(resourceType, label))
+	importBody.SetAttributeValue("id", cty.StringVal(target.ID))
+	f.Body().AppendNewline()
+
+	values := make(map[string]interface{}, len(res.Schema))
+	for k := range res.Schema {
+		values[k] = d.Get(k)
+	}
+	var variables []*hclwrite.Block
+	variable := func(k string, s *schema.Schema) hcl.Traversal {
+		name := strings.TrimPrefix(resourceType, "okta_") + "_" + strings.TrimPrefix(label, "_") + "_" + k
+		block := hclwrite.NewBlock("variable", []string{name})
+		block.Body().SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: importVariableTypes[s.Type]}})
+		block.Body().SetAttributeValue("description", cty.StringVal(fmt.Sprintf("%s of %s.%s, it is sensitive and can't be read from Okta", k, resourceType, label)))
+		block.Body().SetAttributeValue("sensitive", cty.True)
+		variables = append(variables, block)
+		return hcl.Traversal{hcl.TraverseRoot{Name: "var"}, hcl.TraverseAttr{Name: name}}
+	}
+	writeImportBody(f.Body().AppendNewBlock("resource", []string{resourceType, label}).Body(), res.Schema, values, d, variable)
+	f.Body().AppendNewline()
+	for _, block := range variables {
+		f.Body().AppendBlock(block)
+		f.Body().AppendNewline()
+	}
+	return f, nil
+}
+
+// importVariableTypes are the terraform types of the variables required
+// sensitive attributes are written as.
+var importVariableTypes = map[schema.ValueType]string{
+	schema.TypeString: "string",
+	schema.TypeInt:    "number",
+	schema.TypeFloat:  "number",
+	schema.TypeBool:   "bool",
+}
+
+var nonLabelCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// resourceLabel returns a unique terraform resource name derived from the
+// object's name.
+func (i *Importer) resourceLabel(resourceType, name string) string {
+	label := strings.Trim(nonLabelCharacters.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') {
+		label = "_" + label
+	}
+	unique := label
+	for n := 2; i.labels[resourceType+"."+unique]; n++ {
+		unique = fmt.Sprintf("%s_%d", label, n)
+	}
+	i.labels[resourceType+"."+unique] = true
+	return unique
+}
+
+// writeImportBody writes the configurable attributes, followed by the blocks,
+// that have a value other than their default. Deprecated attributes are
+// written last so they are skipped in favor of the attributes they conflict
+// with. Sensitive attributes are never written, Okta doesn't return most of
+// them, the required ones are set to the variable returned by variable for
+// the operator to fill in. When d is set, top level attributes whose removal
+// from the configuration is suppressed by their schema are not written either.
+func writeImportBody(body *hclwrite.Body, sm map[string]*schema.Schema, values map[string]interface{}, d *schema.ResourceData, variable func(k string, s *schema.Schema) hcl.Traversal) {
+	keys := make([]string, 0, len(sm))
+	for k := range sm {
+		keys = append(keys, k)
+	}
+	sort.SliceStable(keys, func(a, b int) bool {
+		if (sm[keys[a]].Deprecated == "") != (sm[keys[b]].Deprecated == "") {
+			return sm[keys[a]].Deprecated == ""
+		}
+		return keys[a] < keys[b]
+	})
+
+	written := make(map[string]bool)
+	writable := func(k string) bool {
+		s := sm[k]
+		if k == "id" || !(s.Required || s.Optional) || s.Sensitive {
+			return false
+		}
+		if conflictsWithWritten(s, written) {
+			return false
+		}
+		if s.Required {
+			return true
+		}
+		if isDefaultImportValue(s, values[k]) {
+			return false
+		}
+		if d != nil && s.DiffSuppressFunc != nil && isPrimitiveImportValue(values[k]) {
+			return !s.DiffSuppressFunc(k, fmt.Sprint(values[k]), "", d)
+		}
+		return true
+	}
+	for _, k := range keys {
+		if s := sm[k]; s.Required && s.Sensitive && importVariableTypes[s.Type] != "" {
+			body.SetAttributeTraversal(k, variable(k, s))
+			written[k] = true
+			continue
+		}
+		if _, ok := sm[k].Elem.(*schema.Resource); ok || !writable(k) {
+			continue
+		}
+		val, ok := importCtyValue(sm[k], values[k])
+		if !ok {
+			continue
+		}
+		body.SetAttributeValue(k, val)
+		written[k] = true
+	}
+	separate := len(written) > 0
+	for _, k := range keys {
+		elem, ok := sm[k].Elem.(*schema.Resource)
+		if !ok || !writable(k) {
+			continue
+		}
+		if sm[k].ConfigMode == schema.SchemaConfigModeAttr {
+			// attributes as blocks can't be written from their flattened
+			// value, leave them for the operator to fill in
+			continue
+		}
+		for _, e := range importListValues(values[k]) {
+			if separate {
+				body.AppendNewline()
+				separate = false
+			}
+			m, _ := e.(map[string]interface{})
+			block := k
+			writeImportBody(body.AppendNewBlock(k, nil).Body(), elem.Schema, m, nil, func(k string, s *schema.Schema) hcl.Traversal {
+				return variable(block+"_"+k, s)
+			})
+		}
+		written[k] = true
+	}
+}
+
+func conflictsWithWritten(s *schema.Schema, written map[string]bool) bool {
+	for _, conflict := range s.ConflictsWith {
+		if written[conflict] {
+			return true
+		}
+	}
+	return false
+}
+
+func isDefaultImportValue(s *schema.Schema, v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	if s.Default != nil {
+		return fmt.Sprint(s.Default) == fmt.Sprint(v)
+	}
+	switch t := v.(type) {
+	case string:
+		return t == ""
+	case int:
+		return t == 0
+	case float64:
+		return t == 0
+	case bool:
+		return !t
+	case map[string]interface{}:
+		return len(t) == 0
+	}
+	return len(importListValues(v)) == 0
+}
+
+func isPrimitiveImportValue(v interface{}) bool {
+	switch v.(type) {
+	case string, int, float64, bool:
+		return true
+	}
+	return false
+}
+
+func importListValues(v interface{}) []interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		return t
+	case *schema.Set:
+		return t.List()
+	}
+	return nil
+}
+
+func importCtyValue(s *schema.Schema, v interface{}) (cty.Value, bool) {
+	switch s.Type {
+	case schema.TypeString:
+		str, ok := v.(string)
+		return cty.StringVal(str), ok
+	case schema.TypeInt:
+		i, ok := v.(int)
+		return cty.NumberIntVal(int64(i)), ok
+	case schema.TypeFloat:
+		f, ok := v.(float64)
+		return cty.NumberFloatVal(f), ok
+	case schema.TypeBool:
+		b, ok := v.(bool)
+		return cty.BoolVal(b), ok
+	case schema.TypeList, schema.TypeSet:
+		elem := elemSchema(s)
+		var vals []cty.Value
+		for _, e := range importListValues(v) {
+			val, ok := importCtyValue(elem, e)
+			if !ok {
+				return cty.NilVal, false
+			}
+			vals = append(vals, val)
+		}
+		if len(vals) == 0 {
+			return cty.NilVal, false
+		}
+		if s.Type == schema.TypeSet {
+			return cty.SetVal(vals), true
+		}
+		return cty.ListVal(vals), true
+	case schema.TypeMap:
+		m, ok := v.(map[string]interface{})
+		if !ok || len(m) == 0 {
+			return cty.NilVal, false
+		}
+		elem := elemSchema(s)
+		vals := make(map[string]cty.Value, len(m))
+		for k, e := range m {
+			val, ok := importCtyValue(elem, e)
+			if !ok {
+				return cty.NilVal, false
+			}
+			vals[k] = val
+		}
+		return cty.MapVal(vals), true
+	}
+	return cty.NilVal, false
+}
+
+// elemSchema returns the schema of the primitive elements of a list, set or
+// map attribute, maps and lists without an Elem hold strings.
+func elemSchema(s *schema.Schema) *schema.Schema {
+	if elem, ok := s.Elem.(*schema.Schema); ok {
+		return elem
+	}
+	return &schema.Schema{Type: schema.TypeString}
+}
+
+func hclTraversal(resourceType, label string) hcl.Traversal {
+	return hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: label},
+	}
+}
@@ -0,0 +1,233 @@
+package okta
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+// TestImporterGenerate imports the groups starting with "testAcc_", reading
+// each one with the okta_group resource.
+func TestImporterGenerate(t *testing.T) {
+	_, config := newFakeOktaConfig(t)
+	ctx := context.TODO()
+	var ids []string
+	for _, name := range []string{"testAcc_4208174747 - Test 1", "testAcc_4208174747  - Test 2", "Engineering"} {
+		group, _, err := config.oktaSDKClientV2.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: name, Description: "testing, testing"}})
+		require.NoError(t, err)
+		ids = append(ids, group.Id)
+	}
+
+	provider := Provider()
+	provider.SetMeta(config)
+
+	var out bytes.Buffer
+	err := NewImporter(provider, "testAcc_").Generate(ctx, []string{group}, &out)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf(`import {
+  to = okta_group.testacc_4208174747_test_1
+  id = %q
+}
+
+resource "okta_group" "testacc_4208174747_test_1" {
+  description = "testing, testing"
+  name        = "testAcc_4208174747 - Test 1"
+}
+
+import {
+  to = okta_group.testacc_4208174747_test_2
+  id = %q
+}
+
+resource "okta_group" "testacc_4208174747_test_2" {
+  description = "testing, testing"
+  name        = "testAcc_4208174747  - Test 2"
+}
+
+`, ids[0], ids[1]), out.String())
+
+	err = NewImporter(provider, "").Generate(context.TODO(), []string{orgSupport}, &out)
+	require.EqualError(t, err, `resource type "okta_org_support" can not be imported: it is an action and can't be imported`)
+}
+
+// TestImportCoverage checks every resource type is either importable or
+// documented as unsupported.
+func TestImportCoverage(t *testing.T) {
+	for resourceType := range Provider().ResourcesMap {
+		_, importable := importListers[resourceType]
+		_, unsupported := importUnsupported[resourceType]
+		require.True(t, importable != unsupported, "%s must either have an import lister or be listed as unsupported", resourceType)
+	}
+	for resourceType := range importOptIn {
+		require.Contains(t, importListers, resourceType)
+	}
+	require.NotContains(t, DefaultImportResourceTypes(), groupMemberships)
+	require.Contains(t, ImportableResourceTypes(), groupMemberships)
+}
+
+func TestImportListers(t *testing.T) {
+	_, config := newFakeOktaConfig(t)
+	ctx := context.TODO()
+
+	re := getRequestExecutor(config)
+	var policy, rule sdk.Policy
+	req, err := re.NewRequest(http.MethodPost, "/api/v1/policies", map[string]interface{}{"type": sdk.SignOnPolicyType, "name": "Contractors"})
+	require.NoError(t, err)
+	_, err = re.Do(ctx, req, &policy)
+	require.NoError(t, err)
+	req, err = re.NewRequest(http.MethodPost, "/api/v1/policies/"+policy.Id+"/rules", map[string]interface{}{"type": "SIGN_ON", "name": "Office"})
+	require.NoError(t, err)
+	_, err = re.Do(ctx, req, &rule)
+	require.NoError(t, err)
+
+	// system policies are only listed for the okta_policy_*_default resources
+	targets, err := importListers[policySignOn](ctx, config, "")
+	require.NoError(t, err)
+	require.Equal(t, []importTarget{{ID: policy.Id, Name: "Contractors"}}, targets)
+	targets, err = importListers[policyMfaDefault](ctx, config, "")
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	require.Equal(t, "Default Policy", targets[0].Name)
+
+	// the rules of every policy but the system ones, named after their policy
+	targets, err = importListers[policyRuleSignOn](ctx, config, "")
+	require.NoError(t, err)
+	require.Equal(t, []importTarget{{ID: policy.Id + "/" + rule.Id, Name: "Contractors Office"}}, targets)
+	targets, err = importListers[policyRuleSignOn](ctx, config, "Default")
+	require.NoError(t, err)
+	require.Empty(t, targets)
+
+	targets, err = importListers[rateLimiting](ctx, config, "")
+	require.NoError(t, err)
+	require.Equal(t, []importTarget{{ID: "rate_limiting", Name: "rate_limiting"}}, targets)
+}
+
+// TestImporterGenerateWarnings checks warnings read along with an object don't
+// prevent it from being imported.
+func TestImporterGenerateWarnings(t *testing.T) {
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true},
+		},
+		ReadContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			_ = d.Set("name", "Payroll")
+			return diag.Diagnostics{{Severity: diag.Warning, Summary: "unable to read the settings"}}
+		},
+	}
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{"okta_test": res}}
+	f, err := NewImporter(provider, "").generate(context.TODO(), "okta_test", importTarget{ID: "0oa1", Name: "Payroll"})
+	require.NoError(t, err)
+	require.Contains(t, string(f.Bytes()), `resource "okta_test" "payroll"`)
+
+	res.ReadContext = func(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+		return diag.Diagnostics{
+			{Severity: diag.Warning, Summary: "unable to read the settings"},
+			{Severity: diag.Error, Summary: "failed to get app", Detail: "not found"},
+		}
+	}
+	_, err = NewImporter(provider, "").generate(context.TODO(), "okta_test", importTarget{ID: "0oa1", Name: "Payroll"})
+	require.EqualError(t, err, "failed to get app: not found")
+}
+
+func TestImporterGenerateRequiredSensitive(t *testing.T) {
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"key":    {Type: schema.TypeString, Required: true},
+			"answer": {Type: schema.TypeString, Required: true, Sensitive: true},
+		},
+		ReadContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			_ = d.Set("key", "disliked_food")
+			return nil
+		},
+	}
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{"okta_test": res}}
+	f, err := NewImporter(provider, "").generate(context.TODO(), "okta_test", importTarget{ID: "ufs1", Name: "Jane Doe"})
+	require.NoError(t, err)
+	require.Equal(t, `import {
+  to = okta_test.jane_doe
+  id = "ufs1"
+}
+
+resource "okta_test" "jane_doe" {
+  answer = var.test_jane_doe_answer
+  key    = "disliked_food"
+}
+
+variable "test_jane_doe_answer" {
+  type        = string
+  description = "answer of okta_test.jane_doe, it is sensitive and can't be read from Okta"
+  sensitive   = true
+}
+
+`, string(f.Bytes()))
+}
+
+func TestWriteImportBody(t *testing.T) {
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":     {Type: schema.TypeString, Required: true},
+			"status":   {Type: schema.TypeString, Optional: true, Default: statusActive},
+			"enabled":  {Type: schema.TypeBool, Optional: true},
+			"computed": {Type: schema.TypeString, Computed: true},
+			"secret":   {Type: schema.TypeString, Optional: true, Sensitive: true},
+			"password": {Type: schema.TypeString, Required: true, Sensitive: true},
+			"old_name": {Type: schema.TypeString, Optional: true, Deprecated: "use name", ConflictsWith: []string{"new_name"}},
+			"new_name": {Type: schema.TypeString, Optional: true, ConflictsWith: []string{"old_name"}},
+			"tags":     {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"setting": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key":   {Type: schema.TypeString, Required: true},
+						"value": {Type: schema.TypeInt, Optional: true},
+					},
+				},
+			},
+		},
+	}
+	d := res.TestResourceData()
+	require.NoError(t, d.Set("name", "Payroll ${app}"))
+	require.NoError(t, d.Set("status", statusInactive))
+	require.NoError(t, d.Set("computed", "x"))
+	require.NoError(t, d.Set("secret", "s3cr3t"))
+	require.NoError(t, d.Set("old_name", "Payroll"))
+	require.NoError(t, d.Set("new_name", "Payroll"))
+	require.NoError(t, d.Set("tags", []interface{}{"a"}))
+	require.NoError(t, d.Set("setting", []interface{}{map[string]interface{}{"key": "k", "value": 2}}))
+	values := map[string]interface{}{}
+	for k := range res.Schema {
+		values[k] = d.Get(k)
+	}
+
+	f := hclwriteFile(t, res.Schema, values)
+	require.Equal(t, `name     = "Payroll $${app}"
+new_name = "Payroll"
+password = var.password
+status   = "INACTIVE"
+tags     = ["a"]
+
+setting {
+  key   = "k"
+  value = 2
+}
+`, f)
+}
+
+func hclwriteFile(t *testing.T, sm map[string]*schema.Schema, values map[string]interface{}) string {
+	t.Helper()
+	f := hclwrite.NewEmptyFile()
+	writeImportBody(f.Body(), sm, values, nil, func(k string, _ *schema.Schema) hcl.Traversal {
+		return hcl.Traversal{hcl.TraverseRoot{Name: "var"}, hcl.TraverseAttr{Name: k}}
+	})
+	return string(f.Bytes())
+}
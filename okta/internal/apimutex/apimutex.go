@@ -20,6 +20,7 @@ type APIMutex struct {
 	capacity int
 	status   map[string]*APIStatus
 	buckets  map[string]string
+	backend  Backend
 }
 
 // APIStatus is used to hold rate limit information from Okta's API, see:
@@ -88,6 +89,23 @@ func (m *APIMutex) Update(method, endPoint string, limit, remaining int, reset i
 	}
 }
 
+// Capacity returns the maximum capacity percentage of the api mutex.
+func (m *APIMutex) Capacity() int {
+	return m.capacity
+}
+
+// SetBackend shares the rate limit status of the api mutex with other
+// processes through the backend.
+func (m *APIMutex) SetBackend(backend Backend) {
+	m.backend = backend
+}
+
+// Backend returns the backend sharing the rate limit status with other
+// processes, nil when the status isn't shared.
+func (m *APIMutex) Backend() Backend {
+	return m.backend
+}
+
 // Status Returns the APIStatus for the given method + endpoint combination.
 func (m *APIMutex) Status(method, endPoint string) *APIStatus {
 	return m.get(method, endPoint)
@@ -95,14 +113,12 @@ func (m *APIMutex) Status(method, endPoint string) *APIStatus {
 
 // Class Returns the api endpoint class.
 func (m *APIMutex) Class(method, endPoint string) string {
-	path := reOktaID.ReplaceAllString(endPoint, "ID")
-	return m.normalizedKey(method, path)
+	return m.normalizedKey(method, normalizedPath(endPoint))
 }
 
 // Bucket Returns the rate limit bucket the api endpoint falls into.
 func (m *APIMutex) Bucket(method, endPoint string) string {
-	path := reOktaID.ReplaceAllString(endPoint, "ID")
-	key := m.normalizedKey(method, path)
+	key := m.normalizedKey(method, normalizedPath(endPoint))
 	bucket, ok := m.buckets[key]
 	if !ok {
 		return "/"
@@ -132,10 +148,19 @@ func (s *APIStatus) Remaining() int {
 var reOktaID = regexp.MustCompile(`[\w]{20}`)
 
 func (m *APIMutex) get(method, endPoint string) *APIStatus {
-	// The important point here is the replace all is performing this
-	// transformation for the bucket lookup /api/v1/users/abcdefghij0123456789
-	// to /api/v1/users/ID .
-	path := reOktaID.ReplaceAllStringFunc(endPoint, func(element string) string {
+	key := m.normalizedKey(method, normalizedPath(endPoint))
+	bucket, ok := m.buckets[key]
+	if !ok {
+		return m.status["/"]
+	}
+	return m.status[bucket]
+}
+
+// normalizedPath replaces the IDs of the endpoint's path, it is performing
+// this transformation for the bucket lookup /api/v1/users/abcdefghij0123456789
+// to /api/v1/users/ID .
+func normalizedPath(endPoint string) string {
+	return reOktaID.ReplaceAllStringFunc(endPoint, func(element string) string {
 		// Any path elements, like "authorizationServers", which are 20
 		// characters long should be handled here.
 		switch element {
@@ -145,12 +170,6 @@ func (m *APIMutex) get(method, endPoint string) *APIStatus {
 			return "ID"
 		}
 	})
-	key := m.normalizedKey(method, path)
-	bucket, ok := m.buckets[key]
-	if !ok {
-		return m.status["/"]
-	}
-	return m.status[bucket]
 }
 
 func (m *APIMutex) initRateLimitLookup() {
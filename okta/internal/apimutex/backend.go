@@ -0,0 +1,174 @@
+package apimutex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/filelock"
+)
+
+// Backend shares the rate limit status of the Okta API buckets between
+// provider processes, for instance the providers of parallel terraform runs
+// against the same org, so the api capacity is enforced across processes.
+type Backend interface {
+	// Acquire reserves a request in the bucket when the bucket's utilization
+	// is under the capacity percentage. Otherwise it returns how long to wait
+	// for the bucket to reset before trying again.
+	Acquire(ctx context.Context, bucket string, capacity int) (time.Duration, error)
+
+	// Update records the rate limit values of a response of the bucket.
+	Update(ctx context.Context, bucket string, limit, remaining int, reset int64) error
+}
+
+// FileBackend is a Backend keeping the rate limit status in a file, for
+// instance on a volume shared by the runners of the terraform runs. The file
+// is locked while it is read and written.
+type FileBackend struct {
+	path string
+	now  func() time.Time
+}
+
+// fileBackendStatus is the status of a bucket in the backend's file.
+type fileBackendStatus struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Reset     int64 `json:"reset"`
+	// Provisional is set from when a process acquires a request in a new
+	// one minute window until the response of a request tells the actual
+	// window's values.
+	Provisional bool `json:"provisional,omitempty"`
+}
+
+const (
+	// windowLength is Okta's rate limit window
+	windowLength = 60 * time.Second
+
+	// recheckInterval is how often a provisional status is checked again
+	// while it is over capacity or while its limit is unknown
+	recheckInterval = time.Second
+)
+
+// NewFileBackend returns a backend keeping the rate limit status in the file
+// at path.
+func NewFileBackend(path string) *FileBackend {
+	return &FileBackend{
+		path: path,
+		now:  time.Now,
+	}
+}
+
+// Acquire reserves a request in the bucket by decrementing its remaining
+// requests. When the bucket's window has reset the request starts a
+// provisional window. While the limit of a bucket isn't known only one request
+// per second is acquired, the others wait for its response.
+func (b *FileBackend) Acquire(ctx context.Context, bucket string, capacity int) (time.Duration, error) {
+	var wait time.Duration
+	err := b.update(ctx, func(statuses map[string]*fileBackendStatus) bool {
+		now := b.now()
+		status, ok := statuses[bucket]
+		switch {
+		case !ok || (status.Limit == 0 && !now.Before(time.Unix(status.Reset, 0))):
+			// the first request of the bucket probes its limit
+			statuses[bucket] = &fileBackendStatus{Reset: now.Add(recheckInterval).Unix(), Provisional: true}
+			return true
+		case status.Limit == 0:
+			wait = recheckInterval
+			return false
+		case !now.Before(time.Unix(status.Reset, 0)):
+			statuses[bucket] = &fileBackendStatus{
+				Limit:       status.Limit,
+				Remaining:   status.Limit - 1,
+				Reset:       now.Add(windowLength).Unix(),
+				Provisional: true,
+			}
+			return true
+		}
+		utilization := 100.0 * (float32(status.Limit-status.Remaining) / float32(status.Limit))
+		if utilization > float32(capacity) {
+			wait = time.Unix(status.Reset, 0).Sub(now)
+			if status.Provisional && wait > recheckInterval {
+				wait = recheckInterval
+			}
+			return false
+		}
+		status.Remaining--
+		return true
+	})
+	return wait, err
+}
+
+// Update records the rate limit values of a response of the bucket with the
+// same accounting as APIMutex.Update. The lowest remaining value of a one
+// minute window is kept, it also accounts for the requests acquired by the
+// processes that are still in flight.
+func (b *FileBackend) Update(ctx context.Context, bucket string, limit, remaining int, reset int64) error {
+	return b.update(ctx, func(statuses map[string]*fileBackendStatus) bool {
+		if !b.now().Before(time.Unix(reset, 0)) {
+			// the response of a request made in a window that has reset
+			return false
+		}
+		status, ok := statuses[bucket]
+		switch {
+		case !ok || reset > status.Reset && !status.Provisional:
+			statuses[bucket] = &fileBackendStatus{Limit: limit, Remaining: remaining, Reset: reset}
+			return true
+		case status.Provisional:
+			if status.Limit != 0 && status.Remaining < remaining {
+				remaining = status.Remaining
+			}
+			statuses[bucket] = &fileBackendStatus{Limit: limit, Remaining: remaining, Reset: reset}
+			return true
+		}
+		if reset <= status.Reset-60 || remaining >= status.Remaining {
+			return false
+		}
+		status.Remaining = remaining
+		return true
+	})
+}
+
+// update applies the function to the statuses while the file is locked, the
+// statuses are written back when the function returns true.
+func (b *FileBackend) update(ctx context.Context, f func(map[string]*fileBackendStatus) bool) error {
+	unlock, err := filelock.Lock(ctx, b.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	statuses := map[string]*fileBackendStatus{}
+	data, err := os.ReadFile(b.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(data) > 0 {
+		// a corrupted file is overwritten, the statuses are refreshed by
+		// the next responses
+		_ = json.Unmarshal(data, &statuses)
+	}
+	if !f(statuses) {
+		return nil
+	}
+
+	data, err = json.Marshal(statuses)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(b.path), filepath.Base(b.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), b.path)
+}
@@ -0,0 +1,79 @@
+package apimutex
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileBackend(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "rate-limits.json")
+	now := time.Unix(1700000000, 0)
+	clock := func() time.Time { return now }
+	// two processes sharing the file
+	b1, b2 := NewFileBackend(path), NewFileBackend(path)
+	b1.now, b2.now = clock, clock
+	bucket := "/api/v1/groups"
+
+	acquire := func(b *FileBackend) time.Duration {
+		t.Helper()
+		wait, err := b.Acquire(ctx, bucket, 50)
+		if err != nil {
+			t.Fatalf("did not expect error, got %v", err)
+		}
+		return wait
+	}
+
+	// the first request probes the bucket's limit, the others wait for it
+	if wait := acquire(b1); wait != 0 {
+		t.Fatalf("expected the probe to be acquired, got wait %s", wait)
+	}
+	if wait := acquire(b2); wait != recheckInterval {
+		t.Fatalf("expected to wait %s for the probe, got %s", recheckInterval, wait)
+	}
+	reset := now.Add(30 * time.Second).Unix()
+	if err := b1.Update(ctx, bucket, 10, 9, reset); err != nil {
+		t.Fatal(err)
+	}
+
+	// 5 of 10 requests (50%) are acquired across processes
+	acquired := 0
+	for i := 0; i < 10; i++ {
+		b := b1
+		if i%2 == 1 {
+			b = b2
+		}
+		if acquire(b) == 0 {
+			acquired++
+		}
+	}
+	if acquired != 5 {
+		t.Errorf("expected 5 requests to be acquired, got %d", acquired)
+	}
+	if wait := acquire(b2); wait != 30*time.Second {
+		t.Errorf("expected to wait 30s for the reset, got %s", wait)
+	}
+
+	// a response with a higher remaining value doesn't release reservations
+	if err := b2.Update(ctx, bucket, 10, 8, reset); err != nil {
+		t.Fatal(err)
+	}
+	if wait := acquire(b1); wait == 0 {
+		t.Error("expected the bucket to still be over capacity")
+	}
+
+	// once the window resets a provisional window is started
+	now = now.Add(31 * time.Second)
+	if wait := acquire(b2); wait != 0 {
+		t.Errorf("expected a request to be acquired in the new window, got wait %s", wait)
+	}
+	// responses of the previous window are ignored
+	if err := b1.Update(ctx, bucket, 10, 0, reset); err != nil {
+		t.Fatal(err)
+	}
+	if wait := acquire(b1); wait != 0 {
+		t.Errorf("expected a request to be acquired in the new window, got wait %s", wait)
+	}
+}
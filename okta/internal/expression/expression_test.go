@@ -0,0 +1,80 @@
+package expression
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		expr string
+		refs []Reference
+	}{
+		{`user.firstName`, []Reference{{Root: "user", Property: "firstName", Pos: 5}}},
+		{`String.startsWith(user.firstName,"andy")`, []Reference{{Root: "user", Property: "firstName", Pos: 23}}},
+		{`String.startsWith(user.firstName,String.toLowerCase("bOb"))`, []Reference{{Root: "user", Property: "firstName", Pos: 23}}},
+		{`isMemberOfGroupName("Engineering") AND user.department == 'R&D'`, []Reference{{Root: "user", Property: "department", Pos: 44}}},
+		{`user.title eq "CEO" or user.isMemberOf({'group.id': {"00g1", "00g2"}})`, []Reference{{Root: "user", Property: "title", Pos: 5}}},
+		{`appuser.email != null ? appuser.email : user.login`, []Reference{
+			{Root: "appuser", Property: "email", Pos: 8},
+			{Root: "appuser", Property: "email", Pos: 32},
+			{Root: "user", Property: "login", Pos: 45},
+		}},
+		{`appuser.nickName ?: ""`, []Reference{{Root: "appuser", Property: "nickName", Pos: 8}}},
+		{`Arrays.contains(user.getGroups({'group.type': {'OKTA_GROUP'}}).![name], "Admins")`, nil},
+		{`Convert.toInt(user.employeeNumber) + 1 > 10`, []Reference{{Root: "user", Property: "employeeNumber", Pos: 19}}},
+		{`!(user.isActive) && user.groups[0] == "a"`, []Reference{
+			{Root: "user", Property: "isActive", Pos: 7},
+			{Root: "user", Property: "groups", Pos: 25},
+		}},
+		{`Groups.startsWith("active_directory", "eng", 10)`, nil},
+		{`user.getInternalProperty("id")`, nil},
+		{`"it's " + 'it''s'`, nil},
+		{`user.email matches ".*@example\\.com"`, []Reference{{Root: "user", Property: "email", Pos: 5}}},
+		{`user.title == 'C:\' or user.title == "C:\"`, []Reference{
+			{Root: "user", Property: "title", Pos: 5},
+			{Root: "user", Property: "title", Pos: 28},
+		}},
+	}
+	for _, test := range tests {
+		refs, err := Validate(test.expr)
+		if err != nil {
+			t.Errorf("%s: did not expect error, got %v", test.expr, err)
+			continue
+		}
+		if !reflect.DeepEqual(refs, test.refs) {
+			t.Errorf("%s: expected references %+v, got %+v", test.expr, test.refs, refs)
+		}
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{``, "empty expression at position 0"},
+		{`user.`, "expected property name after \".\", got end of expression at position 5"},
+		{`String.startsWith(user.firstName,"andy"`, "expected \",\" or \")\", got end of expression at position 39"},
+		{`user.firstName == "andy`, "unterminated string literal at position 18"},
+		{`user.firstName == `, "unexpected end of expression at position 18"},
+		{`user.firstName user.lastName`, "unexpected \"user\" at position 15"},
+		{`String.toLowercase(user.firstName)`, "unknown function String.toLowercase, String functions are: append, endsWith, join, len, removeSpaces, replace, replaceFirst, startsWith, stringContains, stringSwitch, substring, substringAfter, substringBefore, toLowerCase, toUpperCase, trim at position 7"},
+		{`isMemberOfGroupNames("Engineering")`, "unknown function \"isMemberOfGroupNames\" at position 0"},
+		{`user.firstName # "a"`, "unexpected character '#' at position 15"},
+		{`"a"(1)`, "only functions and methods can be called at position 3"},
+		{`user.firstName and`, "unexpected end of expression at position 18"},
+	}
+	for _, test := range tests {
+		_, err := Validate(test.expr)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%s: expected syntax error, got %v", test.expr, err)
+			continue
+		}
+		if err.Error() != test.err {
+			t.Errorf("%s: expected error %q, got %q", test.expr, test.err, err.Error())
+		}
+	}
+}
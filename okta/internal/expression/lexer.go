@@ -0,0 +1,119 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenIdent:
+		return "identifier"
+	case tokenNumber:
+		return "number"
+	case tokenString:
+		return "string"
+	case tokenOperator:
+		return "operator"
+	}
+	return "end of expression"
+}
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return t.kind.String()
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+// operators are ordered so the longest operator is matched first.
+var operators = []string{
+	"?.", "?:", "==", "!=", "<=", ">=", "&&", "||",
+	"(", ")", "[", "]", "{", "}", ",", ".", "?", ":", "!", "+", "-", "*", "/", "%", "<", ">",
+}
+
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			value, n, err := lexString(runes[i:])
+			if err != nil {
+				return nil, &SyntaxError{Pos: start, Msg: err.Error()}
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: start})
+			i += n
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])) {
+				i++
+			}
+			// long and float literal suffixes
+			if i < len(runes) && strings.ContainsRune("lLfFdD", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_' || r == '$':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: string(runes[start:i]), pos: start})
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, &SyntaxError{Pos: i, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+			tokens = append(tokens, token{kind: tokenOperator, value: op, pos: i})
+			i += len([]rune(op))
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+// lexString lexes a quoted string literal. Like SpEL the quote is only
+// escaped by doubling it, a backslash is a backslash.
+func lexString(runes []rune) (string, int, error) {
+	quote := runes[0]
+	var sb strings.Builder
+	for i := 1; i < len(runes); i++ {
+		if runes[i] == quote {
+			if i+1 < len(runes) && runes[i+1] == quote {
+				sb.WriteRune(quote)
+				i++
+				continue
+			}
+			return sb.String(), i + 1, nil
+		}
+		sb.WriteRune(runes[i])
+	}
+	return "", 0, fmt.Errorf("unterminated string literal")
+}
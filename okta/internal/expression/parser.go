@@ -0,0 +1,433 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expr is a node of a parsed Okta Expression Language expression.
+type Expr interface {
+	// Pos is the position of the expression in the source
+	Pos() int
+}
+
+type (
+	// Literal is a string, number, boolean or null literal.
+	Literal struct {
+		Value string
+		pos   int
+	}
+
+	// Ident is a bare identifier, e.g. user or String.
+	Ident struct {
+		Name string
+		pos  int
+	}
+
+	// Member is a property access, e.g. user.firstName.
+	Member struct {
+		X    Expr
+		Name string
+		pos  int
+	}
+
+	// Index is an index access, e.g. user.groups[0].
+	Index struct {
+		X     Expr
+		Index Expr
+		pos   int
+	}
+
+	// Call is a function or method call, e.g. String.toLowerCase(user.email).
+	Call struct {
+		Fun  Expr
+		Args []Expr
+		pos  int
+	}
+
+	// Unary is a unary operation, e.g. !user.isActive.
+	Unary struct {
+		Op  string
+		X   Expr
+		pos int
+	}
+
+	// Binary is a binary operation, e.g. user.title == "CEO".
+	Binary struct {
+		Op   string
+		X, Y Expr
+		pos  int
+	}
+
+	// Conditional is the ternary operator, or the elvis operator when Then
+	// is nil.
+	Conditional struct {
+		Cond, Then, Else Expr
+		pos              int
+	}
+
+	// List is an inline list, e.g. {"00g1", "00g2"}, or an inline map, e.g.
+	// {'group.type': {'OKTA_GROUP'}}, in which case Elems holds the keys and
+	// values in turn.
+	List struct {
+		Elems []Expr
+		Map   bool
+		pos   int
+	}
+
+	// Projection is a collection projection, e.g. groups.![name], or a
+	// selection when Selection is true, e.g. groups.?[type == 'OKTA_GROUP'].
+	// The expression is evaluated against each element of the collection.
+	Projection struct {
+		X         Expr
+		Expr      Expr
+		Selection bool
+		pos       int
+	}
+)
+
+func (e *Literal) Pos() int     { return e.pos }
+func (e *Ident) Pos() int       { return e.pos }
+func (e *Member) Pos() int      { return e.pos }
+func (e *Index) Pos() int       { return e.pos }
+func (e *Call) Pos() int        { return e.pos }
+func (e *Unary) Pos() int       { return e.pos }
+func (e *Binary) Pos() int      { return e.pos }
+func (e *Conditional) Pos() int { return e.pos }
+func (e *List) Pos() int        { return e.pos }
+func (e *Projection) Pos() int  { return e.pos }
+
+// SyntaxError is an error parsing an expression.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos)
+}
+
+// textual operators and their symbolic equivalent
+var wordOperators = map[string]string{
+	"and": "&&",
+	"or":  "||",
+	"not": "!",
+	"eq":  "==",
+	"ne":  "!=",
+	"lt":  "<",
+	"gt":  ">",
+	"le":  "<=",
+	"ge":  ">=",
+	"div": "/",
+	"mod": "%",
+}
+
+var literals = map[string]bool{
+	"true":  true,
+	"false": true,
+	"null":  true,
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+// Parse parses an Okta Expression Language expression.
+func Parse(expr string) (Expr, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &SyntaxError{Pos: 0, Msg: "empty expression"}
+	}
+	e, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t)}
+	}
+	return e, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+// operator returns the symbolic operator of the next token, textual
+// operators are case insensitive.
+func (p *parser) operator() string {
+	t := p.peek()
+	switch t.kind {
+	case tokenOperator:
+		return t.value
+	case tokenIdent:
+		return wordOperators[strings.ToLower(t.value)]
+	}
+	return ""
+}
+
+func (p *parser) expect(op string) (token, error) {
+	t := p.next()
+	if t.kind != tokenOperator || t.value != op {
+		return t, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("expected %q, got %s", op, t)}
+	}
+	return t, nil
+}
+
+func (p *parser) parseConditional() (Expr, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	switch p.operator() {
+	case "?:":
+		t := p.next()
+		els, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+		return &Conditional{Cond: cond, Else: els, pos: t.pos}, nil
+	case "?":
+		t := p.next()
+		then, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		els, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+		return &Conditional{Cond: cond, Then: then, Else: els, pos: t.pos}, nil
+	}
+	return cond, nil
+}
+
+// binaryPrecedence lists the binary operators from the lowest to the highest
+// precedence.
+var binaryPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", ">", "<=", ">=", "matches"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseBinary(level int) (Expr, error) {
+	if level == len(binaryPrecedence) {
+		return p.parseUnary()
+	}
+	x, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.operator()
+		if op == "" && p.peek().kind == tokenIdent && strings.EqualFold(p.peek().value, "matches") {
+			op = "matches"
+		}
+		if !contains(binaryPrecedence[level], op) {
+			return x, nil
+		}
+		t := p.next()
+		y, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &Binary{Op: op, X: x, Y: y, pos: t.pos}
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	switch op := p.operator(); op {
+	case "!", "-", "+":
+		t := p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{Op: op, X: x, pos: t.pos}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (Expr, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokenOperator {
+			return x, nil
+		}
+		switch t.value {
+		case ".", "?.":
+			p.next()
+			if op := p.peek(); t.value == "." && op.kind == tokenOperator && (op.value == "!" || op.value == "?") {
+				p.next()
+				if _, err := p.expect("["); err != nil {
+					return nil, err
+				}
+				projection, err := p.parseConditional()
+				if err != nil {
+					return nil, err
+				}
+				if _, err := p.expect("]"); err != nil {
+					return nil, err
+				}
+				x = &Projection{X: x, Expr: projection, Selection: op.value == "?", pos: op.pos}
+				continue
+			}
+			name := p.next()
+			if name.kind != tokenIdent {
+				return nil, &SyntaxError{Pos: name.pos, Msg: fmt.Sprintf("expected property name after %q, got %s", t.value, name)}
+			}
+			x = &Member{X: x, Name: name.value, pos: name.pos}
+		case "[":
+			p.next()
+			index, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &Index{X: x, Index: index, pos: t.pos}
+		case "(":
+			switch x.(type) {
+			case *Ident, *Member:
+			default:
+				return nil, &SyntaxError{Pos: t.pos, Msg: "only functions and methods can be called"}
+			}
+			p.next()
+			args, err := p.parseList(")")
+			if err != nil {
+				return nil, err
+			}
+			x = &Call{Fun: x, Args: args, pos: x.Pos()}
+		default:
+			return x, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString, tokenNumber:
+		return &Literal{Value: t.value, pos: t.pos}, nil
+	case tokenIdent:
+		if literals[strings.ToLower(t.value)] {
+			return &Literal{Value: t.value, pos: t.pos}, nil
+		}
+		if _, ok := wordOperators[strings.ToLower(t.value)]; ok {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected operator %s", t)}
+		}
+		return &Ident{Name: t.value, pos: t.pos}, nil
+	case tokenOperator:
+		switch t.value {
+		case "(":
+			x, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "{":
+			return p.parseInlineCollection(t)
+		}
+	}
+	return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t)}
+}
+
+// parseList parses comma separated expressions up to the closing operator.
+func (p *parser) parseList(closing string) ([]Expr, error) {
+	var elems []Expr
+	if p.operator() == closing {
+		p.next()
+		return elems, nil
+	}
+	for {
+		e, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, e)
+		t := p.next()
+		if t.kind == tokenOperator && t.value == closing {
+			return elems, nil
+		}
+		if t.kind != tokenOperator || t.value != "," {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("expected \",\" or %q, got %s", closing, t)}
+		}
+	}
+}
+
+// parseInlineCollection parses an inline list or map after its opening brace.
+func (p *parser) parseInlineCollection(open token) (Expr, error) {
+	list := &List{pos: open.pos}
+	if p.operator() == ":" {
+		// {:} is the empty map
+		p.next()
+		list.Map = true
+		_, err := p.expect("}")
+		return list, err
+	}
+	if p.operator() == "}" {
+		p.next()
+		return list, nil
+	}
+	for {
+		e, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+		list.Elems = append(list.Elems, e)
+		if len(list.Elems) == 1 && p.operator() == ":" {
+			list.Map = true
+		}
+		if list.Map {
+			if _, err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			value, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+			list.Elems = append(list.Elems, value)
+		}
+		t := p.next()
+		if t.kind == tokenOperator && t.value == "}" {
+			return list, nil
+		}
+		if t.kind != tokenOperator || t.value != "," {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("expected \",\" or \"}\", got %s", t)}
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
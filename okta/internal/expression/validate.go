@@ -0,0 +1,159 @@
+package expression
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// classFunctions are the functions of the Okta Expression Language classes,
+// see https://developer.okta.com/docs/reference/okta-expression-language/
+var classFunctions = map[string][]string{
+	"Arrays": {
+		"add", "clear", "contains", "flatten", "get", "isEmpty", "remove", "size", "toCsvString",
+	},
+	"Convert": {
+		"toInt", "toNum",
+	},
+	"Groups": {
+		"contains", "endsWith", "startsWith",
+	},
+	"Iso3166Convert": {
+		"toAlpha2", "toAlpha3", "toName", "toNumeric",
+	},
+	"String": {
+		"append", "endsWith", "join", "len", "removeSpaces", "replace", "replaceFirst", "startsWith",
+		"stringContains", "stringSwitch", "substring", "substringAfter", "substringBefore", "toLowerCase",
+		"toUpperCase", "trim",
+	},
+	"Time": {
+		"fromIso8601ToString", "fromStringToIso8601", "fromUnixToIso8601", "fromWindowsToIso8601", "now",
+	},
+}
+
+// globalFunctions are the Okta Expression Language functions that are called
+// without a class.
+var globalFunctions = []string{
+	"findDirectoryUser",
+	"findWorkdayUser",
+	"getAssistantAppUser",
+	"getAssistantUser",
+	"getFilteredGroups",
+	"getManagerAppUser",
+	"getManagerUser",
+	"hasDirectoryUser",
+	"hasWorkdayUser",
+	"isMemberOfAnyGroup",
+	"isMemberOfGroup",
+	"isMemberOfGroupName",
+	"isMemberOfGroupNameContains",
+	"isMemberOfGroupNameRegex",
+	"isMemberOfGroupNameStartsWith",
+}
+
+// Reference is a reference to a profile property, e.g. user.firstName is a
+// reference to the firstName property of the user profile.
+type Reference struct {
+	// Root is the profile being referenced, user or appuser
+	Root string
+	// Property is the profile property being referenced
+	Property string
+	// Pos is the position of the reference in the expression
+	Pos int
+}
+
+// referenceRoots are the identifiers that reference a profile.
+var referenceRoots = map[string]bool{
+	"user":    true,
+	"appuser": true,
+}
+
+// Validate parses the expression, checks that the functions it calls are
+// Okta Expression Language functions, and returns the profile properties it
+// references.
+func Validate(expr string) ([]Reference, error) {
+	e, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	v := &validator{}
+	v.walk(e)
+	if v.err != nil {
+		return nil, v.err
+	}
+	return v.refs, nil
+}
+
+type validator struct {
+	refs []Reference
+	err  error
+}
+
+func (v *validator) walk(e Expr) {
+	if v.err != nil {
+		return
+	}
+	switch e := e.(type) {
+	case *Member:
+		if root, ok := e.X.(*Ident); ok && referenceRoots[root.Name] {
+			v.refs = append(v.refs, Reference{Root: root.Name, Property: e.Name, Pos: e.pos})
+			return
+		}
+		v.walk(e.X)
+	case *Index:
+		v.walk(e.X)
+		v.walk(e.Index)
+	case *Call:
+		v.checkCall(e)
+		for _, arg := range e.Args {
+			v.walk(arg)
+		}
+	case *Unary:
+		v.walk(e.X)
+	case *Binary:
+		v.walk(e.X)
+		v.walk(e.Y)
+	case *Conditional:
+		v.walk(e.Cond)
+		if e.Then != nil {
+			v.walk(e.Then)
+		}
+		v.walk(e.Else)
+	case *List:
+		for _, elem := range e.Elems {
+			v.walk(elem)
+		}
+	case *Projection:
+		// the projected expression is relative to the collection's
+		// elements and doesn't reference a profile
+		v.walk(e.X)
+	}
+}
+
+func (v *validator) checkCall(call *Call) {
+	switch fun := call.Fun.(type) {
+	case *Ident:
+		if !contains(globalFunctions, fun.Name) {
+			v.err = &SyntaxError{Pos: fun.pos, Msg: fmt.Sprintf("unknown function %q", fun.Name)}
+		}
+	case *Member:
+		class, ok := fun.X.(*Ident)
+		if !ok {
+			// a method of a value, e.g. user.getGroups(), or a call on
+			// the result of another expression
+			v.walk(fun.X)
+			return
+		}
+		functions, isClass := classFunctions[class.Name]
+		if !isClass {
+			// methods of the user and appuser objects, e.g.
+			// user.isMemberOf(), are not profile references
+			return
+		}
+		if !contains(functions, fun.Name) {
+			sorted := append([]string{}, functions...)
+			sort.Strings(sorted)
+			v.err = &SyntaxError{Pos: fun.pos, Msg: fmt.Sprintf("unknown function %s.%s, %s functions are: %s", class.Name, fun.Name, class.Name, strings.Join(sorted, ", "))}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package fakeokta
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	superAdmin    = "SUPER_ADMIN"
+	orgAdmin      = "ORG_ADMIN"
+	readOnlyAdmin = "READ_ONLY_ADMIN"
+)
+
+// reservedEndpoints are the endpoints, by path pattern prefix, only some admin
+// roles can call, whatever the method.
+var reservedEndpoints = []struct {
+	pattern string
+	roles   []string
+}{
+	{"users/*/roles", []string{superAdmin}},
+	{"mappings", []string{superAdmin, orgAdmin}},
+	{"org", []string{superAdmin}},
+	{"internal/rateLimits", []string{superAdmin}},
+	{"internal/orgSettings/rateLimitNotificationSetting", []string{superAdmin}},
+	{"threats/configuration", []string{superAdmin}},
+}
+
+// permitted is false if the token's admin role can't make the request: the
+// reserved endpoints are only open to their roles, except for admins reading
+// their own roles, and a read-only admin can only read.
+func (s *Server) permitted(r *http.Request) bool {
+	if s.adminRole == superAdmin {
+		return true
+	}
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/"), "/")
+	if r.Method == http.MethodGet && len(segments) >= 3 && segments[0] == "users" && segments[2] == "roles" &&
+		(segments[1] == "me" || segments[1] == s.adminID) {
+		return true
+	}
+	for _, endpoint := range reservedEndpoints {
+		pattern := strings.Split(endpoint.pattern, "/")
+		if len(segments) < len(pattern) {
+			continue
+		}
+		if _, ok := match(pattern, segments[:len(pattern)]); ok {
+			for _, role := range endpoint.roles {
+				if role == s.adminRole {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return s.adminRole != readOnlyAdmin || r.Method == http.MethodGet
+}
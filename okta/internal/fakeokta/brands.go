@@ -0,0 +1,272 @@
+package fakeokta
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultLogoPath    = "/assets/img/logos/okta-logo.png"
+	defaultFaviconPath = "/assets/img/favicon.ico"
+)
+
+// themeAssets are the images of a theme by the name of their endpoint, the
+// attribute of the theme holding their URL and the URL of Okta's default, ""
+// if the theme has none by default.
+var themeAssets = map[string]struct{ attr, defaultPath string }{
+	"logo":             {"logo", defaultLogoPath},
+	"favicon":          {"favicon", defaultFaviconPath},
+	"background-image": {"backgroundImage", ""},
+}
+
+// themeVariants are the touch point variants of a theme, a replaced theme
+// must have them.
+var themeVariants = []string{
+	"signInPageTouchPointVariant",
+	"endUserDashboardTouchPointVariant",
+	"errorPageTouchPointVariant",
+	"emailTemplateTouchPointVariant",
+}
+
+// brandRoutes are the endpoints of brand themes and of email customizations
+// that aren't the create, read, update and delete endpoints of a kind.
+func (s *Server) brandRoutes(add func(method, pattern string, h handler)) {
+	add(http.MethodGet, "brands/*/themes", s.listThemes)
+	add(http.MethodGet, "brands/*/themes/*", s.getTheme)
+	add(http.MethodPut, "brands/*/themes/*", s.replaceTheme)
+	add(http.MethodPost, "brands/*/themes/*/*", s.uploadThemeAsset)
+	add(http.MethodDelete, "brands/*/themes/*/*", s.deleteThemeAsset)
+	add(http.MethodPost, "brands/*/templates/email/*/customizations", s.createEmailCustomization)
+	add(http.MethodDelete, "brands/*/templates/email/*/customizations", s.deleteEmailCustomizations)
+}
+
+// prepareBrand makes the org's first brand its default brand.
+func prepareBrand(s *Server, _ url.Values, _, obj object) *apiError {
+	obj["isDefault"] = len(s.list("brands")) == 0
+	if _, ok := obj["removePoweredByOkta"]; !ok {
+		obj["removePoweredByOkta"] = false
+	}
+	return nil
+}
+
+// createBrand adds the brand's theme, a brand has exactly one theme.
+func createBrand(s *Server, _ string, obj object) {
+	id := s.nextID("thm")
+	s.put(themesPath(obj["id"].(string)), id, object{
+		"id":                                id,
+		"logo":                              s.URL + defaultLogoPath,
+		"favicon":                           s.URL + defaultFaviconPath,
+		"primaryColorHex":                   "#1662dd",
+		"primaryColorContrastHex":           "#ffffff",
+		"secondaryColorHex":                 "#ebebed",
+		"secondaryColorContrastHex":         "#000000",
+		"signInPageTouchPointVariant":       "OKTA_DEFAULT",
+		"endUserDashboardTouchPointVariant": "OKTA_DEFAULT",
+		"errorPageTouchPointVariant":        "OKTA_DEFAULT",
+		"emailTemplateTouchPointVariant":    "OKTA_DEFAULT",
+		"loadingPageTouchPointVariant":      "OKTA_DEFAULT",
+	})
+}
+
+func updateBrand(_ *Server, old, obj object) *apiError {
+	obj["isDefault"] = old["isDefault"]
+	return nil
+}
+
+func removeBrand(_ *Server, obj object) (bool, *apiError) {
+	if obj["isDefault"] == true {
+		return false, errForbidden("You do not have permission to perform the requested action")
+	}
+	return true, nil
+}
+
+func themesPath(brandID string) string {
+	return "brands/" + brandID + "/themes"
+}
+
+func (s *Server) brand(id string) (object, *apiError) {
+	return s.find(s.kind("brands"), "brands", id)
+}
+
+func (s *Server) theme(brandID, id string) (object, *apiError) {
+	brand, err := s.brand(brandID)
+	if err != nil {
+		return nil, err
+	}
+	theme, ok := s.get(themesPath(brand["id"].(string)), id)
+	if !ok {
+		return nil, errNotFound(id, "Theme")
+	}
+	return theme, nil
+}
+
+func (s *Server) listThemes(r *http.Request, params []string) (*response, *apiError) {
+	brand, err := s.brand(params[0])
+	if err != nil {
+		return nil, err
+	}
+	return s.listResponse(r, s.list(themesPath(brand["id"].(string))))
+}
+
+func (s *Server) getTheme(_ *http.Request, params []string) (*response, *apiError) {
+	theme, err := s.theme(params[0], params[1])
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: http.StatusOK, body: copyObject(theme)}, nil
+}
+
+// replaceTheme replaces the colors and touch point variants of a theme, its
+// images are uploaded and deleted on their own endpoints.
+func (s *Server) replaceTheme(r *http.Request, params []string) (*response, *apiError) {
+	theme, err := s.theme(params[0], params[1])
+	if err != nil {
+		return nil, err
+	}
+	obj, err := decode(r)
+	if err != nil {
+		return nil, err
+	}
+	var causes []string
+	for _, attr := range append([]string{"primaryColorHex", "secondaryColorHex"}, themeVariants...) {
+		if str(obj, attr) == "" {
+			causes = append(causes, blank(attr))
+		}
+	}
+	if len(causes) > 0 {
+		return nil, errValidation(causes...)
+	}
+	for _, asset := range themeAssets {
+		if v, ok := theme[asset.attr]; ok {
+			obj[asset.attr] = v
+		} else {
+			delete(obj, asset.attr)
+		}
+	}
+	obj["id"] = theme["id"]
+	s.put(themesPath(params[0]), params[1], obj)
+	return &response{status: http.StatusOK, body: copyObject(obj)}, nil
+}
+
+// uploadThemeAsset stores the image of the multipart file field and serves it
+// from a new URL, like Okta's CDN.
+func (s *Server) uploadThemeAsset(r *http.Request, params []string) (*response, *apiError) {
+	theme, err := s.theme(params[0], params[1])
+	if err != nil {
+		return nil, err
+	}
+	asset, ok := themeAssets[params[2]]
+	if !ok {
+		return s.unimplementedRoute(r)
+	}
+	file, header, ferr := r.FormFile("file")
+	if ferr != nil {
+		return nil, errValidation("file: The field cannot be left blank")
+	}
+	defer file.Close()
+	content, _ := io.ReadAll(file)
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if !strings.HasPrefix(http.DetectContentType(content), "image/") && ext != ".svg" {
+		return nil, errValidation("file: The file must be an image")
+	}
+	path := "/assets/" + theme["id"].(string) + "/" + params[2] + "-" + s.nextID("ast") + ext
+	s.assets[path] = content
+	theme[asset.attr] = s.URL + path
+	return &response{status: http.StatusCreated, body: object{"url": s.URL + path}}, nil
+}
+
+// deleteThemeAsset reverts an image of a theme to Okta's default.
+func (s *Server) deleteThemeAsset(r *http.Request, params []string) (*response, *apiError) {
+	theme, err := s.theme(params[0], params[1])
+	if err != nil {
+		return nil, err
+	}
+	asset, ok := themeAssets[params[2]]
+	if !ok {
+		return s.unimplementedRoute(r)
+	}
+	if asset.defaultPath == "" {
+		delete(theme, asset.attr)
+	} else {
+		theme[asset.attr] = s.URL + asset.defaultPath
+	}
+	return &response{status: http.StatusNoContent}, nil
+}
+
+// collectionOf returns the path of the collection holding the object with the
+// given ID, IDs are unique across collections.
+func (s *Server) collectionOf(id string) string {
+	for path, c := range s.collections {
+		if _, ok := c.objects[id]; ok && !c.reference {
+			return path
+		}
+	}
+	return ""
+}
+
+// createEmailCustomization makes the first customization of a template its
+// default, later customizations can't be created as the default.
+func (s *Server) createEmailCustomization(r *http.Request, params []string) (*response, *apiError) {
+	k := s.kind("brands/*/templates/email/*/customizations")
+	path := fill(k.path, params)
+	if _, err := s.parentOf(k, path); err != nil {
+		return nil, err
+	}
+	obj, err := decode(r)
+	if err != nil {
+		return nil, err
+	}
+	existing := s.list(path)
+	if obj["isDefault"] == true && len(existing) > 0 {
+		return nil, errConflict("Could not create the email customization because it conflicts with an existing email customization.")
+	}
+	obj["isDefault"] = len(existing) == 0
+	obj, err = s.insert(k, path, r.URL.Query(), obj)
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: http.StatusCreated, body: copyObject(obj)}, nil
+}
+
+func (s *Server) deleteEmailCustomizations(_ *http.Request, params []string) (*response, *apiError) {
+	k := s.kind("brands/*/templates/email/*/customizations")
+	path := fill(k.path, params)
+	if _, err := s.parentOf(k, path); err != nil {
+		return nil, err
+	}
+	for _, obj := range s.list(path) {
+		s.remove(path, obj["id"].(string))
+	}
+	return &response{status: http.StatusNoContent}, nil
+}
+
+// updateEmailCustomization makes a customization the default in place of the
+// previous default, the default can't be updated to not be the default.
+func updateEmailCustomization(s *Server, old, obj object) *apiError {
+	if obj["language"] != old["language"] {
+		return errValidation("language: The language of an email customization can't be changed")
+	}
+	if obj["isDefault"] != true {
+		if old["isDefault"] == true {
+			return errConflict("Could not update the email customization because it is the default.")
+		}
+		obj["isDefault"] = false
+		return nil
+	}
+	for _, other := range s.list(s.collectionOf(old["id"].(string))) {
+		other["isDefault"] = false
+	}
+	return nil
+}
+
+// removeEmailCustomization refuses to delete the default customization while
+// the template has other customizations.
+func removeEmailCustomization(s *Server, obj object) (bool, *apiError) {
+	if obj["isDefault"] == true && len(s.list(s.collectionOf(obj["id"].(string)))) > 1 {
+		return false, errConflict("Could not delete the email customization because it is the default.")
+	}
+	return true, nil
+}
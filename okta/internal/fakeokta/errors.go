@@ -0,0 +1,99 @@
+package fakeokta
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// apiError is an error response of the Okta API.
+type apiError struct {
+	status  int
+	code    string
+	summary string
+	causes  []string
+}
+
+func writeError(w http.ResponseWriter, requestID string, e *apiError) {
+	causes := make([]map[string]string, len(e.causes))
+	for i, cause := range e.causes {
+		causes[i] = map[string]string{"errorSummary": cause}
+	}
+	w.WriteHeader(e.status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"errorCode":    e.code,
+		"errorSummary": e.summary,
+		"errorLink":    e.code,
+		"errorId":      requestID,
+		"errorCauses":  causes,
+	})
+}
+
+func errValidation(causes ...string) *apiError {
+	return &apiError{http.StatusBadRequest, "E0000001", "Api validation failed: " + firstField(causes), causes}
+}
+
+func errMalformedBody() *apiError {
+	return &apiError{http.StatusBadRequest, "E0000003", "The request body was not well-formed.", nil}
+}
+
+func errInvalidParameter(name string) *apiError {
+	return &apiError{http.StatusBadRequest, "E0000001", "Api validation failed: " + name, []string{name + ": The value is invalid."}}
+}
+
+func errInvalidSearch() *apiError {
+	return &apiError{http.StatusBadRequest, "E0000031", "Invalid search criteria.", nil}
+}
+
+func errInvalidToken() *apiError {
+	return &apiError{http.StatusUnauthorized, "E0000011", "Invalid token provided", nil}
+}
+
+func errForbidden(summary string) *apiError {
+	return &apiError{http.StatusForbidden, "E0000006", summary, nil}
+}
+
+func errNotFound(id, kind string) *apiError {
+	return &apiError{http.StatusNotFound, "E0000007", fmt.Sprintf("Not found: Resource not found: %s (%s)", id, kind), nil}
+}
+
+func errNotImplemented(method, path string) *apiError {
+	return &apiError{http.StatusNotFound, "E0000007", fmt.Sprintf("Not found: Resource not found: %s %s isn't implemented by the fake Okta API", method, path), nil}
+}
+
+func errMethodNotAllowed() *apiError {
+	return &apiError{http.StatusMethodNotAllowed, "E0000022", "The endpoint does not support the provided HTTP method", nil}
+}
+
+func errDeleteAppForbidden() *apiError {
+	return &apiError{http.StatusForbidden, "E0000056", "Delete application forbidden.", []string{"Deactivate the application before deleting it."}}
+}
+
+func errConflict(summary string) *apiError {
+	return &apiError{http.StatusConflict, "E0000182", summary, nil}
+}
+
+func errRateLimited() *apiError {
+	return &apiError{http.StatusTooManyRequests, "E0000047", "API call exceeded rate limit due to too many requests.", nil}
+}
+
+// firstField is the field named by the first "field: message" cause.
+func firstField(causes []string) string {
+	if len(causes) == 0 {
+		return ""
+	}
+	for i, c := range causes[0] {
+		if c == ':' {
+			return causes[0][:i]
+		}
+	}
+	return causes[0]
+}
+
+func blank(field string) string {
+	return field + ": The field cannot be left blank"
+}
+
+func taken(field string) string {
+	return field + ": An object with this field already exists in the current organization"
+}
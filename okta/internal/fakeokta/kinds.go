@@ -0,0 +1,944 @@
+package fakeokta
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ruleTypes = map[string]string{
+	"ACCESS_POLICY":      "ACCESS_POLICY",
+	"IDP_DISCOVERY":      "IDP_DISCOVERY",
+	"MFA_ENROLL":         "MFA_ENROLL",
+	"OKTA_SIGN_ON":       "SIGN_ON",
+	"PASSWORD":           "PASSWORD",
+	"PROFILE_ENROLLMENT": "PROFILE_ENROLLMENT",
+}
+
+var appNames = map[string]string{
+	"BOOKMARK":       "bookmark",
+	"OPENID_CONNECT": "oidc_client",
+}
+
+var nonWord = regexp.MustCompile(`\W+`)
+
+func (s *Server) kinds() []*kind {
+	return []*kind{
+		{
+			path:    "users",
+			name:    "User",
+			prefix:  "00u",
+			search:  []string{"profile.login", "profile.email", "profile.firstName", "profile.lastName"},
+			unique:  "profile.login",
+			partial: true,
+			prepare: prepareUser,
+			update:  updateUser,
+			remove:  removeUser,
+			actions: map[string]func(s *Server, obj object) (*response, *apiError){
+				"activate":        activateUser,
+				"deactivate":      setStatus("DEPROVISIONED"),
+				"suspend":         suspendUser,
+				"unsuspend":       setStatus("ACTIVE"),
+				"unlock":          setStatus("ACTIVE"),
+				"expire_password": expirePassword,
+				"reset_factors":   unchanged,
+				"reset_password":  setStatus("RECOVERY"),
+			},
+		},
+		{
+			path:    "groups",
+			name:    "Group",
+			prefix:  "00g",
+			search:  []string{"profile.name"},
+			filters: []string{"type"},
+			require: []string{"profile.name"},
+			unique:  "profile.name",
+			prepare: prepareGroup,
+			update:  updateGroup,
+			remove:  removeGroup,
+		},
+		{
+			path:    "apps",
+			name:    "App",
+			prefix:  "0oa",
+			search:  []string{"label", "name"},
+			related: appAssignments,
+			require: []string{"label"},
+			prepare: prepareApp,
+			created: createApp,
+			update:  updateApp,
+			remove:  removeApp,
+			deleted: deleteAppSchema,
+			actions: activation,
+		},
+		{
+			path:    "apps/*/credentials/secrets",
+			name:    "OAuth2ClientSecret",
+			prefix:  "ocs",
+			prepare: prepareClientSecret,
+			remove:  removeClientSecret,
+			actions: map[string]func(s *Server, obj object) (*response, *apiError){
+				"activate":   setStatusReturned("ACTIVE"),
+				"deactivate": deactivateClientSecret,
+			},
+		},
+		{
+			path:    "apps/*/credentials/csrs",
+			name:    "CSR",
+			prefix:  "csr",
+			require: []string{"subject.commonName"},
+			prepare: prepareCsr,
+		},
+		{
+			path:    "brands",
+			name:    "Brand",
+			prefix:  "bnd",
+			require: []string{"name"},
+			unique:  "name",
+			prepare: prepareBrand,
+			created: createBrand,
+			update:  updateBrand,
+			remove:  removeBrand,
+		},
+		{
+			path:    "brands/*/templates/email/*/customizations",
+			name:    "EmailCustomization",
+			prefix:  "oel",
+			require: []string{"language", "subject", "body"},
+			unique:  "language",
+			update:  updateEmailCustomization,
+			remove:  removeEmailCustomization,
+		},
+		{
+			path:         "policies",
+			name:         "Policy",
+			prefix:       "00p",
+			filters:      []string{"type", "status"},
+			listRequires: "type",
+			require:      []string{"type", "name"},
+			prepare:      preparePolicy,
+			update:       updatePolicy,
+			remove:       removeSystem,
+			actions:      activation,
+		},
+		{
+			path:    "policies/*/rules",
+			name:    "PolicyRule",
+			prefix:  "0pr",
+			require: []string{"name"},
+			prepare: prepareRule,
+			update:  updateRule,
+			remove:  removeSystem,
+			actions: activation,
+		},
+		{
+			path:    "authorizationServers",
+			name:    "AuthorizationServer",
+			prefix:  "aus",
+			search:  []string{"name"},
+			require: []string{"name", "audiences"},
+			prepare: prepareAuthServer,
+			created: createAuthServer,
+			update:  updateAuthServer,
+			actions: activation,
+		},
+		{
+			path:    "authorizationServers/*/scopes",
+			name:    "OAuth2Scope",
+			prefix:  "scp",
+			search:  []string{"name"},
+			require: []string{"name"},
+			unique:  "name",
+			prepare: prepareScope,
+			remove:  removeSystem,
+		},
+		{
+			path:    "authorizationServers/*/claims",
+			name:    "OAuth2Claim",
+			prefix:  "ocl",
+			require: []string{"name", "claimType"},
+			prepare: prepareClaim,
+			remove:  removeSystem,
+		},
+		{
+			path:    "authorizationServers/*/policies",
+			name:    "AuthorizationServerPolicy",
+			prefix:  "00p",
+			require: []string{"name"},
+			prepare: preparePolicy,
+			update:  updatePolicy,
+			actions: activation,
+		},
+		{
+			path:    "authorizationServers/*/policies/*/rules",
+			name:    "AuthorizationServerPolicyRule",
+			prefix:  "0pr",
+			require: []string{"name"},
+			prepare: prepareRule,
+			update:  updateRule,
+			actions: activation,
+		},
+		{
+			path:    "idps",
+			name:    "IdentityProvider",
+			prefix:  "0oa",
+			search:  []string{"name"},
+			filters: []string{"type"},
+			require: []string{"type", "name"},
+			prepare: prepareIdp,
+			actions: activation,
+		},
+		{
+			path:    "meta/types/user",
+			name:    "UserType",
+			prefix:  "oty",
+			require: []string{"name", "displayName"},
+			unique:  "name",
+			prepare: prepareUserType,
+			created: createUserTypeSchema,
+			remove:  removeDefault,
+			deleted: deleteUserTypeSchema,
+		},
+		{
+			path:    "logStreams",
+			name:    "LogStream",
+			prefix:  "0oa",
+			filters: []string{"type", "status"},
+			require: []string{"type", "name"},
+			unique:  "name",
+			prepare: prepareLogStream,
+			update:  updateLogStream,
+			remove:  removeLogStream,
+			actions: map[string]func(s *Server, obj object) (*response, *apiError){
+				"activate":   setStatusReturned("ACTIVE"),
+				"deactivate": setStatusReturned("INACTIVE"),
+			},
+		},
+	}
+}
+
+func (s *Server) kind(path string) *kind {
+	k, ok := s.kindsByPath[path]
+	if !ok {
+		panic("fakeokta: unknown kind " + path)
+	}
+	return k
+}
+
+// seed adds the objects every Okta org starts with.
+func (s *Server) seed() {
+	userType, _ := s.insert(s.kind("meta/types/user"), "meta/types/user", nil, object{
+		"name":        "user",
+		"displayName": "User",
+		"description": "Okta user profile template with default permission settings",
+		"default":     true,
+	})
+	s.schemas["group/default"] = s.newSchema("group/default", "group", "Okta group", groupBaseProperties, []string{"name"})
+	s.defaultUserType = userType["id"].(string)
+
+	admin, _ := s.insert(s.kind("users"), "users", nil, object{
+		"profile": object{
+			"login":     "admin@fake.okta.test",
+			"email":     "admin@fake.okta.test",
+			"firstName": "Fake",
+			"lastName":  "Admin",
+		},
+		"credentials": object{"password": object{"value": "fake"}},
+	})
+	s.adminID = admin["id"].(string)
+	s.putRole(s.adminID, s.adminRole)
+
+	_, _ = s.insert(s.kind("groups"), "groups", nil, object{
+		"type":    "BUILT_IN",
+		"profile": object{"name": "Everyone", "description": "All users in your organization"},
+	})
+
+	for _, p := range []struct{ policyType, name string }{
+		{"OKTA_SIGN_ON", "Default Policy"},
+		{"PASSWORD", "Default Policy"},
+		{"MFA_ENROLL", "Default Policy"},
+		{"IDP_DISCOVERY", "Idp Discovery Policy"},
+		{"ACCESS_POLICY", "Default Policy"},
+		{"PROFILE_ENROLLMENT", "Default Policy"},
+	} {
+		policy, _ := s.insert(s.kind("policies"), "policies", nil, object{
+			"type":        p.policyType,
+			"name":        p.name,
+			"description": "The default policy applies in all situations if no other policy applies.",
+			"system":      true,
+		})
+		_, _ = s.insert(s.kind("policies/*/rules"), "policies/"+policy["id"].(string)+"/rules", nil, object{
+			"name":   "Default Rule",
+			"system": true,
+		})
+	}
+
+	_, _ = s.insert(s.kind("authorizationServers"), "authorizationServers", nil, object{
+		"name":        "default",
+		"description": "Default Authorization Server",
+		"audiences":   []interface{}{"api://default"},
+	})
+
+	brand, _ := s.insert(s.kind("brands"), "brands", nil, object{
+		"name":      "fake-okta-default",
+		"isDefault": true,
+	})
+	s.defaultBrandID = brand["id"].(string)
+}
+
+func prepareUser(s *Server, query url.Values, _, obj object) *apiError {
+	if _, ok := lookup(obj, "type.id"); !ok {
+		obj["type"] = object{"id": s.defaultUserType}
+	}
+	if err := s.validateProfile(obj); err != nil {
+		return err
+	}
+	id := obj["id"].(string)
+	s.scrubCredentials(id, obj)
+	switch {
+	case query.Get("activate") == "false":
+		obj["status"] = "STAGED"
+	case s.passwords[id]:
+		obj["status"] = "ACTIVE"
+		obj["activated"] = s.timestamp()
+	default:
+		obj["status"] = "PROVISIONED"
+		obj["activated"] = s.timestamp()
+	}
+	obj["statusChanged"] = s.timestamp()
+	obj["lastLogin"] = nil
+	obj["passwordChanged"] = nil
+	return nil
+}
+
+func updateUser(s *Server, old, obj object) *apiError {
+	if _, ok := lookup(obj, "type.id"); !ok {
+		obj["type"] = old["type"]
+	}
+	if err := s.validateProfile(obj); err != nil {
+		return err
+	}
+	s.scrubCredentials(obj["id"].(string), obj)
+	return nil
+}
+
+// scrubCredentials remembers that a user has a password and removes the
+// secrets from the user as the API never returns them.
+func (s *Server) scrubCredentials(id string, obj object) {
+	creds, ok := obj["credentials"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if password, ok := creds["password"].(map[string]interface{}); ok && len(password) > 0 {
+		s.passwords[id] = true
+		creds["password"] = object{}
+	}
+	if question, ok := creds["recovery_question"].(map[string]interface{}); ok {
+		delete(question, "answer")
+	}
+	creds["provider"] = object{"type": "OKTA", "name": "OKTA"}
+}
+
+// validateProfile checks a user's profile against the schema of its user
+// type.
+func (s *Server) validateProfile(obj object) *apiError {
+	schema, ok := s.schemas[s.userSchemaKey(str(obj, "type.id"))]
+	if !ok {
+		return errValidation("type: The user type is invalid.")
+	}
+	profile := child(obj, "profile")
+	var causes []string
+	for _, def := range []string{"base", "custom"} {
+		required, _ := lookup(schema, "definitions."+def+".required")
+		list, _ := required.([]interface{})
+		for _, name := range list {
+			if v, ok := profile[name.(string)]; !ok || v == nil || v == "" {
+				causes = append(causes, blank(name.(string)))
+			}
+		}
+	}
+	for name := range profile {
+		_, base := lookup(schema, "definitions.base.properties."+name)
+		_, custom := lookup(schema, "definitions.custom.properties."+name)
+		if !base && !custom {
+			causes = append(causes, fmt.Sprintf("Property name '%s' is not defined in profile", name))
+		}
+	}
+	if len(causes) > 0 {
+		return &apiError{http.StatusBadRequest, "E0000001", "Api validation failed: newUser", causes}
+	}
+	return nil
+}
+
+func removeUser(s *Server, obj object) (bool, *apiError) {
+	if obj["status"] == "DEPROVISIONED" {
+		delete(s.passwords, obj["id"].(string))
+		return true, nil
+	}
+	obj["status"] = "DEPROVISIONED"
+	obj["statusChanged"] = s.timestamp()
+	return false, nil
+}
+
+func activateUser(s *Server, obj object) (*response, *apiError) {
+	if obj["status"] == "ACTIVE" {
+		return nil, &apiError{http.StatusForbidden, "E0000016", "Activation failed because the user is already active", nil}
+	}
+	obj["status"] = "PROVISIONED"
+	if s.passwords[obj["id"].(string)] {
+		obj["status"] = "ACTIVE"
+	}
+	obj["activated"] = s.timestamp()
+	return &response{status: http.StatusOK, body: object{}}, nil
+}
+
+func suspendUser(s *Server, obj object) (*response, *apiError) {
+	if obj["status"] != "ACTIVE" {
+		return nil, errValidation("status: Cannot suspend a user that is not active")
+	}
+	return setStatus("SUSPENDED")(s, obj)
+}
+
+func expirePassword(s *Server, obj object) (*response, *apiError) {
+	obj["status"] = "PASSWORD_EXPIRED"
+	return &response{status: http.StatusOK, body: copyObject(obj)}, nil
+}
+
+// unchanged is a lifecycle operation that doesn't change the object's status.
+func unchanged(_ *Server, _ object) (*response, *apiError) {
+	return &response{status: http.StatusOK, body: object{}}, nil
+}
+
+func prepareGroup(s *Server, _ url.Values, _, obj object) *apiError {
+	if obj["type"] == nil {
+		obj["type"] = "OKTA_GROUP"
+	}
+	obj["objectClass"] = []interface{}{"okta:user_group"}
+	obj["lastMembershipUpdated"] = s.timestamp()
+	return nil
+}
+
+func updateGroup(_ *Server, old, obj object) *apiError {
+	obj["type"] = old["type"]
+	obj["objectClass"] = old["objectClass"]
+	obj["lastMembershipUpdated"] = old["lastMembershipUpdated"]
+	if old["type"] != "OKTA_GROUP" {
+		return errForbidden("You do not have permission to perform the requested action")
+	}
+	return nil
+}
+
+func removeGroup(_ *Server, obj object) (bool, *apiError) {
+	if obj["type"] != "OKTA_GROUP" {
+		return false, errForbidden("You do not have permission to perform the requested action")
+	}
+	return true, nil
+}
+
+func prepareApp(s *Server, query url.Values, _, obj object) *apiError {
+	if str(obj, "name") == "" {
+		name, ok := appNames[str(obj, "signOnMode")]
+		if !ok {
+			name = "fakeorg_" + strings.ToLower(nonWord.ReplaceAllString(str(obj, "label"), "")) + "_1"
+		}
+		obj["name"] = name
+	}
+	obj["status"] = initialStatus(query, "INACTIVE")
+	defaults := object{
+		"accessibility": object{"selfService": false},
+		"visibility": object{
+			"autoSubmitToolbar": false,
+			"hide":              object{"iOS": false, "web": false},
+			"appLinks":          object{},
+		},
+		"features": []interface{}{},
+	}
+	for k, v := range defaults {
+		if obj[k] == nil {
+			obj[k] = v
+		}
+	}
+	setOAuthClient(obj, nil)
+	return nil
+}
+
+func updateApp(s *Server, old, obj object) *apiError {
+	if str(obj, "name") == "" {
+		obj["name"] = old["name"]
+	}
+	if kid := str(old, "credentials.signing.kid"); kid != "" {
+		signing := child(child(obj, "credentials"), "signing")
+		if str(signing, "kid") == "" {
+			signing["kid"] = kid
+		}
+		if _, ok := s.get(appKeysPath(old), str(signing, "kid")); !ok {
+			return errValidation("credentials.signing.kid: The key doesn't exist")
+		}
+	}
+	setOAuthClient(obj, old)
+	return nil
+}
+
+// setOAuthClient gives an OpenID Connect app its client ID, the app's ID
+// unless set, and a client secret unless the client is public.
+func setOAuthClient(obj, old object) {
+	if obj["signOnMode"] != "OPENID_CONNECT" {
+		return
+	}
+	client := child(child(obj, "credentials"), "oauthClient")
+	if old != nil {
+		if v := str(old, "credentials.oauthClient.client_id"); v != "" && str(client, "client_id") == "" {
+			client["client_id"] = v
+		}
+		if v := str(old, "credentials.oauthClient.client_secret"); v != "" && str(client, "client_secret") == "" {
+			client["client_secret"] = v
+		}
+	}
+	if str(client, "client_id") == "" {
+		client["client_id"] = obj["id"]
+	}
+	if str(client, "token_endpoint_auth_method") == "" {
+		client["token_endpoint_auth_method"] = "client_secret_basic"
+	}
+	switch client["token_endpoint_auth_method"] {
+	case "none", "private_key_jwt":
+		delete(client, "client_secret")
+	default:
+		if str(client, "client_secret") == "" {
+			client["client_secret"] = "secret-" + obj["id"].(string)
+		}
+	}
+}
+
+func removeApp(_ *Server, obj object) (bool, *apiError) {
+	if obj["status"] != "INACTIVE" {
+		return false, errDeleteAppForbidden()
+	}
+	return true, nil
+}
+
+// createApp adds the app's user schema, for OpenID Connect apps with a client
+// secret the secret as the app's first client secret and for SAML apps the
+// signing key and the link to the SAML metadata.
+func createApp(s *Server, path string, obj object) {
+	createAppSchema(s, path, obj)
+	if secret := str(obj, "credentials.oauthClient.client_secret"); secret != "" {
+		_, _ = s.insert(s.kind("apps/*/credentials/secrets"), clientSecretsPath(obj), nil, object{"client_secret": secret})
+	}
+	if obj["signOnMode"] == "SAML_2_0" {
+		kid := s.nextID("kid")
+		s.putAppKey(obj, kid, base64.StdEncoding.EncodeToString([]byte("fake certificate of "+kid)), 10)
+		child(child(obj, "credentials"), "signing")["kid"] = kid
+		child(obj, "_links")["metadata"] = object{"href": s.URL + "/api/v1/apps/" + obj["id"].(string) + "/sso/saml/metadata"}
+	}
+}
+
+func createAppSchema(s *Server, _ string, obj object) {
+	key := "apps/" + obj["id"].(string) + "/default"
+	s.schemas[key] = s.newSchema(key, obj["name"].(string), obj["label"].(string)+" User", appUserBaseProperties, []string{"userName"})
+}
+
+func deleteAppSchema(s *Server, obj object) {
+	delete(s.schemas, "apps/"+obj["id"].(string)+"/default")
+}
+
+// preparePolicy sets a policy's status and puts it last unless a priority is
+// given. Authorization server policies are OAUTH_AUTHORIZATION_POLICY.
+func preparePolicy(s *Server, query url.Values, parent, obj object) *apiError {
+	path := "policies"
+	if parent != nil {
+		obj["type"] = "OAUTH_AUTHORIZATION_POLICY"
+		path = "authorizationServers/" + parent["id"].(string) + "/policies"
+	}
+	obj["status"] = initialStatus(query, "INACTIVE")
+	if obj["system"] == nil {
+		obj["system"] = false
+	}
+	prioritize(policiesOfType(s.list(path), obj), obj)
+	return nil
+}
+
+// updatePolicy moves a policy to its new priority.
+func updatePolicy(s *Server, old, obj object) *apiError {
+	if obj["type"] != old["type"] {
+		return errValidation("type: The type of a policy can't be changed")
+	}
+	prioritize(policiesOfType(s.list(s.collectionOf(old["id"].(string))), obj), obj)
+	return nil
+}
+
+func policiesOfType(policies []object, policy object) []object {
+	var ofType []object
+	for _, other := range policies {
+		if other["type"] == policy["type"] {
+			ofType = append(ofType, other)
+		}
+	}
+	return ofType
+}
+
+// prepareRule sets a rule's type after its policy's, its status and puts it
+// last unless a priority is given.
+func prepareRule(s *Server, query url.Values, parent, obj object) *apiError {
+	if str(obj, "type") == "" {
+		ruleType, ok := ruleTypes[str(parent, "type")]
+		if parent["type"] == "OAUTH_AUTHORIZATION_POLICY" {
+			ruleType, ok = "RESOURCE_ACCESS", true
+		}
+		if ok {
+			obj["type"] = ruleType
+		}
+	}
+	obj["status"] = initialStatus(query, "INACTIVE")
+	if obj["system"] == nil {
+		obj["system"] = false
+	}
+	var rules []object
+	for path := range s.collections {
+		if strings.HasSuffix(path, "/"+parent["id"].(string)+"/rules") {
+			rules = s.list(path)
+		}
+	}
+	prioritize(rules, obj)
+	return nil
+}
+
+// updateRule moves a rule to its new priority.
+func updateRule(s *Server, old, obj object) *apiError {
+	prioritize(s.list(s.collectionOf(old["id"].(string))), obj)
+	return nil
+}
+
+// prioritize moves an object to its priority among the other objects ordered
+// by priority, or last if it has none, shifting the others like Okta. System
+// objects, e.g. the default policy, stay last. The priorities are renumbered
+// from 1.
+func prioritize(others []object, obj object) {
+	var ordered, system []object
+	for _, other := range others {
+		switch {
+		case other["id"] == obj["id"]:
+		case other["system"] == true:
+			system = append(system, other)
+		default:
+			ordered = append(ordered, other)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return priority(ordered[i]) < priority(ordered[j]) })
+	sort.SliceStable(system, func(i, j int) bool { return priority(system[i]) < priority(system[j]) })
+	if obj["system"] == true {
+		system = append(system, obj)
+	} else {
+		i := len(ordered)
+		if p := priority(obj); p > 0 && p <= len(ordered) {
+			i = p - 1
+		}
+		ordered = append(ordered[:i], append([]object{obj}, ordered[i:]...)...)
+	}
+	for i, o := range append(ordered, system...) {
+		o["priority"] = i + 1
+	}
+}
+
+// priority is the priority of an object, 0 if it has none.
+func priority(obj object) int {
+	switch p := obj["priority"].(type) {
+	case int:
+		return p
+	case float64:
+		return int(p)
+	}
+	return 0
+}
+
+func removeSystem(_ *Server, obj object) (bool, *apiError) {
+	if obj["system"] == true {
+		return false, errForbidden("You do not have permission to perform the requested action")
+	}
+	return true, nil
+}
+
+func removeDefault(_ *Server, obj object) (bool, *apiError) {
+	if obj["default"] == true {
+		return false, errForbidden("You do not have permission to perform the requested action")
+	}
+	return true, nil
+}
+
+func prepareAuthServer(s *Server, _ url.Values, _, obj object) *apiError {
+	if str(obj, "issuerMode") == "" {
+		obj["issuerMode"] = "ORG_URL"
+	}
+	obj["issuer"] = s.URL + "/oauth2/" + obj["id"].(string)
+	obj["status"] = "ACTIVE"
+	signing := child(child(obj, "credentials"), "signing")
+	if str(signing, "rotationMode") == "" {
+		signing["rotationMode"] = "AUTO"
+	}
+	signing["use"] = "sig"
+	return nil
+}
+
+func updateAuthServer(s *Server, old, obj object) *apiError {
+	obj["issuer"] = old["issuer"]
+	if str(obj, "issuerMode") == "" {
+		obj["issuerMode"] = old["issuerMode"]
+	}
+	rotationMode := str(obj, "credentials.signing.rotationMode")
+	signing := child(child(copyObject(old), "credentials"), "signing")
+	if rotationMode != "" {
+		signing["rotationMode"] = rotationMode
+	}
+	obj["credentials"] = object{"signing": signing}
+	return nil
+}
+
+// createAuthServer adds the system scopes and the signing keys of a new
+// authorization server.
+func createAuthServer(s *Server, path string, obj object) {
+	id := obj["id"].(string)
+	k := s.kind("authorizationServers/*/scopes")
+	for _, name := range []string{"openid", "profile", "email", "address", "phone", "offline_access", "device_sso"} {
+		_, _ = s.insert(k, path+"/"+id+"/scopes", nil, object{
+			"name":        name,
+			"system":      true,
+			"displayName": name,
+		})
+	}
+	s.putKey(id, "ACTIVE")
+	s.putKey(id, "NEXT")
+}
+
+// putKey adds a signing key to an authorization server, the ACTIVE key is the
+// server's signing key.
+func (s *Server) putKey(authServerID, status string) {
+	kid := s.nextID("key")
+	s.put(keysPath(authServerID), kid, object{
+		"id":     kid,
+		"kid":    kid,
+		"alg":    "RS256",
+		"kty":    "RSA",
+		"use":    "sig",
+		"e":      "AQAB",
+		"n":      base64.RawURLEncoding.EncodeToString([]byte("fake modulus of " + kid)),
+		"status": status,
+	})
+	if status != "ACTIVE" {
+		return
+	}
+	authServer, _ := s.get("authorizationServers", authServerID)
+	signing := child(child(authServer, "credentials"), "signing")
+	now := s.now().UTC()
+	signing["kid"] = kid
+	signing["lastRotated"] = now.Format(timeFormat)
+	signing["nextRotation"] = now.Add(90 * 24 * time.Hour).Format(timeFormat)
+}
+
+// putAppKey adds a signing key with the base64 DER certificate cert, valid for
+// years, to an app.
+func (s *Server) putAppKey(app object, kid, cert string, years int) object {
+	now := s.now().UTC()
+	thumbprint := sha256.Sum256([]byte(cert))
+	key := object{
+		"kid":       kid,
+		"kty":       "RSA",
+		"use":       "sig",
+		"e":         "AQAB",
+		"n":         base64.RawURLEncoding.EncodeToString([]byte("fake modulus of " + kid)),
+		"x5c":       []interface{}{cert},
+		"x5t#S256":  base64.RawURLEncoding.EncodeToString(thumbprint[:]),
+		"created":   now.Format(timeFormat),
+		"expiresAt": now.AddDate(years, 0, 0).Format(timeFormat),
+	}
+	s.put(appKeysPath(app), kid, key)
+	return key
+}
+
+func appKeysPath(app object) string {
+	return "apps/" + app["id"].(string) + "/credentials/keys"
+}
+
+func keysPath(authServerID string) string {
+	return "authorizationServers/" + authServerID + "/credentials/keys"
+}
+
+func prepareScope(_ *Server, _ url.Values, _, obj object) *apiError {
+	defaults := object{
+		"consent":         "IMPLICIT",
+		"metadataPublish": "NO_CLIENTS",
+		"system":          false,
+		"default":         false,
+	}
+	for k, v := range defaults {
+		if obj[k] == nil {
+			obj[k] = v
+		}
+	}
+	return nil
+}
+
+func prepareClaim(_ *Server, _ url.Values, _, obj object) *apiError {
+	defaults := object{
+		"valueType":            "EXPRESSION",
+		"status":               "ACTIVE",
+		"alwaysIncludeInToken": true,
+		"system":               false,
+		"conditions":           object{"scopes": []interface{}{}},
+	}
+	for k, v := range defaults {
+		if obj[k] == nil {
+			obj[k] = v
+		}
+	}
+	return nil
+}
+
+func prepareIdp(_ *Server, _ url.Values, _, obj object) *apiError {
+	obj["status"] = "ACTIVE"
+	return nil
+}
+
+func prepareUserType(s *Server, _ url.Values, _, obj object) *apiError {
+	schemaID := "default"
+	if obj["default"] != true {
+		obj["default"] = false
+		schemaID = s.nextID("osc")
+	}
+	obj["_links"] = object{
+		"schema": object{"href": s.URL + "/api/v1/meta/schemas/user/" + schemaID},
+	}
+	return nil
+}
+
+func createUserTypeSchema(s *Server, _ string, obj object) {
+	key := s.userSchemaKey(obj["id"].(string))
+	s.schemas[key] = s.newSchema(key, "user", obj["displayName"].(string), userBaseProperties, []string{"login", "email", "firstName", "lastName"})
+}
+
+func deleteUserTypeSchema(s *Server, obj object) {
+	delete(s.schemas, s.userSchemaKey(obj["id"].(string)))
+}
+
+// userSchemaKey is the schema of a user type, from the type's schema link.
+func (s *Server) userSchemaKey(typeID string) string {
+	userType, ok := s.get("meta/types/user", typeID)
+	if !ok {
+		return ""
+	}
+	href := str(userType, "_links.schema.href")
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.Path, "/api/v1/meta/schemas/")
+}
+
+// logStreamSecrets are the settings Okta only accepts when a log stream is
+// created, it never returns them.
+var logStreamSecrets = []string{"token"}
+
+func prepareLogStream(_ *Server, _ url.Values, _, obj object) *apiError {
+	obj["status"] = "ACTIVE"
+	settings := child(obj, "settings")
+	for _, secret := range logStreamSecrets {
+		delete(settings, secret)
+	}
+	return nil
+}
+
+func updateLogStream(_ *Server, old, obj object) *apiError {
+	if obj["type"] != old["type"] {
+		return errValidation("type: The type of a log stream can't be changed")
+	}
+	settings := child(obj, "settings")
+	for _, secret := range logStreamSecrets {
+		if _, ok := settings[secret]; ok {
+			return errValidation(secret + ": The field can't be updated")
+		}
+	}
+	return nil
+}
+
+func removeLogStream(_ *Server, obj object) (bool, *apiError) {
+	if obj["status"] == "ACTIVE" {
+		return false, errValidation("status: Deactivate the log stream before deleting it")
+	}
+	return true, nil
+}
+
+// setStatusReturned is a lifecycle operation of log streams and client
+// secrets, which return the object unlike the other objects.
+func setStatusReturned(status string) func(s *Server, obj object) (*response, *apiError) {
+	return func(s *Server, obj object) (*response, *apiError) {
+		obj["status"] = status
+		obj["lastUpdated"] = s.timestamp()
+		return &response{status: http.StatusOK, body: copyObject(obj)}, nil
+	}
+}
+
+// maxClientSecrets is the number of client secrets an app can have.
+const maxClientSecrets = 2
+
+func clientSecretsPath(app object) string {
+	return "apps/" + app["id"].(string) + "/credentials/secrets"
+}
+
+// prepareClientSecret generates the secret unless given, the secret is
+// ACTIVE unless created INACTIVE.
+func prepareClientSecret(s *Server, _ url.Values, app, obj object) *apiError {
+	if app["signOnMode"] != "OPENID_CONNECT" {
+		return errValidation("signOnMode: Client secrets are only supported by OpenID Connect apps")
+	}
+	if len(s.list(clientSecretsPath(app))) >= maxClientSecrets {
+		return errValidation(fmt.Sprintf("client_secret: An app can't have more than %d client secrets", maxClientSecrets))
+	}
+	if str(obj, "client_secret") == "" {
+		obj["client_secret"] = "secret-" + obj["id"].(string)
+	}
+	hash := sha256.Sum256([]byte(str(obj, "client_secret")))
+	obj["secret_hash"] = base64.RawURLEncoding.EncodeToString(hash[:16])
+	if obj["status"] != "INACTIVE" {
+		obj["status"] = "ACTIVE"
+	}
+	return nil
+}
+
+func removeClientSecret(_ *Server, obj object) (bool, *apiError) {
+	if obj["status"] == "ACTIVE" {
+		return false, errValidation("status: Deactivate the client secret before deleting it")
+	}
+	return true, nil
+}
+
+// deactivateClientSecret refuses to deactivate the app's last active secret.
+func deactivateClientSecret(s *Server, obj object) (*response, *apiError) {
+	if obj["status"] == "ACTIVE" {
+		active := 0
+		for path, c := range s.collections {
+			if _, ok := c.objects[obj["id"].(string)]; ok && strings.HasSuffix(path, "/credentials/secrets") {
+				for _, secret := range s.list(path) {
+					if secret["status"] == "ACTIVE" {
+						active++
+					}
+				}
+			}
+		}
+		if active == 1 {
+			return nil, errValidation("status: The last active client secret of an app can't be deactivated")
+		}
+	}
+	return setStatusReturned("INACTIVE")(s, obj)
+}
+
+// prepareCsr generates the base64 DER encoded request of a CSR.
+func prepareCsr(_ *Server, _ url.Values, _, obj object) *apiError {
+	obj["csr"] = base64.StdEncoding.EncodeToString([]byte("fake certificate signing request for " + str(obj, "subject.commonName")))
+	obj["kty"] = "RSA"
+	return nil
+}
@@ -0,0 +1,140 @@
+package fakeokta
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultLogPageLimit = 100
+	maxLogPageLimit     = 1000
+
+	// logRetention is how far back the System Log is read when since isn't
+	// given.
+	logRetention = 7 * 24 * time.Hour
+)
+
+// logRoutes are the endpoints of the System Log.
+func (s *Server) logRoutes(add func(method, pattern string, h handler)) {
+	add(http.MethodGet, "logs", s.listLogs)
+}
+
+// AddLogEvents adds events to the System Log, which the API can't add to.
+// Events without uuid or published get a new uuid and the current time.
+func (s *Server) AddLogEvents(events ...map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range events {
+		e := copyObject(event)
+		if _, ok := e["uuid"]; !ok {
+			e["uuid"] = s.nextID("log")
+		}
+		if _, ok := e["published"]; !ok {
+			e["published"] = s.timestamp()
+		}
+		s.logEvents = append(s.logEvents, e)
+	}
+}
+
+// listLogs pages the events published from since, 7 days ago by default,
+// until before until, now by default. Like Okta, a polling request, one
+// without until, always links to a next page so that new events can be read
+// later, a bounded request only links to a next page when there are more
+// events.
+func (s *Server) listLogs(r *http.Request, _ []string) (*response, *apiError) {
+	query := r.URL.Query()
+	now := s.now().UTC()
+	since, until := now.Add(-logRetention), now
+	for param, t := range map[string]*time.Time{"since": &since, "until": &until} {
+		if v := query.Get(param); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, errInvalidParameter(param)
+			}
+			*t = parsed
+		}
+	}
+	var filter expression
+	if v := query.Get("filter"); v != "" {
+		expr, err := parseExpression(v)
+		if err != nil {
+			return nil, err
+		}
+		filter = expr
+	}
+	limit := defaultLogPageLimit
+	if v := query.Get("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l < 1 {
+			return nil, errInvalidParameter("limit")
+		}
+		limit = l
+	}
+	limit = min(limit, s.maxPage(maxLogPageLimit))
+
+	var events []object
+	for _, event := range s.logEvents {
+		published, _ := time.Parse(time.RFC3339, str(event, "published"))
+		if published.Before(since) || !published.Before(until) {
+			continue
+		}
+		if filter != nil && !filter.matches(event, nil) {
+			continue
+		}
+		if q := query.Get("q"); q != "" && !matchesKeywords(event, q) {
+			continue
+		}
+		events = append(events, event)
+	}
+	if strings.EqualFold(query.Get("sortOrder"), "DESCENDING") {
+		for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+			events[i], events[j] = events[j], events[i]
+		}
+	}
+
+	start := 0
+	if after := query.Get("after"); after != "" {
+		for i, event := range events {
+			if event["uuid"] == after {
+				start = i + 1
+				break
+			}
+		}
+	}
+	end := min(start+limit, len(events))
+	page := make([]object, 0, end-start)
+	for _, event := range events[start:end] {
+		page = append(page, copyObject(event))
+	}
+
+	links := []string{fmt.Sprintf("<%s%s>; rel=\"self\"", s.URL, r.URL.RequestURI())}
+	if query.Get("until") == "" || end < len(events) {
+		next := url.Values{}
+		for k, v := range query {
+			next[k] = v
+		}
+		if end > start {
+			next.Set("after", fmt.Sprint(events[end-1]["uuid"]))
+		}
+		links = append(links, fmt.Sprintf("<%s%s?%s>; rel=\"next\"", s.URL, r.URL.Path, next.Encode()))
+	}
+	return &response{status: http.StatusOK, body: page, links: links}, nil
+}
+
+// matchesKeywords is true if the event contains every keyword of q, ignoring
+// case.
+func matchesKeywords(event object, q string) bool {
+	b, _ := json.Marshal(event)
+	text := strings.ToLower(string(b))
+	for _, keyword := range strings.Fields(strings.ToLower(q)) {
+		if !strings.Contains(text, keyword) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,509 @@
+package fakeokta
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// relationRoutes are the endpoints of group memberships, app assignments, app
+// signing keys, admin role assignments, user credentials and authorization
+// server keys.
+func (s *Server) relationRoutes(add func(method, pattern string, h handler)) {
+	add(http.MethodGet, "groups/*/users", s.listGroupUsers)
+	add(http.MethodPut, "groups/*/users/*", s.addGroupUser)
+	add(http.MethodDelete, "groups/*/users/*", s.removeGroupUser)
+	add(http.MethodGet, "users/*/groups", s.listUserGroups)
+	add(http.MethodGet, "users/*/roles", s.listUserRoles)
+	add(http.MethodPost, "users/*/roles", s.assignUserRole)
+	add(http.MethodGet, "users/*/roles/*", s.getUserRole)
+	add(http.MethodDelete, "users/*/roles/*", s.unassignUserRole)
+	add(http.MethodPost, "users/*/credentials/change_password", s.changeCredentials)
+	add(http.MethodPost, "users/*/credentials/change_recovery_question", s.changeCredentials)
+	add(http.MethodGet, "apps/*/groups", s.listAppGroups)
+	add(http.MethodGet, "apps/*/groups/*", s.getAppGroup)
+	add(http.MethodPut, "apps/*/groups/*", s.assignAppGroup)
+	add(http.MethodDelete, "apps/*/groups/*", s.unassignAppGroup)
+	add(http.MethodGet, "apps/*/users", s.listAppUsers)
+	add(http.MethodPost, "apps/*/users", s.assignAppUser)
+	add(http.MethodGet, "apps/*/users/*", s.getAppUser)
+	add(http.MethodPost, "apps/*/users/*", s.updateAppUser)
+	add(http.MethodDelete, "apps/*/users/*", s.unassignAppUser)
+	add(http.MethodGet, "apps/*/credentials/keys", s.listAppKeys)
+	add(http.MethodPost, "apps/*/credentials/keys/generate", s.generateAppKey)
+	add(http.MethodGet, "apps/*/credentials/keys/*", s.getAppKey)
+	add(http.MethodPost, "apps/*/credentials/csrs/*/lifecycle/publish", s.publishCsr)
+	add(http.MethodGet, "apps/*/sso/saml/metadata", s.samlMetadata)
+	add(http.MethodGet, "authorizationServers/*/credentials/keys", s.listKeys)
+	add(http.MethodPost, "authorizationServers/*/credentials/lifecycle/keyRotate", s.rotateKeys)
+}
+
+func (s *Server) user(id string) (object, *apiError) {
+	return s.find(s.kind("users"), "users", id)
+}
+
+func (s *Server) group(id string) (object, *apiError) {
+	return s.find(s.kind("groups"), "groups", id)
+}
+
+func (s *Server) app(id string) (object, *apiError) {
+	return s.find(s.kind("apps"), "apps", id)
+}
+
+func isEveryone(group object) bool {
+	return group["type"] == "BUILT_IN" && str(group, "profile.name") == "Everyone"
+}
+
+func (s *Server) listGroupUsers(r *http.Request, params []string) (*response, *apiError) {
+	group, err := s.group(params[0])
+	if err != nil {
+		return nil, err
+	}
+	if isEveryone(group) {
+		return s.listResponse(r, s.list("users"))
+	}
+	var users []object
+	for _, member := range s.list("groups/" + group["id"].(string) + "/users") {
+		if user, ok := s.get("users", member["id"].(string)); ok {
+			users = append(users, user)
+		}
+	}
+	return s.listResponse(r, users)
+}
+
+func (s *Server) addGroupUser(_ *http.Request, params []string) (*response, *apiError) {
+	group, err := s.group(params[0])
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.user(params[1])
+	if err != nil {
+		return nil, err
+	}
+	if group["type"] != "OKTA_GROUP" {
+		return nil, errForbidden("You do not have permission to perform the requested action")
+	}
+	id := user["id"].(string)
+	path := "groups/" + group["id"].(string) + "/users"
+	s.reference(path)
+	s.put(path, id, object{"id": id})
+	group["lastMembershipUpdated"] = s.timestamp()
+	return &response{status: http.StatusNoContent}, nil
+}
+
+func (s *Server) removeGroupUser(_ *http.Request, params []string) (*response, *apiError) {
+	group, err := s.group(params[0])
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.user(params[1])
+	if err != nil {
+		return nil, err
+	}
+	if group["type"] != "OKTA_GROUP" {
+		return nil, errForbidden("You do not have permission to perform the requested action")
+	}
+	s.collection("groups/" + group["id"].(string) + "/users").remove(user["id"].(string))
+	group["lastMembershipUpdated"] = s.timestamp()
+	return &response{status: http.StatusNoContent}, nil
+}
+
+func (s *Server) listUserGroups(r *http.Request, params []string) (*response, *apiError) {
+	user, err := s.user(params[0])
+	if err != nil {
+		return nil, err
+	}
+	var groups []object
+	for _, group := range s.list("groups") {
+		if _, ok := s.get("groups/"+group["id"].(string)+"/users", user["id"].(string)); ok || isEveryone(group) {
+			groups = append(groups, group)
+		}
+	}
+	return s.listResponse(r, groups)
+}
+
+// putRole assigns a standard admin role to a user.
+func (s *Server) putRole(userID, roleType string) object {
+	id := s.nextID("ra1")
+	role := object{
+		"id":             id,
+		"label":          roleType,
+		"type":           roleType,
+		"status":         "ACTIVE",
+		"assignmentType": "USER",
+		"created":        s.timestamp(),
+		"lastUpdated":    s.timestamp(),
+	}
+	s.put("users/"+userID+"/roles", id, role)
+	return role
+}
+
+func (s *Server) listUserRoles(r *http.Request, params []string) (*response, *apiError) {
+	user, err := s.user(params[0])
+	if err != nil {
+		return nil, err
+	}
+	return s.listResponse(r, s.list("users/"+user["id"].(string)+"/roles"))
+}
+
+func (s *Server) assignUserRole(r *http.Request, params []string) (*response, *apiError) {
+	user, err := s.user(params[0])
+	if err != nil {
+		return nil, err
+	}
+	body, err := decode(r)
+	if err != nil {
+		return nil, err
+	}
+	roleType := str(body, "type")
+	if roleType == "" {
+		return nil, errValidation(blank("type"))
+	}
+	for _, role := range s.list("users/" + user["id"].(string) + "/roles") {
+		if role["type"] == roleType {
+			return nil, &apiError{http.StatusConflict, "E0000090", "Duplicate role assignment exception.", nil}
+		}
+	}
+	return &response{status: http.StatusCreated, body: copyObject(s.putRole(user["id"].(string), roleType))}, nil
+}
+
+func (s *Server) getUserRole(_ *http.Request, params []string) (*response, *apiError) {
+	user, err := s.user(params[0])
+	if err != nil {
+		return nil, err
+	}
+	role, ok := s.get("users/"+user["id"].(string)+"/roles", params[1])
+	if !ok {
+		return nil, errNotFound(params[1], "RoleAssignment")
+	}
+	return &response{status: http.StatusOK, body: copyObject(role)}, nil
+}
+
+func (s *Server) unassignUserRole(_ *http.Request, params []string) (*response, *apiError) {
+	user, err := s.user(params[0])
+	if err != nil {
+		return nil, err
+	}
+	path := "users/" + user["id"].(string) + "/roles"
+	if _, ok := s.get(path, params[1]); !ok {
+		return nil, errNotFound(params[1], "RoleAssignment")
+	}
+	s.collection(path).remove(params[1])
+	return &response{status: http.StatusNoContent}, nil
+}
+
+func (s *Server) changeCredentials(r *http.Request, params []string) (*response, *apiError) {
+	user, err := s.user(params[0])
+	if err != nil {
+		return nil, err
+	}
+	body, err := decode(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := body["newPassword"]; ok {
+		s.passwords[user["id"].(string)] = true
+		user["passwordChanged"] = s.timestamp()
+	}
+	if question, ok := body["recovery_question"].(map[string]interface{}); ok {
+		child(user, "credentials")["recovery_question"] = object{"question": question["question"]}
+	}
+	return &response{status: http.StatusOK, body: copyObject(child(user, "credentials"))}, nil
+}
+
+// appAssignments are the users and groups assigned to an app, that the
+// user.id and group.id filters of the apps list match.
+func appAssignments(s *Server, app object) map[string][]string {
+	related := map[string][]string{}
+	for attr, path := range map[string]string{"user.id": "users", "group.id": "groups"} {
+		ids := []string{}
+		for _, assignment := range s.list("apps/" + app["id"].(string) + "/" + path) {
+			ids = append(ids, assignment["id"].(string))
+		}
+		related[attr] = ids
+	}
+	return related
+}
+
+func (s *Server) listAppGroups(r *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	return s.listResponse(r, s.list("apps/"+app["id"].(string)+"/groups"))
+}
+
+func (s *Server) getAppGroup(_ *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	assignment, ok := s.get("apps/"+app["id"].(string)+"/groups", params[1])
+	if !ok {
+		return nil, errNotFound(params[1], "ApplicationGroupAssignment")
+	}
+	return &response{status: http.StatusOK, body: copyObject(assignment)}, nil
+}
+
+// assignAppGroup assigns a group to an app or updates the assignment, the
+// group is last unless a priority is given.
+func (s *Server) assignAppGroup(r *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	group, err := s.group(params[1])
+	if err != nil {
+		return nil, err
+	}
+	body, err := decode(r)
+	if err != nil {
+		return nil, err
+	}
+	path := "apps/" + app["id"].(string) + "/groups"
+	id := group["id"].(string)
+	assignment, ok := s.get(path, id)
+	if !ok {
+		assignment = object{"id": id, "priority": len(s.list(path)), "profile": object{}}
+	}
+	if body["priority"] != nil {
+		assignment["priority"] = body["priority"]
+	}
+	if body["profile"] != nil {
+		assignment["profile"] = body["profile"]
+	}
+	assignment["lastUpdated"] = s.timestamp()
+	s.reference(path)
+	s.put(path, id, assignment)
+	return &response{status: http.StatusOK, body: copyObject(assignment)}, nil
+}
+
+func (s *Server) unassignAppGroup(_ *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	path := "apps/" + app["id"].(string) + "/groups"
+	if _, ok := s.get(path, params[1]); !ok {
+		return nil, errNotFound(params[1], "ApplicationGroupAssignment")
+	}
+	s.collection(path).remove(params[1])
+	return &response{status: http.StatusNoContent}, nil
+}
+
+func (s *Server) listAppUsers(r *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	return s.listResponse(r, s.list("apps/"+app["id"].(string)+"/users"))
+}
+
+func (s *Server) getAppUser(_ *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	appUser, ok := s.get("apps/"+app["id"].(string)+"/users", params[1])
+	if !ok {
+		return nil, errNotFound(params[1], "AppUser")
+	}
+	return &response{status: http.StatusOK, body: copyObject(appUser)}, nil
+}
+
+func (s *Server) assignAppUser(r *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	body, err := decode(r)
+	if err != nil {
+		return nil, err
+	}
+	if str(body, "id") == "" {
+		return nil, errValidation(blank("id"))
+	}
+	user, err := s.user(str(body, "id"))
+	if err != nil {
+		return nil, err
+	}
+	id := user["id"].(string)
+	body["id"] = id
+	if str(body, "scope") == "" {
+		body["scope"] = "USER"
+	}
+	creds := child(body, "credentials")
+	delete(creds, "password")
+	if str(creds, "userName") == "" {
+		creds["userName"] = str(user, "profile.login")
+	}
+	child(body, "profile")
+	body["status"] = "ACTIVE"
+	body["syncState"] = "DISABLED"
+	body["created"] = s.timestamp()
+	body["lastUpdated"] = s.timestamp()
+	path := "apps/" + app["id"].(string) + "/users"
+	s.reference(path)
+	s.put(path, id, body)
+	return &response{status: http.StatusOK, body: copyObject(body)}, nil
+}
+
+func (s *Server) updateAppUser(r *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	appUser, ok := s.get("apps/"+app["id"].(string)+"/users", params[1])
+	if !ok {
+		return nil, errNotFound(params[1], "AppUser")
+	}
+	body, err := decode(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"credentials", "profile"} {
+		if v, ok := body[key].(map[string]interface{}); ok {
+			merge(child(appUser, key), v)
+		}
+	}
+	delete(child(appUser, "credentials"), "password")
+	appUser["lastUpdated"] = s.timestamp()
+	return &response{status: http.StatusOK, body: copyObject(appUser)}, nil
+}
+
+func (s *Server) unassignAppUser(_ *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	path := "apps/" + app["id"].(string) + "/users"
+	if _, ok := s.get(path, params[1]); !ok {
+		return nil, errNotFound(params[1], "AppUser")
+	}
+	s.collection(path).remove(params[1])
+	return &response{status: http.StatusNoContent}, nil
+}
+
+func (s *Server) authServer(id string) (object, *apiError) {
+	return s.find(s.kind("authorizationServers"), "authorizationServers", id)
+}
+
+func (s *Server) listKeys(r *http.Request, params []string) (*response, *apiError) {
+	authServer, err := s.authServer(params[0])
+	if err != nil {
+		return nil, err
+	}
+	return s.listResponse(r, s.list(keysPath(authServer["id"].(string))))
+}
+
+// rotateKeys expires the ACTIVE key, activates the NEXT key and adds a new
+// NEXT key.
+func (s *Server) rotateKeys(r *http.Request, params []string) (*response, *apiError) {
+	authServer, err := s.authServer(params[0])
+	if err != nil {
+		return nil, err
+	}
+	id := authServer["id"].(string)
+	var next string
+	for _, key := range s.list(keysPath(id)) {
+		switch key["status"] {
+		case "ACTIVE":
+			key["status"] = "EXPIRED"
+		case "NEXT":
+			key["status"] = "ACTIVE"
+			next = key["kid"].(string)
+		}
+	}
+	if next != "" {
+		signing := child(child(authServer, "credentials"), "signing")
+		signing["kid"] = next
+		signing["lastRotated"] = s.timestamp()
+	}
+	s.putKey(id, "NEXT")
+	return s.listResponse(r, s.list(keysPath(id)))
+}
+
+func (s *Server) listAppKeys(r *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	return s.listResponse(r, s.list(appKeysPath(app)))
+}
+
+func (s *Server) getAppKey(_ *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	key, ok := s.get(appKeysPath(app), params[1])
+	if !ok {
+		return nil, errNotFound(params[1], "JsonWebKey")
+	}
+	return &response{status: http.StatusOK, body: copyObject(key)}, nil
+}
+
+// generateAppKey adds a key with a self signed certificate valid for the
+// validityYears query parameter, between 2 and 10 years.
+func (s *Server) generateAppKey(r *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	years, convErr := strconv.Atoi(r.URL.Query().Get("validityYears"))
+	if convErr != nil || years < 2 || years > 10 {
+		return nil, errInvalidParameter("validityYears")
+	}
+	kid := s.nextID("kid")
+	cert := base64.StdEncoding.EncodeToString([]byte("fake certificate of " + kid))
+	return &response{status: http.StatusCreated, body: copyObject(s.putAppKey(app, kid, cert, years))}, nil
+}
+
+// publishCsr adds the key of the PEM certificate signed for a CSR, the CSR is
+// then removed.
+func (s *Server) publishCsr(r *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	path := "apps/" + app["id"].(string) + "/credentials/csrs"
+	if _, ok := s.get(path, params[1]); !ok {
+		return nil, errNotFound(params[1], "CSR")
+	}
+	body, _ := io.ReadAll(r.Body)
+	pem := string(body)
+	// the v2 sdk sends the certificate as a JSON string
+	_ = json.Unmarshal(body, &pem)
+	pem = strings.TrimSpace(pem)
+	if !strings.HasPrefix(pem, "-----BEGIN CERTIFICATE-----") || !strings.HasSuffix(pem, "-----END CERTIFICATE-----") {
+		return nil, errValidation("certificate: The certificate is not a valid PEM certificate")
+	}
+	lines := strings.Split(pem, "\n")
+	cert := strings.Join(lines[1:len(lines)-1], "")
+	s.collection(path).remove(params[1])
+	return &response{status: http.StatusCreated, body: copyObject(s.putAppKey(app, s.nextID("kid"), cert, 1))}, nil
+}
+
+// samlMetadata is the SAML metadata of an app with the certificate of the kid
+// query parameter, the app's signing key by default.
+func (s *Server) samlMetadata(r *http.Request, params []string) (*response, *apiError) {
+	app, err := s.app(params[0])
+	if err != nil {
+		return nil, err
+	}
+	kid := r.URL.Query().Get("kid")
+	if kid == "" {
+		kid = str(app, "credentials.signing.kid")
+	}
+	key, ok := s.get(appKeysPath(app), kid)
+	if !ok {
+		return nil, errNotFound(kid, "JsonWebKey")
+	}
+	metadata := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?><md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="http://www.okta.com/%s"><md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol"><md:KeyDescriptor use="signing"><ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#"><ds:X509Data><ds:X509Certificate>%s</ds:X509Certificate></ds:X509Data></ds:KeyInfo></md:KeyDescriptor></md:IDPSSODescriptor></md:EntityDescriptor>`,
+		app["id"], key["x5c"].([]interface{})[0])
+	return &response{status: http.StatusOK, contentType: "application/xml", raw: []byte(metadata)}, nil
+}
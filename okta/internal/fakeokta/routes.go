@@ -0,0 +1,440 @@
+package fakeokta
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type response struct {
+	status int
+	body   interface{}
+	links  []string
+	// raw is the body of a response that isn't JSON, of type contentType
+	raw         []byte
+	contentType string
+}
+
+type handler func(r *http.Request, params []string) (*response, *apiError)
+
+// route is an endpoint, its pattern is the path below /api/v1/ where *
+// matches any one segment.
+type route struct {
+	method  string
+	pattern []string
+	handler handler
+}
+
+// kind describes a collection of objects the server fakes the create, read,
+// update, delete and lifecycle endpoints of.
+type kind struct {
+	// path is the collection's path pattern, e.g. "policies/*/rules"
+	path string
+	// name names the object in not found errors, e.g. PolicyRule
+	name   string
+	prefix string
+	parent *kind
+
+	// search are the attributes the q query parameter matches
+	search []string
+	// filters are the query parameters filtering on the attribute of the
+	// same name, e.g. type
+	filters []string
+	// related returns the IDs of the objects related to an object by the
+	// filter attributes it doesn't have, e.g. group.id for an app
+	related func(s *Server, obj object) map[string][]string
+	// listRequires is a query parameter listing the collection requires
+	listRequires string
+	// require are the attributes a new or replaced object must have
+	require []string
+	// unique is an attribute no two objects of the collection can share
+	unique string
+	// partial is true if POST to an object updates it partially
+	partial bool
+
+	// prepare validates a new object and sets its defaults
+	prepare func(s *Server, query url.Values, parent, obj object) *apiError
+	// created adds the objects a new object comes with
+	created func(s *Server, path string, obj object)
+	// update validates an updated object and carries over what the update
+	// can't change
+	update func(s *Server, old, obj object) *apiError
+	// remove checks that an object can be deleted, false keeps it, e.g. a
+	// user that is only deprovisioned
+	remove func(s *Server, obj object) (bool, *apiError)
+	// deleted removes what was added for a deleted object
+	deleted func(s *Server, obj object)
+	// actions are the object's lifecycle operations
+	actions map[string]func(s *Server, obj object) (*response, *apiError)
+}
+
+func (s *Server) route(r *http.Request) (*response, *apiError) {
+	if !strings.HasPrefix(r.URL.Path, "/api/v1/") {
+		return s.unimplementedRoute(r)
+	}
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/"), "/")
+	pathMatched := false
+	for _, rt := range s.routes {
+		params, ok := match(rt.pattern, segments)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if rt.method == r.Method {
+			return rt.handler(r, params)
+		}
+	}
+	if pathMatched {
+		return nil, errMethodNotAllowed()
+	}
+	return s.unimplementedRoute(r)
+}
+
+func (s *Server) unimplementedRoute(r *http.Request) (*response, *apiError) {
+	s.unimplemented = append(s.unimplemented, r.Method+" "+r.URL.Path)
+	return nil, errNotImplemented(r.Method, r.URL.Path)
+}
+
+func match(pattern, segments []string) ([]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	var params []string
+	for i, p := range pattern {
+		switch {
+		case p == "*":
+			params = append(params, segments[i])
+		case p != segments[i]:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// fill replaces the wildcards of a path pattern with params.
+func fill(pattern string, params []string) string {
+	segments := strings.Split(pattern, "/")
+	for i := range segments {
+		if segments[i] == "*" && len(params) > 0 {
+			segments[i], params = params[0], params[1:]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func (s *Server) buildRoutes() []route {
+	var routes []route
+	add := func(method, pattern string, h handler) {
+		routes = append(routes, route{method, strings.Split(pattern, "/"), h})
+	}
+	s.relationRoutes(add)
+	s.schemaRoutes(add)
+	s.logRoutes(add)
+	s.brandRoutes(add)
+
+	kinds := s.kinds()
+	s.kindsByPath = make(map[string]*kind, len(kinds))
+	for _, k := range kinds {
+		s.kindsByPath[k.path] = k
+	}
+	for _, k := range kinds {
+		// the parent is the nearest kind the path is nested in, e.g. brands
+		// for "brands/*/templates/email/*/customizations"
+		for i := strings.LastIndex(k.path, "/*/"); i >= 0 && k.parent == nil; i = strings.LastIndex(k.path[:i], "/*/") {
+			k.parent = s.kindsByPath[k.path[:i]]
+		}
+		add(http.MethodGet, k.path, s.listObjects(k))
+		add(http.MethodPost, k.path, s.createObject(k))
+		add(http.MethodGet, k.path+"/*", s.getObject(k))
+		add(http.MethodPut, k.path+"/*", s.updateObject(k, false))
+		if k.partial {
+			add(http.MethodPost, k.path+"/*", s.updateObject(k, true))
+		}
+		add(http.MethodDelete, k.path+"/*", s.deleteObject(k))
+		add(http.MethodPost, k.path+"/*/lifecycle/*", s.objectLifecycle(k))
+	}
+	return routes
+}
+
+// parentOf returns the object a nested collection belongs to, nil for top
+// level collections.
+func (s *Server) parentOf(k *kind, path string) (object, *apiError) {
+	if k.parent == nil {
+		return nil, nil
+	}
+	segments := strings.Split(path, "/")
+	n := strings.Count(k.parent.path, "/") + 1
+	parentPath := strings.Join(segments[:n], "/")
+	parentID := segments[n]
+	parent, ok := s.get(parentPath, parentID)
+	if !ok {
+		return nil, errNotFound(parentID, k.parent.name)
+	}
+	return parent, nil
+}
+
+// find returns an object of a collection, users can also be found by login
+// and the token's admin as "me".
+func (s *Server) find(k *kind, path, id string) (object, *apiError) {
+	if k.path == "users" && id == "me" {
+		id = s.adminID
+	}
+	if obj, ok := s.get(path, id); ok {
+		return obj, nil
+	}
+	if k.path == "users" {
+		for _, obj := range s.list(path) {
+			if strings.EqualFold(str(obj, "profile.login"), id) {
+				return obj, nil
+			}
+		}
+	}
+	return nil, errNotFound(id, k.name)
+}
+
+func (s *Server) validate(k *kind, path string, obj object) *apiError {
+	var causes []string
+	for _, attr := range k.require {
+		v, ok := lookup(obj, attr)
+		if !ok || v == nil || v == "" {
+			causes = append(causes, blank(attr[strings.LastIndex(attr, ".")+1:]))
+		}
+	}
+	if len(causes) > 0 {
+		return errValidation(causes...)
+	}
+	if k.unique == "" {
+		return nil
+	}
+	value := str(obj, k.unique)
+	for _, other := range s.list(path) {
+		if other["id"] != obj["id"] && strings.EqualFold(str(other, k.unique), value) {
+			return errValidation(taken(k.unique[strings.LastIndex(k.unique, ".")+1:]))
+		}
+	}
+	return nil
+}
+
+// insert adds a new object to a collection as POST to the collection does.
+func (s *Server) insert(k *kind, path string, query url.Values, obj object) (object, *apiError) {
+	parent, err := s.parentOf(k, path)
+	if err != nil {
+		return nil, err
+	}
+	id := s.nextID(k.prefix)
+	obj["id"] = id
+	if err := s.validate(k, path, obj); err != nil {
+		s.counters[k.prefix]--
+		return nil, err
+	}
+	obj["created"] = s.timestamp()
+	obj["lastUpdated"] = s.timestamp()
+	if k.prepare != nil {
+		if err := k.prepare(s, query, parent, obj); err != nil {
+			s.counters[k.prefix]--
+			return nil, err
+		}
+	}
+	s.put(path, id, obj)
+	if k.created != nil {
+		k.created(s, path, obj)
+	}
+	return obj, nil
+}
+
+func (s *Server) listObjects(k *kind) handler {
+	return func(r *http.Request, params []string) (*response, *apiError) {
+		path := fill(k.path, params)
+		if _, err := s.parentOf(k, path); err != nil {
+			return nil, err
+		}
+		query := r.URL.Query()
+		if k.listRequires != "" && query.Get(k.listRequires) == "" {
+			return nil, errInvalidParameter(k.listRequires)
+		}
+		var exprs []expression
+		for _, param := range []string{"filter", "search"} {
+			if v := query.Get(param); v != "" {
+				expr, err := parseExpression(v)
+				if err != nil {
+					return nil, err
+				}
+				exprs = append(exprs, expr)
+			}
+		}
+		var objects []object
+	objects:
+		for _, obj := range s.list(path) {
+			var related map[string][]string
+			if k.related != nil {
+				related = k.related(s, obj)
+			}
+			if q := query.Get("q"); q != "" && !matchesQuery(obj, k.search, q) {
+				continue
+			}
+			for _, f := range k.filters {
+				if v := query.Get(f); v != "" && !strings.EqualFold(str(obj, f), v) {
+					continue objects
+				}
+			}
+			for _, expr := range exprs {
+				if !expr.matches(obj, related) {
+					continue objects
+				}
+			}
+			objects = append(objects, obj)
+		}
+		return s.listResponse(r, objects)
+	}
+}
+
+func (s *Server) listResponse(r *http.Request, objects []object) (*response, *apiError) {
+	page, links, err := s.page(r, objects)
+	if err != nil {
+		return nil, err
+	}
+	body := make([]object, len(page))
+	for i, obj := range page {
+		body[i] = copyObject(obj)
+	}
+	return &response{status: http.StatusOK, body: body, links: links}, nil
+}
+
+func (s *Server) createObject(k *kind) handler {
+	return func(r *http.Request, params []string) (*response, *apiError) {
+		obj, err := decode(r)
+		if err != nil {
+			return nil, err
+		}
+		obj, err = s.insert(k, fill(k.path, params), r.URL.Query(), obj)
+		if err != nil {
+			return nil, err
+		}
+		return &response{status: http.StatusOK, body: copyObject(obj)}, nil
+	}
+}
+
+func (s *Server) getObject(k *kind) handler {
+	return func(r *http.Request, params []string) (*response, *apiError) {
+		path := fill(k.path, params[:len(params)-1])
+		if _, err := s.parentOf(k, path); err != nil {
+			return nil, err
+		}
+		obj, err := s.find(k, path, params[len(params)-1])
+		if err != nil {
+			return nil, err
+		}
+		return &response{status: http.StatusOK, body: copyObject(obj)}, nil
+	}
+}
+
+// updateObject replaces an object with the request's, or merges the request's
+// into it if partial. The ID, timestamps and status can't be updated.
+func (s *Server) updateObject(k *kind, partial bool) handler {
+	return func(r *http.Request, params []string) (*response, *apiError) {
+		path := fill(k.path, params[:len(params)-1])
+		if _, err := s.parentOf(k, path); err != nil {
+			return nil, err
+		}
+		old, err := s.find(k, path, params[len(params)-1])
+		if err != nil {
+			return nil, err
+		}
+		obj, err := decode(r)
+		if err != nil {
+			return nil, err
+		}
+		if partial {
+			merged := copyObject(old)
+			merge(merged, obj)
+			obj = merged
+		}
+		for _, key := range []string{"id", "created", "status", "system", "_links"} {
+			if v, ok := old[key]; ok {
+				obj[key] = v
+			}
+		}
+		if err := s.validate(k, path, obj); err != nil {
+			return nil, err
+		}
+		if k.update != nil {
+			if err := k.update(s, old, obj); err != nil {
+				return nil, err
+			}
+		}
+		obj["lastUpdated"] = s.timestamp()
+		s.put(path, obj["id"].(string), obj)
+		return &response{status: http.StatusOK, body: copyObject(obj)}, nil
+	}
+}
+
+func (s *Server) deleteObject(k *kind) handler {
+	return func(r *http.Request, params []string) (*response, *apiError) {
+		path := fill(k.path, params[:len(params)-1])
+		if _, err := s.parentOf(k, path); err != nil {
+			return nil, err
+		}
+		obj, err := s.find(k, path, params[len(params)-1])
+		if err != nil {
+			return nil, err
+		}
+		if k.remove != nil {
+			ok, err := k.remove(s, obj)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return &response{status: http.StatusNoContent}, nil
+			}
+		}
+		s.remove(path, obj["id"].(string))
+		if k.deleted != nil {
+			k.deleted(s, obj)
+		}
+		return &response{status: http.StatusNoContent}, nil
+	}
+}
+
+func (s *Server) objectLifecycle(k *kind) handler {
+	return func(r *http.Request, params []string) (*response, *apiError) {
+		path := fill(k.path, params[:len(params)-2])
+		if _, err := s.parentOf(k, path); err != nil {
+			return nil, err
+		}
+		obj, err := s.find(k, path, params[len(params)-2])
+		if err != nil {
+			return nil, err
+		}
+		action, ok := k.actions[params[len(params)-1]]
+		if !ok {
+			return s.unimplementedRoute(r)
+		}
+		resp, err := action(s, obj)
+		if err != nil {
+			return nil, err
+		}
+		obj["lastUpdated"] = s.timestamp()
+		return resp, nil
+	}
+}
+
+// setStatus is a lifecycle operation changing an object's status.
+func setStatus(status string) func(s *Server, obj object) (*response, *apiError) {
+	return func(s *Server, obj object) (*response, *apiError) {
+		obj["status"] = status
+		return &response{status: http.StatusOK, body: object{}}, nil
+	}
+}
+
+var activation = map[string]func(s *Server, obj object) (*response, *apiError){
+	"activate":   setStatus("ACTIVE"),
+	"deactivate": setStatus("INACTIVE"),
+}
+
+// initialStatus is ACTIVE unless the activate query parameter is false.
+func initialStatus(query url.Values, inactive string) string {
+	if query.Get("activate") == "false" {
+		return inactive
+	}
+	return "ACTIVE"
+}
@@ -0,0 +1,189 @@
+package fakeokta
+
+import (
+	"net/http"
+)
+
+// userBaseProperties are the names and titles of the base properties of user
+// profiles.
+var userBaseProperties = [][2]string{
+	{"login", "Username"},
+	{"firstName", "First name"},
+	{"lastName", "Last name"},
+	{"middleName", "Middle name"},
+	{"honorificPrefix", "Honorific prefix"},
+	{"honorificSuffix", "Honorific suffix"},
+	{"email", "Primary email"},
+	{"title", "Title"},
+	{"displayName", "Display name"},
+	{"nickName", "Nickname"},
+	{"profileUrl", "Profile Url"},
+	{"secondEmail", "Secondary email"},
+	{"mobilePhone", "Mobile phone"},
+	{"primaryPhone", "Primary phone"},
+	{"streetAddress", "Street address"},
+	{"city", "City"},
+	{"state", "State"},
+	{"zipCode", "Zip code"},
+	{"countryCode", "Country code"},
+	{"postalAddress", "Postal Address"},
+	{"preferredLanguage", "Preferred language"},
+	{"locale", "Locale"},
+	{"timezone", "Time zone"},
+	{"userType", "User type"},
+	{"employeeNumber", "Employee number"},
+	{"costCenter", "Cost center"},
+	{"organization", "Organization"},
+	{"division", "Division"},
+	{"department", "Department"},
+	{"managerId", "ManagerId"},
+	{"manager", "Manager"},
+}
+
+var groupBaseProperties = [][2]string{
+	{"name", "Name"},
+	{"description", "Description"},
+}
+
+var appUserBaseProperties = [][2]string{
+	{"userName", "Username"},
+}
+
+func (s *Server) schemaRoutes(add func(method, pattern string, h handler)) {
+	add(http.MethodGet, "meta/schemas/user/*", s.getSchema("user/", "", "UserSchema"))
+	add(http.MethodPost, "meta/schemas/user/*", s.updateSchema("user/", ""))
+	add(http.MethodGet, "meta/schemas/group/*", s.getSchema("group/", "", "GroupSchema"))
+	add(http.MethodPost, "meta/schemas/group/*", s.updateSchema("group/", ""))
+	add(http.MethodGet, "meta/schemas/apps/*/default", s.getSchema("apps/", "/default", "AppUserSchema"))
+	add(http.MethodPost, "meta/schemas/apps/*/default", s.updateSchema("apps/", "/default"))
+}
+
+// newSchema returns a profile schema with the given base properties and no
+// custom properties.
+func (s *Server) newSchema(key, name, title string, base [][2]string, required []string) object {
+	isRequired := make(map[string]bool, len(required))
+	requiredList := make([]interface{}, len(required))
+	for i, r := range required {
+		isRequired[r] = true
+		requiredList[i] = r
+	}
+	properties := object{}
+	for _, p := range base {
+		property := defaultProperty(object{"title": p[1], "type": "string"})
+		if isRequired[p[0]] {
+			property["required"] = true
+		}
+		properties[p[0]] = property
+	}
+	return object{
+		"id":          s.URL + "/meta/schemas/" + key,
+		"$schema":     "http://json-schema.org/draft-04/schema#",
+		"name":        name,
+		"title":       title,
+		"type":        "object",
+		"created":     s.timestamp(),
+		"lastUpdated": s.timestamp(),
+		"definitions": object{
+			"base": object{
+				"id":         "#base",
+				"type":       "object",
+				"properties": properties,
+				"required":   requiredList,
+			},
+			"custom": object{
+				"id":         "#custom",
+				"type":       "object",
+				"properties": object{},
+				"required":   []interface{}{},
+			},
+		},
+		"properties": object{
+			"profile": object{
+				"allOf": []interface{}{
+					object{"$ref": "#/definitions/custom"},
+					object{"$ref": "#/definitions/base"},
+				},
+			},
+		},
+	}
+}
+
+// defaultProperty sets the defaults Okta gives a profile property.
+func defaultProperty(property object) object {
+	defaults := object{
+		"mutability":  "READ_WRITE",
+		"scope":       "NONE",
+		"permissions": []interface{}{object{"principal": "SELF", "action": "READ_WRITE"}},
+		"master":      object{"type": "PROFILE_MASTER"},
+	}
+	for k, v := range defaults {
+		if property[k] == nil {
+			property[k] = v
+		}
+	}
+	return property
+}
+
+func (s *Server) getSchema(prefix, suffix, name string) handler {
+	return func(_ *http.Request, params []string) (*response, *apiError) {
+		schema, ok := s.schemas[prefix+params[0]+suffix]
+		if !ok {
+			return nil, errNotFound(params[0], name)
+		}
+		return &response{status: http.StatusOK, body: copyObject(schema)}, nil
+	}
+}
+
+// updateSchema adds, replaces and, when set to null, removes the custom
+// properties of a schema, and updates its base properties.
+func (s *Server) updateSchema(prefix, suffix string) handler {
+	return func(r *http.Request, params []string) (*response, *apiError) {
+		key := prefix + params[0] + suffix
+		schema, ok := s.schemas[key]
+		if !ok {
+			return nil, errNotFound(params[0], "Schema")
+		}
+		body, err := decode(r)
+		if err != nil {
+			return nil, err
+		}
+		base := child(child(child(schema, "definitions"), "base"), "properties")
+		custom := child(child(child(schema, "definitions"), "custom"), "properties")
+		updates, _ := lookup(body, "definitions.base.properties")
+		baseUpdates, _ := updates.(map[string]interface{})
+		for name, v := range baseUpdates {
+			property, ok := base[name].(map[string]interface{})
+			update, isMap := v.(map[string]interface{})
+			if !ok || !isMap {
+				return nil, errValidation(name + ": Base properties can't be added or removed.")
+			}
+			merge(property, update)
+		}
+		updates, _ = lookup(body, "definitions.custom.properties")
+		customUpdates, _ := updates.(map[string]interface{})
+		for name, v := range customUpdates {
+			if v == nil {
+				delete(custom, name)
+				continue
+			}
+			property, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, errMalformedBody()
+			}
+			if _, isBase := base[name]; isBase {
+				return nil, errValidation(name + ": A base property with this name already exists.")
+			}
+			for _, attr := range []string{"title", "type"} {
+				if str(property, attr) == "" {
+					return nil, errValidation(blank(attr))
+				}
+			}
+			custom[name] = defaultProperty(property)
+		}
+		if required, ok := lookup(body, "definitions.custom.required"); ok {
+			child(child(schema, "definitions"), "custom")["required"] = required
+		}
+		schema["lastUpdated"] = s.timestamp()
+		return &response{status: http.StatusOK, body: copyObject(schema)}, nil
+	}
+}
@@ -0,0 +1,173 @@
+package fakeokta
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// condition is one comparison of a filter or search expression, e.g.
+// profile.login eq "jane@example.com".
+type condition struct {
+	attr  string
+	op    string
+	value string
+}
+
+// expression is a filter or search expression as conditions joined by "or"
+// of conditions joined by "and". Parentheses aren't supported.
+type expression [][]condition
+
+func parseExpression(expr string) (expression, *apiError) {
+	tokens, ok := tokenize(expr)
+	if !ok || len(tokens) == 0 {
+		return nil, errInvalidSearch()
+	}
+	var result expression
+	var and []condition
+	for i := 0; i < len(tokens); {
+		if i+1 >= len(tokens) {
+			return nil, errInvalidSearch()
+		}
+		c := condition{attr: tokens[i], op: strings.ToLower(tokens[i+1])}
+		i += 2
+		if c.op != "pr" {
+			if i >= len(tokens) {
+				return nil, errInvalidSearch()
+			}
+			c.value = tokens[i]
+			i++
+		}
+		switch c.op {
+		case "eq", "ne", "sw", "co", "pr", "gt", "ge", "lt", "le":
+		default:
+			return nil, errInvalidSearch()
+		}
+		and = append(and, c)
+		if i == len(tokens) {
+			break
+		}
+		switch strings.ToLower(tokens[i]) {
+		case "and":
+		case "or":
+			result = append(result, and)
+			and = nil
+		default:
+			return nil, errInvalidSearch()
+		}
+		i++
+		if i == len(tokens) {
+			return nil, errInvalidSearch()
+		}
+	}
+	return append(result, and), nil
+}
+
+// tokenize splits an expression on spaces outside of double quoted values and
+// unquotes the values. False if the expression has parentheses or an
+// unterminated value.
+func tokenize(expr string) ([]string, bool) {
+	var tokens []string
+	var token strings.Builder
+	quoted, inToken := false, false
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(expr):
+			i++
+			token.WriteByte(expr[i])
+		case c == '"':
+			quoted = !quoted
+			inToken = true
+		case !quoted && (c == '(' || c == ')'):
+			return nil, false
+		case !quoted && c == ' ':
+			if inToken {
+				tokens = append(tokens, token.String())
+				token.Reset()
+				inToken = false
+			}
+		default:
+			token.WriteByte(c)
+			inToken = true
+		}
+	}
+	if quoted {
+		return nil, false
+	}
+	if inToken {
+		tokens = append(tokens, token.String())
+	}
+	return tokens, true
+}
+
+// matches is true if the object satisfies the expression. related are the
+// IDs of the objects related to the object by an attribute it doesn't have,
+// e.g. user.id for the users assigned to an app.
+func (e expression) matches(obj object, related map[string][]string) bool {
+	for _, and := range e {
+		matched := true
+		for _, c := range and {
+			if !c.matches(obj, related) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (c condition) matches(obj object, related map[string][]string) bool {
+	if ids, ok := related[c.attr]; ok {
+		switch c.op {
+		case "eq":
+			return slices.Contains(ids, c.value)
+		case "ne":
+			return !slices.Contains(ids, c.value)
+		}
+		return false
+	}
+	v, ok := lookup(obj, c.attr)
+	if c.op == "pr" {
+		return ok && v != nil
+	}
+	if !ok || v == nil {
+		return c.op == "ne"
+	}
+	actual := strings.ToLower(fmt.Sprint(v))
+	expected := strings.ToLower(c.value)
+	switch c.op {
+	case "eq":
+		return actual == expected
+	case "ne":
+		return actual != expected
+	case "sw":
+		return strings.HasPrefix(actual, expected)
+	case "co":
+		return strings.Contains(actual, expected)
+	case "gt":
+		return actual > expected
+	case "ge":
+		return actual >= expected
+	case "lt":
+		return actual < expected
+	case "le":
+		return actual <= expected
+	}
+	return false
+}
+
+// matchesQuery is true if any of the attributes starts with the q query
+// parameter, ignoring case.
+func matchesQuery(obj object, attrs []string, q string) bool {
+	q = strings.ToLower(q)
+	for _, attr := range attrs {
+		if strings.HasPrefix(strings.ToLower(str(obj, attr)), q) {
+			return true
+		}
+	}
+	return false
+}
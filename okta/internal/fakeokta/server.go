@@ -0,0 +1,493 @@
+// Package fakeokta is a stateful, in-memory fake of the core Okta management
+// API endpoints the provider calls: users, groups, apps and their client
+// secrets and signing keys, policies and their rules, authorization servers,
+// identity providers, user types, profile schemas, log streams, brands and
+// their themes and email customizations and the System Log. It pages lists
+// with Link headers, sends rate limit headers and answers with the error
+// bodies of the real API so the provider can be tested without an Okta org.
+package fakeokta
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultToken is the API token the server accepts unless WithToken is
+	// given.
+	DefaultToken = "fake-okta-api-token"
+
+	// DefaultRateLimit is the number of requests per minute the server allows
+	// per rate limit bucket unless WithRateLimit is given.
+	DefaultRateLimit = 600
+
+	defaultPageLimit = 200
+	timeFormat       = "2006-01-02T15:04:05.000Z"
+)
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithToken sets the API token, sent as SSWS or Bearer authorization, that the
+// server accepts.
+func WithToken(token string) Option {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// WithRateLimit sets the number of requests per minute the server allows per
+// rate limit bucket, the request's method and first path segments.
+func WithRateLimit(limit int) Option {
+	return func(s *Server) {
+		s.rateLimit = limit
+	}
+}
+
+// WithPageLimit sets the largest page of objects the server returns, so that
+// tests can page through a few objects.
+func WithPageLimit(limit int) Option {
+	return func(s *Server) {
+		s.pageLimit = limit
+	}
+}
+
+// WithAdminRole sets the standard admin role, e.g. READ_ONLY_ADMIN, of the
+// token's admin, SUPER_ADMIN unless given. Requests the role can't make are
+// answered with 403 Forbidden.
+func WithAdminRole(role string) Option {
+	return func(s *Server) {
+		s.adminRole = role
+	}
+}
+
+// WithClock sets the function returning the current time, used for created
+// and lastUpdated timestamps and rate limit windows.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+type object = map[string]interface{}
+
+// collection holds the objects created under a collection path, e.g.
+// "policies/00p1/rules", in the order they were created. Reference
+// collections, e.g. "groups/00g1/users", hold the IDs of objects stored
+// elsewhere.
+type collection struct {
+	ids       []string
+	objects   map[string]object
+	reference bool
+}
+
+type bucket struct {
+	window time.Time
+	count  int
+}
+
+// Server is the fake Okta API. It embeds the httptest.Server serving it; its
+// URL is the base URL of the fake org.
+type Server struct {
+	*httptest.Server
+
+	token     string
+	rateLimit int
+	pageLimit int
+	now       func() time.Time
+	routes    []route
+
+	kindsByPath map[string]*kind
+
+	mu              sync.Mutex
+	collections     map[string]*collection
+	schemas         map[string]object
+	passwords       map[string]bool
+	buckets         map[string]*bucket
+	counters        map[string]int
+	logEvents       []object
+	assets          map[string][]byte
+	requests        int
+	adminID         string
+	adminRole       string
+	defaultUserType string
+	defaultBrandID  string
+	unimplemented   []string
+}
+
+// NewServer starts a fake Okta org. It is seeded with the token's admin user,
+// a super admin unless WithAdminRole is given, the Everyone group, the default
+// user type, the default policies, the default authorization server and the
+// default brand. Close it when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		token:       DefaultToken,
+		rateLimit:   DefaultRateLimit,
+		adminRole:   superAdmin,
+		now:         time.Now,
+		collections: make(map[string]*collection),
+		schemas:     make(map[string]object),
+		passwords:   make(map[string]bool),
+		buckets:     make(map[string]*bucket),
+		counters:    make(map[string]int),
+		assets: map[string][]byte{
+			defaultLogoPath:    []byte("fake okta default logo"),
+			defaultFaviconPath: []byte("fake okta default favicon"),
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.routes = s.buildRoutes()
+	s.Server = httptest.NewServer(s)
+	s.seed()
+	return s
+}
+
+// Unimplemented returns the requests, as "METHOD /path", the server answered
+// with a not found error because it doesn't fake their endpoint.
+func (s *Server) Unimplemented() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.unimplemented...)
+}
+
+// Requests returns the number of requests the server answered.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// AdminID is the ID of the admin user owning the API token, returned
+// for GET /api/v1/users/me.
+func (s *Server) AdminID() string {
+	return s.adminID
+}
+
+// DefaultBrandID is the ID of the org's default brand.
+func (s *Server) DefaultBrandID() string {
+	return s.defaultBrandID
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	requestID := fmt.Sprintf("fake%016d", s.requests)
+	w.Header().Set("X-Okta-Request-Id", requestID)
+	w.Header().Set("Content-Type", "application/json")
+
+	// theme images are served without authorization, like from Okta's CDN
+	if strings.HasPrefix(r.URL.Path, "/assets/") && r.Method == http.MethodGet {
+		s.serveAsset(w, r)
+		return
+	}
+	if !s.authorized(r) {
+		writeError(w, requestID, errInvalidToken())
+		return
+	}
+	if !s.allow(w, r) {
+		writeError(w, requestID, errRateLimited())
+		return
+	}
+
+	if !s.permitted(r) {
+		writeError(w, requestID, errForbidden("You do not have permission to perform the requested action"))
+		return
+	}
+
+	resp, apiErr := s.route(r)
+	if apiErr != nil {
+		writeError(w, requestID, apiErr)
+		return
+	}
+	for _, link := range resp.links {
+		w.Header().Add("Link", link)
+	}
+	if resp.raw != nil {
+		w.Header().Set("Content-Type", resp.contentType)
+		w.WriteHeader(resp.status)
+		_, _ = w.Write(resp.raw)
+		return
+	}
+	if resp.body == nil {
+		w.WriteHeader(resp.status)
+		return
+	}
+	w.WriteHeader(resp.status)
+	_ = json.NewEncoder(w).Encode(resp.body)
+}
+
+func (s *Server) serveAsset(w http.ResponseWriter, r *http.Request) {
+	content, ok := s.assets[r.URL.Path]
+	if !ok {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(content))
+	_, _ = w.Write(content)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	for _, scheme := range []string{"SSWS ", "Bearer "} {
+		if strings.HasPrefix(auth, scheme) {
+			return strings.TrimPrefix(auth, scheme) == s.token
+		}
+	}
+	return false
+}
+
+// allow counts the request against its rate limit bucket and sets the
+// X-Rate-Limit headers. False if the bucket is exhausted.
+func (s *Server) allow(w http.ResponseWriter, r *http.Request) bool {
+	now := s.now()
+	window := now.Truncate(time.Minute)
+	key := r.Method + " " + bucketPath(r.URL.Path)
+	b, ok := s.buckets[key]
+	if !ok || !b.window.Equal(window) {
+		b = &bucket{window: window}
+		s.buckets[key] = b
+	}
+	allowed := b.count < s.rateLimit
+	if allowed {
+		b.count++
+	}
+	w.Header().Set("X-Rate-Limit-Limit", strconv.Itoa(s.rateLimit))
+	w.Header().Set("X-Rate-Limit-Remaining", strconv.Itoa(s.rateLimit-b.count))
+	w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(window.Add(time.Minute).Unix(), 10))
+	return allowed
+}
+
+// bucketPath is the path of the rate limit bucket of a request, the collection
+// it addresses, e.g. /api/v1/users or /api/v1/meta/schemas.
+func bucketPath(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/api/v1/"), "/")
+	n := 1
+	if segments[0] == "meta" && len(segments) > 1 {
+		n = 2
+	}
+	return "/api/v1/" + strings.Join(segments[:n], "/")
+}
+
+// nextID returns a new ID with the given prefix, IDs are twenty characters
+// long like Okta's and deterministic.
+func (s *Server) nextID(prefix string) string {
+	s.counters[prefix]++
+	return fmt.Sprintf("%s%0*d", prefix, 20-len(prefix), s.counters[prefix])
+}
+
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format(timeFormat)
+}
+
+func (s *Server) collection(path string) *collection {
+	c, ok := s.collections[path]
+	if !ok {
+		c = &collection{objects: make(map[string]object)}
+		s.collections[path] = c
+	}
+	return c
+}
+
+func (s *Server) get(path, id string) (object, bool) {
+	c, ok := s.collections[path]
+	if !ok {
+		return nil, false
+	}
+	obj, ok := c.objects[id]
+	return obj, ok
+}
+
+func (s *Server) put(path, id string, obj object) {
+	c := s.collection(path)
+	if _, ok := c.objects[id]; !ok {
+		c.ids = append(c.ids, id)
+	}
+	c.objects[id] = obj
+}
+
+func (s *Server) list(path string) []object {
+	c, ok := s.collections[path]
+	if !ok {
+		return nil
+	}
+	objects := make([]object, len(c.ids))
+	for i, id := range c.ids {
+		objects[i] = c.objects[id]
+	}
+	return objects
+}
+
+func (s *Server) reference(path string) *collection {
+	c := s.collection(path)
+	c.reference = true
+	return c
+}
+
+// remove deletes an object, the collections nested under it and the
+// references to it.
+func (s *Server) remove(path, id string) {
+	if c, ok := s.collections[path]; ok {
+		c.remove(id)
+	}
+	prefix := path + "/" + id + "/"
+	for p, c := range s.collections {
+		if strings.HasPrefix(p, prefix) {
+			delete(s.collections, p)
+			continue
+		}
+		if c.reference {
+			c.remove(id)
+		}
+	}
+}
+
+func (c *collection) remove(id string) {
+	if _, ok := c.objects[id]; !ok {
+		return
+	}
+	delete(c.objects, id)
+	for i := range c.ids {
+		if c.ids[i] == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+}
+
+// page returns the page of objects selected by the after and limit query
+// parameters, and the Link headers of the page.
+func (s *Server) page(r *http.Request, objects []object) ([]object, []string, *apiError) {
+	query := r.URL.Query()
+	limit := s.maxPage(defaultPageLimit)
+	if v := query.Get("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l < 1 {
+			return nil, nil, errInvalidParameter("limit")
+		}
+		if l < limit {
+			limit = l
+		}
+	}
+	start := 0
+	if after := query.Get("after"); after != "" {
+		for i, obj := range objects {
+			if obj["id"] == after {
+				start = i + 1
+				break
+			}
+		}
+	}
+	if start > len(objects) {
+		start = len(objects)
+	}
+	end := start + limit
+	if end > len(objects) {
+		end = len(objects)
+	}
+
+	self := *r.URL
+	self.Scheme, self.Host = "http", r.Host
+	links := []string{fmt.Sprintf("<%s>; rel=\"self\"", s.URL+self.RequestURI())}
+	if end < len(objects) {
+		next := url.Values{}
+		for k, v := range query {
+			next[k] = v
+		}
+		next.Set("after", fmt.Sprint(objects[end-1]["id"]))
+		next.Set("limit", strconv.Itoa(limit))
+		links = append(links, fmt.Sprintf("<%s%s?%s>; rel=\"next\"", s.URL, r.URL.Path, next.Encode()))
+	}
+	return objects[start:end], links, nil
+}
+
+// maxPage returns the largest page of a collection whose largest page is
+// limit, smaller if WithPageLimit is given.
+func (s *Server) maxPage(limit int) int {
+	if s.pageLimit > 0 && s.pageLimit < limit {
+		return s.pageLimit
+	}
+	return limit
+}
+
+// copyObject deep copies an object so responses and stored state don't share
+// maps and slices.
+func copyObject(obj object) object {
+	b, _ := json.Marshal(obj)
+	var c object
+	_ = json.Unmarshal(b, &c)
+	return c
+}
+
+func decode(r *http.Request) (object, *apiError) {
+	var obj object
+	if r.Body == nil {
+		return object{}, nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+		if err.Error() == "EOF" {
+			return object{}, nil
+		}
+		return nil, errMalformedBody()
+	}
+	if obj == nil {
+		obj = object{}
+	}
+	return obj, nil
+}
+
+// lookup returns the value at a dotted path, e.g. profile.login, of an object.
+func lookup(obj object, path string) (interface{}, bool) {
+	var v interface{} = obj
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		v, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// child returns the map at key of an object, adding an empty one if missing.
+func child(obj object, key string) object {
+	m, ok := obj[key].(map[string]interface{})
+	if !ok {
+		m = object{}
+		obj[key] = m
+	}
+	return m
+}
+
+func str(obj object, path string) string {
+	v, _ := lookup(obj, path)
+	s, _ := v.(string)
+	return s
+}
+
+// merge copies the values of src into dst, recursing into maps.
+func merge(dst, src object) {
+	for k, v := range src {
+		sm, ok := v.(map[string]interface{})
+		dm, dok := dst[k].(map[string]interface{})
+		if ok && dok {
+			merge(dm, sm)
+			continue
+		}
+		dst[k] = v
+	}
+}
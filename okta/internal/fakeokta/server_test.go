@@ -0,0 +1,739 @@
+package fakeokta
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testClient struct {
+	t      *testing.T
+	server *Server
+	token  string
+}
+
+func (c testClient) do(method, path string, body interface{}) (*http.Response, interface{}) {
+	c.t.Helper()
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(b)
+	default:
+		j, _ := json.Marshal(body)
+		reader = strings.NewReader(string(j))
+	}
+	url := path
+	if !strings.HasPrefix(path, "http") {
+		url = c.server.URL + path
+	}
+	req, _ := http.NewRequest(method, url, reader)
+	req.Header.Set("Authorization", "SSWS "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	var result interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	return resp, result
+}
+
+func (c testClient) expect(status int, method, path string, body interface{}) interface{} {
+	c.t.Helper()
+	resp, result := c.do(method, path, body)
+	if resp.StatusCode != status {
+		c.t.Fatalf("%s %s: expected status %d, got %d: %v", method, path, status, resp.StatusCode, result)
+	}
+	return result
+}
+
+func (c testClient) expectError(status int, code, method, path string, body interface{}) map[string]interface{} {
+	c.t.Helper()
+	result, _ := c.expect(status, method, path, body).(map[string]interface{})
+	if result["errorCode"] != code {
+		c.t.Fatalf("%s %s: expected error %s, got %v", method, path, code, result)
+	}
+	if result["errorSummary"] == "" || result["errorId"] == "" {
+		c.t.Fatalf("%s %s: expected error summary and ID, got %v", method, path, result)
+	}
+	return result
+}
+
+func newTestClient(t *testing.T, opts ...Option) testClient {
+	server := NewServer(opts...)
+	t.Cleanup(server.Close)
+	return testClient{t: t, server: server, token: DefaultToken}
+}
+
+func newUser(login string) map[string]interface{} {
+	return map[string]interface{}{
+		"profile": map[string]interface{}{
+			"login":     login,
+			"email":     login,
+			"firstName": "Test",
+			"lastName":  "User",
+		},
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	c := newTestClient(t)
+	c.expect(http.StatusOK, http.MethodGet, "/api/v1/users/me", nil)
+
+	c.token = "wrong"
+	c.expectError(http.StatusUnauthorized, "E0000011", http.MethodGet, "/api/v1/users/me", nil)
+}
+
+func TestSeededOrg(t *testing.T) {
+	c := newTestClient(t)
+	me := c.expect(http.StatusOK, http.MethodGet, "/api/v1/users/me", nil).(map[string]interface{})
+	if me["id"] != c.server.AdminID() || me["status"] != "ACTIVE" {
+		t.Fatalf("expected the active admin, got %v", me)
+	}
+	roles := c.expect(http.StatusOK, http.MethodGet, "/api/v1/users/me/roles", nil).([]interface{})
+	if len(roles) != 1 || roles[0].(map[string]interface{})["type"] != "SUPER_ADMIN" {
+		t.Fatalf("expected the admin to be a super admin, got %v", roles)
+	}
+	groups := c.expect(http.StatusOK, http.MethodGet, "/api/v1/groups?q=Every", nil).([]interface{})
+	if len(groups) != 1 || groups[0].(map[string]interface{})["type"] != "BUILT_IN" {
+		t.Fatalf("expected the Everyone group, got %v", groups)
+	}
+	policies := c.expect(http.StatusOK, http.MethodGet, "/api/v1/policies?type=PASSWORD", nil).([]interface{})
+	if len(policies) != 1 || policies[0].(map[string]interface{})["system"] != true {
+		t.Fatalf("expected the default password policy, got %v", policies)
+	}
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodGet, "/api/v1/policies", nil)
+	userTypes := c.expect(http.StatusOK, http.MethodGet, "/api/v1/meta/types/user", nil).([]interface{})
+	href := userTypes[0].(map[string]interface{})["_links"].(map[string]interface{})["schema"].(map[string]interface{})["href"].(string)
+	c.expect(http.StatusOK, http.MethodGet, href, nil)
+}
+
+func TestUserLifecycle(t *testing.T) {
+	c := newTestClient(t)
+	user := c.expect(http.StatusOK, http.MethodPost, "/api/v1/users?activate=false", newUser("jane@example.com")).(map[string]interface{})
+	id := user["id"].(string)
+	if user["status"] != "STAGED" || len(id) != 20 || !strings.HasPrefix(id, "00u") {
+		t.Fatalf("expected a staged user with an Okta ID, got %v", user)
+	}
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPost, "/api/v1/users", newUser("JANE@example.com"))
+
+	invalid := newUser("john@example.com")
+	invalid["profile"].(map[string]interface{})["favoriteColor"] = "green"
+	delete(invalid["profile"].(map[string]interface{}), "lastName")
+	result := c.expectError(http.StatusBadRequest, "E0000001", http.MethodPost, "/api/v1/users", invalid)
+	if causes := result["errorCauses"].([]interface{}); len(causes) != 2 {
+		t.Fatalf("expected a cause for the missing and the unknown property, got %v", causes)
+	}
+
+	c.expect(http.StatusOK, http.MethodPost, "/api/v1/users/"+id+"/lifecycle/activate", nil)
+	user = c.expect(http.StatusOK, http.MethodGet, "/api/v1/users/jane@example.com", nil).(map[string]interface{})
+	if user["status"] != "PROVISIONED" {
+		t.Fatalf("expected a user without password to be provisioned, got %v", user["status"])
+	}
+	user = c.expect(http.StatusOK, http.MethodPost, "/api/v1/users/"+id, map[string]interface{}{
+		"profile": map[string]interface{}{"title": "Engineer"},
+	}).(map[string]interface{})
+	if user["profile"].(map[string]interface{})["title"] != "Engineer" || user["profile"].(map[string]interface{})["login"] != "jane@example.com" {
+		t.Fatalf("expected a partial profile update, got %v", user["profile"])
+	}
+
+	c.expect(http.StatusNoContent, http.MethodDelete, "/api/v1/users/"+id, nil)
+	user = c.expect(http.StatusOK, http.MethodGet, "/api/v1/users/"+id, nil).(map[string]interface{})
+	if user["status"] != "DEPROVISIONED" {
+		t.Fatalf("expected the first delete to deprovision the user, got %v", user["status"])
+	}
+	c.expect(http.StatusNoContent, http.MethodDelete, "/api/v1/users/"+id, nil)
+	c.expectError(http.StatusNotFound, "E0000007", http.MethodGet, "/api/v1/users/"+id, nil)
+}
+
+func TestGroupMembership(t *testing.T) {
+	c := newTestClient(t)
+	user := c.expect(http.StatusOK, http.MethodPost, "/api/v1/users", newUser("jane@example.com")).(map[string]interface{})
+	group := c.expect(http.StatusOK, http.MethodPost, "/api/v1/groups", map[string]interface{}{
+		"profile": map[string]interface{}{"name": "Engineering"},
+	}).(map[string]interface{})
+	userID, groupID := user["id"].(string), group["id"].(string)
+
+	c.expect(http.StatusNoContent, http.MethodPut, "/api/v1/groups/"+groupID+"/users/"+userID, nil)
+	members := c.expect(http.StatusOK, http.MethodGet, "/api/v1/groups/"+groupID+"/users", nil).([]interface{})
+	if len(members) != 1 || members[0].(map[string]interface{})["id"] != userID {
+		t.Fatalf("expected the user to be a member, got %v", members)
+	}
+	groups := c.expect(http.StatusOK, http.MethodGet, "/api/v1/users/"+userID+"/groups", nil).([]interface{})
+	if len(groups) != 2 {
+		t.Fatalf("expected the user to be in Everyone and Engineering, got %v", groups)
+	}
+
+	c.expect(http.StatusNoContent, http.MethodDelete, "/api/v1/groups/"+groupID, nil)
+	groups = c.expect(http.StatusOK, http.MethodGet, "/api/v1/users/"+userID+"/groups", nil).([]interface{})
+	if len(groups) != 1 {
+		t.Fatalf("expected the deleted group's memberships to be removed, got %v", groups)
+	}
+}
+
+func TestAppLifecycle(t *testing.T) {
+	c := newTestClient(t)
+	app := c.expect(http.StatusOK, http.MethodPost, "/api/v1/apps", map[string]interface{}{
+		"label":      "My App",
+		"signOnMode": "OPENID_CONNECT",
+	}).(map[string]interface{})
+	id := app["id"].(string)
+	client := app["credentials"].(map[string]interface{})["oauthClient"].(map[string]interface{})
+	if app["name"] != "oidc_client" || app["status"] != "ACTIVE" || client["client_id"] != id || client["client_secret"] == nil {
+		t.Fatalf("expected an active OIDC app with client credentials, got %v", app)
+	}
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPost, "/api/v1/apps", map[string]interface{}{"signOnMode": "BOOKMARK"})
+
+	c.expect(http.StatusOK, http.MethodGet, "/api/v1/meta/schemas/apps/"+id+"/default", nil)
+	c.expectError(http.StatusForbidden, "E0000056", http.MethodDelete, "/api/v1/apps/"+id, nil)
+	c.expect(http.StatusOK, http.MethodPost, "/api/v1/apps/"+id+"/lifecycle/deactivate", nil)
+	c.expect(http.StatusNoContent, http.MethodDelete, "/api/v1/apps/"+id, nil)
+	c.expectError(http.StatusNotFound, "E0000007", http.MethodGet, "/api/v1/meta/schemas/apps/"+id+"/default", nil)
+}
+
+func TestAppFilters(t *testing.T) {
+	c := newTestClient(t)
+	var ids []string
+	for _, label := range []string{"Payroll", "Payroll API"} {
+		app := c.expect(http.StatusOK, http.MethodPost, "/api/v1/apps", map[string]interface{}{
+			"label":      label,
+			"signOnMode": "BOOKMARK",
+		}).(map[string]interface{})
+		ids = append(ids, app["id"].(string))
+	}
+	group := c.expect(http.StatusOK, http.MethodPost, "/api/v1/groups", map[string]interface{}{
+		"profile": map[string]interface{}{"name": "Payroll"},
+	}).(map[string]interface{})
+	c.expect(http.StatusOK, http.MethodPut, "/api/v1/apps/"+ids[0]+"/groups/"+group["id"].(string), map[string]interface{}{})
+	c.expect(http.StatusOK, http.MethodPost, "/api/v1/apps/"+ids[1]+"/users", map[string]interface{}{"id": c.server.AdminID()})
+
+	for filter, expected := range map[string]string{
+		`group.id eq "` + group["id"].(string) + `"`: ids[0],
+		`user.id eq "` + c.server.AdminID() + `"`:    ids[1],
+	} {
+		apps := c.expect(http.StatusOK, http.MethodGet, "/api/v1/apps?filter="+url.QueryEscape(filter), nil).([]interface{})
+		if len(apps) != 1 || apps[0].(map[string]interface{})["id"] != expected {
+			t.Fatalf("expected only %s for %s, got %v", expected, filter, apps)
+		}
+	}
+}
+
+func TestClientSecrets(t *testing.T) {
+	c := newTestClient(t)
+	app := c.expect(http.StatusOK, http.MethodPost, "/api/v1/apps", map[string]interface{}{
+		"label":      "My App",
+		"signOnMode": "OPENID_CONNECT",
+	}).(map[string]interface{})
+	path := "/api/v1/apps/" + app["id"].(string) + "/credentials/secrets"
+	secrets := c.expect(http.StatusOK, http.MethodGet, path, nil).([]interface{})
+	first := secrets[0].(map[string]interface{})
+	if len(secrets) != 1 || first["client_secret"] != app["credentials"].(map[string]interface{})["oauthClient"].(map[string]interface{})["client_secret"] || first["status"] != "ACTIVE" {
+		t.Fatalf("expected the app's client secret, got %v", secrets)
+	}
+	firstPath := path + "/" + first["id"].(string)
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPost, firstPath+"/lifecycle/deactivate", nil)
+
+	second := c.expect(http.StatusOK, http.MethodPost, path, map[string]interface{}{}).(map[string]interface{})
+	if second["status"] != "ACTIVE" || second["client_secret"] == "" || second["secret_hash"] == "" {
+		t.Fatalf("expected a new active client secret, got %v", second)
+	}
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPost, path, map[string]interface{}{})
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodDelete, firstPath, nil)
+	deactivated := c.expect(http.StatusOK, http.MethodPost, firstPath+"/lifecycle/deactivate", nil).(map[string]interface{})
+	if deactivated["status"] != "INACTIVE" {
+		t.Fatalf("expected the deactivated client secret, got %v", deactivated)
+	}
+	c.expect(http.StatusNoContent, http.MethodDelete, firstPath, nil)
+
+	bookmark := c.expect(http.StatusOK, http.MethodPost, "/api/v1/apps", map[string]interface{}{
+		"label":      "Bookmark",
+		"signOnMode": "BOOKMARK",
+	}).(map[string]interface{})
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPost, "/api/v1/apps/"+bookmark["id"].(string)+"/credentials/secrets", map[string]interface{}{})
+}
+
+func TestAppSigningKeys(t *testing.T) {
+	c := newTestClient(t)
+	app := c.expect(http.StatusOK, http.MethodPost, "/api/v1/apps", map[string]interface{}{
+		"label":      "My SAML App",
+		"signOnMode": "SAML_2_0",
+	}).(map[string]interface{})
+	id := app["id"].(string)
+	kid := app["credentials"].(map[string]interface{})["signing"].(map[string]interface{})["kid"].(string)
+	keys := c.expect(http.StatusOK, http.MethodGet, "/api/v1/apps/"+id+"/credentials/keys", nil).([]interface{})
+	if len(keys) != 1 || keys[0].(map[string]interface{})["kid"] != kid {
+		t.Fatalf("expected the app's signing key %s, got %v", kid, keys)
+	}
+	metadata := app["_links"].(map[string]interface{})["metadata"].(map[string]interface{})["href"].(string)
+	resp, _ := c.do(http.MethodGet, metadata, nil)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/xml" {
+		t.Fatalf("expected the SAML metadata, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPost, "/api/v1/apps/"+id+"/credentials/keys/generate?validityYears=1", nil)
+	generated := c.expect(http.StatusCreated, http.MethodPost, "/api/v1/apps/"+id+"/credentials/keys/generate?validityYears=2", nil).(map[string]interface{})
+	c.expect(http.StatusOK, http.MethodGet, "/api/v1/apps/"+id+"/credentials/keys/"+generated["kid"].(string), nil)
+
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPost, "/api/v1/apps/"+id+"/credentials/csrs", map[string]interface{}{})
+	csr := c.expect(http.StatusOK, http.MethodPost, "/api/v1/apps/"+id+"/credentials/csrs", map[string]interface{}{
+		"subject": map[string]interface{}{"commonName": "sso.example.com"},
+	}).(map[string]interface{})
+	csrPath := "/api/v1/apps/" + id + "/credentials/csrs/" + csr["id"].(string)
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPost, csrPath+"/lifecycle/publish", "MIIC")
+	published := c.expect(http.StatusCreated, http.MethodPost, csrPath+"/lifecycle/publish", "-----BEGIN CERTIFICATE-----\nMIIC\n-----END CERTIFICATE-----\n").(map[string]interface{})
+	if published["x5c"].([]interface{})[0] != "MIIC" {
+		t.Fatalf("expected the key of the published certificate, got %v", published)
+	}
+	c.expectError(http.StatusNotFound, "E0000007", http.MethodGet, csrPath, nil)
+
+	app["credentials"].(map[string]interface{})["signing"] = map[string]interface{}{"kid": "missing"}
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPut, "/api/v1/apps/"+id, app)
+	app["credentials"].(map[string]interface{})["signing"] = map[string]interface{}{"kid": published["kid"]}
+	c.expect(http.StatusOK, http.MethodPut, "/api/v1/apps/"+id, app)
+}
+
+func TestPolicyRules(t *testing.T) {
+	c := newTestClient(t)
+	policy := c.expect(http.StatusOK, http.MethodPost, "/api/v1/policies", map[string]interface{}{
+		"type": "PASSWORD",
+		"name": "Contractors",
+	}).(map[string]interface{})
+	if policy["priority"] != float64(1) {
+		t.Fatalf("expected the policy before the default policy, got %v", policy["priority"])
+	}
+	id := policy["id"].(string)
+	priorities := func() map[string]interface{} {
+		p := map[string]interface{}{}
+		for _, policy := range c.expect(http.StatusOK, http.MethodGet, "/api/v1/policies?type=PASSWORD", nil).([]interface{}) {
+			p[policy.(map[string]interface{})["name"].(string)] = policy.(map[string]interface{})["priority"]
+		}
+		return p
+	}
+	// a policy given a priority shifts the others, the default policy stays
+	// last
+	other := c.expect(http.StatusOK, http.MethodPost, "/api/v1/policies", map[string]interface{}{
+		"type":     "PASSWORD",
+		"name":     "Employees",
+		"priority": 1,
+	}).(map[string]interface{})
+	if p := priorities(); p["Employees"] != float64(1) || p["Contractors"] != float64(2) || p["Default Policy"] != float64(3) {
+		t.Fatalf("expected the new policy first, got %v", p)
+	}
+	other["priority"] = 5
+	c.expect(http.StatusOK, http.MethodPut, "/api/v1/policies/"+other["id"].(string), other)
+	if p := priorities(); p["Contractors"] != float64(1) || p["Employees"] != float64(2) || p["Default Policy"] != float64(3) {
+		t.Fatalf("expected the moved policy before the default policy, got %v", p)
+	}
+	rule := c.expect(http.StatusOK, http.MethodPost, "/api/v1/policies/"+id+"/rules", map[string]interface{}{
+		"name": "Rule",
+	}).(map[string]interface{})
+	if rule["type"] != "PASSWORD" || rule["priority"] != float64(1) {
+		t.Fatalf("expected a first password rule, got %v", rule)
+	}
+	other = c.expect(http.StatusOK, http.MethodPost, "/api/v1/policies/"+id+"/rules", map[string]interface{}{
+		"name": "Other Rule",
+	}).(map[string]interface{})
+	other["priority"] = 1
+	c.expect(http.StatusOK, http.MethodPut, "/api/v1/policies/"+id+"/rules/"+other["id"].(string), other)
+	rule = c.expect(http.StatusOK, http.MethodGet, "/api/v1/policies/"+id+"/rules/"+rule["id"].(string), nil).(map[string]interface{})
+	if rule["priority"] != float64(2) {
+		t.Fatalf("expected the rule to be shifted by the moved rule, got %v", rule)
+	}
+	c.expectError(http.StatusNotFound, "E0000007", http.MethodGet, "/api/v1/policies/00pmissing/rules", nil)
+
+	c.expect(http.StatusNoContent, http.MethodDelete, "/api/v1/policies/"+id, nil)
+	c.expectError(http.StatusNotFound, "E0000007", http.MethodGet, "/api/v1/policies/"+id+"/rules/"+rule["id"].(string), nil)
+}
+
+func TestAuthServer(t *testing.T) {
+	c := newTestClient(t)
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPost, "/api/v1/authorizationServers", map[string]interface{}{"name": "no audiences"})
+	authServer := c.expect(http.StatusOK, http.MethodPost, "/api/v1/authorizationServers", map[string]interface{}{
+		"name":      "api",
+		"audiences": []string{"api://api"},
+	}).(map[string]interface{})
+	id := authServer["id"].(string)
+	if authServer["issuer"] != c.server.URL+"/oauth2/"+id {
+		t.Fatalf("expected the issuer to be the org URL, got %v", authServer["issuer"])
+	}
+	scopes := c.expect(http.StatusOK, http.MethodGet, "/api/v1/authorizationServers/"+id+"/scopes?q=o", nil).([]interface{})
+	if len(scopes) != 2 {
+		t.Fatalf("expected the openid and offline_access system scopes, got %v", scopes)
+	}
+
+	kid := authServer["credentials"].(map[string]interface{})["signing"].(map[string]interface{})["kid"]
+	keys := c.expect(http.StatusOK, http.MethodPost, "/api/v1/authorizationServers/"+id+"/credentials/lifecycle/keyRotate", map[string]string{"use": "sig"}).([]interface{})
+	statuses := make([]string, len(keys))
+	for i, key := range keys {
+		statuses[i] = key.(map[string]interface{})["status"].(string)
+	}
+	if strings.Join(statuses, ",") != "EXPIRED,ACTIVE,NEXT" {
+		t.Fatalf("expected rotation to expire, activate and add a key, got %v", statuses)
+	}
+	authServer = c.expect(http.StatusOK, http.MethodGet, "/api/v1/authorizationServers/"+id, nil).(map[string]interface{})
+	if authServer["credentials"].(map[string]interface{})["signing"].(map[string]interface{})["kid"] == kid {
+		t.Fatal("expected rotation to change the signing key")
+	}
+}
+
+func TestUserSchema(t *testing.T) {
+	c := newTestClient(t)
+	c.expect(http.StatusOK, http.MethodPost, "/api/v1/meta/schemas/user/default", map[string]interface{}{
+		"definitions": map[string]interface{}{
+			"custom": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"favoriteColor": map[string]interface{}{"title": "Favorite color", "type": "string"},
+				},
+			},
+		},
+	})
+	user := newUser("jane@example.com")
+	user["profile"].(map[string]interface{})["favoriteColor"] = "green"
+	c.expect(http.StatusOK, http.MethodPost, "/api/v1/users", user)
+
+	schema := c.expect(http.StatusOK, http.MethodPost, "/api/v1/meta/schemas/user/default", map[string]interface{}{
+		"definitions": map[string]interface{}{
+			"custom": map[string]interface{}{
+				"properties": map[string]interface{}{"favoriteColor": nil},
+			},
+		},
+	}).(map[string]interface{})
+	custom := schema["definitions"].(map[string]interface{})["custom"].(map[string]interface{})["properties"].(map[string]interface{})
+	if len(custom) != 0 {
+		t.Fatalf("expected the custom property to be removed, got %v", custom)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	c := newTestClient(t)
+	for _, name := range []string{"Engineering", "Engineering Managers", "Sales"} {
+		c.expect(http.StatusOK, http.MethodPost, "/api/v1/groups", map[string]interface{}{
+			"profile": map[string]interface{}{"name": name},
+		})
+	}
+	tests := []struct {
+		query string
+		count int
+	}{
+		{`q=eng`, 2},
+		{`search=profile.name eq "sales"`, 1},
+		{`search=profile.name sw "Engineering" and type eq "OKTA_GROUP"`, 2},
+		{`filter=type eq "BUILT_IN" or profile.name eq "Sales"`, 2},
+		{`search=profile.description pr`, 1},
+	}
+	for _, test := range tests {
+		groups := c.expect(http.StatusOK, http.MethodGet, "/api/v1/groups?"+strings.ReplaceAll(test.query, " ", "%20"), nil).([]interface{})
+		if len(groups) != test.count {
+			t.Errorf("%s: expected %d groups, got %d", test.query, test.count, len(groups))
+		}
+	}
+	c.expectError(http.StatusBadRequest, "E0000031", http.MethodGet, `/api/v1/groups?search=(type%20eq%20"OKTA_GROUP")`, nil)
+}
+
+func TestPagination(t *testing.T) {
+	c := newTestClient(t)
+	for i := 0; i < 5; i++ {
+		c.expect(http.StatusOK, http.MethodPost, "/api/v1/groups", map[string]interface{}{
+			"profile": map[string]interface{}{"name": fmt.Sprintf("group %d", i)},
+		})
+	}
+	var names []string
+	next := "/api/v1/groups?limit=2"
+	for next != "" {
+		resp, result := c.do(http.MethodGet, next, nil)
+		for _, group := range result.([]interface{}) {
+			names = append(names, group.(map[string]interface{})["profile"].(map[string]interface{})["name"].(string))
+		}
+		next = ""
+		for _, link := range resp.Header.Values("Link") {
+			if strings.HasSuffix(link, `rel="next"`) {
+				next = strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`)
+			}
+		}
+	}
+	if len(names) != 6 || names[0] != "Everyone" || names[5] != "group 4" {
+		t.Fatalf("expected every group once in order, got %v", names)
+	}
+}
+
+func TestPageLimit(t *testing.T) {
+	c := newTestClient(t, WithPageLimit(1))
+	c.expect(http.StatusOK, http.MethodPost, "/api/v1/groups", map[string]interface{}{
+		"profile": map[string]interface{}{"name": "Engineering"},
+	})
+	resp, result := c.do(http.MethodGet, "/api/v1/groups?limit=200", nil)
+	if len(result.([]interface{})) != 1 || len(resp.Header.Values("Link")) != 2 {
+		t.Fatalf("expected a page of one group and a next link, got %v %v", result, resp.Header.Values("Link"))
+	}
+}
+
+func TestLogStreams(t *testing.T) {
+	c := newTestClient(t)
+	ls := c.expect(http.StatusOK, http.MethodPost, "/api/v1/logStreams", map[string]interface{}{
+		"name":     "SIEM",
+		"type":     "splunk_cloud_logstreaming",
+		"settings": map[string]interface{}{"host": "acme.splunkcloud.com", "token": "secret"},
+	}).(map[string]interface{})
+	id := ls["id"].(string)
+	if ls["status"] != "ACTIVE" || ls["settings"].(map[string]interface{})["token"] != nil {
+		t.Fatalf("expected an active log stream without its token, got %v", ls)
+	}
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPost, "/api/v1/logStreams", map[string]interface{}{
+		"name": "SIEM",
+		"type": "aws_eventbridge",
+	})
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPut, "/api/v1/logStreams/"+id, map[string]interface{}{
+		"name":     "SIEM",
+		"type":     "splunk_cloud_logstreaming",
+		"settings": map[string]interface{}{"token": "rotated"},
+	})
+
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodDelete, "/api/v1/logStreams/"+id, nil)
+	ls = c.expect(http.StatusOK, http.MethodPost, "/api/v1/logStreams/"+id+"/lifecycle/deactivate", nil).(map[string]interface{})
+	if ls["status"] != "INACTIVE" {
+		t.Fatalf("expected the deactivated log stream, got %v", ls)
+	}
+	c.expect(http.StatusNoContent, http.MethodDelete, "/api/v1/logStreams/"+id, nil)
+}
+
+func TestLogs(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := newTestClient(t, WithClock(func() time.Time { return now }))
+	c.server.AddLogEvents(
+		map[string]interface{}{"eventType": "user.session.start", "published": "2023-12-01T12:00:00.000Z"},
+		map[string]interface{}{"eventType": "user.session.start", "published": "2024-01-01T10:00:00.000Z"},
+		map[string]interface{}{"eventType": "user.session.end", "published": "2024-01-01T10:30:00.000Z"},
+		map[string]interface{}{"eventType": "user.session.start", "published": "2024-01-01T11:00:00.000Z"},
+	)
+	next := func(resp *http.Response) string {
+		for _, link := range resp.Header.Values("Link") {
+			if strings.HasSuffix(link, `rel="next"`) {
+				return strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`)
+			}
+		}
+		return ""
+	}
+
+	// events older than 7 days are left out unless since is given
+	resp, result := c.do(http.MethodGet, "/api/v1/logs?filter=eventType+eq+%22user.session.start%22&limit=1", nil)
+	if events := result.([]interface{}); len(events) != 1 || events[0].(map[string]interface{})["published"] != "2024-01-01T10:00:00.000Z" {
+		t.Fatalf("expected the first recent session start, got %v", result)
+	}
+	resp, result = c.do(http.MethodGet, next(resp), nil)
+	if len(result.([]interface{})) != 1 {
+		t.Fatalf("expected the second recent session start, got %v", result)
+	}
+	// a polling request always links to a next page
+	resp, result = c.do(http.MethodGet, next(resp), nil)
+	if len(result.([]interface{})) != 0 || next(resp) == "" {
+		t.Fatalf("expected an empty page linking to a next page, got %v %v", result, resp.Header.Values("Link"))
+	}
+
+	// a bounded request ends with its last events
+	resp, result = c.do(http.MethodGet, "/api/v1/logs?since=2023-11-01T00:00:00Z&until=2024-01-01T10:45:00Z&sortOrder=DESCENDING&limit=3", nil)
+	events := result.([]interface{})
+	if len(events) != 3 || events[0].(map[string]interface{})["eventType"] != "user.session.end" || next(resp) != "" {
+		t.Fatalf("expected the three events until 10:45 in descending order and no next page, got %v %v", result, resp.Header.Values("Link"))
+	}
+
+	// q matches keywords anywhere in the events
+	_, result = c.do(http.MethodGet, "/api/v1/logs?since=2023-11-01T00:00:00Z&q=SESSION+end", nil)
+	if events := result.([]interface{}); len(events) != 1 || events[0].(map[string]interface{})["eventType"] != "user.session.end" {
+		t.Fatalf("expected the session end, got %v", result)
+	}
+}
+
+func TestBrands(t *testing.T) {
+	c := newTestClient(t)
+	brand := c.expect(http.StatusOK, http.MethodPost, "/api/v1/brands", map[string]interface{}{"name": "Acme"}).(map[string]interface{})
+	id := brand["id"].(string)
+	if brand["isDefault"] != false {
+		t.Fatalf("expected a brand that isn't the default, got %v", brand)
+	}
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPost, "/api/v1/brands", map[string]interface{}{"name": "Acme"})
+	brand["isDefault"] = true
+	brand = c.expect(http.StatusOK, http.MethodPut, "/api/v1/brands/"+id, brand).(map[string]interface{})
+	if brand["isDefault"] != false {
+		t.Fatalf("expected the default brand not to change, got %v", brand)
+	}
+	themes := c.expect(http.StatusOK, http.MethodGet, "/api/v1/brands/"+id+"/themes", nil).([]interface{})
+	if len(themes) != 1 {
+		t.Fatalf("expected the brand's theme, got %v", themes)
+	}
+
+	c.expectError(http.StatusForbidden, "E0000006", http.MethodDelete, "/api/v1/brands/"+c.server.DefaultBrandID(), nil)
+	c.expect(http.StatusNoContent, http.MethodDelete, "/api/v1/brands/"+id, nil)
+	c.expectError(http.StatusNotFound, "E0000007", http.MethodGet, "/api/v1/brands/"+id+"/themes", nil)
+}
+
+func TestEmailCustomizations(t *testing.T) {
+	c := newTestClient(t)
+	path := "/api/v1/brands/" + c.server.DefaultBrandID() + "/templates/email/ForgotPassword/customizations"
+	customization := func(language string, isDefault bool) map[string]interface{} {
+		return map[string]interface{}{
+			"language":  language,
+			"subject":   "Subject " + language,
+			"body":      "${resetPasswordLink}",
+			"isDefault": isDefault,
+		}
+	}
+	c.expectError(http.StatusNotFound, "E0000007", http.MethodGet, "/api/v1/brands/bnd404/templates/email/ForgotPassword/customizations", nil)
+
+	// the first customization is the default, later ones can't be created as
+	// the default
+	en := c.expect(http.StatusCreated, http.MethodPost, path, customization("en", false)).(map[string]interface{})
+	if en["isDefault"] != true {
+		t.Fatalf("expected the first customization to be the default, got %v", en)
+	}
+	c.expectError(http.StatusConflict, "E0000182", http.MethodPost, path, customization("fr", true))
+	fr := c.expect(http.StatusCreated, http.MethodPost, path, customization("fr", false)).(map[string]interface{})
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPost, path, customization("fr", false))
+
+	c.expectError(http.StatusConflict, "E0000182", http.MethodPut, path+"/"+en["id"].(string), customization("en", false))
+	c.expectError(http.StatusConflict, "E0000182", http.MethodDelete, path+"/"+en["id"].(string), nil)
+	c.expect(http.StatusOK, http.MethodPut, path+"/"+fr["id"].(string), customization("fr", true))
+	en = c.expect(http.StatusOK, http.MethodGet, path+"/"+en["id"].(string), nil).(map[string]interface{})
+	if en["isDefault"] != false {
+		t.Fatalf("expected the previous default to be unset, got %v", en)
+	}
+	c.expect(http.StatusNoContent, http.MethodDelete, path+"/"+en["id"].(string), nil)
+
+	c.expect(http.StatusNoContent, http.MethodDelete, path, nil)
+	if customizations := c.expect(http.StatusOK, http.MethodGet, path, nil).([]interface{}); len(customizations) != 0 {
+		t.Fatalf("expected every customization to be deleted, got %v", customizations)
+	}
+}
+
+func TestThemes(t *testing.T) {
+	c := newTestClient(t)
+	themesPath := "/api/v1/brands/" + c.server.DefaultBrandID() + "/themes"
+	themes := c.expect(http.StatusOK, http.MethodGet, themesPath, nil).([]interface{})
+	if len(themes) != 1 {
+		t.Fatalf("expected the brand's theme, got %v", themes)
+	}
+	theme := themes[0].(map[string]interface{})
+	themePath := themesPath + "/" + theme["id"].(string)
+	download := func(url string) string {
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatalf("GET %s: %v", url, err)
+		}
+		defer resp.Body.Close()
+		content, _ := io.ReadAll(resp.Body)
+		return string(content)
+	}
+	if download(theme["logo"].(string)) != "fake okta default logo" || theme["backgroundImage"] != nil {
+		t.Fatalf("expected Okta's default logo and no background image, got %v", theme)
+	}
+
+	upload := func(name string, content []byte) *http.Response {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		part, _ := w.CreateFormFile("file", name)
+		_, _ = part.Write(content)
+		_ = w.Close()
+		req, _ := http.NewRequest(http.MethodPost, c.server.URL+themePath+"/logo", &body)
+		req.Header.Set("Authorization", "SSWS "+c.token)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST %s: %v", themePath+"/logo", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	if resp := upload("logo.txt", []byte("not an image")); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected a file that isn't an image to be rejected, got %d", resp.StatusCode)
+	}
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 16)...)
+	if resp := upload("logo.png", png); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected the logo to be uploaded, got %d", resp.StatusCode)
+	}
+	theme = c.expect(http.StatusOK, http.MethodGet, themePath, nil).(map[string]interface{})
+	logo := theme["logo"].(string)
+	if !strings.HasSuffix(logo, ".png") || download(logo) != string(png) {
+		t.Fatalf("expected the uploaded logo to be served, got %v", theme)
+	}
+
+	// replacing the theme keeps its images
+	theme["primaryColorHex"] = "#1662ff"
+	c.expect(http.StatusOK, http.MethodPut, themePath, theme)
+	delete(theme, "errorPageTouchPointVariant")
+	c.expectError(http.StatusBadRequest, "E0000001", http.MethodPut, themePath, theme)
+	theme = c.expect(http.StatusOK, http.MethodGet, themePath, nil).(map[string]interface{})
+	if theme["primaryColorHex"] != "#1662ff" || theme["logo"] != logo {
+		t.Fatalf("expected the replaced colors and the uploaded logo, got %v", theme)
+	}
+
+	c.expect(http.StatusNoContent, http.MethodDelete, themePath+"/logo", nil)
+	theme = c.expect(http.StatusOK, http.MethodGet, themePath, nil).(map[string]interface{})
+	if download(theme["logo"].(string)) != "fake okta default logo" {
+		t.Fatalf("expected the deleted logo to revert to Okta's default, got %v", theme)
+	}
+
+	// assets are served without a token and count as requests
+	requests := c.server.Requests()
+	resp, err := http.Get(c.server.URL + "/assets/missing.png")
+	if err != nil {
+		t.Fatalf("GET /assets/missing.png: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound || c.server.Requests() != requests+1 {
+		t.Fatalf("expected a counted not found asset, got %d after %d requests", resp.StatusCode, c.server.Requests()-requests)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 30, 0, time.UTC)
+	c := newTestClient(t, WithRateLimit(2), WithClock(func() time.Time { return now }))
+
+	resp, _ := c.do(http.MethodGet, "/api/v1/groups", nil)
+	if resp.Header.Get("X-Rate-Limit-Limit") != "2" || resp.Header.Get("X-Rate-Limit-Remaining") != "1" {
+		t.Fatalf("expected rate limit headers, got %v", resp.Header)
+	}
+	if resp.Header.Get("X-Rate-Limit-Reset") != fmt.Sprint(now.Truncate(time.Minute).Add(time.Minute).Unix()) {
+		t.Fatalf("expected the limit to reset at the next minute, got %s", resp.Header.Get("X-Rate-Limit-Reset"))
+	}
+	c.expect(http.StatusOK, http.MethodGet, "/api/v1/groups", nil)
+	c.expectError(http.StatusTooManyRequests, "E0000047", http.MethodGet, "/api/v1/groups", nil)
+	c.expect(http.StatusOK, http.MethodGet, "/api/v1/users", nil)
+
+	now = now.Add(time.Minute)
+	c.expect(http.StatusOK, http.MethodGet, "/api/v1/groups", nil)
+}
+
+func TestAdminRole(t *testing.T) {
+	c := newTestClient(t, WithAdminRole("READ_ONLY_ADMIN"))
+	roles := c.expect(http.StatusOK, http.MethodGet, "/api/v1/users/"+c.server.AdminID()+"/roles", nil).([]interface{})
+	if len(roles) != 1 || roles[0].(map[string]interface{})["type"] != "READ_ONLY_ADMIN" {
+		t.Fatalf("expected the admin to read their own read-only admin role, got %v", roles)
+	}
+	c.expect(http.StatusOK, http.MethodGet, "/api/v1/groups", nil)
+	c.expectError(http.StatusForbidden, "E0000006", http.MethodPost, "/api/v1/groups", map[string]interface{}{
+		"profile": map[string]interface{}{"name": "Engineering"},
+	})
+	// reserved endpoints are forbidden before they are found
+	c.expectError(http.StatusForbidden, "E0000006", http.MethodGet, "/api/v1/users/00u404/roles", nil)
+	c.expectError(http.StatusForbidden, "E0000006", http.MethodGet, "/api/v1/org/privacy/oktaSupport", nil)
+	if u := c.server.Unimplemented(); len(u) != 0 {
+		t.Fatalf("expected forbidden requests not to be routed, got %v", u)
+	}
+}
+
+func TestUnimplemented(t *testing.T) {
+	c := newTestClient(t)
+	c.expectError(http.StatusNotFound, "E0000007", http.MethodGet, "/api/v1/zones", nil)
+	c.expectError(http.StatusMethodNotAllowed, "E0000022", http.MethodPatch, "/api/v1/groups", nil)
+	if u := c.server.Unimplemented(); len(u) != 1 || u[0] != "GET /api/v1/zones" {
+		t.Fatalf("expected the unimplemented endpoint to be recorded, got %v", u)
+	}
+}
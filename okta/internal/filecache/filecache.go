@@ -0,0 +1,234 @@
+package filecache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	entriesDir = ".entries"
+	stringsDir = ".strings"
+
+	// hasMargin is subtracted from an entry's remaining lifetime in Has so an
+	// entry doesn't expire between the SDK clients' calls to Has and Get.
+	hasMargin = 5 * time.Second
+)
+
+// FileCache is an on-disk implementation of the cache interface shared by the
+// local sdk (sdk/cache.Cache) and okta-sdk-golang v3 (okta.Cache). Because it
+// persists between processes a response read during `terraform plan` can be
+// reused during the `terraform apply` that follows it.
+//
+// Entries are laid out on disk mirroring the request URL, for example
+// GET https://example.okta.com/api/v1/groups?q=eng is stored under
+// <root>/example.okta.com/api/v1/groups/.entries/<sha256 of cache key>. This
+// allows a mutating request to invalidate its own path, everything beneath it,
+// and the collection listings above it without scanning the whole cache.
+type FileCache struct {
+	root string
+	ttl  time.Duration
+}
+
+// NewFileCache returns a file cache rooted at the given directory whose entries
+// expire after the ttl. The directory is created if it does not exist.
+func NewFileCache(root string, ttl time.Duration) (*FileCache, error) {
+	if root == "" {
+		return nil, fmt.Errorf("file cache requires a directory")
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("file cache requires a positive ttl, got %s", ttl)
+	}
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %q: %v", root, err)
+	}
+	return &FileCache{
+		root: filepath.Clean(root),
+		ttl:  ttl,
+	}, nil
+}
+
+// Get returns the cached response for the key or nil if the key is not cached
+// or has expired.
+func (c *FileCache) Get(key string) *http.Response {
+	data := c.read(c.entryPath(key), 0)
+	if data == nil {
+		return nil
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), nil)
+	if err != nil {
+		return nil
+	}
+	return resp
+}
+
+// Set caches the response for the key. The response body is buffered so the
+// caller can continue to read it.
+func (c *FileCache) Set(key string, value *http.Response) {
+	if value == nil {
+		return
+	}
+	data, err := httputil.DumpResponse(value, true)
+	if err != nil {
+		return
+	}
+	c.write(c.entryPath(key), data)
+}
+
+// GetString returns the cached string value for the key or the empty string if
+// it is not cached or has expired.
+func (c *FileCache) GetString(key string) string {
+	return string(c.read(c.stringPath(key), 0))
+}
+
+// SetString caches the string value for the key.
+func (c *FileCache) SetString(key, value string) {
+	c.write(c.stringPath(key), []byte(value))
+}
+
+// Delete removes the key from the cache. The SDK clients call Delete with the
+// request's cache key before every POST, PUT and DELETE so the cache also
+// discards all entries at or below the key's path, and the entries of each
+// parent path, as those collection listings may now be stale.
+func (c *FileCache) Delete(key string) {
+	_ = os.Remove(c.stringPath(key))
+
+	dir, ok := c.pathDir(key)
+	if !ok {
+		_ = os.Remove(c.entryPath(key))
+		return
+	}
+	_ = os.RemoveAll(dir)
+	for dir != c.root && dir != filepath.Dir(dir) {
+		dir = filepath.Dir(dir)
+		_ = os.RemoveAll(filepath.Join(dir, entriesDir))
+	}
+}
+
+// Clear removes every entry from the cache.
+func (c *FileCache) Clear() {
+	entries, err := os.ReadDir(c.root)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		_ = os.RemoveAll(filepath.Join(c.root, entry.Name()))
+	}
+}
+
+// Has returns true if the key is cached and has not expired.
+func (c *FileCache) Has(key string) bool {
+	return c.read(c.entryPath(key), hasMargin) != nil
+}
+
+// read returns the payload stored in the named file; nil if the file does not
+// exist, is malformed, or expires within the margin. Expired files are
+// removed.
+func (c *FileCache) read(name string, margin time.Duration) []byte {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil
+	}
+	header, payload, found := bytes.Cut(data, []byte("\n"))
+	if !found {
+		return nil
+	}
+	expires, err := strconv.ParseInt(string(header), 10, 64)
+	if err != nil {
+		_ = os.Remove(name)
+		return nil
+	}
+	now := time.Now()
+	if now.Unix() >= expires {
+		_ = os.Remove(name)
+		return nil
+	}
+	if now.Add(margin).Unix() >= expires {
+		return nil
+	}
+	return payload
+}
+
+// write stores the payload in the named file prefixed with its expiration
+// time. The file is written to a temporary file and renamed into place so
+// concurrent provider processes never observe a partial entry.
+func (c *FileCache) write(name string, payload []byte) {
+	dir := filepath.Dir(name)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	expires := time.Now().Add(c.ttl).Unix()
+	_, err = fmt.Fprintf(tmp, "%d\n", expires)
+	if err == nil {
+		_, err = tmp.Write(payload)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err = os.Rename(tmp.Name(), name); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+func (c *FileCache) entryPath(key string) string {
+	dir, ok := c.pathDir(key)
+	if !ok {
+		dir = c.root
+	}
+	return filepath.Join(dir, entriesDir, hash(key))
+}
+
+func (c *FileCache) stringPath(key string) string {
+	return filepath.Join(c.root, stringsDir, hash(key))
+}
+
+// pathDir returns the directory mirroring the host and path of the key, the
+// key being in the format of cache.CreateCacheKey.
+func (c *FileCache) pathDir(key string) (string, bool) {
+	u, err := url.Parse(key)
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+	parts := []string{c.root, escapeSegment(u.Host)}
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment == "" {
+			continue
+		}
+		parts = append(parts, escapeSegment(segment))
+	}
+	return filepath.Join(parts...), true
+}
+
+// escapeSegment makes a URL path segment safe to use as a directory name. A
+// leading dot is escaped so segments can't collide with the entry directories
+// or traverse the file system.
+func escapeSegment(segment string) string {
+	escaped := url.PathEscape(segment)
+	if strings.HasPrefix(escaped, ".") {
+		escaped = "%2E" + escaped[1:]
+	}
+	return escaped
+}
+
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
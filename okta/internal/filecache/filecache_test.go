@@ -0,0 +1,146 @@
+package filecache
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newResponse(body string) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+	}
+}
+
+func TestFileCacheSetGet(t *testing.T) {
+	c, err := NewFileCache(t.TempDir(), time.Minute)
+	if err != nil {
+		t.Fatalf("did not expect error, got %+v", err)
+	}
+
+	key := "https://example.okta.com/api/v1/groups/00g1"
+	if c.Has(key) {
+		t.Fatalf("did not expect %q to be cached", key)
+	}
+
+	resp := newResponse(`{"id":"00g1"}`)
+	c.Set(key, resp)
+	// the original response must remain readable after being cached
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"id":"00g1"}` {
+		t.Fatalf("expected original body to be readable, got %q", body)
+	}
+
+	if !c.Has(key) {
+		t.Fatalf("expected %q to be cached", key)
+	}
+	cached := c.Get(key)
+	if cached == nil {
+		t.Fatalf("expected cached response for %q", key)
+	}
+	body, _ = io.ReadAll(cached.Body)
+	if string(body) != `{"id":"00g1"}` {
+		t.Fatalf("expected cached body %q, got %q", `{"id":"00g1"}`, body)
+	}
+	if cached.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("expected cached content type header, got %+v", cached.Header)
+	}
+
+	// a second cache on the same directory, like a subsequent terraform
+	// process, sees the same entries
+	other, _ := NewFileCache(c.root, time.Minute)
+	if !other.Has(key) {
+		t.Fatalf("expected %q to be cached across instances", key)
+	}
+}
+
+func TestFileCacheExpires(t *testing.T) {
+	c, _ := NewFileCache(t.TempDir(), time.Second)
+	key := "https://example.okta.com/api/v1/apps/0oa1"
+	c.Set(key, newResponse(`{}`))
+
+	// the entry is within the Has margin straight away
+	if c.Has(key) {
+		t.Fatalf("did not expect %q to be reported as cached within the expiry margin", key)
+	}
+	time.Sleep(1100 * time.Millisecond)
+	if c.Get(key) != nil {
+		t.Fatalf("did not expect expired %q to be returned", key)
+	}
+}
+
+func TestFileCacheDeleteInvalidatesRelatedPaths(t *testing.T) {
+	c, _ := NewFileCache(t.TempDir(), time.Minute)
+	keys := map[string]bool{
+		"https://example.okta.com/api/v1/groups":                 false,
+		"https://example.okta.com/api/v1/groups?q=eng":           false,
+		"https://example.okta.com/api/v1/groups/00g1":            false,
+		"https://example.okta.com/api/v1/groups/00g1/users":      false,
+		"https://example.okta.com/api/v1/groups/00g1/roles/ra1":  true,
+		"https://example.okta.com/api/v1/groups/00g2":            true,
+		"https://example.okta.com/api/v1/apps":                   true,
+		"https://other.okta.com/api/v1/groups/00g1/users/00u1":   true,
+		"https://example.okta.com/api/v1/groups/00g1/users/00u2": true,
+	}
+	for key := range keys {
+		c.Set(key, newResponse(`{}`))
+	}
+
+	c.Delete("https://example.okta.com/api/v1/groups/00g1/users/00u1")
+
+	for key, cached := range keys {
+		if c.Has(key) != cached {
+			t.Errorf("expected %q cached to be %t after delete", key, cached)
+		}
+	}
+
+	c.Delete("https://example.okta.com/api/v1/groups/00g1")
+	if c.Has("https://example.okta.com/api/v1/groups/00g1/roles/ra1") {
+		t.Errorf("expected sub paths to be invalidated")
+	}
+	if !c.Has("https://example.okta.com/api/v1/groups/00g2") {
+		t.Errorf("did not expect sibling paths to be invalidated")
+	}
+}
+
+func TestFileCacheStringsAndClear(t *testing.T) {
+	c, _ := NewFileCache(t.TempDir(), time.Minute)
+	c.SetString("token", "abc")
+	if c.GetString("token") != "abc" {
+		t.Fatalf("expected cached string value")
+	}
+	key := "https://example.okta.com/api/v1/users/me"
+	c.Set(key, newResponse(`{}`))
+
+	c.Clear()
+	if c.GetString("token") != "" || c.Has(key) {
+		t.Fatalf("expected cache to be cleared")
+	}
+}
+
+func TestEscapeSegment(t *testing.T) {
+	tests := []struct {
+		segment  string
+		expected string
+	}{
+		{"00g1", "00g1"},
+		{"..", "%2E."},
+		{".entries", "%2Eentries"},
+		{"a b", "a%20b"},
+		{"a/b", "a%2Fb"},
+	}
+	for _, test := range tests {
+		if got := escapeSegment(test.segment); got != test.expected {
+			t.Errorf("expected segment %q to escape to %q, got %q", test.segment, test.expected, got)
+		}
+	}
+}
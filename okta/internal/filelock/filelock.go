@@ -0,0 +1,118 @@
+// Package filelock is an advisory lock between processes sharing a file
+// system, for instance provider processes of parallel terraform runs sharing a
+// volume. The lock is a lock file created exclusively next to the locked file,
+// which works on every platform and network file system the provider runs on.
+package filelock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// retryInterval is how often a held lock is tried again
+	retryInterval = 5 * time.Millisecond
+
+	// staleAfter is the age after which a lock is assumed to be left behind
+	// by a killed process and is broken
+	staleAfter = 30 * time.Second
+)
+
+// Lock locks the path, waiting until the lock is released or the context is
+// done. It returns the function releasing the lock.
+func Lock(ctx context.Context, path string) (func(), error) {
+	lock := path + ".lock"
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	owner := hex.EncodeToString(b)
+	for {
+		err := create(lock, owner)
+		if err == nil {
+			return func() { release(lock, owner) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if breakStale(lock) {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for lock %q: %w", lock, ctx.Err())
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// create creates the lock file exclusively and writes its owner to it.
+func create(lock, owner string) error {
+	f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(owner)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(lock)
+	}
+	return err
+}
+
+// release removes the lock file unless it was broken and acquired by another
+// process since.
+func release(lock, owner string) {
+	if b, err := os.ReadFile(lock); err == nil && string(b) == owner {
+		os.Remove(lock)
+	}
+}
+
+// breakStale removes the lock file if it is stale and returns true when the
+// lock can be tried again right away. Processes finding the same stale lock
+// race to exclusively create a marker named after its owner, only the one
+// that creates it removes the lock, once it has checked the lock file is
+// still the stale one. A lock acquired since it was found stale is never
+// removed.
+func breakStale(lock string) bool {
+	info, err := os.Stat(lock)
+	if err != nil {
+		return errors.Is(err, os.ErrNotExist)
+	}
+	if time.Since(info.ModTime()) <= staleAfter {
+		return false
+	}
+	owner, err := os.ReadFile(lock)
+	if err != nil {
+		return false
+	}
+	marker := fmt.Sprintf("%s.%s.break", lock, owner)
+	f, err := os.OpenFile(marker, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		// another process is breaking the lock, unless it was killed doing so
+		if info, err := os.Stat(marker); err == nil && time.Since(info.ModTime()) > staleAfter {
+			os.Remove(marker)
+		}
+		return false
+	}
+	f.Close()
+	defer os.Remove(marker)
+
+	current, err := os.Stat(lock)
+	if err != nil {
+		return errors.Is(err, os.ErrNotExist)
+	}
+	b, err := os.ReadFile(lock)
+	if err != nil || !os.SameFile(info, current) || !current.ModTime().Equal(info.ModTime()) || string(b) != string(owner) {
+		return false
+	}
+	os.Remove(lock)
+	return true
+}
@@ -0,0 +1,110 @@
+package filelock
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	if err := os.WriteFile(path, []byte("0"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// unsynchronized read-modify-write cycles lose increments
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := Lock(context.Background(), path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer unlock()
+			b, _ := os.ReadFile(path)
+			n, _ := strconv.Atoi(string(b))
+			time.Sleep(time.Millisecond)
+			_ = os.WriteFile(path, []byte(strconv.Itoa(n+1)), 0o600)
+		}()
+	}
+	wg.Wait()
+
+	b, _ := os.ReadFile(path)
+	if string(b) != "20" {
+		t.Errorf("expected 20 increments, got %s", b)
+	}
+}
+
+func TestLockContextDone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	unlock, err := Lock(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := Lock(ctx, path); err == nil {
+		t.Error("expected error waiting for a held lock")
+	}
+}
+
+// staleLock writes a lock file owned by a killed process.
+func staleLock(t *testing.T, lock, owner string) {
+	t.Helper()
+	if err := os.WriteFile(lock, []byte(owner), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleAfter)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLockStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	lock := path + ".lock"
+	staleLock(t, lock, "killed")
+
+	// a process breaking the stale lock holds its marker, the lock is left to it
+	marker := lock + ".killed.break"
+	if err := os.WriteFile(marker, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if breakStale(lock) {
+		t.Error("expected the lock being broken by another process to be left to it")
+	}
+	if _, err := os.Stat(lock); err != nil {
+		t.Errorf("expected the lock to be left to the process breaking it: %v", err)
+	}
+	if err := os.Remove(marker); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	unlock, err := Lock(ctx, path)
+	if err != nil {
+		t.Fatalf("expected the stale lock to be broken: %v", err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("expected the marker to be removed once the lock is broken: %v", err)
+	}
+
+	// a lock acquired by another process since is not released
+	if err := os.WriteFile(lock, []byte("another"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+	if b, _ := os.ReadFile(lock); string(b) != "another" {
+		t.Errorf("expected the lock of another process to be kept, got %q", b)
+	}
+}
@@ -0,0 +1,307 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/filelock"
+)
+
+const (
+	// FormatOpenMetrics is the OpenMetrics text exposition format, see
+	// https://github.com/OpenObservability/OpenMetrics/blob/main/specification/OpenMetrics.md
+	FormatOpenMetrics = "openmetrics"
+	// FormatJSON is the JSON encoding of a Run
+	FormatJSON = "json"
+)
+
+// Formats are the formats the metrics can be exported in.
+func Formats() []string {
+	return []string{FormatOpenMetrics, FormatJSON}
+}
+
+// lockTimeout is how long a provider process waits for the other processes
+// of the run to write the file.
+const lockTimeout = 10 * time.Second
+
+// WriteFile writes the recorded usage to the file in the format. When the
+// file holds the usage of the same run, written by another provider process
+// of the run, the recorded usage is added to it.
+func (r *Recorder) WriteFile(path, format, runID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	unlock, err := filelock.Lock(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	run := r.Run(runID)
+	if previous, err := readFile(path, format); err == nil && previous.RunID == runID {
+		for _, s := range previous.Series {
+			run.add(s)
+		}
+		run.sort()
+	}
+
+	var buf bytes.Buffer
+	if err := run.Encode(&buf, format); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func readFile(path, format string) (*Run, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Decode(f, format)
+}
+
+// Encode writes the run in the format.
+func (run *Run) Encode(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(run)
+	case FormatOpenMetrics:
+		return run.encodeOpenMetrics(w)
+	}
+	return fmt.Errorf("unknown metrics format %q, expected one of %s", format, strings.Join(Formats(), ", "))
+}
+
+// Decode reads a run in the format.
+func Decode(r io.Reader, format string) (*Run, error) {
+	switch format {
+	case FormatJSON:
+		var run Run
+		if err := json.NewDecoder(r).Decode(&run); err != nil {
+			return nil, err
+		}
+		return &run, nil
+	case FormatOpenMetrics:
+		return decodeOpenMetrics(r)
+	}
+	return nil, fmt.Errorf("unknown metrics format %q, expected one of %s", format, strings.Join(Formats(), ", "))
+}
+
+const (
+	metricRun             = "okta_api_run"
+	metricRequests        = "okta_api_requests"
+	metricRateLimited     = "okta_api_rate_limited"
+	metricRetries         = "okta_api_retries"
+	metricThrottleSleeps  = "okta_api_throttle_sleeps"
+	metricThrottleSeconds = "okta_api_throttle_sleep_seconds"
+	metricDuration        = "okta_api_request_duration_seconds"
+)
+
+func (run *Run) encodeOpenMetrics(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	family := func(name, typ, help string) {
+		fmt.Fprintf(bw, "# TYPE %s %s\n# HELP %s %s\n", name, typ, name, help)
+	}
+	sample := func(name string, s *Series, value string, extra ...string) {
+		labels := []string{
+			"endpoint", s.Endpoint,
+			"bucket", s.Bucket,
+			"resource_type", s.ResourceType,
+		}
+		fmt.Fprintf(bw, "%s%s %s\n", name, formatLabels(append(labels, extra...)), value)
+	}
+	counter := func(name, help string, value func(*Series) string) {
+		family(name, "counter", help)
+		for _, s := range run.Series {
+			sample(name+"_total", s, value(s))
+		}
+	}
+
+	family(metricRun, "info", "The Terraform run the API usage was recorded for.")
+	fmt.Fprintf(bw, "%s_info%s 1\n", metricRun, formatLabels([]string{"run_id", run.RunID}))
+
+	family(metricRequests, "counter", "Requests made to the Okta API by response status code.")
+	for _, s := range run.Series {
+		codes := make([]string, 0, len(s.Requests))
+		for code := range s.Requests {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			sample(metricRequests+"_total", s, strconv.FormatInt(s.Requests[code], 10), "code", code)
+		}
+	}
+	counter(metricRateLimited, "Requests the Okta API responded 429 Too Many Requests to.", func(s *Series) string {
+		return strconv.FormatInt(s.RateLimited, 10)
+	})
+	counter(metricRetries, "Requests retried after an error or a 429 response.", func(s *Series) string {
+		return strconv.FormatInt(s.Retries, 10)
+	})
+	counter(metricThrottleSleeps, "Requests delayed until the rate limit reset to stay under max_api_capacity.", func(s *Series) string {
+		return strconv.FormatInt(s.ThrottleSleeps, 10)
+	})
+	counter(metricThrottleSeconds, "Seconds requests were delayed until the rate limit reset.", func(s *Series) string {
+		return formatFloat(s.ThrottleSleepSeconds)
+	})
+
+	family(metricDuration, "histogram", "Latency of the requests made to the Okta API.")
+	for _, s := range run.Series {
+		for _, b := range s.Latency.Buckets {
+			sample(metricDuration+"_bucket", s, strconv.FormatInt(b.Count, 10), "le", formatFloat(b.LE))
+		}
+		sample(metricDuration+"_bucket", s, strconv.FormatInt(s.Latency.Count, 10), "le", "+Inf")
+		sample(metricDuration+"_count", s, strconv.FormatInt(s.Latency.Count, 10))
+		sample(metricDuration+"_sum", s, formatFloat(s.Latency.Sum))
+	}
+	fmt.Fprintln(bw, "# EOF")
+	return bw.Flush()
+}
+
+// decodeOpenMetrics reads the metrics written by encodeOpenMetrics, samples of
+// other metrics are ignored.
+func decodeOpenMetrics(r io.Reader) (*Run, error) {
+	run := &Run{Series: []*Series{}}
+	series := map[seriesKey]*Series{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		name, labels, value, err := parseSample(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if name == metricRun+"_info" {
+			run.RunID = labels["run_id"]
+			continue
+		}
+		if !strings.HasPrefix(name, "okta_api_") {
+			continue
+		}
+		key := seriesKey{endpoint: labels["endpoint"], bucket: labels["bucket"], resourceType: labels["resource_type"]}
+		s, ok := series[key]
+		if !ok {
+			s = newSeries(key)
+			series[key] = s
+			run.Series = append(run.Series, s)
+		}
+		switch name {
+		case metricRequests + "_total":
+			s.Requests[labels["code"]] += int64(value)
+		case metricRateLimited + "_total":
+			s.RateLimited += int64(value)
+		case metricRetries + "_total":
+			s.Retries += int64(value)
+		case metricThrottleSleeps + "_total":
+			s.ThrottleSleeps += int64(value)
+		case metricThrottleSeconds + "_total":
+			s.ThrottleSleepSeconds += value
+		case metricDuration + "_count":
+			s.Latency.Count += int64(value)
+		case metricDuration + "_sum":
+			s.Latency.Sum += value
+		case metricDuration + "_bucket":
+			le, err := strconv.ParseFloat(labels["le"], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid le label %q", line, labels["le"])
+			}
+			for i := range s.Latency.Buckets {
+				if s.Latency.Buckets[i].LE == le {
+					s.Latency.Buckets[i].Count += int64(value)
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	run.sort()
+	return run, nil
+}
+
+// parseSample parses a sample line of the form name{label="value",...} value
+func parseSample(line string) (string, map[string]string, float64, error) {
+	labels := map[string]string{}
+	end := strings.IndexAny(line, "{ ")
+	if end < 0 {
+		return "", nil, 0, fmt.Errorf("invalid sample %q", line)
+	}
+	name, rest := line[:end], line[end:]
+	if strings.HasPrefix(rest, "{") {
+		rest = rest[1:]
+		for !strings.HasPrefix(rest, "}") {
+			eq := strings.Index(rest, `="`)
+			if eq < 0 {
+				return "", nil, 0, fmt.Errorf("invalid labels in sample %q", line)
+			}
+			label := rest[:eq]
+			rest = rest[eq+2:]
+			var value strings.Builder
+			for {
+				if rest == "" {
+					return "", nil, 0, fmt.Errorf("unterminated label value in sample %q", line)
+				}
+				c := rest[0]
+				rest = rest[1:]
+				if c == '"' {
+					break
+				}
+				if c == '\\' && rest != "" {
+					c = rest[0]
+					rest = rest[1:]
+					if c == 'n' {
+						c = '\n'
+					}
+				}
+				value.WriteByte(c)
+			}
+			labels[label] = value.String()
+			rest = strings.TrimPrefix(rest, ",")
+		}
+		rest = rest[1:]
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(rest), 64)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("invalid value in sample %q", line)
+	}
+	return name, labels, value, nil
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabels formats label name and value pairs.
+func formatLabels(pairs []string) string {
+	labels := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		labels = append(labels, fmt.Sprintf(`%s="%s"`, pairs[i], labelValueEscaper.Replace(pairs[i+1])))
+	}
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
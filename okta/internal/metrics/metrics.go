@@ -0,0 +1,254 @@
+// Package metrics records the provider's usage of the Okta API, request
+// counts, latencies, 429s, retries and throttle sleeps, per endpoint class and
+// per resource type, and exports it as OpenMetrics text or JSON.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
+)
+
+// LatencyBuckets are the upper bounds, in seconds, of the request latency
+// histogram buckets.
+var LatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// UnknownResourceType is the resource type of requests made outside of a
+// resource or data source operation, for instance while configuring the
+// provider.
+const UnknownResourceType = "unknown"
+
+type resourceTypeKey struct{}
+
+// WithResourceType returns a context attributing the API requests made with
+// it to the resource type, data sources are named data.<type>.
+func WithResourceType(ctx context.Context, resourceType string) context.Context {
+	return context.WithValue(ctx, resourceTypeKey{}, resourceType)
+}
+
+// ResourceType returns the resource type the context's API requests are
+// attributed to.
+func ResourceType(ctx context.Context) string {
+	if resourceType, ok := ctx.Value(resourceTypeKey{}).(string); ok && resourceType != "" {
+		return resourceType
+	}
+	return UnknownResourceType
+}
+
+// Run is the API usage of a Terraform run.
+type Run struct {
+	// RunID identifies the Terraform run, the provider processes started by
+	// the same run add their usage to the same metrics file
+	RunID  string    `json:"run_id"`
+	Series []*Series `json:"series"`
+}
+
+// Series is the API usage of an endpoint class by a resource type.
+type Series struct {
+	// Endpoint is the endpoint class, e.g. "GET /api/v1/users/ID"
+	Endpoint string `json:"endpoint"`
+	// Bucket is the Okta rate limit bucket of the endpoint, e.g.
+	// "/api/v1/groups/{id}"
+	Bucket       string `json:"bucket"`
+	ResourceType string `json:"resource_type"`
+	// Requests are the request counts by response status code, requests
+	// that failed without a response are counted as "error"
+	Requests             map[string]int64 `json:"requests"`
+	RateLimited          int64            `json:"rate_limited"`
+	Retries              int64            `json:"retries"`
+	ThrottleSleeps       int64            `json:"throttle_sleeps"`
+	ThrottleSleepSeconds float64          `json:"throttle_sleep_seconds"`
+	Latency              Histogram        `json:"latency_seconds"`
+}
+
+// Histogram is a histogram of request latencies in seconds.
+type Histogram struct {
+	Count int64   `json:"count"`
+	Sum   float64 `json:"sum"`
+	// Buckets are the cumulative counts of the LatencyBuckets
+	Buckets []Bucket `json:"buckets"`
+}
+
+// Bucket is the count of the observations less than or equal to its upper
+// bound.
+type Bucket struct {
+	LE    float64 `json:"le"`
+	Count int64   `json:"count"`
+}
+
+type seriesKey struct {
+	endpoint     string
+	bucket       string
+	resourceType string
+}
+
+// Recorder records API usage. A nil Recorder records nothing.
+type Recorder struct {
+	lock       sync.Mutex
+	classifier *apimutex.APIMutex
+	series     map[seriesKey]*Series
+}
+
+// NewRecorder returns a new recorder.
+func NewRecorder() *Recorder {
+	// the api mutex is only used to classify endpoints, capacity is moot
+	classifier, _ := apimutex.NewAPIMutex(100)
+	return &Recorder{
+		classifier: classifier,
+		series:     map[seriesKey]*Series{},
+	}
+}
+
+// RecordRequest records a request and its latency, status is 0 when the
+// request failed without a response.
+func (r *Recorder) RecordRequest(ctx context.Context, method, path string, status int, latency time.Duration) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s := r.get(ctx, method, path)
+	code := "error"
+	if status != 0 {
+		code = strconv.Itoa(status)
+	}
+	s.Requests[code]++
+	if status == http.StatusTooManyRequests {
+		s.RateLimited++
+	}
+	s.Latency.observe(latency.Seconds())
+}
+
+// RecordRetry records the retry of a request.
+func (r *Recorder) RecordRetry(ctx context.Context, method, path string) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.get(ctx, method, path).Retries++
+}
+
+// RecordThrottle records the time a request slept waiting for the rate limit
+// to reset.
+func (r *Recorder) RecordThrottle(ctx context.Context, method, path string, sleep time.Duration) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s := r.get(ctx, method, path)
+	s.ThrottleSleeps++
+	s.ThrottleSleepSeconds += sleep.Seconds()
+}
+
+// Run returns a copy of the recorded usage with the given run ID, its series
+// are sorted by endpoint, bucket and resource type.
+func (r *Recorder) Run(runID string) *Run {
+	run := &Run{RunID: runID, Series: []*Series{}}
+	if r == nil {
+		return run
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, s := range r.series {
+		run.add(s)
+	}
+	run.sort()
+	return run
+}
+
+func (r *Recorder) get(ctx context.Context, method, path string) *Series {
+	key := seriesKey{
+		endpoint:     r.classifier.Class(method, path),
+		bucket:       r.classifier.Bucket(method, path),
+		resourceType: ResourceType(ctx),
+	}
+	s, ok := r.series[key]
+	if !ok {
+		s = newSeries(key)
+		r.series[key] = s
+	}
+	return s
+}
+
+func newSeries(key seriesKey) *Series {
+	s := &Series{
+		Endpoint:     key.endpoint,
+		Bucket:       key.bucket,
+		ResourceType: key.resourceType,
+		Requests:     map[string]int64{},
+	}
+	for _, le := range LatencyBuckets {
+		s.Latency.Buckets = append(s.Latency.Buckets, Bucket{LE: le})
+	}
+	return s
+}
+
+func (s *Series) key() seriesKey {
+	return seriesKey{endpoint: s.Endpoint, bucket: s.Bucket, resourceType: s.ResourceType}
+}
+
+func (h *Histogram) observe(v float64) {
+	h.Count++
+	h.Sum += v
+	for i := range h.Buckets {
+		if v <= h.Buckets[i].LE {
+			h.Buckets[i].Count++
+		}
+	}
+}
+
+// add adds the series' usage to the run's series of the same endpoint class
+// and resource type.
+func (run *Run) add(s *Series) {
+	var sum *Series
+	for _, candidate := range run.Series {
+		if candidate.key() == s.key() {
+			sum = candidate
+			break
+		}
+	}
+	if sum == nil {
+		sum = newSeries(s.key())
+		run.Series = append(run.Series, sum)
+	}
+	for code, count := range s.Requests {
+		sum.Requests[code] += count
+	}
+	sum.RateLimited += s.RateLimited
+	sum.Retries += s.Retries
+	sum.ThrottleSleeps += s.ThrottleSleeps
+	sum.ThrottleSleepSeconds += s.ThrottleSleepSeconds
+	sum.Latency.Count += s.Latency.Count
+	sum.Latency.Sum += s.Latency.Sum
+	for _, b := range s.Latency.Buckets {
+		for i := range sum.Latency.Buckets {
+			if sum.Latency.Buckets[i].LE == b.LE {
+				sum.Latency.Buckets[i].Count += b.Count
+			}
+		}
+	}
+}
+
+func (run *Run) sort() {
+	sort.Slice(run.Series, func(i, j int) bool {
+		a, b := run.Series[i], run.Series[j]
+		if a.Endpoint != b.Endpoint {
+			return a.Endpoint < b.Endpoint
+		}
+		if a.Bucket != b.Bucket {
+			return a.Bucket < b.Bucket
+		}
+		return a.ResourceType < b.ResourceType
+	})
+}
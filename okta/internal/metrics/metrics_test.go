@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+func TestTransport(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder()
+	client := retryablehttp.NewClient()
+	client.Logger = nil
+	client.RetryWaitMin = time.Millisecond
+	client.RetryWaitMax = time.Millisecond
+	client.HTTPClient.Transport = NewTransport(client.HTTPClient.Transport, recorder)
+	client.RequestLogHook = func(_ retryablehttp.Logger, req *http.Request, attempt int) {
+		if attempt > 0 {
+			recorder.RecordRetry(req.Context(), req.Method, req.URL.Path)
+		}
+	}
+
+	ctx := WithResourceType(context.Background(), "okta_user")
+	req, _ := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/users/00u1abcdefghijklmnop", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("did not expect error, got %v", err)
+	}
+	resp.Body.Close()
+
+	run := recorder.Run("1")
+	if len(run.Series) != 1 {
+		t.Fatalf("expected one series, got %+v", run.Series)
+	}
+	s := run.Series[0]
+	if s.Endpoint != "GET /api/v1/users/ID" || s.Bucket != "/api/v1/users/{id:.+}" || s.ResourceType != "okta_user" {
+		t.Errorf("unexpected series %q %q %q", s.Endpoint, s.Bucket, s.ResourceType)
+	}
+	if !reflect.DeepEqual(s.Requests, map[string]int64{"200": 1, "429": 1}) {
+		t.Errorf("expected a 429 and a 200, got %+v", s.Requests)
+	}
+	if s.RateLimited != 1 || s.Retries != 1 || s.Latency.Count != 2 {
+		t.Errorf("expected one 429, one retry and two latencies, got %d, %d and %d", s.RateLimited, s.Retries, s.Latency.Count)
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	recorder := NewRecorder()
+	ctx := WithResourceType(context.Background(), "data.okta_groups")
+	recorder.RecordRequest(ctx, http.MethodGet, "/api/v1/groups", 200, 80*time.Millisecond)
+	recorder.RecordRequest(ctx, http.MethodGet, "/api/v1/groups", 429, 2*time.Second)
+	recorder.RecordRetry(ctx, http.MethodGet, "/api/v1/groups")
+	recorder.RecordThrottle(ctx, http.MethodGet, "/api/v1/groups", 1500*time.Millisecond)
+	recorder.RecordRequest(context.Background(), http.MethodGet, "/api/v1/users/me", 0, time.Second)
+	run := recorder.Run("42")
+
+	for _, format := range Formats() {
+		var buf bytes.Buffer
+		if err := run.Encode(&buf, format); err != nil {
+			t.Fatalf("%s: did not expect error, got %v", format, err)
+		}
+		decoded, err := Decode(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: did not expect error, got %v", format, err)
+		}
+		if !reflect.DeepEqual(run, decoded) {
+			t.Errorf("%s: expected %+v, got %+v", format, run, decoded)
+		}
+	}
+
+	var buf bytes.Buffer
+	_ = run.Encode(&buf, FormatOpenMetrics)
+	for _, line := range []string{
+		`okta_api_run_info{run_id="42"} 1`,
+		`okta_api_requests_total{endpoint="GET /api/v1/groups",bucket="/api/v1/groups",resource_type="data.okta_groups",code="429"} 1`,
+		`okta_api_requests_total{endpoint="GET /api/v1/users/me",bucket="/api/v1/users/me",resource_type="unknown",code="error"} 1`,
+		`okta_api_throttle_sleep_seconds_total{endpoint="GET /api/v1/groups",bucket="/api/v1/groups",resource_type="data.okta_groups"} 1.5`,
+		`okta_api_request_duration_seconds_bucket{endpoint="GET /api/v1/groups",bucket="/api/v1/groups",resource_type="data.okta_groups",le="0.1"} 1`,
+		`okta_api_request_duration_seconds_bucket{endpoint="GET /api/v1/groups",bucket="/api/v1/groups",resource_type="data.okta_groups",le="+Inf"} 2`,
+		`# EOF`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("expected OpenMetrics output to contain %q, got\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.prom")
+	recorder := NewRecorder()
+	recorder.RecordRequest(context.Background(), http.MethodGet, "/api/v1/groups", 200, time.Millisecond)
+
+	// provider processes of the same run add up their usage
+	for i := 0; i < 2; i++ {
+		if err := recorder.WriteFile(path, FormatOpenMetrics, "1"); err != nil {
+			t.Fatalf("did not expect error, got %v", err)
+		}
+	}
+	if got := readRequests(t, path); got != 2 {
+		t.Errorf("expected 2 requests for the same run, got %d", got)
+	}
+
+	// a new run replaces the usage of the previous run
+	if err := recorder.WriteFile(path, FormatOpenMetrics, "2"); err != nil {
+		t.Fatalf("did not expect error, got %v", err)
+	}
+	if got := readRequests(t, path); got != 1 {
+		t.Errorf("expected 1 request for a new run, got %d", got)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("expected the lock file to be removed, got %v", err)
+	}
+}
+
+func readRequests(t *testing.T, path string) int64 {
+	run, err := readFile(path, FormatOpenMetrics)
+	if err != nil {
+		t.Fatalf("did not expect error, got %v", err)
+	}
+	var requests int64
+	for _, s := range run.Series {
+		for _, count := range s.Requests {
+			requests += count
+		}
+	}
+	return requests
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+)
+
+// Transport records the requests made through it and their latency.
+type Transport struct {
+	base     http.RoundTripper
+	recorder *Recorder
+}
+
+// NewTransport returns a transport recording requests with the recorder. It
+// should wrap the transport making each attempt of a request, for instance the
+// transport of a retryable client, so 429s and retried requests are counted.
+func NewTransport(base http.RoundTripper, recorder *Recorder) *Transport {
+	return &Transport{
+		base:     base,
+		recorder: recorder,
+	}
+}
+
+// RoundTrip records the request once the base transport returns.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	t.recorder.RecordRequest(req.Context(), req.Method, req.URL.Path, status, time.Since(start))
+	return resp, err
+}
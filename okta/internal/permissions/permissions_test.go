@@ -0,0 +1,66 @@
+package permissions
+
+import (
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		path  string
+		class string
+	}{
+		{"/api/v1/users/00u1/roles", "user admin roles"},
+		{"/api/v1/users/00u1/roles/ra1/targets/groups", "user admin roles"},
+		{"/api/v1/users/00u1", ""},
+		{"/api/v1/users", ""},
+		{"/api/v1/groups/00g1/users", ""},
+		{"/api/v1/mappings", "profile mappings"},
+		{"/api/v1/org/privacy/oktaSupport", "org settings"},
+		{"/api/v1/orgs", ""},
+		{"/api/v1/internal/rateLimits/clientRateLimitMode", "rate limit settings"},
+		{"/api/internal/orgSettings/rateLimitNotificationSetting", "rate limit settings"},
+		{"/api/v1/threats/configuration", "threat insight settings"},
+		{"/api/v1/authorizationServers/aus1/claims", ""},
+	}
+	for _, test := range tests {
+		class := Lookup(test.path)
+		name := ""
+		if class != nil {
+			name = class.Name
+		}
+		if name != test.class {
+			t.Errorf("expected %q to be in endpoint class %q, got %q", test.path, test.class, name)
+		}
+	}
+}
+
+func TestMostPrivileged(t *testing.T) {
+	tests := []struct {
+		roleTypes []string
+		expected  Role
+	}{
+		{nil, Unknown},
+		{[]string{"CUSTOM"}, Unknown},
+		{[]string{"READ_ONLY_ADMIN", "ORG_ADMIN"}, OrgAdmin},
+		{[]string{"APP_ADMIN", "SUPER_ADMIN", "CUSTOM"}, SuperAdmin},
+		{[]string{"help_desk_admin"}, HelpDeskAdmin},
+	}
+	for _, test := range tests {
+		if role := MostPrivileged(test.roleTypes); role != test.expected {
+			t.Errorf("expected %v to have most privileged role %q, got %q", test.roleTypes, test.expected, role)
+		}
+	}
+}
+
+func TestPermits(t *testing.T) {
+	class := Lookup("/api/v1/mappings")
+	if !class.Permits(OrgAdmin) {
+		t.Errorf("expected %q to permit %q", class.Name, OrgAdmin)
+	}
+	if class.Permits(ReadOnlyAdmin) {
+		t.Errorf("did not expect %q to permit %q", class.Name, ReadOnlyAdmin)
+	}
+	if class.Permits(Unknown) {
+		t.Errorf("did not expect %q to permit an unknown role", class.Name)
+	}
+}
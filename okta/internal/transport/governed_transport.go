@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
+	"github.com/okta/terraform-provider-okta/okta/internal/metrics"
 )
 
 const (
@@ -21,6 +22,7 @@ const (
 type GovernedTransport struct {
 	base     http.RoundTripper
 	apiMutex *apimutex.APIMutex
+	recorder *metrics.Recorder
 	logger   hclog.Logger
 }
 
@@ -28,11 +30,13 @@ type GovernedTransport struct {
 // requests from the http round tripper. The pre request consults the api mutex
 // to determine if sleeping for the Okta API one minute bucket is called for.
 // The post request updates the information it is holding about the current api
-// rate limits.
-func NewGovernedTransport(base http.RoundTripper, apiMutex *apimutex.APIMutex, logger hclog.Logger) *GovernedTransport {
+// rate limits. The time spent sleeping is recorded with the recorder, which can
+// be nil.
+func NewGovernedTransport(base http.RoundTripper, apiMutex *apimutex.APIMutex, recorder *metrics.Recorder, logger hclog.Logger) *GovernedTransport {
 	return &GovernedTransport{
 		base:     base,
 		apiMutex: apiMutex,
+		recorder: recorder,
 		logger:   logger,
 	}
 }
@@ -47,7 +51,7 @@ func (t *GovernedTransport) RoundTrip(req *http.Request) (*http.Response, error)
 
 	resp, err := t.base.RoundTrip(req)
 	// always attempt to save x-headers
-	t.postRequestHook(req.Method, path, resp)
+	t.postRequestHook(req.Context(), req.Method, path, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +60,13 @@ func (t *GovernedTransport) RoundTrip(req *http.Request) (*http.Response, error)
 }
 
 func (t *GovernedTransport) preRequestHook(ctx context.Context, method, path string) error {
+	if backend := t.apiMutex.Backend(); backend != nil {
+		err := t.sharedPreRequestHook(ctx, backend, method, path)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		t.logger.Warn("unable to read the shared rate limit status, falling back to the status known to this process", "error", err)
+	}
 	if t.apiMutex.HasCapacity(method, path) {
 		return nil
 	}
@@ -75,6 +86,10 @@ func (t *GovernedTransport) preRequestHook(ctx context.Context, method, path str
 	)
 	t.logger.Info(line)
 
+	start := time.Now()
+	defer func() {
+		t.recorder.RecordThrottle(ctx, method, path, time.Since(start))
+	}()
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
@@ -83,7 +98,41 @@ func (t *GovernedTransport) preRequestHook(ctx context.Context, method, path str
 	}
 }
 
-func (t *GovernedTransport) postRequestHook(method, path string, resp *http.Response) {
+// sharedPreRequestHook acquires the request from the backend shared with the
+// other provider processes, sleeping until the bucket resets when it is over
+// capacity.
+func (t *GovernedTransport) sharedPreRequestHook(ctx context.Context, backend apimutex.Backend, method, path string) error {
+	bucket := t.apiMutex.Bucket(method, path)
+	for {
+		wait, err := backend.Acquire(ctx, bucket, t.apiMutex.Capacity())
+		if err != nil {
+			return err
+		}
+		if wait <= 0 {
+			return nil
+		}
+
+		line := fmt.Sprintf("Throttling API requests; sleeping for %s until the shared rate limit reset (path class %q, bucket %q); current request \"%s %s\"",
+			wait.Round(time.Millisecond),
+			t.apiMutex.Class(method, path),
+			bucket,
+			method,
+			path,
+		)
+		t.logger.Info(line)
+
+		start := time.Now()
+		select {
+		case <-ctx.Done():
+			t.recorder.RecordThrottle(ctx, method, path, time.Since(start))
+			return ctx.Err()
+		case <-time.NewTimer(wait).C:
+			t.recorder.RecordThrottle(ctx, method, path, time.Since(start))
+		}
+	}
+}
+
+func (t *GovernedTransport) postRequestHook(ctx context.Context, method, path string, resp *http.Response) {
 	if resp == nil {
 		return
 	}
@@ -104,4 +153,9 @@ func (t *GovernedTransport) postRequestHook(method, path string, resp *http.Resp
 	}
 
 	t.apiMutex.Update(method, path, limit, remaining, reset)
+	if backend := t.apiMutex.Backend(); backend != nil {
+		if err := backend.Update(ctx, t.apiMutex.Bucket(method, path), limit, remaining, reset); err != nil {
+			t.logger.Warn("unable to update the shared rate limit status", "error", err)
+		}
+	}
 }
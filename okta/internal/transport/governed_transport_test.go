@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"path/filepath"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
+	"github.com/okta/terraform-provider-okta/okta/internal/metrics"
 )
 
 func TestPreRequestHook(t *testing.T) {
@@ -22,7 +27,8 @@ func TestPreRequestHook(t *testing.T) {
 
 	client := &http.Client{}
 	apiMutex, _ := apimutex.NewAPIMutex(percentage)
-	transport := NewGovernedTransport(client.Transport, apiMutex, hclog.NewNullLogger())
+	recorder := metrics.NewRecorder()
+	transport := NewGovernedTransport(client.Transport, apiMutex, recorder, hclog.NewNullLogger())
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -37,13 +43,18 @@ func TestPreRequestHook(t *testing.T) {
 	if err := transport.preRequestHook(ctx, http.MethodGet, path); err != context.Canceled {
 		t.Errorf("Expected %v error, got %+v", context.Canceled, err)
 	}
+
+	run := recorder.Run("")
+	if len(run.Series) != 1 || run.Series[0].ThrottleSleeps != 1 {
+		t.Errorf("Expected one throttle sleep to be recorded, got %+v", run.Series)
+	}
 }
 
 func TestPostRequestHook(t *testing.T) {
 	percentage := 10
 	client := &http.Client{}
 	apiMutex, _ := apimutex.NewAPIMutex(percentage)
-	transport := NewGovernedTransport(client.Transport, apiMutex, hclog.NewNullLogger())
+	transport := NewGovernedTransport(client.Transport, apiMutex, nil, hclog.NewNullLogger())
 
 	path := "/api/v1/apps"
 	request := http.Request{
@@ -63,9 +74,92 @@ func TestPostRequestHook(t *testing.T) {
 		Header:  headers,
 	}
 
-	transport.postRequestHook(http.MethodGet, path, &response)
+	transport.postRequestHook(context.Background(), http.MethodGet, path, &response)
 	status := apiMutex.Status(http.MethodGet, path)
 	if status.Reset() != reset || status.Limit() != limit || status.Remaining() != remaining {
 		t.Fatalf("expected %q api mutex status %+v to have reset %d, limit %d, and remaining %d values", path, status, reset, limit, remaining)
 	}
 }
+
+// TestGovernedTransportSharedBackend simulates parallel provider processes,
+// each with its own api mutex and governed transport, sharing their rate limit
+// status through a file backend against a stubbed Okta org with one second
+// rate limit windows.
+func TestGovernedTransportSharedBackend(t *testing.T) {
+	const (
+		limit     = 20
+		capacity  = 50
+		processes = 4
+		workers   = 2
+		requests  = 3
+	)
+	var (
+		lock        sync.Mutex
+		windows     = map[int64]int{}
+		rateLimited int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		window := time.Now().Unix()
+		windows[window]++
+		count := windows[window]
+		if count > limit {
+			rateLimited++
+		}
+		lock.Unlock()
+
+		remaining := limit - count
+		if remaining < 0 {
+			remaining = 0
+		}
+		w.Header().Set(X_RATE_LIMIT_LIMIT, strconv.Itoa(limit))
+		w.Header().Set(X_RATE_LIMIT_REMAINING, strconv.Itoa(remaining))
+		w.Header().Set(X_RATE_LIMIT_RESET, strconv.FormatInt(window+1, 10))
+		if count > limit {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "rate-limits.json")
+	var wg sync.WaitGroup
+	for p := 0; p < processes; p++ {
+		apiMutex, _ := apimutex.NewAPIMutex(capacity)
+		apiMutex.SetBackend(apimutex.NewFileBackend(path))
+		client := &http.Client{Transport: NewGovernedTransport(http.DefaultTransport, apiMutex, nil, hclog.NewNullLogger())}
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < requests; i++ {
+					resp, err := client.Get(server.URL + "/api/v1/groups")
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					resp.Body.Close()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	// requests acquired at the end of a window may reach the server at the
+	// start of the next one
+	maxPerWindow := limit*capacity/100 + 1 + processes*workers/2
+	total := 0
+	for window, count := range windows {
+		total += count
+		if count > maxPerWindow {
+			t.Errorf("expected at most %d requests in window %d, got %d", maxPerWindow, window, count)
+		}
+	}
+	if total != processes*workers*requests {
+		t.Errorf("expected %d requests, got %d", processes*workers*requests, total)
+	}
+	if rateLimited != 0 {
+		t.Errorf("expected no request to be rate limited, got %d", rateLimited)
+	}
+}
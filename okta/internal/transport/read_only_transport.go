@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/metrics"
+)
+
+// oauthTokenPath is the org authorization server's token endpoint. The SDK
+// clients POST to it to obtain an access token when the provider is configured
+// for OAuth 2.0 private key authorization; that request doesn't mutate the
+// org.
+const oauthTokenPath = "/oauth2/v1/token"
+
+// ReadOnlyError is returned by ReadOnlyTransport for any request that could
+// mutate the Okta org.
+type ReadOnlyError struct {
+	// ResourceType is the Terraform resource or data source that made the
+	// request, metrics.UnknownResourceType outside of their operations
+	ResourceType string
+	Method       string
+	Path         string
+}
+
+func (e *ReadOnlyError) Error() string {
+	if e.ResourceType == "" || e.ResourceType == metrics.UnknownResourceType {
+		return fmt.Sprintf("the provider is configured read_only and refused to call %s %s on the Okta API, this operation would modify the org", e.Method, e.Path)
+	}
+	return fmt.Sprintf("the provider is configured read_only and refused to call %s %s on the Okta API for %s, this operation would modify the org", e.Method, e.Path, e.ResourceType)
+}
+
+type ReadOnlyTransport struct {
+	base http.RoundTripper
+}
+
+// NewReadOnlyTransport returns a transport that only allows requests that can
+// not modify the Okta org to reach the base transport. Any other request fails
+// immediately with a ReadOnlyError.
+func NewReadOnlyTransport(base http.RoundTripper) *ReadOnlyTransport {
+	return &ReadOnlyTransport{
+		base: base,
+	}
+}
+
+// RoundTrip sends GET, HEAD and OPTIONS requests to the base transport and
+// refuses all others.
+func (t *ReadOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isReadOnlyRequest(req) {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, &ReadOnlyError{
+			ResourceType: metrics.ResourceType(req.Context()),
+			Method:       req.Method,
+			Path:         req.URL.Path,
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+func isReadOnlyRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		return req.URL.Path == oauthTokenPath
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/metrics"
+)
+
+func TestReadOnlyTransport(t *testing.T) {
+	var served []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = append(served, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: NewReadOnlyTransport(http.DefaultTransport),
+	}
+
+	tests := []struct {
+		method  string
+		path    string
+		allowed bool
+	}{
+		{http.MethodGet, "/api/v1/groups", true},
+		{http.MethodHead, "/api/v1/groups", true},
+		{http.MethodOptions, "/api/v1/groups", true},
+		{http.MethodPost, "/oauth2/v1/token", true},
+		{http.MethodPost, "/api/v1/groups", false},
+		{http.MethodPut, "/api/v1/groups/00g1/users/00u1", false},
+		{http.MethodPatch, "/api/v1/brands/bnd1", false},
+		{http.MethodDelete, "/api/v1/apps/0oa1", false},
+		{http.MethodPost, "/oauth2/aus1/v1/token", false},
+	}
+	for _, test := range tests {
+		served = nil
+		ctx := metrics.WithResourceType(context.Background(), "okta_group")
+		req, _ := http.NewRequestWithContext(ctx, test.method, server.URL+test.path, strings.NewReader("{}"))
+		resp, err := client.Do(req)
+		if test.allowed {
+			if err != nil {
+				t.Errorf("%s %s: did not expect error, got %+v", test.method, test.path, err)
+				continue
+			}
+			resp.Body.Close()
+			if len(served) != 1 {
+				t.Errorf("%s %s: expected request to reach the server", test.method, test.path)
+			}
+			continue
+		}
+
+		var roErr *ReadOnlyError
+		if !errors.As(err, &roErr) {
+			t.Errorf("%s %s: expected read only error, got %+v", test.method, test.path, err)
+			continue
+		}
+		if roErr.Method != test.method || roErr.Path != test.path {
+			t.Errorf("%s %s: expected error to name the endpoint, got %q", test.method, test.path, roErr.Error())
+		}
+		if roErr.ResourceType != "okta_group" || !strings.Contains(roErr.Error(), "for okta_group") {
+			t.Errorf("%s %s: expected error to name the resource, got %q", test.method, test.path, roErr.Error())
+		}
+		if len(served) != 0 {
+			t.Errorf("%s %s: did not expect request to reach the server", test.method, test.path)
+		}
+	}
+}
@@ -0,0 +1,787 @@
+// Package velocity parses, validates and renders the subset of the Velocity
+// Template Language supported by Okta email templates: references such as
+// ${user.profile.firstName}, $!{quiet} and ${f.escapeHtml($value)}, the #if,
+// #elseif, #else, #foreach, #set and #end directives, and ## and #* *#
+// comments.
+package velocity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SyntaxError is an error parsing a template.
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at line %d, column %d", e.Msg, e.Line, e.Column)
+}
+
+// Template is a parsed template.
+type Template struct {
+	src   []rune
+	nodes []node
+}
+
+type node interface{}
+
+type (
+	textNode struct {
+		text string
+	}
+
+	// refNode is a reference rendered in the output. When the reference is
+	// undefined the raw source is rendered, or nothing when quiet.
+	refNode struct {
+		ref   *reference
+		quiet bool
+		raw   string
+	}
+
+	ifNode struct {
+		conds    []expr
+		bodies   [][]node
+		elseBody []node
+	}
+
+	foreachNode struct {
+		name string
+		list expr
+		body []node
+	}
+
+	setNode struct {
+		name  string
+		value expr
+	}
+)
+
+// reference is a variable followed by property accesses and method calls,
+// e.g. $user.profile.firstName or $f.escapeHtml($name).
+type reference struct {
+	name string
+	path []segment
+	pos  int
+}
+
+type segment struct {
+	name string
+	call bool
+	args []expr
+	pos  int
+}
+
+type expr interface{}
+
+type (
+	literal struct {
+		value interface{}
+	}
+
+	// interpolated is a double quoted string, references in it are rendered.
+	interpolated struct {
+		nodes []node
+	}
+
+	refExpr struct {
+		ref *reference
+	}
+
+	listExpr struct {
+		items []expr
+	}
+
+	rangeExpr struct {
+		from, to expr
+	}
+
+	unaryExpr struct {
+		op string
+		x  expr
+	}
+
+	binaryExpr struct {
+		op   string
+		x, y expr
+	}
+)
+
+// unsupportedDirectives are Velocity directives Okta email templates don't
+// support.
+var unsupportedDirectives = map[string]bool{
+	"break":    true,
+	"define":   true,
+	"evaluate": true,
+	"include":  true,
+	"macro":    true,
+	"parse":    true,
+	"stop":     true,
+}
+
+// wordOperators are the word forms of the Velocity operators.
+var wordOperators = map[string]string{
+	"and": "&&",
+	"or":  "||",
+	"not": "!",
+	"eq":  "==",
+	"ne":  "!=",
+	"lt":  "<",
+	"gt":  ">",
+	"le":  "<=",
+	"ge":  ">=",
+}
+
+type parser struct {
+	src []rune
+	pos int
+	end int
+}
+
+// Parse parses a template.
+func Parse(src string) (*Template, error) {
+	runes := []rune(src)
+	p := &parser{src: runes, end: len(runes)}
+	nodes, term, termPos, err := p.parseNodes()
+	if err != nil {
+		return nil, err
+	}
+	if term != "" {
+		return nil, p.errorf(termPos, "#%s without #if or #foreach", term)
+	}
+	return &Template{src: runes, nodes: nodes}, nil
+}
+
+func (p *parser) errorf(pos int, format string, args ...interface{}) error {
+	line, col := position(p.src, pos)
+	return &SyntaxError{Line: line, Column: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// position returns the line and column, starting at 1, of a rune offset.
+func position(src []rune, pos int) (int, int) {
+	line, col := 1, 1
+	for i := 0; i < pos && i < len(src); i++ {
+		if src[i] == '\n' {
+			line++
+			col = 1
+			continue
+		}
+		col++
+	}
+	return line, col
+}
+
+func (p *parser) hasPrefix(s string) bool {
+	runes := []rune(s)
+	if p.pos+len(runes) > p.end {
+		return false
+	}
+	for i, r := range runes {
+		if p.src[p.pos+i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *parser) peek(offset int) rune {
+	if p.pos+offset >= p.end {
+		return 0
+	}
+	return p.src[p.pos+offset]
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentPart(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func (p *parser) ident() string {
+	start := p.pos
+	if !isIdentStart(p.peek(0)) {
+		return ""
+	}
+	for p.pos < p.end && isIdentPart(p.src[p.pos]) {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+// parseNodes parses text, references and directives up to the end of the
+// template or an #elseif, #else or #end directive, which is returned with its
+// position.
+func (p *parser) parseNodes() ([]node, string, int, error) {
+	var nodes []node
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, &textNode{text: text.String()})
+			text.Reset()
+		}
+	}
+	for p.pos < p.end {
+		r := p.src[p.pos]
+		switch {
+		case r == '\\' && (p.peek(1) == '$' || p.peek(1) == '#'):
+			text.WriteRune(p.peek(1))
+			p.pos += 2
+		case r == '$':
+			start := p.pos
+			ref, quiet, ok, err := p.parseReference()
+			if err != nil {
+				return nil, "", 0, err
+			}
+			if !ok {
+				text.WriteRune(r)
+				p.pos = start + 1
+				continue
+			}
+			flush()
+			nodes = append(nodes, &refNode{ref: ref, quiet: quiet, raw: string(p.src[start:p.pos])})
+		case r == '#' && p.peek(1) == '#':
+			for p.pos < p.end && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+			if p.pos < p.end {
+				p.pos++
+			}
+		case r == '#' && p.peek(1) == '*':
+			start := p.pos
+			p.pos += 2
+			for p.pos < p.end && !p.hasPrefix("*#") {
+				p.pos++
+			}
+			if p.pos >= p.end {
+				return nil, "", 0, p.errorf(start, "unterminated comment")
+			}
+			p.pos += 2
+		case r == '#' && p.peek(1) == '[' && p.peek(2) == '[':
+			start := p.pos
+			p.pos += 3
+			contentStart := p.pos
+			for p.pos < p.end && !p.hasPrefix("]]#") {
+				p.pos++
+			}
+			if p.pos >= p.end {
+				return nil, "", 0, p.errorf(start, "unterminated unparsed content")
+			}
+			text.WriteString(string(p.src[contentStart:p.pos]))
+			p.pos += 3
+		case r == '#':
+			start := p.pos
+			name, braced := p.directiveName()
+			switch name {
+			case "if", "foreach", "set":
+				flush()
+				n, err := p.parseDirective(name, start)
+				if err != nil {
+					return nil, "", 0, err
+				}
+				nodes = append(nodes, n)
+			case "elseif", "else", "end":
+				flush()
+				return nodes, name, start, nil
+			default:
+				if unsupportedDirectives[name] && (braced || p.peek(0) == '(') {
+					return nil, "", 0, p.errorf(start, "#%s is not supported", name)
+				}
+				// not a directive, e.g. a color like #ff0000
+				text.WriteRune(r)
+				p.pos = start + 1
+			}
+		default:
+			text.WriteRune(r)
+			p.pos++
+		}
+	}
+	flush()
+	return nodes, "", 0, nil
+}
+
+// directiveName reads the name of the directive at the # either as #name or
+// #{name}.
+func (p *parser) directiveName() (string, bool) {
+	start := p.pos
+	p.pos++
+	if p.peek(0) == '{' {
+		p.pos++
+		name := p.ident()
+		if name != "" && p.peek(0) == '}' {
+			p.pos++
+			return name, true
+		}
+		p.pos = start
+		return "", false
+	}
+	var name strings.Builder
+	for p.pos < p.end && unicode.IsLetter(p.src[p.pos]) {
+		name.WriteRune(p.src[p.pos])
+		p.pos++
+	}
+	return name.String(), false
+}
+
+func (p *parser) parseDirective(name string, start int) (node, error) {
+	switch name {
+	case "if":
+		n := &ifNode{}
+		cond, err := p.parseCondition("if")
+		if err != nil {
+			return nil, err
+		}
+		for {
+			body, term, termPos, err := p.parseNodes()
+			if err != nil {
+				return nil, err
+			}
+			n.conds = append(n.conds, cond)
+			n.bodies = append(n.bodies, body)
+			switch term {
+			case "":
+				return nil, p.errorf(start, "#if without #end")
+			case "end":
+				return n, nil
+			case "elseif":
+				if cond, err = p.parseCondition("elseif"); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			// #else
+			body, term, termPos, err = p.parseNodes()
+			if err != nil {
+				return nil, err
+			}
+			if term != "end" {
+				if term == "" {
+					return nil, p.errorf(start, "#if without #end")
+				}
+				return nil, p.errorf(termPos, "#%s after #else", term)
+			}
+			n.elseBody = body
+			return n, nil
+		}
+	case "foreach":
+		if err := p.expectOpen("foreach"); err != nil {
+			return nil, err
+		}
+		name, err := p.parseVariable()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if inPos := p.pos; p.word() != "in" {
+			return nil, p.errorf(inPos, "expected \"in\" after the #foreach variable")
+		}
+		list, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectClose(); err != nil {
+			return nil, err
+		}
+		body, term, termPos, err := p.parseNodes()
+		if err != nil {
+			return nil, err
+		}
+		if term != "end" {
+			if term == "" {
+				return nil, p.errorf(start, "#foreach without #end")
+			}
+			return nil, p.errorf(termPos, "#%s without #if", term)
+		}
+		return &foreachNode{name: name, list: list, body: body}, nil
+	}
+	// #set
+	if err := p.expectOpen("set"); err != nil {
+		return nil, err
+	}
+	name, err := p.parseVariable()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.peek(0) != '=' {
+		return nil, p.errorf(p.pos, "expected \"=\" after the #set variable")
+	}
+	p.pos++
+	value, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectClose(); err != nil {
+		return nil, err
+	}
+	return &setNode{name: name, value: value}, nil
+}
+
+func (p *parser) parseCondition(directive string) (expr, error) {
+	if err := p.expectOpen(directive); err != nil {
+		return nil, err
+	}
+	cond, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return cond, p.expectClose()
+}
+
+func (p *parser) expectOpen(directive string) error {
+	for p.peek(0) == ' ' || p.peek(0) == '\t' {
+		p.pos++
+	}
+	if p.peek(0) != '(' {
+		return p.errorf(p.pos, "expected \"(\" after #%s", directive)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *parser) expectClose() error {
+	p.skipSpace()
+	if p.peek(0) != ')' {
+		return p.unexpected("\")\"")
+	}
+	p.pos++
+	return nil
+}
+
+func (p *parser) unexpected(expected string) error {
+	if p.pos >= p.end {
+		return p.errorf(p.pos, "expected %s, got end of template", expected)
+	}
+	return p.errorf(p.pos, "expected %s, got %q", expected, string(p.src[p.pos]))
+}
+
+// parseVariable parses the variable assigned by #set or #foreach, e.g. $name.
+func (p *parser) parseVariable() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	ref, _, ok, err := p.parseReference()
+	if err != nil {
+		return "", err
+	}
+	if !ok || len(ref.path) > 0 {
+		p.pos = start
+		return "", p.errorf(start, "expected a variable, e.g. $name")
+	}
+	return ref.name, nil
+}
+
+// parseReference parses the reference at the $. ok is false when the $ isn't
+// followed by an identifier and so is text.
+func (p *parser) parseReference() (ref *reference, quiet, ok bool, err error) {
+	start := p.pos
+	p.pos++
+	if p.peek(0) == '!' {
+		quiet = true
+		p.pos++
+	}
+	braced := p.peek(0) == '{'
+	if braced {
+		p.pos++
+	}
+	name := p.ident()
+	if name == "" {
+		p.pos = start
+		return nil, false, false, nil
+	}
+	ref = &reference{name: name, pos: start}
+	for {
+		if p.peek(0) == '.' && isIdentStart(p.peek(1)) {
+			p.pos++
+			seg := segment{pos: p.pos}
+			seg.name = p.ident()
+			if p.peek(0) == '(' {
+				p.pos++
+				seg.call = true
+				if seg.args, err = p.parseArgs(); err != nil {
+					return nil, false, false, err
+				}
+			}
+			ref.path = append(ref.path, seg)
+			continue
+		}
+		break
+	}
+	if braced {
+		if p.peek(0) != '}' {
+			return nil, false, false, p.unexpected("\"}\"")
+		}
+		p.pos++
+	}
+	return ref, quiet, true, nil
+}
+
+// parseArgs parses the arguments of a method call up to the closing
+// parenthesis.
+func (p *parser) parseArgs() ([]expr, error) {
+	var args []expr
+	p.skipSpace()
+	if p.peek(0) == ')' {
+		p.pos++
+		return args, nil
+	}
+	for {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		p.skipSpace()
+		switch p.peek(0) {
+		case ',':
+			p.pos++
+			continue
+		case ')':
+			p.pos++
+			return args, nil
+		}
+		return nil, p.unexpected("\",\" or \")\"")
+	}
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < p.end && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// word returns the identifier at the position, without consuming it when it
+// isn't a word operator or keyword the caller uses.
+func (p *parser) word() string {
+	start := p.pos
+	w := p.ident()
+	if w == "" {
+		p.pos = start
+	}
+	return w
+}
+
+// operator consumes and returns one of the operators, or their word form, at
+// the position.
+func (p *parser) operator(ops ...string) string {
+	p.skipSpace()
+	for _, op := range ops {
+		if p.hasPrefix(op) && !(op == "!" && p.peek(1) == '=') && !((op == "<" || op == ">") && p.peek(1) == '=') {
+			p.pos += len(op)
+			return op
+		}
+	}
+	start := p.pos
+	if w := p.word(); w != "" {
+		for _, op := range ops {
+			if wordOperators[w] == op {
+				return op
+			}
+		}
+	}
+	p.pos = start
+	return ""
+}
+
+func (p *parser) parseExpr() (expr, error) {
+	return p.parseBinary(0)
+}
+
+// precedence lists the binary operators from the lowest to the highest
+// precedence.
+var precedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<=", ">=", "<", ">"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseBinary(level int) (expr, error) {
+	if level == len(precedence) {
+		return p.parseUnary()
+	}
+	x, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.operator(precedence[level]...)
+		if op == "" {
+			return x, nil
+		}
+		y, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &binaryExpr{op: op, x: x, y: y}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if op := p.operator("!"); op != "" {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: op, x: x}, nil
+	}
+	if p.peek(0) == '-' && unicode.IsDigit(p.peek(1)) {
+		p.pos++
+		x, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "-", x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	p.skipSpace()
+	r := p.peek(0)
+	switch {
+	case p.pos >= p.end:
+		return nil, p.errorf(p.pos, "unexpected end of template")
+	case r == '(':
+		p.pos++
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expectClose()
+	case r == '"':
+		return p.parseString()
+	case r == '\'':
+		start := p.pos
+		p.pos++
+		for p.pos < p.end && p.src[p.pos] != '\'' {
+			p.pos++
+		}
+		if p.pos >= p.end {
+			return nil, p.errorf(start, "unterminated string literal")
+		}
+		p.pos++
+		return &literal{value: string(p.src[start+1 : p.pos-1])}, nil
+	case unicode.IsDigit(r):
+		return p.parseNumber()
+	case r == '$':
+		start := p.pos
+		ref, _, ok, err := p.parseReference()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, p.errorf(start, "expected a reference after \"$\"")
+		}
+		return &refExpr{ref: ref}, nil
+	case r == '[':
+		return p.parseList()
+	}
+	start := p.pos
+	switch w := p.word(); w {
+	case "true", "false":
+		return &literal{value: w == "true"}, nil
+	case "null":
+		return &literal{}, nil
+	}
+	p.pos = start
+	return nil, p.errorf(start, "unexpected %q", string(r))
+}
+
+func (p *parser) parseNumber() (expr, error) {
+	start := p.pos
+	for unicode.IsDigit(p.peek(0)) {
+		p.pos++
+	}
+	if p.peek(0) == '.' && unicode.IsDigit(p.peek(1)) {
+		p.pos++
+		for unicode.IsDigit(p.peek(0)) {
+			p.pos++
+		}
+		f, err := strconv.ParseFloat(string(p.src[start:p.pos]), 64)
+		if err != nil {
+			return nil, p.errorf(start, "invalid number")
+		}
+		return &literal{value: f}, nil
+	}
+	i, err := strconv.ParseInt(string(p.src[start:p.pos]), 10, 64)
+	if err != nil {
+		return nil, p.errorf(start, "invalid number")
+	}
+	return &literal{value: i}, nil
+}
+
+// parseString parses a double quoted string, whose references are rendered.
+func (p *parser) parseString() (expr, error) {
+	start := p.pos
+	p.pos++
+	contentStart := p.pos
+	for p.pos < p.end && p.src[p.pos] != '"' {
+		p.pos++
+	}
+	if p.pos >= p.end {
+		return nil, p.errorf(start, "unterminated string literal")
+	}
+	sub := &parser{src: p.src, pos: contentStart, end: p.pos}
+	nodes, term, termPos, err := sub.parseNodes()
+	if err != nil {
+		return nil, err
+	}
+	if term != "" {
+		return nil, p.errorf(termPos, "#%s in a string literal", term)
+	}
+	p.pos++
+	return &interpolated{nodes: nodes}, nil
+}
+
+// parseList parses a list, e.g. ["a", "b"], or a range, e.g. [1..3].
+func (p *parser) parseList() (expr, error) {
+	p.pos++
+	p.skipSpace()
+	if p.peek(0) == ']' {
+		p.pos++
+		return &listExpr{}, nil
+	}
+	first, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.hasPrefix("..") {
+		p.pos += 2
+		last, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.peek(0) != ']' {
+			return nil, p.unexpected("\"]\"")
+		}
+		p.pos++
+		return &rangeExpr{from: first, to: last}, nil
+	}
+	list := &listExpr{items: []expr{first}}
+	for {
+		p.skipSpace()
+		switch p.peek(0) {
+		case ']':
+			p.pos++
+			return list, nil
+		case ',':
+			p.pos++
+			item, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			list.items = append(list.items, item)
+			continue
+		}
+		return nil, p.unexpected("\",\" or \"]\"")
+	}
+}
@@ -0,0 +1,494 @@
+package velocity
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Function is a function of the f object of Okta email templates, e.g.
+// ${f.escapeHtml($name)}. ok is false when the arguments aren't valid.
+type Function func(args []interface{}) (result interface{}, ok bool)
+
+// Functions are the functions of the f object of Okta email templates, see
+// https://developer.okta.com/docs/guides/custom-email/main/
+var Functions = map[string]Function{
+	"escapeHtml":                         stringFunction(html.EscapeString),
+	"escapeHtmlAttr":                     stringFunction(escapeHTMLAttr),
+	"formatTimeDiffDateNow":              formatTimeDiffDateNow,
+	"formatTimeDiffDateNowInUserLocale":  formatTimeDiffDateNow,
+	"formatTimeDiffHoursNow":             formatTimeDiffHoursNow,
+	"formatTimeDiffHoursNowInUserLocale": formatTimeDiffHoursNow,
+	"substringAfter":                     substringFunction(true),
+	"substringBefore":                    substringFunction(false),
+}
+
+// functionsObject is the value of the f object.
+type functionsObject struct{}
+
+func stringFunction(fn func(string) string) Function {
+	return func(args []interface{}) (interface{}, bool) {
+		if len(args) != 1 {
+			return nil, false
+		}
+		return fn(toString(args[0])), true
+	}
+}
+
+func escapeHTMLAttr(s string) string {
+	return strings.NewReplacer("`", "&#96;", "=", "&#61;").Replace(html.EscapeString(s))
+}
+
+func substringFunction(after bool) Function {
+	return func(args []interface{}) (interface{}, bool) {
+		if len(args) != 2 {
+			return nil, false
+		}
+		s, sep := toString(args[0]), toString(args[1])
+		i := strings.Index(s, sep)
+		switch {
+		case i < 0 && after:
+			return "", true
+		case i < 0:
+			return s, true
+		case after:
+			return s[i+len(sep):], true
+		}
+		return s[:i], true
+	}
+}
+
+// formatTimeDiffHoursNow formats a number of hours as a duration, e.g. "7
+// days" or "2 hours".
+func formatTimeDiffHoursNow(args []interface{}) (interface{}, bool) {
+	if len(args) != 1 {
+		return nil, false
+	}
+	hours, ok := toNumber(args[0])
+	if !ok {
+		return nil, false
+	}
+	return formatHours(int64(hours)), true
+}
+
+// formatTimeDiffDateNow formats the time from now until an ISO 8601 date as a
+// duration.
+func formatTimeDiffDateNow(args []interface{}) (interface{}, bool) {
+	if len(args) != 1 {
+		return nil, false
+	}
+	t, err := time.Parse(time.RFC3339, toString(args[0]))
+	if err != nil {
+		return nil, false
+	}
+	return formatHours(int64(time.Until(t).Round(time.Hour).Hours())), true
+}
+
+func formatHours(hours int64) string {
+	n, unit := hours, "hour"
+	if hours != 0 && hours%24 == 0 {
+		n, unit = hours/24, "day"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", n, unit)
+}
+
+// stringMethods are the Java String methods templates may call on strings.
+var stringMethods = map[string]func(s string, args []interface{}) (interface{}, bool){
+	"toUpperCase": func(s string, args []interface{}) (interface{}, bool) { return strings.ToUpper(s), len(args) == 0 },
+	"toLowerCase": func(s string, args []interface{}) (interface{}, bool) { return strings.ToLower(s), len(args) == 0 },
+	"trim":        func(s string, args []interface{}) (interface{}, bool) { return strings.TrimSpace(s), len(args) == 0 },
+	"length":      func(s string, args []interface{}) (interface{}, bool) { return int64(len([]rune(s))), len(args) == 0 },
+	"isEmpty":     func(s string, args []interface{}) (interface{}, bool) { return s == "", len(args) == 0 },
+	"contains": func(s string, args []interface{}) (interface{}, bool) {
+		return len(args) == 1 && strings.Contains(s, toString(args[0])), len(args) == 1
+	},
+	"startsWith": func(s string, args []interface{}) (interface{}, bool) {
+		return len(args) == 1 && strings.HasPrefix(s, toString(args[0])), len(args) == 1
+	},
+	"endsWith": func(s string, args []interface{}) (interface{}, bool) {
+		return len(args) == 1 && strings.HasSuffix(s, toString(args[0])), len(args) == 1
+	},
+	"equals": func(s string, args []interface{}) (interface{}, bool) {
+		return len(args) == 1 && s == toString(args[0]), len(args) == 1
+	},
+	"replace": func(s string, args []interface{}) (interface{}, bool) {
+		if len(args) != 2 {
+			return nil, false
+		}
+		return strings.ReplaceAll(s, toString(args[0]), toString(args[1])), true
+	},
+}
+
+type renderer struct {
+	vars map[string]interface{}
+}
+
+// Render renders the template with the variables of the context. Values are
+// strings, numbers, booleans, []interface{} lists and map[string]interface{}
+// objects whose properties are referenced with dots. The f object is provided.
+// Like Velocity, undefined references are rendered as written unless quiet,
+// e.g. $!{name}.
+func (t *Template) Render(context map[string]interface{}) string {
+	r := &renderer{vars: map[string]interface{}{"f": functionsObject{}}}
+	for k, v := range context {
+		r.vars[k] = v
+	}
+	var sb strings.Builder
+	r.render(&sb, t.nodes)
+	return sb.String()
+}
+
+func (r *renderer) render(sb *strings.Builder, nodes []node) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *textNode:
+			sb.WriteString(n.text)
+		case *refNode:
+			v, ok := r.resolve(n.ref)
+			switch {
+			case ok:
+				sb.WriteString(toString(v))
+			case !n.quiet:
+				sb.WriteString(n.raw)
+			}
+		case *setNode:
+			r.vars[n.name] = r.eval(n.value)
+		case *ifNode:
+			r.renderIf(sb, n)
+		case *foreachNode:
+			r.renderForeach(sb, n)
+		}
+	}
+}
+
+func (r *renderer) renderIf(sb *strings.Builder, n *ifNode) {
+	for i, cond := range n.conds {
+		if truthy(r.eval(cond)) {
+			r.render(sb, n.bodies[i])
+			return
+		}
+	}
+	r.render(sb, n.elseBody)
+}
+
+func (r *renderer) renderForeach(sb *strings.Builder, n *foreachNode) {
+	items := toList(r.eval(n.list))
+	saved := make(map[string]interface{})
+	for _, name := range []string{n.name, "foreach", "velocityCount"} {
+		if v, ok := r.vars[name]; ok {
+			saved[name] = v
+		}
+	}
+	for i, item := range items {
+		r.vars[n.name] = item
+		r.vars["foreach"] = map[string]interface{}{
+			"count":   int64(i + 1),
+			"index":   int64(i),
+			"hasNext": i < len(items)-1,
+			"first":   i == 0,
+			"last":    i == len(items)-1,
+		}
+		r.vars["velocityCount"] = int64(i + 1)
+		r.render(sb, n.body)
+	}
+	for _, name := range []string{n.name, "foreach", "velocityCount"} {
+		if v, ok := saved[name]; ok {
+			r.vars[name] = v
+		} else {
+			delete(r.vars, name)
+		}
+	}
+}
+
+// resolve returns the value of a reference, false when it is undefined.
+func (r *renderer) resolve(ref *reference) (interface{}, bool) {
+	v, ok := r.vars[ref.name]
+	for _, seg := range ref.path {
+		if !ok || v == nil {
+			return nil, false
+		}
+		if seg.call {
+			args := make([]interface{}, len(seg.args))
+			for i, arg := range seg.args {
+				args[i] = r.eval(arg)
+			}
+			v, ok = call(v, seg.name, args)
+			continue
+		}
+		v, ok = property(v, seg.name)
+	}
+	return v, ok && v != nil
+}
+
+func property(v interface{}, name string) (interface{}, bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		p, ok := v[name]
+		return p, ok
+	case map[string]string:
+		p, ok := v[name]
+		return p, ok
+	}
+	return nil, false
+}
+
+func call(v interface{}, name string, args []interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case functionsObject:
+		fn, ok := Functions[name]
+		if !ok {
+			return nil, false
+		}
+		return fn(args)
+	case string:
+		method, ok := stringMethods[name]
+		if !ok {
+			return nil, false
+		}
+		return method(v, args)
+	case map[string]interface{}:
+		switch name {
+		case "get":
+			if len(args) == 1 {
+				p, ok := v[toString(args[0])]
+				return p, ok
+			}
+		case "size":
+			return int64(len(v)), len(args) == 0
+		case "isEmpty":
+			return len(v) == 0, len(args) == 0
+		}
+	case []interface{}:
+		switch name {
+		case "get":
+			if n, ok := toNumber(firstArg(args)); ok && len(args) == 1 && int(n) >= 0 && int(n) < len(v) {
+				return v[int(n)], true
+			}
+		case "size":
+			return int64(len(v)), len(args) == 0
+		case "isEmpty":
+			return len(v) == 0, len(args) == 0
+		case "contains":
+			for _, item := range v {
+				if len(args) == 1 && equal(item, args[0]) {
+					return true, true
+				}
+			}
+			return false, len(args) == 1
+		}
+	}
+	return nil, false
+}
+
+func firstArg(args []interface{}) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	return args[0]
+}
+
+func (r *renderer) eval(e expr) interface{} {
+	switch e := e.(type) {
+	case *literal:
+		return e.value
+	case *interpolated:
+		var sb strings.Builder
+		r.render(&sb, e.nodes)
+		return sb.String()
+	case *refExpr:
+		v, _ := r.resolve(e.ref)
+		return v
+	case *listExpr:
+		items := make([]interface{}, len(e.items))
+		for i, item := range e.items {
+			items[i] = r.eval(item)
+		}
+		return items
+	case *rangeExpr:
+		from, ok1 := toNumber(r.eval(e.from))
+		to, ok2 := toNumber(r.eval(e.to))
+		if !ok1 || !ok2 {
+			return nil
+		}
+		var items []interface{}
+		step := int64(1)
+		if to < from {
+			step = -1
+		}
+		for i := int64(from); ; i += step {
+			items = append(items, i)
+			if i == int64(to) {
+				break
+			}
+		}
+		return items
+	case *unaryExpr:
+		x := r.eval(e.x)
+		if e.op == "!" {
+			return !truthy(x)
+		}
+		if n, ok := x.(int64); ok {
+			return -n
+		}
+		if n, ok := toNumber(x); ok {
+			return -n
+		}
+		return nil
+	case *binaryExpr:
+		return r.evalBinary(e)
+	}
+	return nil
+}
+
+func (r *renderer) evalBinary(e *binaryExpr) interface{} {
+	switch e.op {
+	case "&&":
+		return truthy(r.eval(e.x)) && truthy(r.eval(e.y))
+	case "||":
+		return truthy(r.eval(e.x)) || truthy(r.eval(e.y))
+	}
+	x, y := r.eval(e.x), r.eval(e.y)
+	switch e.op {
+	case "==":
+		return equal(x, y)
+	case "!=":
+		return !equal(x, y)
+	}
+	xi, xInt := x.(int64)
+	yi, yInt := y.(int64)
+	xn, xOk := toNumber(x)
+	yn, yOk := toNumber(y)
+	if !xOk || !yOk {
+		if xs, ok := x.(string); ok && e.op == "+" {
+			return xs + toString(y)
+		}
+		return nil
+	}
+	switch e.op {
+	case "<":
+		return xn < yn
+	case ">":
+		return xn > yn
+	case "<=":
+		return xn <= yn
+	case ">=":
+		return xn >= yn
+	}
+	if xInt && yInt {
+		switch e.op {
+		case "+":
+			return xi + yi
+		case "-":
+			return xi - yi
+		case "*":
+			return xi * yi
+		case "/":
+			if yi == 0 {
+				return nil
+			}
+			return xi / yi
+		case "%":
+			if yi == 0 {
+				return nil
+			}
+			return xi % yi
+		}
+	}
+	switch e.op {
+	case "+":
+		return xn + yn
+	case "-":
+		return xn - yn
+	case "*":
+		return xn * yn
+	case "/":
+		if yn == 0 {
+			return nil
+		}
+		return xn / yn
+	}
+	return nil
+}
+
+// truthy follows Velocity, only null and false are false.
+func truthy(v interface{}) bool {
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	return v != nil
+}
+
+func equal(x, y interface{}) bool {
+	if x == nil || y == nil {
+		return x == nil && y == nil
+	}
+	xn, xOk := toNumber(x)
+	yn, yOk := toNumber(y)
+	if xOk && yOk {
+		return xn == yn
+	}
+	return toString(x) == toString(y)
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func toList(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		return v
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return items
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := make([]interface{}, len(keys))
+		for i, k := range keys {
+			items[i] = v[k]
+		}
+		return items
+	}
+	return nil
+}
+
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = toString(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return fmt.Sprint(v)
+}
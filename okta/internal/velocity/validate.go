@@ -0,0 +1,156 @@
+package velocity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Reference is a reference to a variable of the template's context, e.g.
+// ${user.profile.firstName}.
+type Reference struct {
+	// Name is the variable and the properties accessed, e.g.
+	// user.profile.firstName
+	Name string
+	// Method is the method called on the variable, if any, e.g. toUpperCase
+	// for $user.profile.firstName.toUpperCase()
+	Method string
+	// Line and Column are the position of the reference in the template
+	Line   int
+	Column int
+}
+
+// localVariables are set by #foreach.
+var localVariables = []string{"foreach", "velocityCount"}
+
+// Validate parses the template, checks that the functions it calls are
+// functions of the f object, and returns the context variables it references.
+// Variables assigned by #set and #foreach aren't context variables.
+func Validate(src string) ([]Reference, error) {
+	t, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	v := &validator{src: t.src, locals: make(map[string]bool)}
+	for _, name := range localVariables {
+		v.locals[name] = true
+	}
+	v.collectLocals(t.nodes)
+	v.walkNodes(t.nodes)
+	if v.err != nil {
+		return nil, v.err
+	}
+	return v.refs, nil
+}
+
+type validator struct {
+	src    []rune
+	locals map[string]bool
+	refs   []Reference
+	err    error
+}
+
+func (v *validator) collectLocals(nodes []node) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *setNode:
+			v.locals[n.name] = true
+		case *foreachNode:
+			v.locals[n.name] = true
+			v.collectLocals(n.body)
+		case *ifNode:
+			for _, body := range n.bodies {
+				v.collectLocals(body)
+			}
+			v.collectLocals(n.elseBody)
+		}
+	}
+}
+
+func (v *validator) walkNodes(nodes []node) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *refNode:
+			v.walkReference(n.ref)
+		case *setNode:
+			v.walkExpr(n.value)
+		case *foreachNode:
+			v.walkExpr(n.list)
+			v.walkNodes(n.body)
+		case *ifNode:
+			for i, cond := range n.conds {
+				v.walkExpr(cond)
+				v.walkNodes(n.bodies[i])
+			}
+			v.walkNodes(n.elseBody)
+		}
+	}
+}
+
+func (v *validator) walkExpr(e expr) {
+	switch e := e.(type) {
+	case *interpolated:
+		v.walkNodes(e.nodes)
+	case *refExpr:
+		v.walkReference(e.ref)
+	case *listExpr:
+		for _, item := range e.items {
+			v.walkExpr(item)
+		}
+	case *rangeExpr:
+		v.walkExpr(e.from)
+		v.walkExpr(e.to)
+	case *unaryExpr:
+		v.walkExpr(e.x)
+	case *binaryExpr:
+		v.walkExpr(e.x)
+		v.walkExpr(e.y)
+	}
+}
+
+func (v *validator) walkReference(ref *reference) {
+	for _, seg := range ref.path {
+		for _, arg := range seg.args {
+			v.walkExpr(arg)
+		}
+	}
+	if v.err != nil {
+		return
+	}
+	if ref.name == "f" {
+		if len(ref.path) == 0 || !ref.path[0].call {
+			v.errorf(ref.pos, "f is the object of the template functions, call one, e.g. ${f.escapeHtml($value)}")
+			return
+		}
+		if _, ok := Functions[ref.path[0].name]; !ok {
+			names := make([]string, 0, len(Functions))
+			for name := range Functions {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			v.errorf(ref.path[0].pos, "unknown function f.%s, functions are: %s", ref.path[0].name, strings.Join(names, ", "))
+		}
+		return
+	}
+	if v.locals[ref.name] {
+		return
+	}
+	r := Reference{Name: ref.name}
+	r.Line, r.Column = position(v.src, ref.pos)
+	for _, seg := range ref.path {
+		if seg.call {
+			r.Method = seg.name
+			break
+		}
+		r.Name += "." + seg.name
+	}
+	v.refs = append(v.refs, r)
+}
+
+func (v *validator) errorf(pos int, format string, args ...interface{}) {
+	if v.err != nil {
+		return
+	}
+	line, col := position(v.src, pos)
+	v.err = &SyntaxError{Line: line, Column: col, Msg: fmt.Sprintf(format, args...)}
+}
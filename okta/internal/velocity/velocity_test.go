@@ -0,0 +1,119 @@
+package velocity
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		src  string
+		refs []Reference
+	}{
+		{`Hello ${user.profile.firstName}!`, []Reference{{Name: "user.profile.firstName", Line: 1, Column: 7}}},
+		{"Hi $user.profile.firstName.\n<a href=\"$!{activationLink}\">", []Reference{
+			{Name: "user.profile.firstName", Line: 1, Column: 4},
+			{Name: "activationLink", Line: 2, Column: 10},
+		}},
+		{`${f.formatTimeDiffHoursNowInUserLocale(${org.activationTokenExpirationHours})}`, []Reference{
+			{Name: "org.activationTokenExpirationHours", Line: 1, Column: 40},
+		}},
+		{`#if(${user.profile.locale} == "fr")Bonjour#elseif($user.profile.locale eq 'de')Hallo#{else}Hello#end`, []Reference{
+			{Name: "user.profile.locale", Line: 1, Column: 5},
+			{Name: "user.profile.locale", Line: 1, Column: 51},
+		}},
+		{`#set($name = "$user.profile.firstName $user.profile.lastName")$name.toUpperCase()`, []Reference{
+			{Name: "user.profile.firstName", Line: 1, Column: 15},
+			{Name: "user.profile.lastName", Line: 1, Column: 39},
+		}},
+		{`#foreach($i in [1..3])$i of $foreach.count#end`, nil},
+		{`${user.profile.email.substring(0)}`, []Reference{{Name: "user.profile.email", Method: "substring", Line: 1, Column: 1}}},
+		{`color: #ff0000; ## comment ${ignored}
+#* block $ignored *#costs $5 \$escaped #[[$unparsed]]#`, nil},
+	}
+	for _, test := range tests {
+		refs, err := Validate(test.src)
+		if err != nil {
+			t.Errorf("%s: did not expect error, got %v", test.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(refs, test.refs) {
+			t.Errorf("%s: expected references %+v, got %+v", test.src, test.refs, refs)
+		}
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		err string
+	}{
+		{`${user.profile.firstName`, "expected \"}\", got end of template at line 1, column 25"},
+		{`#if($a)yes`, "#if without #end at line 1, column 1"},
+		{"a\n#end", "#end without #if or #foreach at line 2, column 1"},
+		{`#if($a)a#else b#elseif($b)c#end`, "#elseif after #else at line 1, column 16"},
+		{`#if $a)#end`, "expected \"(\" after #if at line 1, column 5"},
+		{`#if($a == )#end`, "unexpected \")\" at line 1, column 11"},
+		{`#set($a.b = 1)`, "expected a variable, e.g. $name at line 1, column 6"},
+		{`#foreach($a of $b)#end`, "expected \"in\" after the #foreach variable at line 1, column 13"},
+		{`${f.escapeHTML($a)}`, "unknown function f.escapeHTML, functions are: escapeHtml, escapeHtmlAttr, formatTimeDiffDateNow, formatTimeDiffDateNowInUserLocale, formatTimeDiffHoursNow, formatTimeDiffHoursNowInUserLocale, substringAfter, substringBefore at line 1, column 5"},
+		{`${f}`, "f is the object of the template functions, call one, e.g. ${f.escapeHtml($value)} at line 1, column 1"},
+		{`#parse("header.vm")`, "#parse is not supported at line 1, column 1"},
+		{`#* open`, "unterminated comment at line 1, column 1"},
+		{`$a.b("c)`, "unterminated string literal at line 1, column 6"},
+	}
+	for _, test := range tests {
+		_, err := Validate(test.src)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%s: expected syntax error, got %v", test.src, err)
+			continue
+		}
+		if err.Error() != test.err {
+			t.Errorf("%s: expected error %q, got %q", test.src, test.err, err.Error())
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	context := map[string]interface{}{
+		"user": map[string]interface{}{
+			"profile": map[string]interface{}{
+				"firstName": "Jane",
+				"lastName":  "<Doe>",
+				"locale":    "fr",
+			},
+		},
+		"org": map[string]interface{}{
+			"activationTokenExpirationHours": int64(168),
+		},
+		"groups": []interface{}{"Admins", "Everyone"},
+	}
+	tests := []struct {
+		src      string
+		rendered string
+	}{
+		{`Hello ${user.profile.firstName} ${f.escapeHtml($user.profile.lastName)}`, "Hello Jane &lt;Doe&gt;"},
+		{`Expires in ${f.formatTimeDiffHoursNowInUserLocale(${org.activationTokenExpirationHours})}`, "Expires in 7 days"},
+		{`#if($user.profile.locale == "fr")Bonjour#elseif($user.profile.locale == "de")Hallo#else Hello#end`, "Bonjour"},
+		{`#if(!$user.profile.missing && $org.activationTokenExpirationHours > 24)yes#end`, "yes"},
+		{`#set($n = "$user.profile.firstName!")$n.toUpperCase() $n.length()`, "JANE! 5"},
+		{`#foreach($g in $groups)$foreach.count. $g#if($foreach.hasNext), #end#end`, "1. Admins, 2. Everyone"},
+		{`#foreach($i in [3..1])$i#end`, "321"},
+		{`${missing} $!{missing} $missing.name $!missing`, "${missing}  $missing.name "},
+		{`${f.substringBefore("jane@example.com", "@")} ${f.substringAfter("jane@example.com", "@")}`, "jane example.com"},
+		{`#set($total = 2 * 3 + 1)$total $user.profile.firstName-san`, "7 Jane-san"},
+		{"Line ## comment\nnext #ff0000 costs $5", "Line next #ff0000 costs $5"},
+	}
+	for _, test := range tests {
+		tmpl, err := Parse(test.src)
+		if err != nil {
+			t.Errorf("%s: did not expect error, got %v", test.src, err)
+			continue
+		}
+		if rendered := tmpl.Render(context); rendered != test.rendered {
+			t.Errorf("%s: expected %q, got %q", test.src, test.rendered, rendered)
+		}
+	}
+}
@@ -0,0 +1,483 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// logStreamSetting is a setting of a log stream type, an attribute of the
+// block named after the type.
+type logStreamSetting struct {
+	// json is the name of the setting in the settings object of the API.
+	json        string
+	description string
+	required    bool
+	// forceNew settings can't be changed once the log stream is created.
+	forceNew bool
+	// secret settings are only sent when the log stream is created, Okta
+	// doesn't return them and they can't be updated.
+	secret     bool
+	validators []validator.String
+}
+
+// logStreamType is a streaming provider Okta can send the System Log to.
+type logStreamType struct {
+	title    string
+	settings map[string]logStreamSetting
+}
+
+// logStreamTypes are the log stream types the provider supports. Adding a
+// type only takes an entry here, its settings block and validation are built
+// from it.
+var logStreamTypes = map[string]logStreamType{
+	logStreamTypeEventBridge: {
+		title: "AWS EventBridge",
+		settings: map[string]logStreamSetting{
+			"account_id": {
+				json:        "accountId",
+				description: "AWS account ID",
+				required:    true,
+				forceNew:    true,
+				validators: []validator.String{
+					stringvalidator.LengthBetween(12, 12),
+				},
+			},
+			"event_source_name": {
+				json:        "eventSourceName",
+				description: "An alphanumeric name (no spaces) to identify this event source in AWS EventBridge",
+				required:    true,
+				forceNew:    true,
+				validators: []validator.String{
+					stringvalidator.RegexMatches(awsEventBridgeEventSourceNameRegex, "Event Source must have an alphanumeric name (no spaces) shorter than 76 characters"),
+				},
+			},
+			"region": {
+				json:        "region",
+				description: "The destination AWS region where event source is located",
+				required:    true,
+				forceNew:    true,
+			},
+		},
+	},
+	logStreamTypeSplunk: {
+		title: "Splunk Cloud",
+		settings: map[string]logStreamSetting{
+			"edition": {
+				json:        "edition",
+				description: "Edition of the Splunk Cloud instance. Could be one of: 'aws', 'aws_govcloud', 'gcp'",
+				required:    true,
+				validators: []validator.String{
+					stringvalidator.OneOf([]string{
+						logStreamSplunkEditionAws,
+						logStreamSplunkEditionAwsGovCloud,
+						logStreamSplunkEditionGcp,
+					}...),
+				},
+			},
+			"host": {
+				json:        "host",
+				description: "The domain name for Splunk Cloud instance. Don't include http or https in the string. For example: 'acme.splunkcloud.com'",
+				required:    true,
+				validators: []validator.String{
+					stringvalidator.RegexMatches(
+						splunkHostRegex,
+						"Splunk host must match the pattern: `^(?!(?:http-inputs-))([a-z0-9]+(-[a-z0-9]+)*){1,100}\\\\.splunkcloud(gc\\\\.com|fed\\\\.com|\\\\.com|\\\\.mil)$`",
+					),
+				},
+			},
+			"token": {
+				json:        "token",
+				description: "The HEC token for your Splunk Cloud HTTP Event Collector",
+				required:    true,
+				forceNew:    true,
+				secret:      true,
+				validators: []validator.String{
+					stringvalidator.RegexMatches(
+						splunkTokenRegex,
+						"Splunk token must match the pattern: `(?i)^[0-9a-f]{8}-[0-9a-f]{4}-[1-5][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`",
+					),
+				},
+			},
+		},
+	},
+}
+
+// logStreamLegacySettings is the deprecated block holding the settings of all
+// the types.
+const logStreamLegacySettings = "settings"
+
+// sortedLogStreamTypes returns the names of the log stream types.
+func sortedLogStreamTypes() []string {
+	names := make([]string, 0, len(logStreamTypes))
+	for name := range logStreamTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// logStreamTypesDescription lists the log stream types for descriptions.
+func logStreamTypesDescription() string {
+	names := sortedLogStreamTypes()
+	for i, name := range names {
+		names[i] = fmt.Sprintf("'%s'", name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// logStreamLegacySetting returns the setting of the deprecated settings block
+// named name and the type it belongs to.
+func logStreamLegacySetting(name string) (string, logStreamSetting) {
+	for _, typeName := range sortedLogStreamTypes() {
+		if setting, ok := logStreamTypes[typeName].settings[name]; ok {
+			return typeName, setting
+		}
+	}
+	return "", logStreamSetting{}
+}
+
+// logStreamBlockSettings returns the settings of a settings block, the
+// deprecated settings block holds the settings of all the types.
+func logStreamBlockSettings(block string) map[string]logStreamSetting {
+	if block != logStreamLegacySettings {
+		return logStreamTypes[block].settings
+	}
+	settings := make(map[string]logStreamSetting)
+	for _, typeName := range sortedLogStreamTypes() {
+		for name, setting := range logStreamTypes[typeName].settings {
+			if _, ok := settings[name]; !ok {
+				settings[name] = setting
+			}
+		}
+	}
+	return settings
+}
+
+// logStreamBlocks returns the names of the settings blocks.
+func logStreamBlocks() []string {
+	return append(sortedLogStreamTypes(), logStreamLegacySettings)
+}
+
+func logStreamBlockAttrTypes(block string) map[string]attr.Type {
+	attrTypes := make(map[string]attr.Type)
+	for name := range logStreamBlockSettings(block) {
+		attrTypes[name] = types.StringType
+	}
+	return attrTypes
+}
+
+// logStreamModel is the configuration and state of a log stream, the settings
+// blocks are keyed by block name as they are built from logStreamTypes.
+type logStreamModel struct {
+	ID       types.String
+	Name     types.String
+	Type     types.String
+	Status   types.String
+	Settings map[string]types.Object
+}
+
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, p path.Path, target interface{}) diag.Diagnostics
+}
+
+type attributeSetter interface {
+	SetAttribute(ctx context.Context, p path.Path, val interface{}) diag.Diagnostics
+}
+
+func getLogStreamModel(ctx context.Context, src attributeGetter) (*logStreamModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	m := &logStreamModel{Settings: make(map[string]types.Object)}
+	diags.Append(src.GetAttribute(ctx, path.Root("id"), &m.ID)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("name"), &m.Name)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("type"), &m.Type)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("status"), &m.Status)...)
+	for _, block := range logStreamBlocks() {
+		var settings types.Object
+		diags.Append(src.GetAttribute(ctx, path.Root(block), &settings)...)
+		m.Settings[block] = settings
+	}
+	return m, diags
+}
+
+func setLogStreamModel(ctx context.Context, dst attributeSetter, m *logStreamModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	diags.Append(dst.SetAttribute(ctx, path.Root("id"), m.ID)...)
+	diags.Append(dst.SetAttribute(ctx, path.Root("name"), m.Name)...)
+	diags.Append(dst.SetAttribute(ctx, path.Root("type"), m.Type)...)
+	diags.Append(dst.SetAttribute(ctx, path.Root("status"), m.Status)...)
+	for _, block := range logStreamBlocks() {
+		settings, ok := m.Settings[block]
+		if !ok {
+			settings = types.ObjectNull(logStreamBlockAttrTypes(block))
+		}
+		diags.Append(dst.SetAttribute(ctx, path.Root(block), settings)...)
+	}
+	return diags
+}
+
+// settingsBlock returns the block the settings of the log stream are set in,
+// the block of its type unless only the deprecated settings block is set.
+func (m *logStreamModel) settingsBlock() string {
+	if m.Settings[m.Type.ValueString()].IsNull() && !m.Settings[logStreamLegacySettings].IsNull() {
+		return logStreamLegacySettings
+	}
+	return m.Type.ValueString()
+}
+
+// setting returns the value of a setting of the log stream's type.
+func (m *logStreamModel) setting(name string) types.String {
+	settings := m.Settings[m.settingsBlock()]
+	if settings.IsNull() || settings.IsUnknown() {
+		return types.StringNull()
+	}
+	v, _ := settings.Attributes()[name].(types.String)
+	return v
+}
+
+// validateLogStream checks the settings of the log stream are set in the
+// block of its type, or in the deprecated settings block, and that the
+// required settings of the type are set.
+func validateLogStream(m *logStreamModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	typeName := m.Type.ValueString()
+	t, ok := logStreamTypes[typeName]
+	if m.Type.IsUnknown() || !ok {
+		return diags
+	}
+	for _, block := range sortedLogStreamTypes() {
+		if block != typeName && !m.Settings[block].IsNull() {
+			diags.AddAttributeError(path.Root(block), "Invalid log stream settings",
+				fmt.Sprintf("The %s block can only be set when type is %q", block, block))
+		}
+	}
+	legacy := m.Settings[logStreamLegacySettings]
+	if !legacy.IsNull() && !m.Settings[typeName].IsNull() {
+		diags.AddAttributeError(path.Root(logStreamLegacySettings), "Invalid log stream settings",
+			fmt.Sprintf("The settings block is deprecated and can't be set along with the %s block, set only the %s block", typeName, typeName))
+	}
+	if diags.HasError() {
+		return diags
+	}
+	block := m.settingsBlock()
+	settings := m.Settings[block]
+	if settings.IsUnknown() {
+		return diags
+	}
+	if settings.IsNull() {
+		diags.AddAttributeError(path.Root(typeName), "Missing log stream settings",
+			fmt.Sprintf("The %s block is required when type is %q", typeName, typeName))
+		return diags
+	}
+	for name, v := range settings.Attributes() {
+		s, _ := v.(types.String)
+		if s.IsNull() {
+			continue
+		}
+		if _, ok := t.settings[name]; !ok {
+			diags.AddAttributeError(path.Root(block).AtName(name), "Invalid log stream setting",
+				fmt.Sprintf("%s is not a setting of %s log streams", name, t.title))
+		}
+	}
+	for name, setting := range t.settings {
+		if setting.required && m.setting(name).IsNull() {
+			diags.AddAttributeError(path.Root(block).AtName(name), "Missing log stream setting",
+				fmt.Sprintf("%s is required when type is %q", name, typeName))
+		}
+	}
+	return diags
+}
+
+// apiLogStream is a log stream of any type as the API sends and receives it,
+// the v3 SDK only models the types it was generated with.
+type apiLogStream struct {
+	ID       string                 `json:"id,omitempty"`
+	Name     string                 `json:"name"`
+	Type     string                 `json:"type"`
+	Status   string                 `json:"status,omitempty"`
+	Settings map[string]interface{} `json:"settings"`
+}
+
+// buildLogStream returns the log stream to create, or to replace the log
+// stream with when replace is set, secret settings are only sent on create.
+func buildLogStream(m *logStreamModel, replace bool) *apiLogStream {
+	ls := &apiLogStream{
+		Name:     m.Name.ValueString(),
+		Type:     m.Type.ValueString(),
+		Settings: make(map[string]interface{}),
+	}
+	for name, setting := range logStreamTypes[ls.Type].settings {
+		if replace && setting.secret {
+			continue
+		}
+		if v := m.setting(name); !v.IsNull() && !v.IsUnknown() {
+			ls.Settings[setting.json] = v.ValueString()
+		}
+	}
+	return ls
+}
+
+// applyLogStreamToModel sets the attributes of the log stream returned by the
+// API. The settings are set in the block they are configured in, in the
+// block of the type when imported. Secret settings are never returned so
+// the configured value is kept.
+func applyLogStreamToModel(ls *apiLogStream, m *logStreamModel) {
+	m.ID = types.StringValue(ls.ID)
+	m.Name = types.StringValue(ls.Name)
+	m.Status = types.StringValue(ls.Status)
+	m.Type = types.StringValue(ls.Type)
+	if m.Settings == nil {
+		m.Settings = make(map[string]types.Object)
+	}
+
+	block := m.settingsBlock()
+	previous := m.Settings[block]
+	for _, b := range logStreamBlocks() {
+		m.Settings[b] = types.ObjectNull(logStreamBlockAttrTypes(b))
+	}
+	m.Settings[block] = logStreamSettingsObject(ls, block, previous)
+}
+
+// logStreamSettingsObject returns the settings block of the log stream,
+// settings the API doesn't return are taken from previous.
+func logStreamSettingsObject(ls *apiLogStream, block string, previous types.Object) types.Object {
+	values := make(map[string]attr.Value)
+	for name := range logStreamBlockSettings(block) {
+		values[name] = types.StringNull()
+		if !previous.IsNull() && !previous.IsUnknown() {
+			if v, ok := previous.Attributes()[name]; ok && !v.IsUnknown() {
+				values[name] = v
+			}
+		}
+	}
+	for name, setting := range logStreamTypes[ls.Type].settings {
+		if v, ok := ls.Settings[setting.json]; ok && v != nil && !setting.secret {
+			values[name] = types.StringValue(fmt.Sprint(v))
+		}
+	}
+	return types.ObjectValueMust(logStreamBlockAttrTypes(block), values)
+}
+
+func logStreamURL(id string) string {
+	if id == "" {
+		return "/api/v1/logStreams"
+	}
+	return "/api/v1/logStreams/" + url.PathEscape(id)
+}
+
+func getLogStream(ctx context.Context, m interface{}, id string) (*apiLogStream, error) {
+	re := getRequestExecutor(m)
+	req, err := re.WithAccept("application/json").WithContentType("application/json").
+		NewRequest(http.MethodGet, logStreamURL(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	var ls apiLogStream
+	_, err = re.Do(ctx, req, &ls)
+	if err != nil {
+		return nil, err
+	}
+	return &ls, nil
+}
+
+// findLogStreamByName returns the log stream named name, log stream names are
+// unique. The log streams are listed page by page until it is found.
+func findLogStreamByName(ctx context.Context, m interface{}, name string) (*apiLogStream, error) {
+	re := getRequestExecutor(m)
+	req, err := re.WithAccept("application/json").WithContentType("application/json").
+		NewRequest(http.MethodGet, fmt.Sprintf("%s?limit=%d", logStreamURL(""), defaultPaginationLimit), nil)
+	if err != nil {
+		return nil, err
+	}
+	var streams []*apiLogStream
+	resp, err := re.Do(ctx, req, &streams)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		for _, ls := range streams {
+			if ls.Name == name {
+				return ls, nil
+			}
+		}
+		if !resp.HasNextPage() {
+			return nil, fmt.Errorf("log stream with name '%s' does not exist", name)
+		}
+		streams = nil
+		resp, err = resp.Next(ctx, &streams)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func createLogStream(ctx context.Context, m interface{}, body *apiLogStream) (*apiLogStream, error) {
+	return sendLogStream(ctx, m, http.MethodPost, logStreamURL(""), body)
+}
+
+func replaceLogStream(ctx context.Context, m interface{}, id string, body *apiLogStream) (*apiLogStream, error) {
+	return sendLogStream(ctx, m, http.MethodPut, logStreamURL(id), body)
+}
+
+// setLogStreamStatus activates or deactivates the log stream.
+func setLogStreamStatus(ctx context.Context, m interface{}, id, status string) (*apiLogStream, error) {
+	action := "activate"
+	if status == statusInactive {
+		action = "deactivate"
+	}
+	return sendLogStream(ctx, m, http.MethodPost, fmt.Sprintf("%s/lifecycle/%s", logStreamURL(id), action), nil)
+}
+
+func sendLogStream(ctx context.Context, m interface{}, method, url string, body *apiLogStream) (*apiLogStream, error) {
+	re := getRequestExecutor(m)
+	var reqBody interface{}
+	if body != nil {
+		reqBody = body
+	}
+	req, err := re.WithAccept("application/json").WithContentType("application/json").
+		NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	var ls apiLogStream
+	_, err = re.Do(ctx, req, &ls)
+	if err != nil {
+		return nil, err
+	}
+	return &ls, nil
+}
+
+// deleteLogStream deletes the log stream, it is deactivated first as only
+// inactive log streams can be deleted.
+func deleteLogStream(ctx context.Context, m interface{}, id string) error {
+	re := getRequestExecutor(m)
+	req, err := re.WithAccept("application/json").WithContentType("application/json").
+		NewRequest(http.MethodPost, logStreamURL(id)+"/lifecycle/deactivate", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := re.Do(ctx, req, nil)
+	if is404(resp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	req, err = re.WithAccept("application/json").WithContentType("application/json").
+		NewRequest(http.MethodDelete, logStreamURL(id), nil)
+	if err != nil {
+		return err
+	}
+	resp, err = re.Do(ctx, req, nil)
+	return suppressErrorOn404(resp, err)
+}
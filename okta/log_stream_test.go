@@ -0,0 +1,189 @@
+package okta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/internal/fakeokta"
+	"github.com/stretchr/testify/require"
+)
+
+// logStreamTestModel returns a log stream with the settings set in block.
+func logStreamTestModel(typeName, block string, settings map[string]string) *logStreamModel {
+	m := &logStreamModel{
+		Name:     types.StringValue("SIEM"),
+		Type:     types.StringValue(typeName),
+		Status:   types.StringValue(statusActive),
+		Settings: make(map[string]types.Object),
+	}
+	for _, b := range logStreamBlocks() {
+		m.Settings[b] = types.ObjectNull(logStreamBlockAttrTypes(b))
+	}
+	if settings != nil {
+		values := make(map[string]attr.Value)
+		for name := range logStreamBlockSettings(block) {
+			values[name] = types.StringNull()
+			if v, ok := settings[name]; ok {
+				values[name] = types.StringValue(v)
+			}
+		}
+		m.Settings[block] = types.ObjectValueMust(logStreamBlockAttrTypes(block), values)
+	}
+	return m
+}
+
+func TestValidateLogStream(t *testing.T) {
+	splunk := map[string]string{"edition": "aws", "host": "acme.splunkcloud.com", "token": "58A7C8D6-4E2F-4C3B-8F5B-D4E2F3A4B5C6"}
+	tests := []struct {
+		name  string
+		model *logStreamModel
+		err   string
+	}{
+		{"type block", logStreamTestModel(logStreamTypeSplunk, logStreamTypeSplunk, splunk), ""},
+		{"deprecated settings block", logStreamTestModel(logStreamTypeSplunk, logStreamLegacySettings, splunk), ""},
+		{"no settings", logStreamTestModel(logStreamTypeSplunk, "", nil),
+			`The splunk_cloud_logstreaming block is required when type is "splunk_cloud_logstreaming"`},
+		{"block of another type", logStreamTestModel(logStreamTypeEventBridge, logStreamTypeSplunk, splunk),
+			`The splunk_cloud_logstreaming block can only be set when type is "splunk_cloud_logstreaming"`},
+		{"missing required setting", logStreamTestModel(logStreamTypeSplunk, logStreamTypeSplunk, map[string]string{"edition": "aws", "host": "acme.splunkcloud.com"}),
+			`token is required when type is "splunk_cloud_logstreaming"`},
+		{"setting of another type", logStreamTestModel(logStreamTypeEventBridge, logStreamLegacySettings, map[string]string{"account_id": "123456789012", "region": "eu-west-3", "event_source_name": "siem", "host": "acme.splunkcloud.com"}),
+			"host is not a setting of AWS EventBridge log streams"},
+	}
+	for _, test := range tests {
+		diags := validateLogStream(test.model)
+		if test.err == "" {
+			require.False(t, diags.HasError(), test.name)
+			continue
+		}
+		require.True(t, diags.HasError(), test.name)
+		require.Equal(t, test.err, diags[0].Detail(), test.name)
+	}
+
+	m := logStreamTestModel(logStreamTypeEventBridge, logStreamTypeSplunk, splunk)
+	m.Settings[logStreamLegacySettings] = m.Settings[logStreamTypeSplunk]
+	m.Type = types.StringValue(logStreamTypeSplunk)
+	diags := validateLogStream(m)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Detail(), "The settings block is deprecated")
+}
+
+func TestLogStreamAPI(t *testing.T) {
+	_, config := newFakeOktaConfig(t)
+	ctx := context.TODO()
+
+	m := logStreamTestModel(logStreamTypeSplunk, logStreamLegacySettings, map[string]string{"edition": "aws", "host": "acme.splunkcloud.com", "token": "58A7C8D6-4E2F-4C3B-8F5B-D4E2F3A4B5C6"})
+	ls, err := createLogStream(ctx, config, buildLogStream(m, false))
+	require.NoError(t, err)
+	require.Equal(t, statusActive, ls.Status)
+	require.NotContains(t, ls.Settings, "token")
+
+	// the settings stay in the block they are set in and the token, never
+	// returned, is kept
+	applyLogStreamToModel(ls, m)
+	require.True(t, m.Settings[logStreamTypeSplunk].IsNull())
+	require.Equal(t, "acme.splunkcloud.com", m.setting("host").ValueString())
+	require.Equal(t, "58A7C8D6-4E2F-4C3B-8F5B-D4E2F3A4B5C6", m.setting("token").ValueString())
+
+	// the token is only sent on create, Okta rejects updating it
+	m.Name = types.StringValue("SIEM updated")
+	ls, err = replaceLogStream(ctx, config, ls.ID, buildLogStream(m, true))
+	require.NoError(t, err)
+	require.Equal(t, "SIEM updated", ls.Name)
+
+	ls, err = setLogStreamStatus(ctx, config, ls.ID, statusInactive)
+	require.NoError(t, err)
+	require.Equal(t, statusInactive, ls.Status)
+
+	// an imported log stream has its settings set in the block of its type
+	found, err := findLogStreamByName(ctx, config, "SIEM updated")
+	require.NoError(t, err)
+	imported := &logStreamModel{ID: types.StringValue(found.ID)}
+	applyLogStreamToModel(found, imported)
+	require.Equal(t, logStreamTypeSplunk, imported.settingsBlock())
+	require.Equal(t, "aws", imported.setting("edition").ValueString())
+	require.True(t, imported.setting("token").IsNull())
+	require.True(t, imported.Settings[logStreamLegacySettings].IsNull())
+
+	_, err = findLogStreamByName(ctx, config, "SIEM")
+	require.EqualError(t, err, "log stream with name 'SIEM' does not exist")
+
+	require.NoError(t, deleteLogStream(ctx, config, ls.ID))
+	_, err = getLogStream(ctx, config, ls.ID)
+	require.Error(t, err)
+	require.NoError(t, deleteLogStream(ctx, config, ls.ID), "deleting a deleted log stream")
+}
+
+func TestFindLogStreamByNamePages(t *testing.T) {
+	_, config := newFakeOktaConfig(t, fakeokta.WithPageLimit(1))
+	ctx := context.TODO()
+
+	for _, name := range []string{"first", "second", "third"} {
+		m := logStreamTestModel(logStreamTypeEventBridge, logStreamTypeEventBridge, map[string]string{"account_id": "123456789012", "region": "eu-west-3", "event_source_name": name})
+		m.Name = types.StringValue(name)
+		_, err := createLogStream(ctx, config, buildLogStream(m, false))
+		require.NoError(t, err)
+	}
+	for _, name := range []string{"first", "third"} {
+		ls, err := findLogStreamByName(ctx, config, name)
+		require.NoError(t, err)
+		require.Equal(t, name, ls.Name)
+	}
+	_, err := findLogStreamByName(ctx, config, "fourth")
+	require.EqualError(t, err, "log stream with name 'fourth' does not exist")
+}
+
+// TestLogStreamTypeRegistry registers a log stream type and checks it gets
+// its settings block, validation and API mapping from its entry alone.
+func TestLogStreamTypeRegistry(t *testing.T) {
+	const webhook = "webhook_logstreaming"
+	logStreamTypes[webhook] = logStreamType{
+		title: "Webhook",
+		settings: map[string]logStreamSetting{
+			"url": {
+				json:        "url",
+				description: "URL the events are posted to",
+				required:    true,
+				forceNew:    true,
+			},
+			"api_key": {
+				json:        "apiKey",
+				description: "API key sent with the events",
+				required:    true,
+				secret:      true,
+			},
+		},
+	}
+	defer delete(logStreamTypes, webhook)
+
+	var resp resource.SchemaResponse
+	(&logStreamResource{}).Schema(context.TODO(), resource.SchemaRequest{}, &resp)
+	block, ok := resp.Schema.Blocks[webhook].(schema.SingleNestedBlock)
+	require.True(t, ok, "the type has a settings block")
+	require.Contains(t, block.Attributes, "url")
+	require.Contains(t, block.Attributes, "api_key")
+	require.Contains(t, resp.Schema.Blocks[logStreamLegacySettings].(schema.SingleNestedBlock).Attributes, "api_key")
+	require.Contains(t, resp.Schema.Attributes["type"].GetDescription(), "'webhook_logstreaming'")
+
+	settings := map[string]string{"url": "https://siem.example.com/okta", "api_key": "secret"}
+	require.False(t, validateLogStream(logStreamTestModel(webhook, webhook, settings)).HasError())
+	diags := validateLogStream(logStreamTestModel(webhook, webhook, map[string]string{"url": "https://siem.example.com/okta"}))
+	require.True(t, diags.HasError())
+	require.Equal(t, `api_key is required when type is "webhook_logstreaming"`, diags[0].Detail())
+	diags = validateLogStream(logStreamTestModel(logStreamTypeSplunk, webhook, settings))
+	require.True(t, diags.HasError())
+	require.Equal(t, `The webhook_logstreaming block can only be set when type is "webhook_logstreaming"`, diags[0].Detail())
+
+	// the secret setting is only sent on create and kept from the
+	// configuration
+	m := logStreamTestModel(webhook, webhook, settings)
+	require.Equal(t, map[string]interface{}{"url": "https://siem.example.com/okta", "apiKey": "secret"}, buildLogStream(m, false).Settings)
+	require.Equal(t, map[string]interface{}{"url": "https://siem.example.com/okta"}, buildLogStream(m, true).Settings)
+	applyLogStreamToModel(&apiLogStream{ID: "0oa1", Name: "SIEM", Type: webhook, Status: statusActive, Settings: map[string]interface{}{"url": "https://siem.example.com/okta"}}, m)
+	require.Equal(t, "https://siem.example.com/okta", m.setting("url").ValueString())
+	require.Equal(t, "secret", m.setting("api_key").ValueString())
+}
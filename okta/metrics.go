@@ -0,0 +1,81 @@
+package okta
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/internal/metrics"
+)
+
+// apiMetrics records the API usage of the provider process when metrics_file
+// is set. It is shared by the plugin sdk and the framework providers.
+var apiMetrics = metrics.NewRecorder()
+
+var apiMetricsOutput struct {
+	lock   sync.Mutex
+	file   string
+	format string
+}
+
+func setAPIMetricsOutput(file, format string) {
+	apiMetricsOutput.lock.Lock()
+	defer apiMetricsOutput.lock.Unlock()
+	apiMetricsOutput.file = file
+	apiMetricsOutput.format = format
+}
+
+// WriteAPIMetrics writes the API usage of the provider process to the
+// configured metrics_file, if any. It's called once the provider is shut
+// down. Terraform starts the provider several times per run, the usage of
+// every process started by the same Terraform process is added up in the file.
+func WriteAPIMetrics() error {
+	apiMetricsOutput.lock.Lock()
+	defer apiMetricsOutput.lock.Unlock()
+	if apiMetricsOutput.file == "" {
+		return nil
+	}
+	return apiMetrics.WriteFile(apiMetricsOutput.file, apiMetricsOutput.format, strconv.Itoa(os.Getppid()))
+}
+
+// withResourceTypes attributes the API requests made by the provider's
+// resources and data sources to their type in the API metrics.
+func withResourceTypes(p *schema.Provider) *schema.Provider {
+	for name, r := range p.ResourcesMap {
+		withResourceType(name, r)
+	}
+	for name, d := range p.DataSourcesMap {
+		withResourceType("data."+name, d)
+	}
+	return p
+}
+
+func withResourceType(name string, r *schema.Resource) {
+	r.CreateContext = withResourceTypeCRUD(name, r.CreateContext)
+	r.ReadContext = withResourceTypeCRUD(name, r.ReadContext)
+	r.UpdateContext = withResourceTypeCRUD(name, r.UpdateContext)
+	r.DeleteContext = withResourceTypeCRUD(name, r.DeleteContext)
+	if f := r.CustomizeDiff; f != nil {
+		r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+			return f(metrics.WithResourceType(ctx, name), d, m)
+		}
+	}
+	if r.Importer != nil && r.Importer.StateContext != nil {
+		f := r.Importer.StateContext
+		r.Importer.StateContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+			return f(metrics.WithResourceType(ctx, name), d, m)
+		}
+	}
+}
+
+func withResourceTypeCRUD[F ~func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics](name string, f F) F {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		return f(metrics.WithResourceType(ctx, name), d, m)
+	}
+}
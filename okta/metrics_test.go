@@ -0,0 +1,53 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/metrics"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIMetrics(t *testing.T) {
+	_, config := newFakeOktaConfig(t)
+	ctx := context.TODO()
+	g, _, err := config.oktaSDKClientV2.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: "Engineering"}})
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "okta-metrics.json")
+	config.metricsFile = file
+	config.metricsFormat = metrics.FormatJSON
+	require.NoError(t, config.loadClients(ctx))
+	// the sdk clients don't use the configured http client when the org URL
+	// names a port, as the fake org's does
+	rt := http.RoundTripper(metrics.NewTransport(http.DefaultTransport, apiMetrics))
+	config.resetHttpTransport(&rt)
+
+	res := Provider().ResourcesMap[group]
+	d := res.Data(nil)
+	d.SetId(g.Id)
+	diags := res.ReadContext(ctx, d, config)
+	require.False(t, diags.HasError(), "%+v", diags)
+	require.NoError(t, WriteAPIMetrics())
+
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer f.Close()
+	run, err := metrics.Decode(f, metrics.FormatJSON)
+	require.NoError(t, err)
+
+	var series *metrics.Series
+	for _, s := range run.Series {
+		if s.Endpoint == "GET /api/v1/groups/ID" && s.ResourceType == group {
+			series = s
+		}
+	}
+	require.NotNil(t, series, "expected okta_group's requests to be recorded, got %+v", run.Series)
+	require.Equal(t, "/api/v1/groups/{id}", series.Bucket)
+	require.Equal(t, map[string]int64{"200": 1}, series.Requests)
+	require.Equal(t, int64(1), series.Latency.Count)
+}
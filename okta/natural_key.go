@@ -0,0 +1,545 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+// importSegment is one "/" separated part of the import ID of a resource.
+type importSegment struct {
+	// kind is the type of object the segment refers to, it is looked up by
+	// its natural key when the segment is written as "<kind>:<value>" or
+	// "<natural key>:<value>", e.g. "app:My App" or "label:My App". Segments
+	// without kind only take the value itself.
+	kind string
+	// attr is the attribute holding the segment once imported, "id" being
+	// the ID of the resource. It names the segment in the resource identity.
+	attr string
+}
+
+// importKeys are the import ID formats of the resources that can be imported
+// by natural key and by identity. Trailing import options, e.g. the
+// "skip_users" of the app importers, are left out.
+var importKeys = map[string][]importSegment{
+	adminRoleCustom:               {{"admin_role_custom", "id"}},
+	adminRoleCustomAssignments:    {{"resource_set", "resource_set_id"}, {"admin_role_custom", "custom_role_id"}},
+	adminRoleTargets:              {{"user", "user_id"}, {"", "role_type"}},
+	appAutoLogin:                  {{"app", "id"}},
+	appBasicAuth:                  {{"app", "id"}},
+	appBookmark:                   {{"app", "id"}},
+	appGroupAssignment:            {{"app", "app_id"}, {"group", "group_id"}},
+	appGroupAssignments:           {{"app", "app_id"}},
+	appOAuth:                      {{"app", "id"}},
+	appOAuthAPIScope:              {{"app", "app_id"}},
+	appOAuthPostLogoutRedirectURI: {{"app", "app_id"}, {"", "id"}},
+	appOAuthRedirectURI:           {{"app", "app_id"}, {"", "id"}},
+	appSaml:                       {{"app", "id"}},
+	appSamlAppSettings:            {{"app", "id"}},
+	appSamlSigningKey:             {{"app", "app_id"}, {"", "kid"}},
+	appSecurePasswordStore:        {{"app", "id"}},
+	appSharedCredentials:          {{"app", "id"}},
+	appSignOnPolicy:               {{"policy", "id"}},
+	appSignOnPolicyRule:           {{"policy", "policy_id"}, {"rule", "id"}},
+	appSwa:                        {{"app", "id"}},
+	appThreeField:                 {{"app", "id"}},
+	appUser:                       {{"app", "app_id"}, {"user", "user_id"}},
+	appUserBaseSchemaProperty:     {{"app", "app_id"}, {"", "index"}},
+	appUserSchemaProperty:         {{"app", "app_id"}, {"", "index"}},
+	authenticator:                 {{"authenticator", "id"}},
+	authServer:                    {{"auth_server", "id"}},
+	authServerClaim:               {{"auth_server", "auth_server_id"}, {"claim", "id"}},
+	authServerClaimDefault:        {{"auth_server", "auth_server_id"}, {"", "id"}},
+	authServerDefault:             {{"auth_server", "id"}},
+	authServerKeyRotation:         {{"auth_server", "auth_server_id"}},
+	authServerPolicy:              {{"auth_server", "auth_server_id"}, {"policy", "id"}},
+	authServerPolicyRule:          {{"auth_server", "auth_server_id"}, {"policy", "policy_id"}, {"rule", "id"}},
+	authServerScope:               {{"auth_server", "auth_server_id"}, {"scope", "id"}},
+	behavior:                      {{"behavior", "id"}},
+	captcha:                       {{"captcha", "id"}},
+	captchaOrgWideSettings:        {{"", "id"}},
+	domain:                        {{"domain", "id"}},
+	emailCustomization:            {{"", "id"}, {"brand", "brand_id"}, {"", "template_name"}},
+	emailCustomizations:           {{"brand", "brand_id"}, {"", "template_name"}},
+	emailDomain:                   {{"email_domain", "id"}},
+	emailSender:                   {{"", "id"}},
+	eventHook:                     {{"event_hook", "id"}},
+	factor:                        {{"", "id"}},
+	factorTotp:                    {{"factor_totp", "id"}},
+	group:                         {{"group", "id"}},
+	groupMemberships:              {{"group", "id"}},
+	groupRole:                     {{"group", "group_id"}, {"", "id"}},
+	groupRule:                     {{"group_rule", "id"}},
+	groupSchemaProperty:           {{"", "id"}},
+	idpOidc:                       {{"idp", "id"}},
+	idpSaml:                       {{"idp", "id"}},
+	idpSamlKey:                    {{"", "id"}},
+	idpSocial:                     {{"idp", "id"}},
+	inlineHook:                    {{"inline_hook", "id"}},
+	linkDefinition:                {{"", "id"}},
+	linkValue:                     {{"", "primary_name"}, {"user", "primary_user_id"}},
+	networkZone:                   {{"network_zone", "id"}},
+	orgConfiguration:              {{"", "id"}},
+	policyMfa:                     {{"policy", "id"}},
+	policyMfaDefault:              {{"", "id"}},
+	policyOrder:                   {{"", "id"}},
+	policyPassword:                {{"policy", "id"}},
+	policyPasswordDefault:         {{"", "id"}},
+	policyProfileEnrollment:       {{"policy", "id"}},
+	policyProfileEnrollmentApps:   {{"policy", "id"}},
+	policyRuleIdpDiscovery:        {{"policy", "policy_id"}, {"rule", "id"}},
+	policyRuleMfa:                 {{"policy", "policy_id"}, {"rule", "id"}},
+	policyRuleOrder:               {{"auth_server", "auth_server_id"}, {"policy", "policy_id"}},
+	policyRulePassword:            {{"policy", "policy_id"}, {"rule", "id"}},
+	policyRuleProfileEnrollment:   {{"policy", "policy_id"}, {"rule", "id"}},
+	policyRuleSignOn:              {{"policy", "policy_id"}, {"rule", "id"}},
+	policySignOn:                  {{"policy", "id"}},
+	rateLimiting:                  {{"", "id"}},
+	resourceSet:                   {{"resource_set", "id"}},
+	roleSubscription:              {{"", "role_type"}, {"", "notification_type"}},
+	securityNotificationEmails:    {{"", "id"}},
+	templateSms:                   {{"", "id"}},
+	theme:                         {{"brand", "brand_id"}, {"", "id"}},
+	threatInsightSettings:         {{"", "id"}},
+	trustedOrigin:                 {{"trusted_origin", "id"}},
+	user:                          {{"user", "id"}},
+	userAdminRoles:                {{"user", "user_id"}},
+	userBaseSchemaProperty:        {{"user_type", "user_type"}, {"", "index"}},
+	userFactorQuestion:            {{"user", "user_id"}, {"", "id"}},
+	userSchemaProperty:            {{"user_type", "user_type"}, {"", "index"}},
+	userType:                      {{"user_type", "id"}},
+}
+
+// importSeparators are the separators of the segments of the import IDs
+// that aren't separated by "/".
+var importSeparators = map[string]string{
+	userBaseSchemaProperty: ".",
+	userSchemaProperty:     ".",
+}
+
+// importOptionalParents are the resources whose first segment can be left
+// out of the import ID, e.g. the user type of the properties of the default
+// user type or the authorization server of the policies of the organization.
+var importOptionalParents = map[string]bool{
+	policyRuleOrder:        true,
+	userBaseSchemaProperty: true,
+	userSchemaProperty:     true,
+}
+
+func importSeparator(resourceType string) string {
+	if sep, ok := importSeparators[resourceType]; ok {
+		return sep
+	}
+	return "/"
+}
+
+// importSegments returns the segments of an import ID made of n parts.
+func importSegments(resourceType string, n int) []importSegment {
+	segments := importKeys[resourceType]
+	if importOptionalParents[resourceType] && n == len(segments)-1 {
+		return segments[1:]
+	}
+	return segments
+}
+
+// importPolicyTypes are the types of the policies the "policy" segments of
+// the resources refer to.
+var importPolicyTypes = map[string]string{
+	appSignOnPolicy:             sdk.AccessPolicyType,
+	appSignOnPolicyRule:         sdk.AccessPolicyType,
+	policyMfa:                   sdk.MfaPolicyType,
+	policyPassword:              sdk.PasswordPolicyType,
+	policyProfileEnrollment:     sdk.ProfileEnrollmentPolicyType,
+	policyProfileEnrollmentApps: sdk.ProfileEnrollmentPolicyType,
+	policyRuleIdpDiscovery:      sdk.IdpDiscoveryType,
+	policyRuleMfa:               sdk.MfaPolicyType,
+	policyRulePassword:          sdk.PasswordPolicyType,
+	policyRuleProfileEnrollment: sdk.ProfileEnrollmentPolicyType,
+	policyRuleSignOn:            sdk.SignOnPolicyType,
+	policySignOn:                sdk.SignOnPolicyType,
+}
+
+// importScope holds the resource type and the IDs of the parent segments an
+// object is looked up in.
+type importScope struct {
+	resourceType string
+	authServerID string
+	policyID     string
+}
+
+// naturalKeyLookup returns the ID of the object of a kind whose natural key
+// is value.
+type naturalKeyLookup func(ctx context.Context, m interface{}, scope importScope, value string) (string, error)
+
+var naturalKeyLookups = map[string]naturalKeyLookup{
+	"admin_role_custom": importListerLookup(listAdminRoleCustomImportTargets),
+	"app":               lookupAppByLabel,
+	"auth_server":       lookupAuthServerByName,
+	"authenticator":     lookupAuthenticatorByName,
+	"behavior":          lookupBehaviorByName,
+	"brand":             importListerLookup(listBrandImportTargets),
+	"captcha":           importListerLookup(listCaptchaImportTargets),
+	"claim":             lookupAuthServerClaimByName,
+	"domain":            importListerLookup(listDomainImportTargets),
+	"email_domain":      importListerLookup(listEmailDomainImportTargets),
+	"event_hook":        importListerLookup(listEventHookImportTargets),
+	"factor_totp":       importListerLookup(listFactorTotpImportTargets),
+	"group":             lookupGroupByName,
+	"group_rule":        importListerLookup(listGroupRuleImportTargets),
+	"idp":               lookupIdpByName,
+	"inline_hook":       importListerLookup(listInlineHookImportTargets),
+	"network_zone":      importListerLookup(listNetworkZoneImportTargets),
+	"policy":            lookupPolicyByName,
+	"resource_set":      importListerLookup(listResourceSetImportTargets),
+	"rule":              lookupPolicyRuleByName,
+	"scope":             lookupAuthServerScopeByName,
+	"trusted_origin":    importListerLookup(listTrustedOriginImportTargets),
+	"user":              lookupUserByLogin,
+	"user_type":         importListerLookup(listUserTypeImportTargets),
+}
+
+// naturalKeyName returns the attribute objects of a kind are looked up by.
+func naturalKeyName(kind string) string {
+	switch kind {
+	case "admin_role_custom", "app", "resource_set":
+		return "label"
+	case "domain", "email_domain":
+		return "domain"
+	case "user":
+		return "login"
+	}
+	return "name"
+}
+
+// withImportKeys lets the resources listed in importKeys be imported by
+// natural key and by identity.
+func withImportKeys(p *schema.Provider) *schema.Provider {
+	for name, r := range p.ResourcesMap {
+		if _, ok := importKeys[name]; ok && r.Importer != nil && r.Importer.StateContext != nil {
+			withImportKey(name, r)
+		}
+	}
+	return p
+}
+
+func withImportKey(name string, r *schema.Resource) {
+	segments := importKeys[name]
+	r.Identity = &schema.ResourceIdentity{
+		SchemaFunc: func() map[string]*schema.Schema {
+			s := make(map[string]*schema.Schema, len(segments))
+			for i, segment := range segments {
+				optional := i == 0 && importOptionalParents[name]
+				s[segment.attr] = &schema.Schema{
+					Type:              schema.TypeString,
+					RequiredForImport: !optional,
+					OptionalForImport: optional,
+					Description:       fmt.Sprintf("The `%s` of the resource.", segment.attr),
+				}
+			}
+			return s
+		},
+	}
+	f := r.Importer.StateContext
+	r.Importer.StateContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		if d.Id() == "" {
+			id, err := importIDFromIdentity(d, name)
+			if err != nil {
+				return nil, err
+			}
+			d.SetId(id)
+		}
+		id, err := resolveImportID(ctx, m, name, d.Id())
+		if err != nil {
+			return nil, err
+		}
+		d.SetId(id)
+		return f(ctx, d, m)
+	}
+	r.CreateContext = withImportIdentity(r.CreateContext, name)
+	r.ReadContext = withImportIdentity(r.ReadContext, name)
+	r.UpdateContext = withImportIdentity(r.UpdateContext, name)
+}
+
+// withImportIdentity sets the identity of the resource once f has run.
+func withImportIdentity[F ~func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics](f F, resourceType string) F {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		diags := f(ctx, d, m)
+		if diags.HasError() || d.Id() == "" {
+			return diags
+		}
+		setImportIdentity(d, resourceType)
+		return diags
+	}
+}
+
+// setImportIdentity sets the identity from the attributes of the segments,
+// it is left unset until all of them but an optional parent are known.
+func setImportIdentity(d *schema.ResourceData, resourceType string) {
+	identity, err := d.Identity()
+	if err != nil {
+		return
+	}
+	segments := importKeys[resourceType]
+	values := make(map[string]string, len(segments))
+	for i, segment := range segments {
+		v := d.Id()
+		if segment.attr != "id" {
+			v, _ = d.Get(segment.attr).(string)
+		}
+		if v == "" && i == 0 && importOptionalParents[resourceType] {
+			continue
+		}
+		if v == "" {
+			return
+		}
+		values[segment.attr] = v
+	}
+	for k, v := range values {
+		_ = identity.Set(k, v)
+	}
+}
+
+func importIDFromIdentity(d *schema.ResourceData, resourceType string) (string, error) {
+	identity, err := d.Identity()
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+	for i, segment := range importKeys[resourceType] {
+		v, _ := identity.Get(segment.attr).(string)
+		if v == "" && i == 0 && importOptionalParents[resourceType] {
+			continue
+		}
+		if v == "" {
+			return "", fmt.Errorf("the identity of the resource requires '%s'", segment.attr)
+		}
+		parts = append(parts, v)
+	}
+	return strings.Join(parts, importSeparator(resourceType)), nil
+}
+
+// resolveImportID replaces the natural keys of the import ID with the IDs of
+// the objects they refer to, e.g. "policy:Default Policy/rule:Catch-all"
+// becomes "00p1.../0pr1...". Segments that are not natural keys are kept,
+// the natural keys can't contain the separator of the segments.
+func resolveImportID(ctx context.Context, m interface{}, resourceType, id string) (string, error) {
+	sep := importSeparator(resourceType)
+	parts := strings.Split(id, sep)
+	segments := importSegments(resourceType, len(parts))
+	scope := importScope{resourceType: resourceType}
+	for i, part := range parts {
+		if i >= len(segments) || segments[i].kind == "" {
+			continue
+		}
+		kind := segments[i].kind
+		if prefix, value, ok := strings.Cut(part, ":"); ok && (prefix == kind || prefix == naturalKeyName(kind)) {
+			objectID, err := naturalKeyLookups[kind](ctx, m, scope, value)
+			if err != nil {
+				return "", fmt.Errorf("failed to import %s '%s': %v", strings.ReplaceAll(kind, "_", " "), value, err)
+			}
+			parts[i] = objectID
+		}
+		switch kind {
+		case "auth_server":
+			scope.authServerID = parts[i]
+		case "policy":
+			scope.policyID = parts[i]
+		}
+	}
+	return strings.Join(parts, sep), nil
+}
+
+// uniqueImportTarget returns the ID of the only target named name.
+func uniqueImportTarget(targets []importTarget, name string) (string, error) {
+	var ids []string
+	for _, target := range targets {
+		if target.Name == name {
+			ids = append(ids, target.ID)
+		}
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("not found")
+	case 1:
+		return ids[0], nil
+	}
+	return "", fmt.Errorf("the name is ambiguous, import one of the IDs %s instead", strings.Join(ids, ", "))
+}
+
+func importListerLookup(lister importLister) naturalKeyLookup {
+	return func(ctx context.Context, m interface{}, _ importScope, value string) (string, error) {
+		targets, err := lister(ctx, m, value)
+		if err != nil {
+			return "", err
+		}
+		return uniqueImportTarget(targets, value)
+	}
+}
+
+func lookupAppByLabel(ctx context.Context, m interface{}, _ importScope, value string) (string, error) {
+	apps, err := listApps(ctx, getOktaClientFromMetadata(m), &appFilters{Label: value}, defaultPaginationLimit)
+	if err != nil {
+		return "", err
+	}
+	targets := make([]importTarget, len(apps))
+	for i, app := range apps {
+		targets[i] = importTarget{ID: app.Id, Name: app.Label}
+	}
+	return uniqueImportTarget(targets, value)
+}
+
+func lookupGroupByName(ctx context.Context, m interface{}, _ importScope, value string) (string, error) {
+	groups, err := listGroups(ctx, getOktaClientFromMetadata(m), &query.Params{Q: value, Limit: defaultPaginationLimit})
+	if err != nil {
+		return "", err
+	}
+	targets := make([]importTarget, len(groups))
+	for i, group := range groups {
+		targets[i] = importTarget{ID: group.Id, Name: group.Profile.Name}
+	}
+	return uniqueImportTarget(targets, value)
+}
+
+func lookupUserByLogin(ctx context.Context, m interface{}, _ importScope, value string) (string, error) {
+	user, _, err := getOktaClientFromMetadata(m).User.GetUser(ctx, value)
+	if err != nil {
+		return "", err
+	}
+	return user.Id, nil
+}
+
+func lookupAuthServerByName(ctx context.Context, m interface{}, _ importScope, value string) (string, error) {
+	servers, resp, err := getOktaClientFromMetadata(m).AuthorizationServer.ListAuthorizationServers(ctx, &query.Params{Limit: defaultPaginationLimit, Q: value})
+	if err != nil {
+		return "", err
+	}
+	for resp.HasNextPage() {
+		var nextServers []*sdk.AuthorizationServer
+		resp, err = resp.Next(ctx, &nextServers)
+		if err != nil {
+			return "", err
+		}
+		servers = append(servers, nextServers...)
+	}
+	targets := make([]importTarget, len(servers))
+	for i, server := range servers {
+		targets[i] = importTarget{ID: server.Id, Name: server.Name}
+	}
+	return uniqueImportTarget(targets, value)
+}
+
+func lookupAuthServerClaimByName(ctx context.Context, m interface{}, scope importScope, value string) (string, error) {
+	claim, err := getAuthServerClaimByName(ctx, m, scope.authServerID, value)
+	if err != nil {
+		return "", err
+	}
+	return claim.Id, nil
+}
+
+func lookupAuthServerScopeByName(ctx context.Context, m interface{}, scope importScope, value string) (string, error) {
+	scopes, _, err := getOktaClientFromMetadata(m).AuthorizationServer.ListOAuth2Scopes(ctx, scope.authServerID, &query.Params{Q: value})
+	if err != nil {
+		return "", err
+	}
+	targets := make([]importTarget, len(scopes))
+	for i, s := range scopes {
+		targets[i] = importTarget{ID: s.Id, Name: s.Name}
+	}
+	return uniqueImportTarget(targets, value)
+}
+
+// lookupPolicyByName looks up the policies of the authorization server of the
+// scope, or the policies of the type of the resource. The policies of
+// okta_policy_rule_order are looked up in every type with ordered rules.
+func lookupPolicyByName(ctx context.Context, m interface{}, scope importScope, value string) (string, error) {
+	if scope.authServerID != "" {
+		policies, _, err := getOktaClientFromMetadata(m).AuthorizationServer.ListAuthorizationServerPolicies(ctx, scope.authServerID)
+		if err != nil {
+			return "", err
+		}
+		targets := make([]importTarget, len(policies))
+		for i, policy := range policies {
+			targets[i] = importTarget{ID: policy.Id, Name: policy.Name}
+		}
+		return uniqueImportTarget(targets, value)
+	}
+	if scope.resourceType == policyRuleOrder {
+		var targets []importTarget
+		for _, policyType := range append([]string{sdk.AccessPolicyType}, orderedPolicyTypes...) {
+			policies, err := listAllPolicyImportTargets(policyType)(ctx, m, value)
+			if err != nil {
+				return "", err
+			}
+			targets = append(targets, policies...)
+		}
+		return uniqueImportTarget(targets, value)
+	}
+	policy, err := findPolicyByNameAndType(ctx, m, value, importPolicyTypes[scope.resourceType])
+	if err != nil {
+		return "", err
+	}
+	return policy.Id, nil
+}
+
+// lookupPolicyRuleByName looks up the rules of the policy of the scope.
+func lookupPolicyRuleByName(ctx context.Context, m interface{}, scope importScope, value string) (string, error) {
+	var targets []importTarget
+	if scope.authServerID != "" {
+		rules, _, err := getOktaClientFromMetadata(m).AuthorizationServer.ListAuthorizationServerPolicyRules(ctx, scope.authServerID, scope.policyID)
+		if err != nil {
+			return "", err
+		}
+		for _, rule := range rules {
+			targets = append(targets, importTarget{ID: rule.Id, Name: rule.Name})
+		}
+	} else {
+		rules, _, err := getOktaClientFromMetadata(m).Policy.ListPolicyRules(ctx, scope.policyID)
+		if err != nil {
+			return "", err
+		}
+		for _, rule := range rules {
+			targets = append(targets, importTarget{ID: rule.Id, Name: rule.Name})
+		}
+	}
+	return uniqueImportTarget(targets, value)
+}
+
+func lookupIdpByName(ctx context.Context, m interface{}, _ importScope, value string) (string, error) {
+	idps, _, err := getOktaClientFromMetadata(m).IdentityProvider.ListIdentityProviders(ctx, &query.Params{Q: value, Limit: defaultPaginationLimit})
+	if err != nil {
+		return "", err
+	}
+	targets := make([]importTarget, len(idps))
+	for i, idp := range idps {
+		targets[i] = importTarget{ID: idp.Id, Name: idp.Name}
+	}
+	return uniqueImportTarget(targets, value)
+}
+
+func lookupBehaviorByName(ctx context.Context, m interface{}, _ importScope, value string) (string, error) {
+	behaviors, _, err := getAPISupplementFromMetadata(m).ListBehaviors(ctx, &query.Params{Q: value})
+	if err != nil {
+		return "", err
+	}
+	targets := make([]importTarget, len(behaviors))
+	for i, behavior := range behaviors {
+		targets[i] = importTarget{ID: behavior.ID, Name: behavior.Name}
+	}
+	return uniqueImportTarget(targets, value)
+}
+
+func lookupAuthenticatorByName(ctx context.Context, m interface{}, _ importScope, value string) (string, error) {
+	authenticator, err := findAuthenticator(ctx, m, value, "")
+	if err != nil {
+		return "", err
+	}
+	return authenticator.Id, nil
+}
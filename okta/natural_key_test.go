@@ -0,0 +1,161 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveImportID(t *testing.T) {
+	server, config := newFakeOktaConfig(t)
+	ctx := context.TODO()
+	re := getRequestExecutor(config)
+	post := func(path string, body map[string]interface{}) string {
+		t.Helper()
+		var result map[string]interface{}
+		req, err := re.NewRequest(http.MethodPost, path, body)
+		require.NoError(t, err)
+		_, err = re.Do(ctx, req, &result)
+		require.NoError(t, err)
+		return result["id"].(string)
+	}
+	var apps []string
+	for _, app := range [][2]string{{"My App", "OPENID_CONNECT"}, {"My App 2", "OPENID_CONNECT"}, {"Twin", "BOOKMARK"}, {"Twin", "BOOKMARK"}} {
+		apps = append(apps, post("/api/v1/apps", map[string]interface{}{"label": app[0], "signOnMode": app[1]}))
+	}
+	groupID := post("/api/v1/groups", map[string]interface{}{"profile": map[string]interface{}{"name": "Engineering"}})
+	policies, _, err := config.oktaSDKClientV2.Policy.ListPolicies(ctx, &query.Params{Type: sdk.PasswordPolicyType})
+	require.NoError(t, err)
+	policyID := policies[0].(*sdk.Policy).Id
+	rules, _, err := config.oktaSDKClientV2.Policy.ListPolicyRules(ctx, policyID)
+	require.NoError(t, err)
+	defaultRuleID := rules[0].Id
+	ruleID := post("/api/v1/policies/"+policyID+"/rules", map[string]interface{}{"name": "Engineering"})
+
+	tests := []struct {
+		resourceType string
+		id           string
+		expected     string
+		err          string
+	}{
+		{appOAuth, "label:My App", apps[0], ""},
+		{appOAuth, "app:My App/skip_users", apps[0] + "/skip_users", ""},
+		{appGroupAssignment, "label:My App/name:Engineering", apps[0] + "/" + groupID, ""},
+		{appGroupAssignment, apps[0] + "/group:Engineering", apps[0] + "/" + groupID, ""},
+		{policyRulePassword, "policy:Default Policy/rule:Default Rule", policyID + "/" + defaultRuleID, ""},
+		{policyRulePassword, policyID + "/name:Engineering", policyID + "/" + ruleID, ""},
+		{appBookmark, "label:Twin", "", fmt.Sprintf("import one of the IDs %s, %s instead", apps[2], apps[3])},
+		{appOAuth, "label:Missing", "", "failed to import app 'Missing': not found"},
+	}
+	for _, test := range tests {
+		id, err := resolveImportID(ctx, config, test.resourceType, test.id)
+		if test.err != "" {
+			require.ErrorContains(t, err, test.err, test.id)
+			continue
+		}
+		require.NoError(t, err, test.id)
+		require.Equal(t, test.expected, id, test.id)
+	}
+
+	// IDs and segments that aren't looked up are kept as they are
+	requests := server.Requests()
+	for resourceType, id := range map[string]string{
+		appOAuth:            apps[0],
+		appOAuthRedirectURI: apps[0] + "/https://example.com/callback",
+		user:                "jane@example.com",
+		policyRulePassword:  policyID + "/" + ruleID,
+		policyRuleOrder:     policyID,
+		userSchemaProperty:  "oty1.employeeNumber",
+	} {
+		resolved, err := resolveImportID(ctx, config, resourceType, id)
+		require.NoError(t, err)
+		require.Equal(t, id, resolved)
+	}
+	require.Equal(t, requests, server.Requests())
+}
+
+func TestImportIdentity(t *testing.T) {
+	r := Provider().ResourcesMap[policyRulePassword]
+	require.NotNil(t, r.Identity)
+
+	d := r.Data(&terraform.InstanceState{ID: "0pr1", Attributes: map[string]string{"policy_id": "00p1"}})
+	setImportIdentity(d, policyRulePassword)
+	identity, err := d.Identity()
+	require.NoError(t, err)
+	require.Equal(t, "00p1", identity.Get("policy_id"))
+	require.Equal(t, "0pr1", identity.Get("id"))
+
+	// an import by identity uses the import ID format of the resource
+	id, err := importIDFromIdentity(d, policyRulePassword)
+	require.NoError(t, err)
+	require.Equal(t, "00p1/0pr1", id)
+
+	// the identity is left unset until all its attributes are known
+	d = r.Data(&terraform.InstanceState{ID: "0pr1"})
+	setImportIdentity(d, policyRulePassword)
+	identity, err = d.Identity()
+	require.NoError(t, err)
+	require.Equal(t, "", identity.Get("policy_id"))
+	require.Equal(t, "", identity.Get("id"))
+	_, err = importIDFromIdentity(d, policyRulePassword)
+	require.ErrorContains(t, err, "requires 'policy_id'")
+}
+
+func TestImportIdentityOptionalParent(t *testing.T) {
+	r := Provider().ResourcesMap[policyRuleOrder]
+	require.NotNil(t, r.Identity)
+
+	// the policies of the organization have no authorization server
+	d := r.Data(&terraform.InstanceState{ID: "00p1", Attributes: map[string]string{"policy_id": "00p1"}})
+	setImportIdentity(d, policyRuleOrder)
+	identity, err := d.Identity()
+	require.NoError(t, err)
+	require.Equal(t, "00p1", identity.Get("policy_id"))
+	id, err := importIDFromIdentity(d, policyRuleOrder)
+	require.NoError(t, err)
+	require.Equal(t, "00p1", id)
+
+	d = r.Data(&terraform.InstanceState{ID: "00p1", Attributes: map[string]string{"auth_server_id": "aus1", "policy_id": "00p1"}})
+	setImportIdentity(d, policyRuleOrder)
+	id, err = importIDFromIdentity(d, policyRuleOrder)
+	require.NoError(t, err)
+	require.Equal(t, "aus1/00p1", id)
+
+	// the segments of the user schema properties are separated by "."
+	r = Provider().ResourcesMap[userSchemaProperty]
+	d = r.Data(&terraform.InstanceState{ID: "employeeNumber", Attributes: map[string]string{"user_type": "oty1", "index": "employeeNumber"}})
+	setImportIdentity(d, userSchemaProperty)
+	id, err = importIDFromIdentity(d, userSchemaProperty)
+	require.NoError(t, err)
+	require.Equal(t, "oty1.employeeNumber", id)
+}
+
+// TestImportKeysCoverage checks every resource that can be imported is listed
+// in importKeys and every kind of segment can be looked up.
+func TestImportKeysCoverage(t *testing.T) {
+	p := Provider()
+	for name, r := range p.ResourcesMap {
+		if r.Importer == nil {
+			continue
+		}
+		_, ok := importKeys[name]
+		require.True(t, ok, "%s has no import key", name)
+		require.NotNil(t, r.Identity, name)
+	}
+	for name, segments := range importKeys {
+		r, ok := p.ResourcesMap[name]
+		require.True(t, ok, "%s is not a resource", name)
+		require.NotNil(t, r.Importer, "%s can't be imported", name)
+		for _, segment := range segments {
+			if segment.kind != "" {
+				require.Contains(t, naturalKeyLookups, segment.kind, name)
+			}
+		}
+	}
+}
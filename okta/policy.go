@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -327,3 +328,102 @@ func findPolicyByNameAndType(ctx context.Context, m interface{}, name, policyTyp
 	}
 	return nil, fmt.Errorf("no policies retrieved for policy type '%s' and name '%s'", policyType, name)
 }
+
+// priorityMove sets the priority of a policy or a policy rule, Okta moves it
+// to the priority and shifts the others.
+type priorityMove struct {
+	ID       string
+	Priority int
+}
+
+// priorityMoves returns the minimal sequence of moves reordering current, the
+// IDs ordered by priority, into target, a permutation of current. The IDs in
+// the longest subsequence already in the target order are kept in place, the
+// others are moved in the target order right after their target predecessor,
+// so every intermediate order keeps the already ordered IDs in the target
+// order.
+func priorityMoves(current, target []string) []priorityMove {
+	position := make(map[string]int, len(current))
+	for i, id := range current {
+		position[id] = i
+	}
+
+	// longest increasing subsequence of the current positions in the target
+	// order, O(n^2) is fine for the number of rules of a policy
+	length := make([]int, len(target))
+	previous := make([]int, len(target))
+	last := -1
+	for i := range target {
+		length[i], previous[i] = 1, -1
+		for j := 0; j < i; j++ {
+			if position[target[j]] < position[target[i]] && length[j]+1 > length[i] {
+				length[i], previous[i] = length[j]+1, j
+			}
+		}
+		if last == -1 || length[i] > length[last] {
+			last = i
+		}
+	}
+	inPlace := make(map[string]bool, len(target))
+	for i := last; i >= 0; i = previous[i] {
+		inPlace[target[i]] = true
+	}
+
+	var moves []priorityMove
+	order := append([]string(nil), current...)
+	for i, id := range target {
+		if inPlace[id] {
+			continue
+		}
+		order = remove(order, id)
+		index := 0
+		if i > 0 {
+			index = indexOfString(order, target[i-1]) + 1
+		}
+		order = append(order[:index], append([]string{id}, order[index:]...)...)
+		moves = append(moves, priorityMove{ID: id, Priority: index + 1})
+	}
+	return moves
+}
+
+func indexOfString(s []string, v string) int {
+	for i := range s {
+		if s[i] == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// priorityUpdateBody is the body updating the priority of a policy or a policy
+// rule listed as a raw JSON object, so it's updated whatever its type.
+func priorityUpdateBody(obj map[string]interface{}, priority int) map[string]interface{} {
+	body := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		switch k {
+		case "_embedded", "_links", "created", "lastUpdated":
+		default:
+			body[k] = v
+		}
+	}
+	body["priority"] = priority
+	return body
+}
+
+// sortByPriority sorts policies or policy rules listed as raw JSON objects by
+// priority.
+func sortByPriority(objs []map[string]interface{}) {
+	sort.SliceStable(objs, func(i, j int) bool {
+		return jsonPriority(objs[i]) < jsonPriority(objs[j])
+	})
+}
+
+func jsonPriority(obj map[string]interface{}) float64 {
+	priority, _ := obj["priority"].(float64)
+	return priority
+}
+
+func jsonIsSystem(obj map[string]interface{}) bool {
+	system, _ := obj["system"].(bool)
+	return system
+}
@@ -12,7 +12,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
+	"github.com/okta/terraform-provider-okta/okta/internal/metrics"
 	"github.com/okta/terraform-provider-okta/okta/internal/mutexkv"
+	"github.com/okta/terraform-provider-okta/okta/internal/permissions"
 )
 
 // Resource names, defined in place, used throughout the provider and tests
@@ -29,11 +31,13 @@ const (
 	appMetadataSaml               = "okta_app_metadata_saml"
 	appOAuth                      = "okta_app_oauth"
 	appOAuthAPIScope              = "okta_app_oauth_api_scope"
+	appOAuthClientSecret          = "okta_app_oauth_client_secret"
 	appOAuthPostLogoutRedirectURI = "okta_app_oauth_post_logout_redirect_uri"
 	appOAuthRedirectURI           = "okta_app_oauth_redirect_uri"
 	appOAuthRoleAssignment        = "okta_app_oauth_role_assignment"
 	appSaml                       = "okta_app_saml"
 	appSamlAppSettings            = "okta_app_saml_app_settings"
+	appSamlSigningKey             = "okta_app_saml_signing_key"
 	appSecurePasswordStore        = "okta_app_secure_password_store"
 	appSharedCredentials          = "okta_app_shared_credentials"
 	appSignOnPolicy               = "okta_app_signon_policy"
@@ -42,6 +46,7 @@ const (
 	appThreeField                 = "okta_app_three_field"
 	appUser                       = "okta_app_user"
 	appUserAssignments            = "okta_app_user_assignments"
+	apps                          = "okta_apps"
 	appUserBaseSchemaProperty     = "okta_app_user_base_schema_property"
 	appUserSchemaProperty         = "okta_app_user_schema_property"
 	authenticator                 = "okta_authenticator"
@@ -50,6 +55,8 @@ const (
 	authServerClaimDefault        = "okta_auth_server_claim_default"
 	authServerClaims              = "okta_auth_server_claims"
 	authServerDefault             = "okta_auth_server_default"
+	authServerKeyRotation         = "okta_auth_server_key_rotation"
+	authServerKeys                = "okta_auth_server_keys"
 	authServerPolicy              = "okta_auth_server_policy"
 	authServerPolicyRule          = "okta_auth_server_policy_rule"
 	authServerScope               = "okta_auth_server_scope"
@@ -69,6 +76,7 @@ const (
 	emailSender                   = "okta_email_sender"
 	emailSenderVerification       = "okta_email_sender_verification"
 	emailCustomization            = "okta_email_customization"
+	emailCustomizationPreview     = "okta_email_customization_preview"
 	emailCustomizations           = "okta_email_customizations"
 	emailTemplate                 = "okta_email_template"
 	emailTemplates                = "okta_email_templates"
@@ -91,6 +99,7 @@ const (
 	inlineHook                    = "okta_inline_hook"
 	linkDefinition                = "okta_link_definition"
 	linkValue                     = "okta_link_value"
+	logEvents                     = "okta_log_events"
 	logStream                     = "okta_log_stream"
 	networkZone                   = "okta_network_zone"
 	orgConfiguration              = "okta_org_configuration"
@@ -98,12 +107,14 @@ const (
 	policy                        = "okta_policy"
 	policyMfa                     = "okta_policy_mfa"
 	policyMfaDefault              = "okta_policy_mfa_default"
+	policyOrder                   = "okta_policy_order"
 	policyPassword                = "okta_policy_password"
 	policyPasswordDefault         = "okta_policy_password_default"
 	policyProfileEnrollment       = "okta_policy_profile_enrollment"
 	policyProfileEnrollmentApps   = "okta_policy_profile_enrollment_apps"
 	policyRuleIdpDiscovery        = "okta_policy_rule_idp_discovery"
 	policyRuleMfa                 = "okta_policy_rule_mfa"
+	policyRuleOrder               = "okta_policy_rule_order"
 	policyRulePassword            = "okta_policy_rule_password"
 	policyRuleProfileEnrollment   = "okta_policy_rule_profile_enrollment"
 	policyRuleSignOn              = "okta_policy_rule_signon"
@@ -137,7 +148,7 @@ var oktaMutexKV = mutexkv.NewMutexKV()
 // Provider establishes a client connection to an okta site
 // determined by its schema string values
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	return withImportKeys(withResourceTypes(&schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"org_name": {
 				Type:        schema.TypeString,
@@ -184,7 +195,7 @@ func Provider() *schema.Provider {
 			"base_url": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "The Okta url. (Use 'oktapreview.com' for Okta testing)",
+				Description: "The Okta url. (Use 'oktapreview.com' for Okta testing, or the full URL of a fake Okta API, e.g. 'http://127.0.0.1:8080')",
 			},
 			"http_proxy": {
 				Type:        schema.TypeString,
@@ -237,6 +248,57 @@ func Provider() *schema.Provider {
 				ValidateDiagFunc: intBetween(0, 300),
 				Description:      "Timeout for single request (in seconds) which is made to Okta, the default is `0` (means no limit is set). The maximum value can be `300`.",
 			},
+			"api_token_role": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: stringInSlice(permissions.Roles()),
+				Description: "The admin role of the API token or OAuth 2.0 service app, one of the standard role types such as `SUPER_ADMIN` or `ORG_ADMIN`. " +
+					"When a request to an endpoint the role isn't known to have access to responds 401 Unauthorized or 403 Forbidden the provider warns and leaves the attributes depending on it unset instead of erroring. " +
+					"Detected from the token admin's roles for `api_token` when not set. Can also be sourced from the `OKTA_API_TOKEN_ROLE` environment variable.",
+			},
+			"read_only": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Description: "Refuse every request to the Okta API that would modify the org, for instance to run `terraform plan` or `terraform refresh` with a token that may not be scoped read-only. " +
+					"A mutating request fails with an error naming the resource that made it, its method and endpoint. Can also be sourced from the `OKTA_READ_ONLY` environment variable.",
+			},
+			"metrics_file": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "File the provider writes its Okta API usage to once a terraform run ends: request counts, latencies, 429s, retries and throttle sleeps " +
+					"per endpoint class and per resource type. The usage of the provider processes started by the same terraform run is added up. " +
+					"Can also be sourced from the `OKTA_METRICS_FILE` environment variable.",
+			},
+			"metrics_format": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: stringInSlice(metrics.Formats()),
+				Description:      "Format of the `metrics_file`, `openmetrics` (the default) or `json`. Can also be sourced from the `OKTA_METRICS_FORMAT` environment variable.",
+			},
+			"rate_limit_coordination_file": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "(Experimental) file on a volume shared by the terraform runs against the org, for instance parallel workspaces, through which the providers share the rate limit status of the Okta API so `max_api_capacity` is enforced across processes. " +
+					"Only used when `max_api_capacity` is set below 100. Can also be sourced from the `OKTA_RATE_LIMIT_COORDINATION_FILE` environment variable.",
+			},
+			"cache_enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Description: "(Experimental) cache responses of GET requests on disk so they can be reused across terraform runs, " +
+					"for instance a plan followed by an apply. A cached path, the paths beneath it, and the collections above it are invalidated " +
+					"whenever the provider makes a POST, PUT, or DELETE request to it. Can also be sourced from the `OKTA_CACHE_ENABLED` environment variable.",
+			},
+			"cache_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Directory of the response cache when `cache_enabled` is set, the default is a `terraform-provider-okta` directory in the user's cache directory. Can also be sourced from the `OKTA_CACHE_DIR` environment variable.",
+			},
+			"cache_ttl_seconds": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: intBetween(1, 86400),
+				Description:      "Time to live (in seconds) of a cached response when `cache_enabled` is set, the default is `300`.",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			adminRoleCustom:               resourceAdminRoleCustom(),
@@ -253,6 +315,7 @@ func Provider() *schema.Provider {
 			appOAuthRedirectURI:           resourceAppOAuthRedirectURI(),
 			appSaml:                       resourceAppSaml(),
 			appSamlAppSettings:            resourceAppSamlAppSettings(),
+			appSamlSigningKey:             resourceAppSamlSigningKey(),
 			appSecurePasswordStore:        resourceAppSecurePasswordStore(),
 			appSharedCredentials:          resourceAppSharedCredentials(),
 			appSignOnPolicy:               resourceAppSignOnPolicy(),
@@ -267,6 +330,7 @@ func Provider() *schema.Provider {
 			authServerClaim:               resourceAuthServerClaim(),
 			authServerClaimDefault:        resourceAuthServerClaimDefault(),
 			authServerDefault:             resourceAuthServerDefault(),
+			authServerKeyRotation:         resourceAuthServerKeyRotation(),
 			authServerPolicy:              resourceAuthServerPolicy(),
 			authServerPolicyRule:          resourceAuthServerPolicyRule(),
 			authServerScope:               resourceAuthServerScope(),
@@ -277,6 +341,7 @@ func Provider() *schema.Provider {
 			domainCertificate:             resourceDomainCertificate(),
 			domainVerification:            resourceDomainVerification(),
 			emailCustomization:            resourceEmailCustomization(),
+			emailCustomizations:           resourceEmailCustomizations(),
 			emailDomain:                   resourceEmailDomain(),
 			emailDomainVerification:       resourceEmailDomainVerification(),
 			emailSender:                   resourceEmailSender(),
@@ -302,12 +367,14 @@ func Provider() *schema.Provider {
 			orgSupport:                    resourceOrgSupport(),
 			policyMfa:                     resourcePolicyMfa(),
 			policyMfaDefault:              resourcePolicyMfaDefault(),
+			policyOrder:                   resourcePolicyOrder(),
 			policyPassword:                resourcePolicyPassword(),
 			policyPasswordDefault:         resourcePolicyPasswordDefault(),
 			policyProfileEnrollment:       resourcePolicyProfileEnrollment(),
 			policyProfileEnrollmentApps:   resourcePolicyProfileEnrollmentApps(),
 			policyRuleIdpDiscovery:        resourcePolicyRuleIdpDiscovery(),
 			policyRuleMfa:                 resourcePolicyMfaRule(),
+			policyRuleOrder:               resourcePolicyRuleOrder(),
 			policyRulePassword:            resourcePolicyPasswordRule(),
 			policyRuleProfileEnrollment:   resourcePolicyProfileEnrollmentRule(),
 			policyRuleSignOn:              resourcePolicySignOnRule(),
@@ -330,51 +397,55 @@ func Provider() *schema.Provider {
 			userType:                      resourceUserType(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			app:                      dataSourceApp(),
-			appGroupAssignments:      dataSourceAppGroupAssignments(),
-			appMetadataSaml:          dataSourceAppMetadataSaml(),
-			appOAuth:                 dataSourceAppOauth(),
-			appSaml:                  dataSourceAppSaml(),
-			appSignOnPolicy:          dataSourceAppSignOnPolicy(),
-			appUserAssignments:       dataSourceAppUserAssignments(),
-			authenticator:            dataSourceAuthenticator(),
-			authServer:               dataSourceAuthServer(),
-			authServerClaim:          dataSourceAuthServerClaim(),
-			authServerClaims:         dataSourceAuthServerClaims(),
-			authServerPolicy:         dataSourceAuthServerPolicy(),
-			authServerScopes:         dataSourceAuthServerScopes(),
-			behavior:                 dataSourceBehavior(),
-			behaviors:                dataSourceBehaviors(),
-			brand:                    dataSourceBrand(),
-			brands:                   dataSourceBrands(),
-			domain:                   dataSourceDomain(),
-			emailCustomization:       dataSourceEmailCustomization(),
-			emailCustomizations:      dataSourceEmailCustomizations(),
-			emailTemplate:            dataSourceEmailTemplate(),
-			emailTemplates:           dataSourceEmailTemplates(),
-			defaultPolicy:            dataSourceDefaultPolicy(),
-			group:                    dataSourceGroup(),
-			groupEveryone:            dataSourceEveryoneGroup(),
-			groupRule:                dataSourceGroupRule(),
-			groups:                   dataSourceGroups(),
-			idpMetadataSaml:          dataSourceIdpMetadataSaml(),
-			idpOidc:                  dataSourceIdpOidc(),
-			idpSaml:                  dataSourceIdpSaml(),
-			idpSocial:                dataSourceIdpSocial(),
-			networkZone:              dataSourceNetworkZone(),
-			policy:                   dataSourcePolicy(),
-			roleSubscription:         dataSourceRoleSubscription(),
-			theme:                    dataSourceTheme(),
-			themes:                   dataSourceThemes(),
-			trustedOrigins:           dataSourceTrustedOrigins(),
-			user:                     dataSourceUser(),
-			userProfileMappingSource: dataSourceUserProfileMappingSource(),
-			users:                    dataSourceUsers(),
-			userSecurityQuestions:    dataSourceUserSecurityQuestions(),
-			userType:                 dataSourceUserType(),
+			app:                       dataSourceApp(),
+			appGroupAssignments:       dataSourceAppGroupAssignments(),
+			appMetadataSaml:           dataSourceAppMetadataSaml(),
+			appOAuth:                  dataSourceAppOauth(),
+			appSaml:                   dataSourceAppSaml(),
+			appSignOnPolicy:           dataSourceAppSignOnPolicy(),
+			appUserAssignments:        dataSourceAppUserAssignments(),
+			apps:                      dataSourceApps(),
+			authenticator:             dataSourceAuthenticator(),
+			authServer:                dataSourceAuthServer(),
+			authServerClaim:           dataSourceAuthServerClaim(),
+			authServerClaims:          dataSourceAuthServerClaims(),
+			authServerKeys:            dataSourceAuthServerKeys(),
+			authServerPolicy:          dataSourceAuthServerPolicy(),
+			authServerScopes:          dataSourceAuthServerScopes(),
+			behavior:                  dataSourceBehavior(),
+			behaviors:                 dataSourceBehaviors(),
+			brand:                     dataSourceBrand(),
+			brands:                    dataSourceBrands(),
+			domain:                    dataSourceDomain(),
+			emailCustomization:        dataSourceEmailCustomization(),
+			emailCustomizationPreview: dataSourceEmailCustomizationPreview(),
+			emailCustomizations:       dataSourceEmailCustomizations(),
+			emailTemplate:             dataSourceEmailTemplate(),
+			emailTemplates:            dataSourceEmailTemplates(),
+			defaultPolicy:             dataSourceDefaultPolicy(),
+			group:                     dataSourceGroup(),
+			groupEveryone:             dataSourceEveryoneGroup(),
+			groupRule:                 dataSourceGroupRule(),
+			groups:                    dataSourceGroups(),
+			idpMetadataSaml:           dataSourceIdpMetadataSaml(),
+			idpOidc:                   dataSourceIdpOidc(),
+			idpSaml:                   dataSourceIdpSaml(),
+			idpSocial:                 dataSourceIdpSocial(),
+			logEvents:                 dataSourceLogEvents(),
+			networkZone:               dataSourceNetworkZone(),
+			policy:                    dataSourcePolicy(),
+			roleSubscription:          dataSourceRoleSubscription(),
+			theme:                     dataSourceTheme(),
+			themes:                    dataSourceThemes(),
+			trustedOrigins:            dataSourceTrustedOrigins(),
+			user:                      dataSourceUser(),
+			userProfileMappingSource:  dataSourceUserProfileMappingSource(),
+			users:                     dataSourceUsers(),
+			userSecurityQuestions:     dataSourceUserSecurityQuestions(),
+			userType:                  dataSourceUserType(),
 		},
 		ConfigureContextFunc: providerConfigure,
-	}
+	}))
 }
 
 // providerConfigure is only called once when a terraform command is run but it
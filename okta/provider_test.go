@@ -18,6 +18,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
@@ -27,6 +28,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/okta/okta-sdk-golang/v3/okta"
+	"github.com/okta/terraform-provider-okta/okta/internal/fakeokta"
 	"github.com/okta/terraform-provider-okta/sdk"
 	"gopkg.in/dnaeon/go-vcr.v3/cassette"
 	"gopkg.in/dnaeon/go-vcr.v3/recorder"
@@ -140,6 +142,73 @@ func TestProvider_impl(t *testing.T) {
 	_ = Provider()
 }
 
+func TestProviderFakeOktaAPI(t *testing.T) {
+	server := fakeokta.NewServer()
+	defer server.Close()
+	t.Setenv("OKTA_ACCESS_TOKEN", "")
+	t.Setenv("OKTA_HTTP_PROXY", "")
+
+	ctx := context.Background()
+	provider := Provider()
+	diags := provider.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"org_name":  "fake",
+		"base_url":  server.URL,
+		"api_token": fakeokta.DefaultToken,
+	}))
+	if diags.HasError() {
+		t.Fatalf("failed to configure the provider with the fake Okta API: %+v", diags)
+	}
+	m := provider.Meta()
+
+	g := schema.TestResourceDataRaw(t, resourceGroup().Schema, map[string]interface{}{
+		"name":        "Engineering",
+		"description": "Engineers",
+	})
+	if diags := resourceGroup().CreateContext(ctx, g, m); diags.HasError() {
+		t.Fatalf("failed to create group: %+v", diags)
+	}
+	u := schema.TestResourceDataRaw(t, resourceUser().Schema, map[string]interface{}{
+		"login":      "jane@example.com",
+		"email":      "jane@example.com",
+		"first_name": "Jane",
+		"last_name":  "Doe",
+		"password":   "Sup3rS3cret!",
+	})
+	if diags := resourceUser().CreateContext(ctx, u, m); diags.HasError() {
+		t.Fatalf("failed to create user: %+v", diags)
+	}
+	if u.Get("status") != statusActive {
+		t.Errorf("expected the user with a password to be active, got %q", u.Get("status"))
+	}
+
+	client := getOktaClientFromMetadata(m)
+	if _, err := client.Group.AddUserToGroup(ctx, g.Id(), u.Id()); err != nil {
+		t.Fatalf("failed to add user to group: %v", err)
+	}
+	users, _, err := client.Group.ListGroupUsers(ctx, g.Id(), nil)
+	if err != nil || len(users) != 1 || users[0].Id != u.Id() {
+		t.Fatalf("expected the user to be the group's member, got %v, %v", users, err)
+	}
+
+	for _, r := range []struct {
+		resource *schema.Resource
+		d        *schema.ResourceData
+	}{
+		{resourceUser(), u},
+		{resourceGroup(), g},
+	} {
+		if diags := r.resource.DeleteContext(ctx, r.d, m); diags.HasError() {
+			t.Fatalf("failed to delete %s: %+v", r.d.Id(), diags)
+		}
+		if diags := r.resource.ReadContext(ctx, r.d, m); diags.HasError() || r.d.Id() != "" {
+			t.Errorf("expected %s to be gone after delete, got %+v", r.d.Id(), diags)
+		}
+	}
+	if unimplemented := server.Unimplemented(); len(unimplemented) > 0 {
+		t.Errorf("expected the fake to implement every request, got %v", unimplemented)
+	}
+}
+
 func testAccPreCheck(t *testing.T) func() {
 	return func() {
 		err := accPreCheck()
@@ -288,6 +357,11 @@ func sdkSupplementClientForTest() *sdk.APISupplement {
 // oktaResourceTest is the entry to overriding the Terraform SDKs Acceptance
 // Test framework before the call to resource.Test
 func oktaResourceTest(t *testing.T, c resource.TestCase) {
+	if os.Getenv("OKTA_FAKE_TF_ACC") != "" {
+		fakeOktaResourceTest(t, c)
+		return
+	}
+
 	// plug in the VCR
 	mgr := newVCRManager(t.Name())
 
@@ -348,6 +422,63 @@ func oktaResourceTest(t *testing.T, c resource.TestCase) {
 	}
 }
 
+// fakeOktaResourceTest runs the test case against an in-memory fake of the
+// Okta API, wired into the provider by setting base_url to the fake's URL,
+// instead of an org or VCR cassettes. Endpoints the fake doesn't implement
+// fail the test and are logged.
+func fakeOktaResourceTest(t *testing.T, c resource.TestCase) {
+	server := fakeokta.NewServer()
+	defer server.Close()
+
+	t.Setenv("OKTA_ORG_NAME", "fake")
+	t.Setenv("OKTA_BASE_URL", server.URL)
+	t.Setenv("OKTA_API_TOKEN", fakeokta.DefaultToken)
+	t.Setenv("TF_VAR_hostname", strings.TrimPrefix(server.URL, "http://"))
+	for _, key := range []string{"OKTA_ACCESS_TOKEN", "OKTA_API_CLIENT_ID", "OKTA_API_PRIVATE_KEY", "OKTA_API_PRIVATE_KEY_ID", "OKTA_HTTP_PROXY"} {
+		t.Setenv(key, "")
+	}
+	resource.Test(t, c)
+
+	if t.Failed() {
+		for _, request := range server.Unimplemented() {
+			t.Logf("the fake Okta API doesn't implement %s", request)
+		}
+	}
+}
+
+// newFakeOktaConfig returns a fake Okta org and the configuration of the
+// provider calling it, the org is closed when the test ends.
+func newFakeOktaConfig(t *testing.T, opts ...fakeokta.Option) (*fakeokta.Server, *Config) {
+	t.Helper()
+	server := fakeokta.NewServer(opts...)
+	t.Cleanup(server.Close)
+	config := &Config{
+		domain:   server.URL,
+		apiToken: fakeokta.DefaultToken,
+		logger:   hclog.NewNullLogger(),
+	}
+	if err := config.loadClients(context.TODO()); err != nil {
+		t.Fatalf("failed to load clients: %v", err)
+	}
+	return server, config
+}
+
+// createFakeOktaObject creates an object by POSTing body to the path of the
+// fake Okta org of config and returns its ID.
+func createFakeOktaObject(t *testing.T, config *Config, path string, body map[string]interface{}) string {
+	t.Helper()
+	re := getRequestExecutor(config)
+	req, err := re.NewRequest(http.MethodPost, path, body)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	var obj map[string]interface{}
+	if _, err := re.Do(context.TODO(), req, &obj); err != nil {
+		t.Fatalf("failed to create %s: %v", path, err)
+	}
+	return obj["id"].(string)
+}
+
 // vcrProviderFactoriesForTest Returns the overridden provider factories used by
 // the resource test case given the state of the VCR manager.  func
 // vcrProviderFactoriesForTest(mgr *vcrManager) map[string]func()
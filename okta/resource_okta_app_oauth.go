@@ -111,7 +111,7 @@ func resourceAppOAuth() *schema.Resource {
 					return d.ForceNew("omit_secret")
 				}
 			}
-			return nil
+			return appOAuthRotationDiff(d)
 		},
 		// For those familiar with Terraform schemas be sure to check the base application schema and/or
 		// the examples in the documentation
@@ -146,11 +146,66 @@ func resourceAppOAuth() *schema.Resource {
 				Sensitive:   true,
 				Description: "OAuth client secret value, this is output only. This will be in plain text in your statefile unless you set omit_secret above.",
 			},
-			"client_basic_secret": {
-				Type:        schema.TypeString,
+			"rotation": {
+				Type:        schema.TypeList,
 				Optional:    true,
-				Sensitive:   true,
-				Description: "The user provided OAuth client secret key value, this can be set when token_endpoint_auth_method is client_secret_basic. This does nothing when `omit_secret is set to true.",
+				MaxItems:    1,
+				Description: "Rotation of the client secrets with several active secrets, so consuming services can pick up a new secret before the old one is deactivated.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"trigger": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Arbitrary value, a change of the value generates a new client secret.",
+						},
+						"keep": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Default:          2,
+							ValidateDiagFunc: intBetween(1, maxAppOAuthClientSecrets),
+							Description:      "Number of client secrets to keep, 1 or 2, the oldest secrets are deleted once a new one is generated.",
+						},
+						"overlap": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "0s",
+							ValidateDiagFunc: stringIsDuration,
+							Description:      "Duration after a rotation during which the previous client secrets stay active, e.g. 24h.",
+						},
+					},
+				},
+			},
+			"active_client_secret_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the active client secrets from the newest to the oldest, only set along with `rotation`.",
+			},
+			"client_secret_rotated_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Creation timestamp of the newest client secret, only set along with `rotation`.",
+			},
+			"client_basic_secret": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"client_basic_secret_wo"},
+				Description:   "The user provided OAuth client secret key value, this can be set when token_endpoint_auth_method is client_secret_basic. This does nothing when `omit_secret is set to true.",
+			},
+			"client_basic_secret_wo": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				WriteOnly:    true,
+				RequiredWith: []string{"client_basic_secret_wo_version"},
+				Description:  "Write-only variant of `client_basic_secret`. It is never stored in the state, change `client_basic_secret_wo_version` to update it. Use it along with `omit_secret` to keep the client secret out of the state. Requires Terraform 1.11 or later.",
+			},
+			"client_basic_secret_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"client_basic_secret_wo"},
+				Description:  "Version of `client_basic_secret_wo`, a change of the version updates the client secret.",
 			},
 			"token_endpoint_auth_method": {
 				Type:        schema.TypeString,
@@ -438,6 +493,10 @@ func resourceAppOAuthCreate(ctx context.Context, d *schema.ResourceData, m inter
 	if err != nil {
 		return diag.Errorf("failed to set authentication policy for an OAuth application: %v", err)
 	}
+	err = rotateAppOAuthClientSecrets(ctx, d, m)
+	if err != nil {
+		return diag.Errorf("failed to rotate client secrets of an OAuth application: %v", err)
+	}
 	return resourceAppOAuthRead(ctx, d, m)
 }
 
@@ -517,6 +576,13 @@ func resourceAppOAuthRead(ctx context.Context, d *schema.ResourceData, m interfa
 	appRead(d, app.Name, app.Status, app.SignOnMode, app.Label, app.Accessibility, app.Visibility, app.Settings.Notes)
 	_ = d.Set("profile", rawProfile)
 	// Not setting client_secret, it is only provided on create and update for auth methods that require it
+	if len(d.Get("rotation").([]interface{})) > 0 {
+		secrets, err := listAppOAuthClientSecrets(ctx, m, d.Id())
+		if err != nil {
+			return diag.Errorf("failed to list client secrets of OAuth application: %v", err)
+		}
+		setAppOAuthActiveClientSecrets(d, secrets)
+	}
 	if app.Credentials.OauthClient != nil {
 		_ = d.Set("client_id", app.Credentials.OauthClient.ClientId)
 		_ = d.Set("token_endpoint_auth_method", app.Credentials.OauthClient.TokenEndpointAuthMethod)
@@ -669,7 +735,9 @@ func resourceAppOAuthUpdate(ctx context.Context, d *schema.ResourceData, m inter
 	// the client secret value in the api call.
 	// This is to ensure that when this is "toggled on", the apply which this occurs also does
 	// not do a final "reset" of the client secret value to the original stored in state.
-	if d.Get("omit_secret").(bool) {
+	// The same goes for rotation, the secrets are managed through the client
+	// secrets API.
+	if d.Get("omit_secret").(bool) || len(d.Get("rotation").([]interface{})) > 0 {
 		app.Credentials.OauthClient.ClientSecret = ""
 	}
 	appResp, _, err := client.Application.UpdateApplication(ctx, d.Id(), app)
@@ -707,6 +775,10 @@ func resourceAppOAuthUpdate(ctx context.Context, d *schema.ResourceData, m inter
 	if err != nil {
 		return diag.Errorf("failed to set authentication policy an OAuth application: %v", err)
 	}
+	err = rotateAppOAuthClientSecrets(ctx, d, m)
+	if err != nil {
+		return diag.Errorf("failed to rotate client secrets of an OAuth application: %v", err)
+	}
 	return resourceAppOAuthRead(ctx, d, m)
 }
 
@@ -785,8 +857,8 @@ func buildAppOAuth(d *schema.ResourceData, isNew bool) *sdk.OpenIdConnectApplica
 	}
 	app.Credentials.OauthClient.PkceRequired = pkceRequired
 
-	if sec, ok := d.GetOk("client_basic_secret"); ok {
-		app.Credentials.OauthClient.ClientSecret = sec.(string)
+	if sec := getSecret(d, "client_basic_secret"); sec != "" {
+		app.Credentials.OauthClient.ClientSecret = sec
 	}
 
 	oktaRespTypes := make([]*sdk.OAuthResponseType, len(responseTypes))
@@ -961,3 +1033,150 @@ func verifyOidcAppType(app sdk.App) (*sdk.OpenIdConnectApplication, error) {
 	}
 	return oidcApp, nil
 }
+
+// maxAppOAuthClientSecrets is the number of client secrets Okta allows an
+// application to have.
+const maxAppOAuthClientSecrets = 2
+
+type appOAuthRotation struct {
+	trigger string
+	keep    int
+	overlap time.Duration
+}
+
+func buildAppOAuthRotation(raw interface{}) *appOAuthRotation {
+	l, _ := raw.([]interface{})
+	if len(l) == 0 {
+		return nil
+	}
+	rotation := &appOAuthRotation{keep: 2}
+	if r, ok := l[0].(map[string]interface{}); ok {
+		rotation.trigger = r["trigger"].(string)
+		if keep := r["keep"].(int); keep > 0 {
+			rotation.keep = keep
+		}
+		rotation.overlap, _ = time.ParseDuration(r["overlap"].(string))
+	}
+	return rotation
+}
+
+// appOAuthRotationOverlapOver tells whether the previous client secrets can be
+// deactivated, the overlap after the last rotation being over.
+func appOAuthRotationOverlapOver(rotatedAt string, overlap time.Duration, now time.Time) bool {
+	t, err := time.Parse(time.RFC3339, rotatedAt)
+	if err != nil {
+		return true
+	}
+	return !now.Before(t.Add(overlap))
+}
+
+// appOAuthRotationDiff plans the rotation of the client secrets: a new secret
+// when the rotation trigger changes, the deactivation of the previous secrets
+// once the overlap after the last rotation is over.
+func appOAuthRotationDiff(d *schema.ResourceDiff) error {
+	rotation := buildAppOAuthRotation(d.Get("rotation"))
+	if d.Id() == "" || rotation == nil {
+		return nil
+	}
+	if d.HasChange("rotation.0.trigger") && rotation.trigger != "" {
+		if err := d.SetNewComputed("active_client_secret_ids"); err != nil {
+			return err
+		}
+		if err := d.SetNewComputed("client_secret_rotated_at"); err != nil {
+			return err
+		}
+		if !d.Get("omit_secret").(bool) {
+			return d.SetNewComputed("client_secret")
+		}
+		return nil
+	}
+	ids := convertInterfaceToStringArr(d.Get("active_client_secret_ids"))
+	if len(ids) > 1 && appOAuthRotationOverlapOver(d.Get("client_secret_rotated_at").(string), rotation.overlap, time.Now()) {
+		return d.SetNew("active_client_secret_ids", ids[:1])
+	}
+	return nil
+}
+
+// rotateAppOAuthClientSecrets generates a new client secret when the rotation
+// trigger changes, deleting the oldest secrets beyond the number of secrets to
+// keep once it exists, and deactivates the previous secrets once the overlap
+// is over. The newest active secret is never deactivated before a new one
+// replaces it.
+func rotateAppOAuthClientSecrets(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	rotation := buildAppOAuthRotation(d.Get("rotation"))
+	if rotation == nil {
+		return nil
+	}
+	secrets, err := listAppOAuthClientSecrets(ctx, m, d.Id())
+	if err != nil {
+		return err
+	}
+	sortAppOAuthClientSecrets(secrets)
+	newest := newestActiveAppOAuthClientSecret(secrets)
+	if !d.IsNewResource() && d.HasChange("rotation.0.trigger") && rotation.trigger != "" {
+		// only the secrets older than the current one make room for the new
+		// secret, the current one is pruned once the new secret exists
+		secrets, err = pruneAppOAuthClientSecrets(ctx, m, d.Id(), secrets, maxAppOAuthClientSecrets-1, newest)
+		if err != nil {
+			return err
+		}
+		newest, err = createAppOAuthClientSecret(ctx, m, d.Id(), &oauthClientSecret{})
+		if err != nil {
+			return err
+		}
+		secrets = append([]*oauthClientSecret{newest}, secrets...)
+		secrets, err = pruneAppOAuthClientSecrets(ctx, m, d.Id(), secrets, rotation.keep, newest)
+		if err != nil {
+			return err
+		}
+	}
+	if newest != nil && appOAuthRotationOverlapOver(newest.Created, rotation.overlap, time.Now()) {
+		for _, s := range secrets {
+			if s == newest || s.Status != statusActive {
+				continue
+			}
+			inactive, err := setAppOAuthClientSecretStatus(ctx, m, d.Id(), s.ID, statusInactive)
+			if err != nil {
+				return err
+			}
+			*s = *inactive
+		}
+	}
+	setAppOAuthActiveClientSecrets(d, secrets)
+	if !d.Get("omit_secret").(bool) && newest != nil && newest.ClientSecret != "" {
+		_ = d.Set("client_secret", newest.ClientSecret)
+	}
+	return nil
+}
+
+// pruneAppOAuthClientSecrets deletes the oldest client secrets, other than
+// keep, until there are at most max secrets. The secrets are sorted from the
+// newest to the oldest.
+func pruneAppOAuthClientSecrets(ctx context.Context, m interface{}, appID string, secrets []*oauthClientSecret, max int, keep *oauthClientSecret) ([]*oauthClientSecret, error) {
+	for i := len(secrets) - 1; i >= 0 && len(secrets) > max; i-- {
+		if secrets[i] == keep {
+			continue
+		}
+		if err := deleteAppOAuthClientSecret(ctx, m, appID, secrets[i].ID, secrets[i].Status); err != nil {
+			return nil, err
+		}
+		secrets = append(secrets[:i], secrets[i+1:]...)
+	}
+	return secrets, nil
+}
+
+func setAppOAuthActiveClientSecrets(d *schema.ResourceData, secrets []*oauthClientSecret) {
+	sortAppOAuthClientSecrets(secrets)
+	var ids []string
+	for _, s := range secrets {
+		if s.Status == statusActive {
+			ids = append(ids, s.ID)
+		}
+	}
+	_ = d.Set("active_client_secret_ids", ids)
+	if newest := newestActiveAppOAuthClientSecret(secrets); newest != nil {
+		_ = d.Set("client_secret_rotated_at", newest.Created)
+	} else {
+		_ = d.Set("client_secret_rotated_at", "")
+	}
+}
@@ -0,0 +1,318 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithImportState = &appOAuthClientSecretResource{}
+
+func NewAppOAuthClientSecretResource() resource.Resource {
+	return &appOAuthClientSecretResource{}
+}
+
+type appOAuthClientSecretResource struct {
+	*Config
+}
+
+type appOAuthClientSecretResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	AppID        types.String `tfsdk:"app_id"`
+	Status       types.String `tfsdk:"status"`
+	ClientSecret types.String `tfsdk:"client_secret"`
+	SecretHash   types.String `tfsdk:"secret_hash"`
+	Created      types.String `tfsdk:"created"`
+	LastUpdated  types.String `tfsdk:"last_updated"`
+}
+
+// oauthClientSecret is a client secret of an OAuth application, neither the
+// local SDK nor the v3 SDK version in use support the client secrets API.
+type oauthClientSecret struct {
+	ID           string `json:"id,omitempty"`
+	Status       string `json:"status,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	SecretHash   string `json:"secret_hash,omitempty"`
+	Created      string `json:"created,omitempty"`
+	LastUpdated  string `json:"lastUpdated,omitempty"`
+}
+
+func (r *appOAuthClientSecretResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_oauth_client_secret"
+}
+
+func (r *appOAuthClientSecretResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.Config = resourceConfiguration(req, resp)
+}
+
+func (r *appOAuthClientSecretResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Manages a client secret of an OAuth application. An application can have several client secrets, so a new secret can be rolled out before the old one is deactivated.",
+		MarkdownDescription: "Manages a client secret of an OAuth application. An application can have several client secrets, so a new secret can be rolled out before the old one is deactivated.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:         "ID of the client secret.",
+				MarkdownDescription: "ID of the client secret.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"app_id": schema.StringAttribute{
+				Description:         "ID of the OAuth application.",
+				MarkdownDescription: "ID of the OAuth application.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Description:         "Status of the client secret: ACTIVE or INACTIVE. Default is ACTIVE.",
+				MarkdownDescription: "Status of the client secret: `ACTIVE` or `INACTIVE`. Default is `ACTIVE`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(statusActive),
+				Validators: []validator.String{
+					stringvalidator.OneOf(statusActive, statusInactive),
+				},
+			},
+			"client_secret": schema.StringAttribute{
+				Description:         "Value of the client secret.",
+				MarkdownDescription: "Value of the client secret.",
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"secret_hash": schema.StringAttribute{
+				Description:         "Hash of the client secret.",
+				MarkdownDescription: "Hash of the client secret.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created": schema.StringAttribute{
+				Description:         "Creation timestamp of the client secret.",
+				MarkdownDescription: "Creation timestamp of the client secret.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Description:         "Last update timestamp of the client secret.",
+				MarkdownDescription: "Last update timestamp of the client secret.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *appOAuthClientSecretResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data appOAuthClientSecretResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appID := data.AppID.ValueString()
+	secret, err := createAppOAuthClientSecret(ctx, r.Config, appID, &oauthClientSecret{Status: data.Status.ValueString()})
+	if err != nil {
+		resp.Diagnostics.AddError("failed to create client secret of OAuth application", err.Error())
+		return
+	}
+	if secret.Status != data.Status.ValueString() {
+		secret, err = setAppOAuthClientSecretStatus(ctx, r.Config, appID, secret.ID, data.Status.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("failed to change status of client secret of OAuth application", err.Error())
+			return
+		}
+	}
+
+	setAppOAuthClientSecretModel(&data, secret)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *appOAuthClientSecretResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data appOAuthClientSecretResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secret, err := getAppOAuthClientSecret(ctx, r.Config, data.AppID.ValueString(), data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("failed to get client secret of OAuth application", err.Error())
+		return
+	}
+	if secret == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	setAppOAuthClientSecretModel(&data, secret)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *appOAuthClientSecretResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data appOAuthClientSecretResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secret, err := setAppOAuthClientSecretStatus(ctx, r.Config, data.AppID.ValueString(), data.ID.ValueString(), data.Status.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("failed to change status of client secret of OAuth application", err.Error())
+		return
+	}
+
+	setAppOAuthClientSecretModel(&data, secret)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *appOAuthClientSecretResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data appOAuthClientSecretResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := deleteAppOAuthClientSecret(ctx, r.Config, data.AppID.ValueString(), data.ID.ValueString(), data.Status.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("failed to delete client secret of OAuth application", err.Error())
+	}
+}
+
+func (r *appOAuthClientSecretResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, "/")
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError("Unexpected Import Identifier", "Expected import identifier with format <app_id>/<secret_id>")
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &appOAuthClientSecretResourceModel{
+		AppID: types.StringValue(idParts[0]),
+		ID:    types.StringValue(idParts[1]),
+	})...)
+}
+
+func setAppOAuthClientSecretModel(data *appOAuthClientSecretResourceModel, secret *oauthClientSecret) {
+	data.ID = types.StringValue(secret.ID)
+	data.Status = types.StringValue(secret.Status)
+	data.ClientSecret = types.StringValue(secret.ClientSecret)
+	data.SecretHash = types.StringValue(secret.SecretHash)
+	data.Created = types.StringValue(secret.Created)
+	data.LastUpdated = types.StringValue(secret.LastUpdated)
+}
+
+// sortAppOAuthClientSecrets sorts the secrets from the newest to the oldest.
+func sortAppOAuthClientSecrets(secrets []*oauthClientSecret) {
+	sort.SliceStable(secrets, func(i, j int) bool {
+		ci, _ := time.Parse(time.RFC3339, secrets[i].Created)
+		cj, _ := time.Parse(time.RFC3339, secrets[j].Created)
+		return ci.After(cj)
+	})
+}
+
+func appOAuthClientSecretsURL(appID string) string {
+	return fmt.Sprintf("/api/v1/apps/%s/credentials/secrets", appID)
+}
+
+func listAppOAuthClientSecrets(ctx context.Context, m interface{}, appID string) ([]*oauthClientSecret, error) {
+	re := getRequestExecutor(m)
+	req, err := re.WithAccept("application/json").WithContentType("application/json").
+		NewRequest(http.MethodGet, appOAuthClientSecretsURL(appID), nil)
+	if err != nil {
+		return nil, err
+	}
+	var secrets []*oauthClientSecret
+	_, err = re.Do(ctx, req, &secrets)
+	return secrets, err
+}
+
+// getAppOAuthClientSecret returns the client secret, nil when it doesn't exist.
+func getAppOAuthClientSecret(ctx context.Context, m interface{}, appID, secretID string) (*oauthClientSecret, error) {
+	re := getRequestExecutor(m)
+	req, err := re.WithAccept("application/json").WithContentType("application/json").
+		NewRequest(http.MethodGet, appOAuthClientSecretsURL(appID)+"/"+secretID, nil)
+	if err != nil {
+		return nil, err
+	}
+	var secret oauthClientSecret
+	resp, err := re.Do(ctx, req, &secret)
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return nil, err
+	}
+	if secret.ID == "" {
+		return nil, nil
+	}
+	return &secret, nil
+}
+
+func createAppOAuthClientSecret(ctx context.Context, m interface{}, appID string, body *oauthClientSecret) (*oauthClientSecret, error) {
+	re := getRequestExecutor(m)
+	req, err := re.WithAccept("application/json").WithContentType("application/json").
+		NewRequest(http.MethodPost, appOAuthClientSecretsURL(appID), body)
+	if err != nil {
+		return nil, err
+	}
+	var secret oauthClientSecret
+	_, err = re.Do(ctx, req, &secret)
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// setAppOAuthClientSecretStatus activates or deactivates the client secret.
+func setAppOAuthClientSecretStatus(ctx context.Context, m interface{}, appID, secretID, status string) (*oauthClientSecret, error) {
+	action := "activate"
+	if status == statusInactive {
+		action = "deactivate"
+	}
+	re := getRequestExecutor(m)
+	req, err := re.WithAccept("application/json").WithContentType("application/json").
+		NewRequest(http.MethodPost, fmt.Sprintf("%s/%s/lifecycle/%s", appOAuthClientSecretsURL(appID), secretID, action), nil)
+	if err != nil {
+		return nil, err
+	}
+	var secret oauthClientSecret
+	_, err = re.Do(ctx, req, &secret)
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// deleteAppOAuthClientSecret deletes the client secret, an active secret is
+// deactivated first as only inactive secrets can be deleted.
+func deleteAppOAuthClientSecret(ctx context.Context, m interface{}, appID, secretID, status string) error {
+	if status == statusActive {
+		_, err := setAppOAuthClientSecretStatus(ctx, m, appID, secretID, statusInactive)
+		if err != nil {
+			return err
+		}
+	}
+	re := getRequestExecutor(m)
+	req, err := re.WithAccept("application/json").WithContentType("application/json").
+		NewRequest(http.MethodDelete, appOAuthClientSecretsURL(appID)+"/"+secretID, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := re.Do(ctx, req, nil)
+	return suppressErrorOn404(resp, err)
+}
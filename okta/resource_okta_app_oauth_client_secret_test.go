@@ -0,0 +1,142 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/okta/internal/fakeokta"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccResourceOktaAppOAuthClientSecret_basic(t *testing.T) {
+	mgr := newFixtureManager("resources", appOAuthClientSecret, t.Name())
+	resourceName := fmt.Sprintf("%s.test", appOAuthClientSecret)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:                 testAccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: testAccMergeProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: mgr.GetFixtures("basic.tf", t),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "status", statusActive),
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "client_secret"),
+					resource.TestCheckResourceAttrSet(resourceName, "secret_hash"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					r, ok := s.RootModule().Resources[resourceName]
+					if !ok {
+						return "", fmt.Errorf("Unable to find resource: %s", resourceName)
+					}
+					return fmt.Sprintf("%s/%s", r.Primary.Attributes["app_id"], r.Primary.Attributes["id"]), nil
+				},
+			},
+			{
+				Config: mgr.GetFixtures("basic_updated.tf", t),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "status", statusInactive),
+				),
+			},
+		},
+	})
+}
+
+// newFakeOktaOAuthApp returns the configuration of the provider calling a
+// fake org, with the ID of an OAuth app of the org and its client secret.
+// Every request to the org is a second later, so that the secrets are ordered.
+func newFakeOktaOAuthApp(t *testing.T) (*Config, string, *oauthClientSecret) {
+	t.Helper()
+	now := time.Now().Add(-2 * time.Hour)
+	_, config := newFakeOktaConfig(t, fakeokta.WithClock(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}))
+	app := sdk.NewOpenIdConnectApplication()
+	app.Label = "My App"
+	_, _, err := config.oktaSDKClientV2.Application.CreateApplication(context.TODO(), app, nil)
+	require.NoError(t, err)
+	secrets, err := listAppOAuthClientSecrets(context.TODO(), config, app.Id)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	return config, app.Id, secrets[0]
+}
+
+func TestRotateAppOAuthClientSecrets(t *testing.T) {
+	config, appID, old := newFakeOktaOAuthApp(t)
+	ctx := context.TODO()
+	current, err := createAppOAuthClientSecret(ctx, config, appID, &oauthClientSecret{})
+	require.NoError(t, err)
+	_, err = setAppOAuthClientSecretStatus(ctx, config, appID, old.ID, statusInactive)
+	require.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, resourceAppOAuth().Schema, map[string]interface{}{
+		"rotation": []interface{}{map[string]interface{}{"trigger": "v2", "keep": 2, "overlap": "24h"}},
+	})
+	d.SetId(appID)
+
+	// the oldest secret makes room for the new one, the current secret stays
+	// active during the overlap
+	require.NoError(t, rotateAppOAuthClientSecrets(ctx, d, config))
+	secrets, err := listAppOAuthClientSecrets(ctx, config, appID)
+	require.NoError(t, err)
+	require.Len(t, secrets, 2)
+	sortAppOAuthClientSecrets(secrets)
+	newest := secrets[0]
+	require.Equal(t, current.ID, secrets[1].ID)
+	require.Equal(t, []string{newest.ID, current.ID}, convertInterfaceToStringArr(d.Get("active_client_secret_ids")))
+	require.Equal(t, newest.ClientSecret, d.Get("client_secret"))
+
+	// the current secret is deactivated once the overlap, since the new secret
+	// was created two hours ago, is over
+	d = resourceAppOAuth().TestResourceData()
+	d.SetId(appID)
+	_ = d.Set("rotation", []interface{}{map[string]interface{}{"keep": 2, "overlap": "1h"}})
+	require.NoError(t, rotateAppOAuthClientSecrets(ctx, d, config))
+	require.Equal(t, []string{newest.ID}, convertInterfaceToStringArr(d.Get("active_client_secret_ids")))
+	secrets, err = listAppOAuthClientSecrets(ctx, config, appID)
+	require.NoError(t, err)
+	require.Len(t, secrets, 2)
+	sortAppOAuthClientSecrets(secrets)
+	require.Equal(t, statusInactive, secrets[1].Status)
+}
+
+func TestRotateAppOAuthClientSecretsKeepOne(t *testing.T) {
+	config, appID, current := newFakeOktaOAuthApp(t)
+	ctx := context.TODO()
+
+	d := schema.TestResourceDataRaw(t, resourceAppOAuth().Schema, map[string]interface{}{
+		"rotation": []interface{}{map[string]interface{}{"trigger": "v2", "keep": 1}},
+	})
+	d.SetId(appID)
+
+	// the new secret is created before the current one, the only active
+	// secret, is deleted
+	require.NoError(t, rotateAppOAuthClientSecrets(ctx, d, config))
+	secrets, err := listAppOAuthClientSecrets(ctx, config, appID)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	require.NotEqual(t, current.ID, secrets[0].ID)
+	require.Equal(t, statusActive, secrets[0].Status)
+	require.Equal(t, []string{secrets[0].ID}, convertInterfaceToStringArr(d.Get("active_client_secret_ids")))
+	require.Equal(t, secrets[0].ClientSecret, d.Get("client_secret"))
+}
+
+func TestAppOAuthRotationOverlapOver(t *testing.T) {
+	now := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	require.True(t, appOAuthRotationOverlapOver("2024-03-01T12:00:00Z", 24*time.Hour, now))
+	require.False(t, appOAuthRotationOverlapOver("2024-03-01T12:00:01Z", 24*time.Hour, now))
+	require.True(t, appOAuthRotationOverlapOver("", time.Hour, now))
+}
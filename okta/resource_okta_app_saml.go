@@ -400,9 +400,36 @@ func resourceAppSaml() *schema.Resource {
 			Read:   schema.DefaultTimeout(1 * time.Hour),
 			Update: schema.DefaultTimeout(1 * time.Hour),
 		},
+		CustomizeDiff: resourceAppSamlCustomizeDiff,
 	}
 }
 
+// resourceAppSamlCustomizeDiff checks the expressions of EXPRESSION attribute
+// statements, appuser properties are checked once the app exists.
+func resourceAppSamlCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("attribute_statements") {
+		return nil
+	}
+	statements, _ := d.Get("attribute_statements").([]interface{})
+	for i := range statements {
+		if !d.NewValueKnown(fmt.Sprintf("attribute_statements.%d.type", i)) ||
+			d.Get(fmt.Sprintf("attribute_statements.%d.type", i)).(string) != "EXPRESSION" {
+			continue
+		}
+		values, _ := d.Get(fmt.Sprintf("attribute_statements.%d.values", i)).([]interface{})
+		for j := range values {
+			k := fmt.Sprintf("attribute_statements.%d.values.%d", i, j)
+			if !d.NewValueKnown(k) {
+				continue
+			}
+			if err := checkExpression(ctx, m, k, d.Get(k).(string), d.Id()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func resourceAppSamlCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	err := validateAppSaml(d)
 	if err != nil {
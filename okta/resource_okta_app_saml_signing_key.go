@@ -0,0 +1,357 @@
+package okta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+func resourceAppSamlSigningKey() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppSamlSigningKeyCreate,
+		ReadContext:   resourceAppSamlSigningKeyRead,
+		UpdateContext: resourceAppSamlSigningKeyUpdate,
+		DeleteContext: resourceAppSamlSigningKeyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				parts := strings.Split(d.Id(), "/")
+				if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+					return nil, errors.New("invalid SAML signing key import ID, expected format <app_id>/<kid>")
+				}
+				_ = d.Set("app_id", parts[0])
+				_ = d.Set("kid", parts[1])
+				d.SetId(parts[1])
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		CustomizeDiff: appSamlSigningKeyDiff,
+		Schema: map[string]*schema.Schema{
+			"app_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the SAML application.",
+			},
+			"years_valid": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: intBetween(2, 10),
+				ExactlyOneOf:     []string{"years_valid", "csr"},
+				Description:      "Number of years the key generated by Okta is valid, from 2 to 10.",
+			},
+			"csr": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Certificate signing request for a key whose certificate is signed by a certificate authority.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"common_name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+							Description: "Common name of the subject.",
+						},
+						"country_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Description: "Country name of the subject.",
+						},
+						"locality_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Description: "Locality name of the subject.",
+						},
+						"organization_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Description: "Organization name of the subject.",
+						},
+						"organizational_unit_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Description: "Organizational unit name of the subject.",
+						},
+						"state_or_province_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Description: "State or province name of the subject.",
+						},
+						"dns_names": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							ForceNew:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "DNS subject alternative names.",
+						},
+					},
+				},
+			},
+			"certificate": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"csr"},
+				Description:  "PEM encoded certificate signed by the certificate authority for `csr_pem`. The certificate is published once set, the key is staged until it is activated.",
+			},
+			"active": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the key is the signing key of the application. Activating the key switches the `kid` of the application, a key is deactivated by activating another key.",
+				// The key stays the signing key until another key is activated.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return new == "false"
+				},
+			},
+			"kid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Key ID, known once the key is generated or its certificate is published.",
+			},
+			"csr_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the certificate signing request.",
+			},
+			"csr_pem": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "PEM encoded certificate signing request to sign by the certificate authority.",
+			},
+			"x509_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Base64 encoded X.509 certificate of the key.",
+			},
+			"x5t_s256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 thumbprint of the certificate of the key.",
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Expiration timestamp of the certificate of the key.",
+			},
+			"metadata": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SAML XML metadata of the application signed with the key, to publish to the service provider.",
+			},
+			"metadata_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL of the SAML XML metadata of the application signed with the key.",
+			},
+		},
+	}
+}
+
+// appSamlSigningKeyDiff replaces a CSR based key when the certificate changes
+// after its publication, and rejects the activation of a key whose
+// certificate isn't published.
+func appSamlSigningKeyDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if _, ok := d.GetOk("csr"); !ok {
+		return nil
+	}
+	if d.Id() != "" && d.HasChange("certificate") {
+		if d.Get("kid").(string) != "" {
+			if err := d.ForceNew("certificate"); err != nil {
+				return err
+			}
+		} else {
+			for _, k := range []string{"kid", "x509_certificate", "x5t_s256", "expires_at", "metadata", "metadata_url"} {
+				if err := d.SetNewComputed(k); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if d.Get("active").(bool) && d.NewValueKnown("certificate") && d.Get("certificate").(string) == "" {
+		return errors.New("a key based on a CSR can only be activated once its 'certificate' is set")
+	}
+	return nil
+}
+
+func resourceAppSamlSigningKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getOktaClientFromMetadata(m)
+	appID := d.Get("app_id").(string)
+	if _, ok := d.GetOk("csr"); ok {
+		csr, _, err := client.Application.GenerateCsrForApplication(ctx, appID, buildCsrMetadata(d))
+		if err != nil {
+			return diag.Errorf("failed to generate CSR for SAML application: %v", err)
+		}
+		d.SetId(csr.Id)
+		_ = d.Set("csr_id", csr.Id)
+		_ = d.Set("csr_pem", csrPEM(csr.Csr))
+	} else {
+		years := int64(d.Get("years_valid").(int))
+		key, _, err := client.Application.GenerateApplicationKey(ctx, appID, &query.Params{ValidityYears: years})
+		if err != nil {
+			return diag.Errorf("failed to generate key for SAML application: %v", err)
+		}
+		d.SetId(key.Kid)
+		_ = d.Set("kid", key.Kid)
+	}
+	if err := publishAndActivateAppSamlSigningKey(ctx, d, m); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceAppSamlSigningKeyRead(ctx, d, m)
+}
+
+func resourceAppSamlSigningKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getOktaClientFromMetadata(m)
+	appID := d.Get("app_id").(string)
+	app := sdk.NewSamlApplication()
+	if err := fetchAppByID(ctx, appID, m, app); err != nil {
+		return diag.Errorf("failed to get SAML application: %v", err)
+	}
+	if app.Id == "" {
+		d.SetId("")
+		return nil
+	}
+	kid := d.Get("kid").(string)
+	if kid == "" {
+		csr, resp, err := client.Application.GetCsrForApplication(ctx, appID, d.Get("csr_id").(string))
+		if err := suppressErrorOn404(resp, err); err != nil {
+			return diag.Errorf("failed to get CSR for SAML application: %v", err)
+		}
+		if csr == nil || csr.Id == "" {
+			d.SetId("")
+			return nil
+		}
+		_ = d.Set("csr_pem", csrPEM(csr.Csr))
+		_ = d.Set("active", false)
+		return nil
+	}
+	key, resp, err := client.Application.GetApplicationKey(ctx, appID, kid)
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to get key for SAML application: %v", err)
+	}
+	if key == nil || key.Kid == "" {
+		d.SetId("")
+		return nil
+	}
+	if len(key.X5c) > 0 {
+		_ = d.Set("x509_certificate", key.X5c[0])
+	}
+	_ = d.Set("x5t_s256", key.X5tS256)
+	if key.ExpiresAt != nil {
+		_ = d.Set("expires_at", key.ExpiresAt.Format(time.RFC3339))
+	}
+	metadata, _, err := getAPISupplementFromMetadata(m).GetSAMLMetadata(ctx, appID, kid)
+	if err != nil {
+		return diag.Errorf("failed to get SAML metadata for key: %v", err)
+	}
+	_ = d.Set("metadata", string(metadata))
+	if href := linksValue(app.Links, "metadata", "href"); href != "" {
+		_ = d.Set("metadata_url", fmt.Sprintf("%s?kid=%s", href, kid))
+	}
+	_ = d.Set("active", app.Credentials != nil && app.Credentials.Signing != nil && app.Credentials.Signing.Kid == kid)
+	return nil
+}
+
+func resourceAppSamlSigningKeyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := publishAndActivateAppSamlSigningKey(ctx, d, m); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceAppSamlSigningKeyRead(ctx, d, m)
+}
+
+// resourceAppSamlSigningKeyDelete revokes the CSR of a key whose certificate
+// isn't published, the API doesn't allow to delete the keys of an
+// application so published keys are only removed from the state.
+func resourceAppSamlSigningKeyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.Get("kid").(string) != "" {
+		return nil
+	}
+	resp, err := getOktaClientFromMetadata(m).Application.RevokeCsrFromApplication(ctx, d.Get("app_id").(string), d.Get("csr_id").(string))
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to revoke CSR for SAML application: %v", err)
+	}
+	return nil
+}
+
+// publishAndActivateAppSamlSigningKey publishes the certificate of a CSR based
+// key once it is set, then switches the signing key of the application when
+// the key is active.
+func publishAndActivateAppSamlSigningKey(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	client := getOktaClientFromMetadata(m)
+	appID := d.Get("app_id").(string)
+	kid := d.Get("kid").(string)
+	if cert := d.Get("certificate").(string); kid == "" && cert != "" {
+		key, _, err := client.Application.PublishBinaryPemCert(ctx, appID, d.Get("csr_id").(string), cert)
+		if err != nil {
+			return fmt.Errorf("failed to publish certificate for SAML application: %v", err)
+		}
+		kid = key.Kid
+		d.SetId(kid)
+		_ = d.Set("kid", kid)
+	}
+	if !d.Get("active").(bool) || kid == "" {
+		return nil
+	}
+	app := sdk.NewSamlApplication()
+	if err := fetchAppByID(ctx, appID, m, app); err != nil {
+		return fmt.Errorf("failed to get SAML application: %v", err)
+	}
+	if app.Id == "" {
+		return fmt.Errorf("application with id %s does not exist", appID)
+	}
+	if app.Credentials.Signing != nil && app.Credentials.Signing.Kid == kid {
+		return nil
+	}
+	app.Credentials.Signing = &sdk.ApplicationCredentialsSigning{Kid: kid}
+	if _, _, err := client.Application.UpdateApplication(ctx, appID, app); err != nil {
+		return fmt.Errorf("failed to activate signing key of SAML application: %v", err)
+	}
+	return nil
+}
+
+func buildCsrMetadata(d *schema.ResourceData) sdk.CsrMetadata {
+	csr := d.Get("csr").([]interface{})[0].(map[string]interface{})
+	metadata := sdk.CsrMetadata{
+		Subject: &sdk.CsrMetadataSubject{
+			CommonName:             csr["common_name"].(string),
+			CountryName:            csr["country_name"].(string),
+			LocalityName:           csr["locality_name"].(string),
+			OrganizationName:       csr["organization_name"].(string),
+			OrganizationalUnitName: csr["organizational_unit_name"].(string),
+			StateOrProvinceName:    csr["state_or_province_name"].(string),
+		},
+	}
+	if dnsNames := convertInterfaceToStringArr(csr["dns_names"]); len(dnsNames) > 0 {
+		metadata.SubjectAltNames = &sdk.CsrMetadataSubjectAltNames{DnsNames: dnsNames}
+	}
+	return metadata
+}
+
+// csrPEM wraps the base64 encoded DER certificate signing request returned by
+// the API in a PEM block.
+func csrPEM(csr string) string {
+	if csr == "" || strings.HasPrefix(csr, "-----BEGIN") {
+		return csr
+	}
+	var b strings.Builder
+	b.WriteString("-----BEGIN CERTIFICATE REQUEST-----\n")
+	for len(csr) > 64 {
+		b.WriteString(csr[:64] + "\n")
+		csr = csr[64:]
+	}
+	b.WriteString(csr + "\n-----END CERTIFICATE REQUEST-----\n")
+	return b.String()
+}
@@ -0,0 +1,134 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccResourceOktaAppSamlSigningKey_generated(t *testing.T) {
+	mgr := newFixtureManager("resources", appSamlSigningKey, t.Name())
+	resourceName := fmt.Sprintf("%s.test", appSamlSigningKey)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: mgr.GetFixtures("basic.tf", t),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "active", "false"),
+					resource.TestCheckResourceAttrSet(resourceName, "kid"),
+					resource.TestCheckResourceAttrSet(resourceName, "x509_certificate"),
+					resource.TestCheckResourceAttrSet(resourceName, "metadata"),
+					resource.TestCheckResourceAttrSet(resourceName, "metadata_url"),
+				),
+			},
+			{
+				Config: mgr.GetFixtures("basic_updated.tf", t),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "active", "true"),
+					resource.TestCheckResourceAttrPair(resourceName, "kid", "data.okta_app_saml.test", "key_id"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"years_valid"},
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					r, ok := s.RootModule().Resources[resourceName]
+					if !ok {
+						return "", fmt.Errorf("Unable to find resource: %s", resourceName)
+					}
+					return fmt.Sprintf("%s/%s", r.Primary.Attributes["app_id"], r.Primary.Attributes["kid"]), nil
+				},
+			},
+		},
+	})
+}
+
+// newFakeOktaSamlApp returns the configuration of the provider calling a fake
+// org and a SAML app of the org.
+func newFakeOktaSamlApp(t *testing.T) (*Config, *sdk.SamlApplication) {
+	t.Helper()
+	_, config := newFakeOktaConfig(t)
+	app := sdk.NewSamlApplication()
+	app.Label = "My SAML App"
+	_, _, err := config.oktaSDKClientV2.Application.CreateApplication(context.TODO(), app, nil)
+	require.NoError(t, err)
+	return config, app
+}
+
+func TestAppSamlSigningKeyCSR(t *testing.T) {
+	config, app := newFakeOktaSamlApp(t)
+	ctx := context.TODO()
+
+	r := resourceAppSamlSigningKey()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"app_id": app.Id,
+		"csr":    []interface{}{map[string]interface{}{"common_name": "sso.example.com"}},
+	})
+
+	// the CSR is staged until its certificate is signed
+	diags := r.CreateContext(ctx, d, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	csrID := d.Id()
+	require.Equal(t, csrID, d.Get("csr_id"))
+	require.Equal(t, "", d.Get("kid"))
+	require.True(t, strings.HasPrefix(d.Get("csr_pem").(string), "-----BEGIN CERTIFICATE REQUEST-----\n"))
+
+	// the signed certificate is published, the key is staged
+	_ = d.Set("certificate", "-----BEGIN CERTIFICATE-----\nMIIC\n-----END CERTIFICATE-----\n")
+	diags = r.UpdateContext(ctx, d, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	kid := d.Id()
+	require.NotEqual(t, csrID, kid)
+	require.Equal(t, kid, d.Get("kid"))
+	require.Equal(t, "MIIC", d.Get("x509_certificate"))
+	require.Equal(t, linksValue(app.Links, "metadata", "href")+"?kid="+kid, d.Get("metadata_url"))
+	require.Contains(t, d.Get("metadata"), "MIIC")
+	require.False(t, d.Get("active").(bool))
+	require.Equal(t, app.Credentials.Signing.Kid, getSamlAppSigningKid(t, config, app.Id))
+
+	// the activation switches the kid of the application
+	_ = d.Set("active", true)
+	diags = r.UpdateContext(ctx, d, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, kid, getSamlAppSigningKid(t, config, app.Id))
+	require.True(t, d.Get("active").(bool))
+}
+
+func getSamlAppSigningKid(t *testing.T, config *Config, appID string) string {
+	t.Helper()
+	app := sdk.NewSamlApplication()
+	require.NoError(t, fetchAppByID(context.TODO(), appID, config, app))
+	return app.Credentials.Signing.Kid
+}
+
+func TestAppSamlSigningKeyRevokeCSR(t *testing.T) {
+	config, app := newFakeOktaSamlApp(t)
+	ctx := context.TODO()
+	csr, _, err := config.oktaSDKClientV2.Application.GenerateCsrForApplication(ctx, app.Id, sdk.CsrMetadata{Subject: &sdk.CsrMetadataSubject{CommonName: "sso.example.com"}})
+	require.NoError(t, err)
+
+	r := resourceAppSamlSigningKey()
+	d := r.TestResourceData()
+	d.SetId(csr.Id)
+	_ = d.Set("app_id", app.Id)
+	_ = d.Set("csr_id", csr.Id)
+	diags := r.DeleteContext(ctx, d, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	_, resp, err := config.oktaSDKClientV2.Application.GetCsrForApplication(ctx, app.Id, csr.Id)
+	require.Error(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
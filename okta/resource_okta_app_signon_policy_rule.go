@@ -3,6 +3,8 @@ package okta
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -17,191 +19,235 @@ func resourceAppSignOnPolicyRule() *schema.Resource {
 		UpdateContext: resourceAppSignOnPolicyRuleUpdate,
 		DeleteContext: resourceAppSignOnPolicyRuleDelete,
 		Importer:      createPolicyRuleImporter(),
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Policy Rule Name",
-			},
-			"policy_id": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "ID of the policy",
-			},
-			"system": {
-				Type:        schema.TypeBool,
-				Computed:    true,
-				Description: `Often the "Catch-all Rule" this rule is the system (default) rule for its associated policy`,
-			},
-			"status": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Status of the rule",
-				Default:     statusActive,
-			},
-			"priority": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-					p, n := d.GetChange("priority")
-					return p == n && new == "0"
+		Schema: buildSchema(
+			appSignOnPolicyRuleSchema,
+			map[string]*schema.Schema{
+				"constraint": {
+					Type:          schema.TypeList,
+					Optional:      true,
+					ConflictsWith: []string{"constraints"},
+					Description:   "List of authenticator constraints organized by the authenticator class, the user must satisfy one of them",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"knowledge": {
+								Type:        schema.TypeList,
+								Optional:    true,
+								MaxItems:    1,
+								Description: "Constraint of the knowledge factor",
+								Elem:        appSignOnPolicyKnowledgeConstraintResource,
+							},
+							"possession": {
+								Type:        schema.TypeList,
+								Optional:    true,
+								MaxItems:    1,
+								Description: "Constraint of the possession factor",
+								Elem:        appSignOnPolicyPossessionConstraintResource,
+							},
+						},
+					},
 				},
-				Description: "Priority of the rule.",
-			},
-			"groups_included": {
-				Type:        schema.TypeSet,
-				Optional:    true,
-				Description: "List of group IDs to include",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
-			"groups_excluded": {
-				Type:        schema.TypeSet,
-				Optional:    true,
-				Description: "List of group IDs to exclude",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
-			"users_excluded": {
-				Type:        schema.TypeSet,
-				Optional:    true,
-				Description: "Set of User IDs to exclude",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
-			"users_included": {
-				Type:        schema.TypeSet,
-				Optional:    true,
-				Description: "Set of User IDs to include",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
-			"network_connection": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Network selection mode: ANYWHERE, ZONE, ON_NETWORK, or OFF_NETWORK.",
-				Default:     "ANYWHERE",
-			},
-			"network_includes": {
-				Type:          schema.TypeList,
-				Optional:      true,
-				Description:   "The zones to include",
-				ConflictsWith: []string{"network_excludes"},
-				Elem:          &schema.Schema{Type: schema.TypeString},
-			},
-			"network_excludes": {
-				Type:          schema.TypeList,
-				Optional:      true,
-				Description:   "The zones to exclude",
-				ConflictsWith: []string{"network_includes"},
-				Elem:          &schema.Schema{Type: schema.TypeString},
-			},
-			"device_is_registered": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Description: "If the device is registered. A device is registered if the User enrolls with Okta Verify that is installed on the device.",
-				ValidateDiagFunc: func(i interface{}, path cty.Path) diag.Diagnostics {
-					// Note: Keep this validator as it is enforcing payload
-					// format the API is expecting and the side effects related
-					// to that.
-					if i == nil {
-						return nil
-					}
-					v := i.(bool)
-					if !v {
-						return diag.Errorf("'device_is_registered' can either be set to 'true' or should not be present in the configuration")
-					}
-					return nil
-				},
-			},
-			"device_is_managed": {
-				Type:         schema.TypeBool,
-				Optional:     true,
-				RequiredWith: []string{"device_is_registered"},
-				Description:  "If the device is managed. A device is managed if it's managed by a device management system. When managed is passed, registered must also be included and must be set to true.",
-			},
-			"device_assurances_included": {
-				Type:        schema.TypeSet,
-				Optional:    true,
-				Description: "List of device assurance IDs to include",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
-			"platform_include": {
-				Type:     schema.TypeSet,
-				Elem:     platformIncludeResource,
-				Optional: true,
-			},
-			"custom_expression": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "This is an optional advanced setting. If the expression is formatted incorrectly or conflicts with conditions set above, the rule may not match any users.",
-			},
-			"user_types_excluded": {
-				Type:        schema.TypeSet,
-				Optional:    true,
-				Description: "Set of User Type IDs to exclude",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
-			"user_types_included": {
-				Type:        schema.TypeSet,
-				Optional:    true,
-				Description: "Set of User Type IDs to include",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
-			"access": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Allow or deny access based on the rule conditions: ALLOW or DENY",
-				Default:     "ALLOW",
-			},
-			"factor_mode": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The number of factors required to satisfy this assurance level",
-				Default:     "2FA",
-			},
-			"type": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The Verification Method type",
-				Default:     "ASSURANCE",
-			},
-			"re_authentication_frequency": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The duration after which the end user must re-authenticate, regardless of user activity. Use the ISO 8601 Period format for recurring time intervals. PT0S - Every sign-in attempt, PT43800H - Once per session",
-				Default:     "PT2H",
-			},
-			"inactivity_period": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The inactivity duration after which the end user must re-authenticate. Use the ISO 8601 Period format for recurring time intervals.",
-				Default:     "PT1H",
-			},
-			"constraints": {
-				Type: schema.TypeList,
-				Elem: &schema.Schema{
-					Type:             schema.TypeString,
-					ValidateDiagFunc: stringIsJSON,
-					StateFunc:        normalizeDataJSON,
-					DiffSuppressFunc: noChangeInObjectFromUnmarshaledJSON,
+				"constraints": {
+					Type: schema.TypeList,
+					Elem: &schema.Schema{
+						Type:             schema.TypeString,
+						ValidateDiagFunc: stringIsJSON,
+						StateFunc:        normalizeDataJSON,
+						DiffSuppressFunc: noChangeInObjectFromUnmarshaledJSON,
+					},
+					Optional:      true,
+					ConflictsWith: []string{"constraint"},
+					Deprecated:    "Use the `constraint` blocks instead",
+					Description:   "An array that contains nested Authenticator Constraint objects that are organized by the Authenticator class",
 				},
-				Optional:    true,
-				Description: "An array that contains nested Authenticator Constraint objects that are organized by the Authenticator class",
-			},
-			"risk_score": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				Description: "The risk score specifies a particular level of risk to match on: ANY, LOW, MEDIUM, HIGH",
 			},
+		),
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+			// the deprecated JSON constraints are left to the API like before
+			// the constraint blocks, so configurations it accepts still plan
+			if legacy, _ := d.Get("constraints").([]interface{}); len(legacy) > 0 {
+				return nil
+			}
+			return validateAppSignOnPolicyConstraints(buildAppSignOnPolicyConstraints(d.Get("constraint")))
 		},
 	}
 }
 
+var appSignOnPolicyRuleSchema = map[string]*schema.Schema{
+	"name": {
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "Policy Rule Name",
+	},
+	"policy_id": {
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "ID of the policy",
+	},
+	"system": {
+		Type:        schema.TypeBool,
+		Computed:    true,
+		Description: `Often the "Catch-all Rule" this rule is the system (default) rule for its associated policy`,
+	},
+	"status": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Status of the rule",
+		Default:     statusActive,
+	},
+	"priority": {
+		Type:     schema.TypeInt,
+		Optional: true,
+		DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+			p, n := d.GetChange("priority")
+			return p == n && new == "0"
+		},
+		Description: "Priority of the rule.",
+	},
+	"groups_included": {
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Description: "List of group IDs to include",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	},
+	"groups_excluded": {
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Description: "List of group IDs to exclude",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	},
+	"users_excluded": {
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Description: "Set of User IDs to exclude",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	},
+	"users_included": {
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Description: "Set of User IDs to include",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	},
+	"network_connection": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Network selection mode: ANYWHERE, ZONE, ON_NETWORK, or OFF_NETWORK.",
+		Default:     "ANYWHERE",
+	},
+	"network_includes": {
+		Type:          schema.TypeList,
+		Optional:      true,
+		Description:   "The zones to include",
+		ConflictsWith: []string{"network_excludes"},
+		Elem:          &schema.Schema{Type: schema.TypeString},
+	},
+	"network_excludes": {
+		Type:          schema.TypeList,
+		Optional:      true,
+		Description:   "The zones to exclude",
+		ConflictsWith: []string{"network_includes"},
+		Elem:          &schema.Schema{Type: schema.TypeString},
+	},
+	"device_is_registered": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "If the device is registered. A device is registered if the User enrolls with Okta Verify that is installed on the device.",
+		ValidateDiagFunc: func(i interface{}, path cty.Path) diag.Diagnostics {
+			// Note: Keep this validator as it is enforcing payload
+			// format the API is expecting and the side effects related
+			// to that.
+			if i == nil {
+				return nil
+			}
+			v := i.(bool)
+			if !v {
+				return diag.Errorf("'device_is_registered' can either be set to 'true' or should not be present in the configuration")
+			}
+			return nil
+		},
+	},
+	"device_is_managed": {
+		Type:         schema.TypeBool,
+		Optional:     true,
+		RequiredWith: []string{"device_is_registered"},
+		Description:  "If the device is managed. A device is managed if it's managed by a device management system. When managed is passed, registered must also be included and must be set to true.",
+	},
+	"device_assurances_included": {
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Description: "List of device assurance IDs to include",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	},
+	"platform_include": {
+		Type:     schema.TypeSet,
+		Elem:     platformIncludeResource,
+		Optional: true,
+	},
+	"custom_expression": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "This is an optional advanced setting. If the expression is formatted incorrectly or conflicts with conditions set above, the rule may not match any users.",
+	},
+	"user_types_excluded": {
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Description: "Set of User Type IDs to exclude",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	},
+	"user_types_included": {
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Description: "Set of User Type IDs to include",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	},
+	"access": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Allow or deny access based on the rule conditions: ALLOW or DENY",
+		Default:     "ALLOW",
+	},
+	"factor_mode": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "The number of factors required to satisfy this assurance level",
+		Default:     "2FA",
+	},
+	"type": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "The Verification Method type",
+		Default:     "ASSURANCE",
+	},
+	"re_authentication_frequency": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "The duration after which the end user must re-authenticate, regardless of user activity. Use the ISO 8601 Period format for recurring time intervals. PT0S - Every sign-in attempt, PT43800H - Once per session",
+		Default:     "PT2H",
+	},
+	"inactivity_period": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "The inactivity duration after which the end user must re-authenticate. Use the ISO 8601 Period format for recurring time intervals.",
+		Default:     "PT1H",
+	},
+	"risk_score": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+		Description: "The risk score specifies a particular level of risk to match on: ANY, LOW, MEDIUM, HIGH",
+	},
+}
+
 func resourceAppSignOnPolicyRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	if isClassicOrg(ctx, m) {
 		return resourceOIEOnlyFeatureError(appSignOnPolicyRule)
 	}
 
-	rule, _, err := getAPISupplementFromMetadata(m).CreateAppSignOnPolicyRule(ctx, d.Get("policy_id").(string), buildAppSignOnPolicyRule(d))
+	body, err := buildAppSignOnPolicyRule(d)
+	if err != nil {
+		return diag.Errorf("failed to create app sign on policy rule: %v", err)
+	}
+	rule, _, err := createAppSignOnPolicyRule(ctx, m, d.Get("policy_id").(string), body)
 	if err != nil {
 		return diag.Errorf("failed to create app sign on policy rule: %v", err)
 	}
@@ -222,7 +268,7 @@ func resourceAppSignOnPolicyRuleRead(ctx context.Context, d *schema.ResourceData
 		return resourceOIEOnlyFeatureError(appSignOnPolicyRule)
 	}
 
-	rule, resp, err := getAPISupplementFromMetadata(m).GetAppSignOnPolicyRule(ctx, d.Get("policy_id").(string), d.Id())
+	rule, resp, err := getAppSignOnPolicyRule(ctx, m, d.Get("policy_id").(string), d.Id())
 	if err := suppressErrorOn404(resp, err); err != nil {
 		return diag.Errorf("failed to get app sign on policy rule: %v", err)
 	}
@@ -243,12 +289,16 @@ func resourceAppSignOnPolicyRuleRead(ctx context.Context, d *schema.ResourceData
 			_ = d.Set("factor_mode", rule.Actions.AppSignOn.VerificationMethod.FactorMode)
 			_ = d.Set("re_authentication_frequency", rule.Actions.AppSignOn.VerificationMethod.ReauthenticateIn)
 			_ = d.Set("inactivity_period", rule.Actions.AppSignOn.VerificationMethod.InactivityPeriod)
-			arr := make([]interface{}, len(rule.Actions.AppSignOn.VerificationMethod.Constraints))
-			for i := range rule.Actions.AppSignOn.VerificationMethod.Constraints {
-				b, _ := json.Marshal(rule.Actions.AppSignOn.VerificationMethod.Constraints[i])
-				arr[i] = string(b)
+			if _, ok := d.GetOk("constraints"); ok {
+				arr := make([]interface{}, len(rule.Constraints))
+				for i := range rule.Constraints {
+					b, _ := json.Marshal(rule.Constraints[i])
+					arr[i] = string(b)
+				}
+				_ = d.Set("constraints", arr)
+			} else {
+				_ = d.Set("constraint", flattenAppSignOnPolicyConstraints(rule.Constraints))
 			}
-			_ = d.Set("constraints", arr)
 		}
 	}
 	if rule.Conditions != nil {
@@ -299,12 +349,15 @@ func resourceAppSignOnPolicyRuleUpdate(ctx context.Context, d *schema.ResourceDa
 		return resourceOIEOnlyFeatureError(appSignOnPolicyRule)
 	}
 
-	rule := buildAppSignOnPolicyRule(d)
+	rule, err := buildAppSignOnPolicyRule(d)
+	if err != nil {
+		return diag.Errorf("failed to update app sign on policy rule: %v", err)
+	}
 	if boolFromBoolPtr(rule.System) {
 		// Conditions can't be set on the default/system rule
 		rule.Conditions = nil
 	}
-	_, _, err := getAPISupplementFromMetadata(m).UpdateAppSignOnPolicyRule(ctx, d.Get("policy_id").(string), d.Id(), rule)
+	_, _, err = updateAppSignOnPolicyRule(ctx, m, d.Get("policy_id").(string), d.Id(), rule)
 	if err != nil {
 		return diag.Errorf("failed to create app sign on policy rule: %v", err)
 	}
@@ -338,7 +391,7 @@ func resourceAppSignOnPolicyRuleDelete(ctx context.Context, d *schema.ResourceDa
 	return nil
 }
 
-func buildAppSignOnPolicyRule(d *schema.ResourceData) sdk.AccessPolicyRule {
+func buildAppSignOnPolicyRule(d *schema.ResourceData) (*appSignOnPolicyRuleBody, error) {
 	rule := sdk.AccessPolicyRule{
 		Actions: &sdk.AccessPolicyRuleActions{
 			AppSignOn: &sdk.AccessPolicyRuleApplicationSignOn{
@@ -365,17 +418,6 @@ func buildAppSignOnPolicyRule(d *schema.ResourceData) sdk.AccessPolicyRule {
 		rule.System = boolPtr(v.(bool))
 	}
 
-	var constraints []*sdk.AccessPolicyConstraints
-	v, ok := d.GetOk("constraints")
-	if ok {
-		valueList := v.([]interface{})
-		for _, item := range valueList {
-			var constraint sdk.AccessPolicyConstraints
-			_ = json.Unmarshal([]byte(item.(string)), &constraint)
-			constraints = append(constraints, &constraint)
-		}
-	}
-	rule.Actions.AppSignOn.VerificationMethod.Constraints = constraints
 	rule.Conditions = &sdk.AccessPolicyRuleConditions{
 		Network: buildPolicyNetworkCondition(d),
 		Platform: &sdk.PlatformPolicyRuleCondition{
@@ -442,7 +484,14 @@ func buildAppSignOnPolicyRule(d *schema.ResourceData) sdk.AccessPolicyRule {
 			Include: convertInterfaceToStringSetNullable(userTypesIncluded),
 		}
 	}
-	return rule
+	constraints, err := appSignOnPolicyRuleConstraints(d.Get("constraint"), d.Get("constraints"))
+	if err != nil {
+		return nil, err
+	}
+	return &appSignOnPolicyRuleBody{
+		AccessPolicyRule: rule,
+		Constraints:      constraints,
+	}, nil
 }
 
 func buildAccessPolicyPlatformInclude(d *schema.ResourceData) []*sdk.PlatformConditionEvaluatorPlatform {
@@ -489,3 +538,375 @@ func flattenAccessPolicyPlatformInclude(platform *sdk.PlatformPolicyRuleConditio
 	}
 	return schema.NewSet(schema.HashResource(platformIncludeResource), flattened)
 }
+
+var (
+	appSignOnPolicyKnowledgeTypes   = []string{"password", "security_question"}
+	appSignOnPolicyKnowledgeMethods = []string{"password", "security_question"}
+	appSignOnPolicyPossessionTypes  = []string{"app", "email", "federated", "phone", "security_key"}
+	// The methods are the ones of the authenticators with possession factor
+	// verification.
+	appSignOnPolicyPossessionMethods = []string{"cert", "duo", "email", "idp", "otp", "push", "signed_nonce", "sms", "totp", "voice", "webauthn"}
+
+	// Possession factors that don't prove the device nor protect against
+	// phishing, they can't satisfy a constraint requiring it.
+	appSignOnPolicyUnboundPossessionTypes   = []string{"email", "phone"}
+	appSignOnPolicyUnboundPossessionMethods = []string{"email", "sms", "voice"}
+	appSignOnPolicyPhishablePossessionTypes = []string{"email", "phone"}
+	// Okta FastPass (signed_nonce), WebAuthn and smart cards are phishing
+	// resistant.
+	appSignOnPolicyPhishablePossessionMethods = []string{"duo", "email", "otp", "push", "sms", "totp", "voice"}
+
+	appSignOnPolicyExcludedAuthenticationMethodsSchema = &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Description: "Authentication methods that can't satisfy the constraint",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"key": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Key of the authenticator, e.g. okta_verify",
+				},
+				"method": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Method of the authenticator, all its methods are excluded when it's not set",
+				},
+			},
+		},
+	}
+
+	appSignOnPolicyKnowledgeConstraintResource = &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"types": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Authenticator types that satisfy the constraint: password or security_question",
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: stringInSlice(appSignOnPolicyKnowledgeTypes),
+				},
+			},
+			"methods": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Authenticator methods that satisfy the constraint: password or security_question",
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: stringInSlice(appSignOnPolicyKnowledgeMethods),
+				},
+			},
+			"reauthenticate_in": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The duration after which the user must re-authenticate with the factor, ISO 8601 duration, e.g. PT2H",
+			},
+			"excluded_authentication_methods": appSignOnPolicyExcludedAuthenticationMethodsSchema,
+		},
+	}
+
+	appSignOnPolicyPossessionConstraintResource = &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"types": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Authenticator types that satisfy the constraint: app, email, federated, phone or security_key",
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: stringInSlice(appSignOnPolicyPossessionTypes),
+				},
+			},
+			"methods": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Authenticator methods that satisfy the constraint, e.g. push, signed_nonce or webauthn",
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: stringInSlice(appSignOnPolicyPossessionMethods),
+				},
+			},
+			"reauthenticate_in": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The duration after which the user must re-authenticate with the factor, ISO 8601 duration, e.g. PT2H",
+			},
+			"device_bound": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Whether the authenticator must be bound to the device: REQUIRED or OPTIONAL",
+				ValidateDiagFunc: stringInSlice([]string{"REQUIRED", "OPTIONAL"}),
+			},
+			"hardware_protection": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Whether the authenticator's keys must be stored in hardware: REQUIRED or OPTIONAL",
+				ValidateDiagFunc: stringInSlice([]string{"REQUIRED", "OPTIONAL"}),
+			},
+			"phishing_resistant": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Whether the authenticator must be phishing resistant: REQUIRED or OPTIONAL",
+				ValidateDiagFunc: stringInSlice([]string{"REQUIRED", "OPTIONAL"}),
+			},
+			"user_presence": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Whether the user must prove their presence, e.g. with a touch: REQUIRED or OPTIONAL",
+				ValidateDiagFunc: stringInSlice([]string{"REQUIRED", "OPTIONAL"}),
+			},
+			"excluded_authentication_methods": appSignOnPolicyExcludedAuthenticationMethodsSchema,
+		},
+	}
+)
+
+// appSignOnPolicyRuleBody is an app sign-on policy rule with the authenticator
+// constraints of its verification method. The constraints of the local SDK
+// don't have the excluded authentication methods.
+type appSignOnPolicyRuleBody struct {
+	sdk.AccessPolicyRule
+	Constraints []*appSignOnPolicyConstraints
+}
+
+type appSignOnPolicyConstraints struct {
+	Knowledge  *appSignOnPolicyConstraint `json:"knowledge,omitempty"`
+	Possession *appSignOnPolicyConstraint `json:"possession,omitempty"`
+}
+
+type appSignOnPolicyConstraint struct {
+	Methods                       []string                               `json:"methods,omitempty"`
+	ReauthenticateIn              string                                 `json:"reauthenticateIn,omitempty"`
+	Types                         []string                               `json:"types,omitempty"`
+	DeviceBound                   string                                 `json:"deviceBound,omitempty"`
+	HardwareProtection            string                                 `json:"hardwareProtection,omitempty"`
+	PhishingResistant             string                                 `json:"phishingResistant,omitempty"`
+	UserPresence                  string                                 `json:"userPresence,omitempty"`
+	ExcludedAuthenticationMethods []*appSignOnPolicyAuthenticationMethod `json:"excludedAuthenticationMethods,omitempty"`
+}
+
+type appSignOnPolicyAuthenticationMethod struct {
+	Key    string `json:"key"`
+	Method string `json:"method,omitempty"`
+}
+
+func (r *appSignOnPolicyRuleBody) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(&r.AccessPolicyRule)
+	if err != nil {
+		return nil, err
+	}
+	var rule map[string]interface{}
+	if err := json.Unmarshal(b, &rule); err != nil {
+		return nil, err
+	}
+	if len(r.Constraints) == 0 {
+		return b, nil
+	}
+	actions, _ := rule["actions"].(map[string]interface{})
+	appSignOn, _ := actions["appSignOn"].(map[string]interface{})
+	verificationMethod, ok := appSignOn["verificationMethod"].(map[string]interface{})
+	if !ok {
+		return b, nil
+	}
+	verificationMethod["constraints"] = r.Constraints
+	return json.Marshal(rule)
+}
+
+func (r *appSignOnPolicyRuleBody) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &r.AccessPolicyRule); err != nil {
+		return err
+	}
+	var rule struct {
+		Actions struct {
+			AppSignOn struct {
+				VerificationMethod struct {
+					Constraints []*appSignOnPolicyConstraints `json:"constraints"`
+				} `json:"verificationMethod"`
+			} `json:"appSignOn"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return err
+	}
+	r.Constraints = rule.Actions.AppSignOn.VerificationMethod.Constraints
+	return nil
+}
+
+func createAppSignOnPolicyRule(ctx context.Context, m interface{}, policyID string, rule *appSignOnPolicyRuleBody) (*appSignOnPolicyRuleBody, *sdk.Response, error) {
+	return appSignOnPolicyRuleRequest(ctx, m, http.MethodPost, fmt.Sprintf("/api/v1/policies/%v/rules", policyID), rule)
+}
+
+func getAppSignOnPolicyRule(ctx context.Context, m interface{}, policyID, ruleID string) (*appSignOnPolicyRuleBody, *sdk.Response, error) {
+	return appSignOnPolicyRuleRequest(ctx, m, http.MethodGet, fmt.Sprintf("/api/v1/policies/%v/rules/%v", policyID, ruleID), nil)
+}
+
+func updateAppSignOnPolicyRule(ctx context.Context, m interface{}, policyID, ruleID string, rule *appSignOnPolicyRuleBody) (*appSignOnPolicyRuleBody, *sdk.Response, error) {
+	return appSignOnPolicyRuleRequest(ctx, m, http.MethodPut, fmt.Sprintf("/api/v1/policies/%v/rules/%v", policyID, ruleID), rule)
+}
+
+func appSignOnPolicyRuleRequest(ctx context.Context, m interface{}, method, url string, body *appSignOnPolicyRuleBody) (*appSignOnPolicyRuleBody, *sdk.Response, error) {
+	re := getRequestExecutor(m)
+	var reqBody interface{}
+	if body != nil {
+		reqBody = body
+	}
+	req, err := re.WithAccept("application/json").WithContentType("application/json").NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rule *appSignOnPolicyRuleBody
+	resp, err := re.Do(ctx, req, &rule)
+	if err != nil {
+		return nil, resp, err
+	}
+	return rule, resp, nil
+}
+
+// appSignOnPolicyRuleConstraints returns the constraints of the constraint
+// blocks or, when they are set instead, of the deprecated JSON constraints.
+func appSignOnPolicyRuleConstraints(blocks, legacy interface{}) ([]*appSignOnPolicyConstraints, error) {
+	items, _ := legacy.([]interface{})
+	if len(items) == 0 {
+		return buildAppSignOnPolicyConstraints(blocks), nil
+	}
+	constraints := make([]*appSignOnPolicyConstraints, len(items))
+	for i, item := range items {
+		s, _ := item.(string)
+		var constraint appSignOnPolicyConstraints
+		if err := json.Unmarshal([]byte(s), &constraint); err != nil {
+			return nil, fmt.Errorf("failed to parse constraint %q: %v", s, err)
+		}
+		constraints[i] = &constraint
+	}
+	return constraints, nil
+}
+
+func buildAppSignOnPolicyConstraints(raw interface{}) []*appSignOnPolicyConstraints {
+	var constraints []*appSignOnPolicyConstraints
+	items, _ := raw.([]interface{})
+	for _, item := range items {
+		value, ok := item.(map[string]interface{})
+		if !ok {
+			// an empty constraints block
+			constraints = append(constraints, &appSignOnPolicyConstraints{})
+			continue
+		}
+		constraints = append(constraints, &appSignOnPolicyConstraints{
+			Knowledge:  buildAppSignOnPolicyConstraint(value["knowledge"]),
+			Possession: buildAppSignOnPolicyConstraint(value["possession"]),
+		})
+	}
+	return constraints
+}
+
+func buildAppSignOnPolicyConstraint(raw interface{}) *appSignOnPolicyConstraint {
+	items, _ := raw.([]interface{})
+	if len(items) == 0 {
+		return nil
+	}
+	value, ok := items[0].(map[string]interface{})
+	if !ok {
+		// an empty knowledge or possession block
+		return &appSignOnPolicyConstraint{}
+	}
+	constraint := &appSignOnPolicyConstraint{
+		Methods:            convertInterfaceToStringSetNullable(value["methods"]),
+		ReauthenticateIn:   getMapString(value, "reauthenticate_in"),
+		Types:              convertInterfaceToStringSetNullable(value["types"]),
+		DeviceBound:        getMapString(value, "device_bound"),
+		HardwareProtection: getMapString(value, "hardware_protection"),
+		PhishingResistant:  getMapString(value, "phishing_resistant"),
+		UserPresence:       getMapString(value, "user_presence"),
+	}
+	if excluded, ok := value["excluded_authentication_methods"].(*schema.Set); ok {
+		for _, v := range excluded.List() {
+			method := v.(map[string]interface{})
+			constraint.ExcludedAuthenticationMethods = append(constraint.ExcludedAuthenticationMethods, &appSignOnPolicyAuthenticationMethod{
+				Key:    getMapString(method, "key"),
+				Method: getMapString(method, "method"),
+			})
+		}
+	}
+	return constraint
+}
+
+// flattenAppSignOnPolicyConstraints flattens the constraints into the values
+// of the constraints blocks, sets are flattened into lists so the values are
+// also valid in a raw state.
+func flattenAppSignOnPolicyConstraints(constraints []*appSignOnPolicyConstraints) []interface{} {
+	flattened := make([]interface{}, len(constraints))
+	for i, c := range constraints {
+		value := map[string]interface{}{}
+		if c.Knowledge != nil {
+			knowledge := flattenAppSignOnPolicyConstraint(c.Knowledge)
+			delete(knowledge, "device_bound")
+			delete(knowledge, "hardware_protection")
+			delete(knowledge, "phishing_resistant")
+			delete(knowledge, "user_presence")
+			value["knowledge"] = []interface{}{knowledge}
+		}
+		if c.Possession != nil {
+			value["possession"] = []interface{}{flattenAppSignOnPolicyConstraint(c.Possession)}
+		}
+		flattened[i] = value
+	}
+	return flattened
+}
+
+func flattenAppSignOnPolicyConstraint(c *appSignOnPolicyConstraint) map[string]interface{} {
+	excluded := make([]interface{}, len(c.ExcludedAuthenticationMethods))
+	for i, method := range c.ExcludedAuthenticationMethods {
+		excluded[i] = map[string]interface{}{
+			"key":    method.Key,
+			"method": method.Method,
+		}
+	}
+	return map[string]interface{}{
+		"methods":                         convertStringSliceToInterfaceSlice(c.Methods),
+		"reauthenticate_in":               c.ReauthenticateIn,
+		"types":                           convertStringSliceToInterfaceSlice(c.Types),
+		"device_bound":                    c.DeviceBound,
+		"hardware_protection":             c.HardwareProtection,
+		"phishing_resistant":              c.PhishingResistant,
+		"user_presence":                   c.UserPresence,
+		"excluded_authentication_methods": excluded,
+	}
+}
+
+// validateAppSignOnPolicyConstraints validates the combinations of the
+// constraints the API doesn't allow or that no authenticator can satisfy.
+func validateAppSignOnPolicyConstraints(constraints []*appSignOnPolicyConstraints) error {
+	for i, c := range constraints {
+		if c.Knowledge == nil && c.Possession == nil {
+			return fmt.Errorf("'constraint.%d' should have a 'knowledge' or a 'possession' constraint", i)
+		}
+		p := c.Possession
+		if p == nil {
+			continue
+		}
+		requirements := []struct {
+			name    string
+			value   string
+			types   []string
+			methods []string
+		}{
+			{"device_bound", p.DeviceBound, appSignOnPolicyUnboundPossessionTypes, appSignOnPolicyUnboundPossessionMethods},
+			{"hardware_protection", p.HardwareProtection, appSignOnPolicyUnboundPossessionTypes, appSignOnPolicyUnboundPossessionMethods},
+			{"phishing_resistant", p.PhishingResistant, appSignOnPolicyPhishablePossessionTypes, appSignOnPolicyPhishablePossessionMethods},
+		}
+		for _, r := range requirements {
+			if r.value != "REQUIRED" {
+				continue
+			}
+			for _, t := range p.Types {
+				if contains(r.types, t) {
+					return fmt.Errorf("'constraint.%d.possession' type '%s' can't satisfy '%s' REQUIRED", i, t, r.name)
+				}
+			}
+			for _, method := range p.Methods {
+				if contains(r.methods, method) {
+					return fmt.Errorf("'constraint.%d.possession' method '%s' can't satisfy '%s' REQUIRED", i, method, r.name)
+				}
+			}
+		}
+	}
+	return nil
+}
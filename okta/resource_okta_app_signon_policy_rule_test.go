@@ -12,6 +12,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/sdk"
 )
 
 func TestAccResourceOktaAppSignOnPolicyRule(t *testing.T) {
@@ -44,7 +45,7 @@ func TestAccResourceOktaAppSignOnPolicyRule(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "network_includes.#", "0"),
 					resource.TestCheckResourceAttr(resourceName, "network_excludes.#", "0"),
 					resource.TestCheckResourceAttr(resourceName, "network_connection", "ANYWHERE"),
-					resource.TestCheckResourceAttr(resourceName, "constraints.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "constraint.#", "0"),
 					resource.TestCheckResourceAttr(resourceName, "re_authentication_frequency", "PT2H"),
 					resource.TestCheckResourceAttr(resourceName, "inactivity_period", "PT1H"),
 					resource.TestCheckResourceAttr(resourceName, "risk_score", "LOW"),
@@ -72,7 +73,7 @@ func TestAccResourceOktaAppSignOnPolicyRule(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "re_authentication_frequency", "PT43800H"),
 					resource.TestCheckResourceAttr(resourceName, "inactivity_period", "PT2H"),
 					resource.TestCheckResourceAttr(resourceName, "type", "ASSURANCE"),
-					resource.TestCheckResourceAttr(resourceName, "constraints.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "constraint.#", "2"),
 					resource.TestCheckResourceAttr(resourceName, "risk_score", "MEDIUM"),
 				),
 			},
@@ -126,6 +127,61 @@ resource "okta_app_signon_policy" "test" {
 	name        = "testAcc_replace_with_uuid"
 	description = "Test App Signon Policy with updated Okta TF Provider"
 }
+resource "okta_app_signon_policy_rule" "test" {
+	policy_id                   = okta_app_signon_policy.test.id
+	name                        = "Require MFA_replace_with_uuid"
+	access                      = "ALLOW"
+	re_authentication_frequency = "PT43800H"
+	constraint {
+		knowledge {
+			reauthenticate_in = "PT43800H"
+			types             = ["password"]
+		}
+		possession {
+			device_bound = "REQUIRED"
+		}
+	}
+}`
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: mgr.ConfigReplace(config),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", buildResourceNameWithPrefix("Require MFA", mgr.Seed)),
+					resource.TestCheckResourceAttr(resourceName, "access", "ALLOW"),
+					resource.TestCheckResourceAttr(resourceName, "re_authentication_frequency", "PT43800H"),
+					validateOktaAppSignonPolicyRuleConstraintsAreSet(resourceName, constraints),
+				),
+			},
+		},
+	})
+}
+
+// TestAccResourceOktaAppSignOnPolicyRule_deprecated_constraints makes sure
+// the deprecated JSON constraints still create the rule.
+func TestAccResourceOktaAppSignOnPolicyRule_deprecated_constraints(t *testing.T) {
+	mgr := newFixtureManager("resources", appSignOnPolicyRule, t.Name())
+	resourceName := fmt.Sprintf("%s.test", appSignOnPolicyRule)
+	constraints := []interface{}{
+		map[string]interface{}{
+			"knowledge": map[string]interface{}{
+				"reauthenticateIn": "PT43800H",
+				"types":            []string{"password"},
+			},
+			"possession": map[string]interface{}{
+				"deviceBound": "REQUIRED",
+			},
+		},
+	}
+	config := `
+resource "okta_app_signon_policy" "test" {
+	name        = "testAcc_replace_with_uuid"
+	description = "Test App Signon Policy with updated Okta TF Provider"
+}
 resource "okta_app_signon_policy_rule" "test" {
 	policy_id                   = okta_app_signon_policy.test.id
 	name                        = "Require MFA_replace_with_uuid"
@@ -133,14 +189,14 @@ resource "okta_app_signon_policy_rule" "test" {
 	re_authentication_frequency = "PT43800H"
 	constraints = [
 		jsonencode({
-			knowledge = {
-				reauthenticateIn = "PT43800H"
-				types            = ["password"]
-			}
-			possession = {
-			  deviceBound = "REQUIRED"
+			"knowledge" : {
+				"reauthenticateIn" : "PT43800H",
+				"types" : ["password"]
+			},
+			"possession" : {
+				"deviceBound" : "REQUIRED"
 			}
-	  })
+		})
 	]
 }`
 
@@ -152,9 +208,8 @@ resource "okta_app_signon_policy_rule" "test" {
 			{
 				Config: mgr.ConfigReplace(config),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr(resourceName, "name", buildResourceNameWithPrefix("Require MFA", mgr.Seed)),
-					resource.TestCheckResourceAttr(resourceName, "access", "ALLOW"),
-					resource.TestCheckResourceAttr(resourceName, "re_authentication_frequency", "PT43800H"),
+					resource.TestCheckResourceAttr(resourceName, "constraints.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "constraint.#", "0"),
 					validateOktaAppSignonPolicyRuleConstraintsAreSet(resourceName, constraints),
 				),
 			},
@@ -180,13 +235,11 @@ resource "okta_app_signon_policy" "test" {
 resource "okta_app_signon_policy_rule" "test" {
 	name                        = "Catch-all Rule"
 	policy_id                   = okta_app_signon_policy.test.id
-	constraints = [
-		jsonencode({
-			possession = {
-			  deviceBound = "REQUIRED"
-			}
-	  })
-	]
+	constraint {
+		possession {
+			device_bound = "REQUIRED"
+		}
+	}
 }`
 	step4Config := `
 resource "okta_app_signon_policy_rule" "test" {
@@ -194,13 +247,11 @@ resource "okta_app_signon_policy_rule" "test" {
 	policy_id                   = okta_app_signon_policy.test.id
 	inactivity_period           = "PT1H"
 	re_authentication_frequency = "PT2H"
-	constraints = [
-		jsonencode({
-			possession = {
-			  deviceBound = "REQUIRED"
-			}
-	  })
-	]
+	constraint {
+		possession {
+			device_bound = "REQUIRED"
+		}
+	}
 }`
 	oktaResourceTest(t, resource.TestCase{
 		PreCheck:          testAccPreCheck(t),
@@ -341,7 +392,7 @@ func TestAccResourceOktaAppSignOnPolicyRuleDefault(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "network_includes.#", "0"),
 					resource.TestCheckResourceAttr(resourceName, "network_excludes.#", "0"),
 					resource.TestCheckResourceAttr(resourceName, "network_connection", "ANYWHERE"),
-					resource.TestCheckResourceAttr(resourceName, "constraints.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "constraint.#", "0"),
 					resource.TestCheckResourceAttr(resourceName, "re_authentication_frequency", "PT2H"),
 					resource.TestCheckResourceAttr(resourceName, "inactivity_period", "PT1H"),
 					resource.TestCheckResourceAttr(resourceName, "risk_score", "ANY"),
@@ -350,3 +401,170 @@ func TestAccResourceOktaAppSignOnPolicyRuleDefault(t *testing.T) {
 		},
 	})
 }
+
+func TestAppSignOnPolicyRuleConstraints(t *testing.T) {
+	expected := []*appSignOnPolicyConstraints{
+		{
+			Knowledge:  &appSignOnPolicyConstraint{ReauthenticateIn: "PT2H", Types: []string{"password"}},
+			Possession: &appSignOnPolicyConstraint{DeviceBound: "REQUIRED"},
+		},
+		{
+			Possession: &appSignOnPolicyConstraint{
+				PhishingResistant:             "REQUIRED",
+				ExcludedAuthenticationMethods: []*appSignOnPolicyAuthenticationMethod{{Key: "okta_email", Method: "email"}},
+			},
+		},
+	}
+
+	// the constraint blocks and the deprecated JSON constraints build the
+	// same constraints
+	d := resourceAppSignOnPolicyRule().Data(&terraform.InstanceState{ID: "rul1"})
+	if err := d.Set("constraint", flattenAppSignOnPolicyConstraints(expected)); err != nil {
+		t.Fatalf("did not expect error, got %v", err)
+	}
+	constraints, err := appSignOnPolicyRuleConstraints(d.Get("constraint"), d.Get("constraints"))
+	if err != nil {
+		t.Fatalf("did not expect error, got %v", err)
+	}
+	if !reflect.DeepEqual(expected, constraints) {
+		a, _ := json.Marshal(expected)
+		b, _ := json.Marshal(constraints)
+		t.Errorf("expected constraints %s, got %s", a, b)
+	}
+	legacy := []interface{}{
+		`{"knowledge":{"reauthenticateIn":"PT2H","types":["password"]},"possession":{"deviceBound":"REQUIRED"}}`,
+		`{"possession":{"excludedAuthenticationMethods":[{"key":"okta_email","method":"email"}],"phishingResistant":"REQUIRED"}}`,
+	}
+	constraints, err = appSignOnPolicyRuleConstraints([]interface{}{}, legacy)
+	if err != nil {
+		t.Fatalf("did not expect error, got %v", err)
+	}
+	if !reflect.DeepEqual(expected, constraints) {
+		a, _ := json.Marshal(expected)
+		b, _ := json.Marshal(constraints)
+		t.Errorf("expected constraints %s, got %s", a, b)
+	}
+
+	if _, err := appSignOnPolicyRuleConstraints(nil, []interface{}{"{"}); err == nil {
+		t.Error("expected error for invalid JSON constraints")
+	}
+}
+
+func TestAppSignOnPolicyRuleBodyJSON(t *testing.T) {
+	rule := &appSignOnPolicyRuleBody{
+		AccessPolicyRule: sdk.AccessPolicyRule{
+			Name: "rule",
+			Actions: &sdk.AccessPolicyRuleActions{
+				AppSignOn: &sdk.AccessPolicyRuleApplicationSignOn{
+					Access:             "ALLOW",
+					VerificationMethod: &sdk.VerificationMethod{FactorMode: "2FA", Type: "ASSURANCE"},
+				},
+			},
+		},
+		Constraints: []*appSignOnPolicyConstraints{
+			{
+				Possession: &appSignOnPolicyConstraint{
+					Methods:                       []string{"push"},
+					ExcludedAuthenticationMethods: []*appSignOnPolicyAuthenticationMethod{{Key: "phone_number"}},
+				},
+			},
+		},
+	}
+	b, err := json.Marshal(rule)
+	if err != nil {
+		t.Fatalf("did not expect error, got %v", err)
+	}
+	if !strings.Contains(string(b), `"constraints":[{"possession":{"methods":["push"],"excludedAuthenticationMethods":[{"key":"phone_number"}]}}]`) {
+		t.Errorf("expected the constraints in the verification method, got %s", b)
+	}
+
+	var decoded appSignOnPolicyRuleBody
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("did not expect error, got %v", err)
+	}
+	if decoded.Name != "rule" || decoded.Actions.AppSignOn.VerificationMethod.FactorMode != "2FA" {
+		t.Errorf("expected the rule to be decoded, got %+v", decoded.AccessPolicyRule)
+	}
+	if !reflect.DeepEqual(rule.Constraints, decoded.Constraints) {
+		t.Errorf("expected constraints %+v, got %+v", rule.Constraints, decoded.Constraints)
+	}
+}
+
+func TestValidateAppSignOnPolicyConstraints(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints []*appSignOnPolicyConstraints
+		err         string
+	}{
+		{
+			name: "valid",
+			constraints: []*appSignOnPolicyConstraints{
+				{
+					Knowledge:  &appSignOnPolicyConstraint{Types: []string{"password"}},
+					Possession: &appSignOnPolicyConstraint{Methods: []string{"signed_nonce", "webauthn"}, PhishingResistant: "REQUIRED"},
+				},
+				{Possession: &appSignOnPolicyConstraint{Types: []string{"phone"}, DeviceBound: "OPTIONAL"}},
+			},
+		},
+		{
+			name:        "empty",
+			constraints: []*appSignOnPolicyConstraints{{}},
+			err:         "should have a 'knowledge' or a 'possession' constraint",
+		},
+		{
+			name:        "phishable method",
+			constraints: []*appSignOnPolicyConstraints{{Possession: &appSignOnPolicyConstraint{Methods: []string{"push", "sms"}, PhishingResistant: "REQUIRED"}}},
+			err:         "method 'push' can't satisfy 'phishing_resistant' REQUIRED",
+		},
+		{
+			name:        "unbound type",
+			constraints: []*appSignOnPolicyConstraints{{Possession: &appSignOnPolicyConstraint{Types: []string{"email"}, HardwareProtection: "REQUIRED"}}},
+			err:         "type 'email' can't satisfy 'hardware_protection' REQUIRED",
+		},
+	}
+	for _, tt := range tests {
+		err := validateAppSignOnPolicyConstraints(tt.constraints)
+		if tt.err == "" && err != nil {
+			t.Errorf("%s: did not expect error, got %v", tt.name, err)
+		}
+		if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("%s: expected error %q, got %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestAppSignOnPolicyRuleDiffSkipsLegacyConstraints(t *testing.T) {
+	r := resourceAppSignOnPolicyRule()
+	base := map[string]interface{}{
+		"policy_id": "rst1",
+		"name":      "Require MFA",
+	}
+	config := func(attrs map[string]interface{}) *terraform.ResourceConfig {
+		raw := map[string]interface{}{}
+		for k, v := range base {
+			raw[k] = v
+		}
+		for k, v := range attrs {
+			raw[k] = v
+		}
+		return terraform.NewResourceConfigRaw(raw)
+	}
+
+	_, err := r.Diff(context.TODO(), nil, config(map[string]interface{}{
+		"constraint": []interface{}{map[string]interface{}{
+			"possession": []interface{}{map[string]interface{}{"types": []interface{}{"email"}, "hardware_protection": "REQUIRED"}},
+		}},
+	}), nil)
+	if err == nil || err.Error() != "'constraint.0.possession' type 'email' can't satisfy 'hardware_protection' REQUIRED" {
+		t.Errorf("expected the constraint block to be validated, got %v", err)
+	}
+
+	// the same constraint written in the deprecated JSON form plans like it
+	// did before the constraint blocks
+	_, err = r.Diff(context.TODO(), nil, config(map[string]interface{}{
+		"constraints": []interface{}{`{"possession":{"types":["email"],"hardwareProtection":"REQUIRED"}}`},
+	}), nil)
+	if err != nil {
+		t.Errorf("did not expect the JSON constraints to be validated, got %v", err)
+	}
+}
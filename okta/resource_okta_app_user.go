@@ -64,10 +64,25 @@ func resourceAppUser() *schema.Resource {
 				Computed: true,
 			},
 			"password": {
-				Type:        schema.TypeString,
-				Sensitive:   true,
-				Optional:    true,
-				Description: "The password to use.",
+				Type:          schema.TypeString,
+				Sensitive:     true,
+				Optional:      true,
+				ConflictsWith: []string{"password_wo"},
+				Description:   "The password to use.",
+			},
+			"password_wo": {
+				Type:         schema.TypeString,
+				Sensitive:    true,
+				Optional:     true,
+				WriteOnly:    true,
+				RequiredWith: []string{"password_wo_version"},
+				Description:  "Write-only password to use. It is never stored in the state, change `password_wo_version` to update it. Requires Terraform 1.11 or later.",
+			},
+			"password_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"password_wo"},
+				Description:  "Version of `password_wo`, a change of the version updates the password.",
 			},
 			"profile": {
 				Type:             schema.TypeString,
@@ -219,7 +234,7 @@ func getAppUser(d *schema.ResourceData) *sdk.AppUser {
 		Credentials: &sdk.AppUserCredentials{
 			UserName: d.Get("username").(string),
 			Password: &sdk.AppUserPasswordCredential{
-				Value: d.Get("password").(string),
+				Value: getSecret(d, "password"),
 			},
 		},
 		Profile: profile,
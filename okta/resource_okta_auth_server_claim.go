@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/okta/terraform-provider-okta/sdk"
 )
@@ -61,6 +62,9 @@ func resourceAuthServerClaim() *schema.Resource {
 				Description: "Specifies the type of group filter if `value_type` is `GROUPS`. Can be set to one of the following `STARTS_WITH`, `EQUALS`, `CONTAINS`, `REGEX`.",
 			},
 		},
+		CustomizeDiff: customdiff.IfValue("value_type", func(ctx context.Context, value, meta interface{}) bool {
+			return value.(string) == "EXPRESSION"
+		}, expressionDiff("value")),
 	}
 }
 
@@ -0,0 +1,107 @@
+package okta
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func resourceAuthServerKeyRotation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAuthServerKeyRotationCreate,
+		ReadContext:   resourceAuthServerKeyRotationRead,
+		UpdateContext: resourceAuthServerKeyRotationUpdate,
+		DeleteContext: resourceFuncNoOp,
+		CustomizeDiff: authServerKeyRotationDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				_ = d.Set("auth_server_id", d.Id())
+				_ = d.Set("use", "sig")
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: map[string]*schema.Schema{
+			"auth_server_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the authorization server.",
+			},
+			"use": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "sig",
+				ValidateDiagFunc: stringInSlice([]string{"sig"}),
+				Description:      "Intended use of the rotated key. Only `sig` is supported.",
+			},
+			"rotate_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Arbitrary value whose change rotates the keys of the authorization server, for example the date of the scheduled rotation.",
+			},
+			"kid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the active key.",
+			},
+			"keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Signing keys of the authorization server.",
+				Elem:        authServerKeyResource,
+			},
+		},
+		Description: "Rotates the signing keys of an authorization server whose `credentials_rotation_mode` is `MANUAL`. Creating the resource does not rotate the keys, they are rotated each time `rotate_trigger` changes. Destroying the resource keeps the keys as they are.",
+	}
+}
+
+// authServerKeyRotationDiff plans the rotation of the keys when the rotation
+// trigger changes, the active key and the keys are unknown until the keys are
+// rotated.
+func authServerKeyRotationDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange("rotate_trigger") {
+		return nil
+	}
+	if err := d.SetNewComputed("kid"); err != nil {
+		return err
+	}
+	return d.SetNewComputed("keys")
+}
+
+func resourceAuthServerKeyRotationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId(d.Get("auth_server_id").(string))
+	return resourceAuthServerKeyRotationRead(ctx, d, m)
+}
+
+func resourceAuthServerKeyRotationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	keys, resp, err := getOktaClientFromMetadata(m).AuthorizationServer.ListAuthorizationServerKeys(ctx, d.Get("auth_server_id").(string))
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to list authorization server keys: %v", err)
+	}
+	if keys == nil {
+		d.SetId("")
+		return nil
+	}
+	setAuthServerKeys(d, keys)
+	return nil
+}
+
+func resourceAuthServerKeyRotationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.HasChange("rotate_trigger") {
+		return resourceAuthServerKeyRotationRead(ctx, d, m)
+	}
+	keys, _, err := getOktaClientFromMetadata(m).AuthorizationServer.RotateAuthorizationServerKeys(ctx,
+		d.Get("auth_server_id").(string), sdk.JwkUse{Use: d.Get("use").(string)})
+	if err != nil {
+		return diag.Errorf("failed to rotate authorization server keys: %v", err)
+	}
+	setAuthServerKeys(d, keys)
+	return nil
+}
+
+func setAuthServerKeys(d *schema.ResourceData, keys []*sdk.JsonWebKey) {
+	_ = d.Set("kid", activeAuthServerKeyID(keys))
+	_ = d.Set("keys", flattenAuthServerKeys(keys))
+}
@@ -0,0 +1,107 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccResourceOktaAuthServerKeyRotation(t *testing.T) {
+	mgr := newFixtureManager("resources", authServerKeyRotation, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	updatedConfig := mgr.GetFixtures("basic_updated.tf", t)
+	resourceName := fmt.Sprintf("%s.test", authServerKeyRotation)
+	var kid string
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "kid"),
+					resource.TestCheckResourceAttrSet(resourceName, "keys.#"),
+					func(s *terraform.State) error {
+						kid = s.RootModule().Resources[resourceName].Primary.Attributes["kid"]
+						return nil
+					},
+				),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "rotate_trigger", "2024-07"),
+					func(s *terraform.State) error {
+						if s.RootModule().Resources[resourceName].Primary.Attributes["kid"] == kid {
+							return fmt.Errorf("active key %s was not rotated", kid)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAuthServerKeyRotation(t *testing.T) {
+	_, config := newFakeOktaConfig(t)
+	ctx := context.TODO()
+	authServer, _, err := config.oktaSDKClientV2.AuthorizationServer.CreateAuthorizationServer(ctx, sdk.AuthorizationServer{Name: "api", Audiences: []string{"api://api"}})
+	require.NoError(t, err)
+	kid := authServer.Credentials.Signing.Kid
+
+	r := resourceAuthServerKeyRotation()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"auth_server_id": authServer.Id,
+		"rotate_trigger": "2024-01",
+	})
+
+	// creating the resource does not rotate the keys
+	diags := r.CreateContext(ctx, d, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, authServer.Id, d.Id())
+	require.Equal(t, kid, d.Get("kid"))
+	require.Equal(t, 2, d.Get("keys.#"))
+
+	// a trigger change rotates the keys
+	_ = d.Set("rotate_trigger", "2024-07")
+	diags = r.UpdateContext(ctx, d, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.NotEqual(t, kid, d.Get("kid"))
+	require.Equal(t, 3, d.Get("keys.#"))
+	require.Equal(t, kid, d.Get("keys.0.kid"))
+	require.Equal(t, "EXPIRED", d.Get("keys.0.status"))
+	require.Equal(t, d.Get("kid"), d.Get("keys.1.kid"))
+	require.Equal(t, "sig", d.Get("keys.1.use"))
+
+	// a trigger change plans the active key and the keys as unknown
+	state := d.State()
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"auth_server_id": authServer.Id,
+		"rotate_trigger": "2025-01",
+	}), config)
+	require.NoError(t, err)
+	require.True(t, diff.Attributes["kid"].NewComputed)
+	require.True(t, diff.Attributes["keys.#"].NewComputed)
+	diff, err = r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"auth_server_id": authServer.Id,
+		"rotate_trigger": "2024-07",
+	}), config)
+	require.NoError(t, err)
+	require.Nil(t, diff)
+
+	// the resource is removed from the state when the authorization server is gone
+	d.SetId("aus404")
+	_ = d.Set("auth_server_id", "aus404")
+	diags = r.ReadContext(ctx, d, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, "", d.Id())
+}
@@ -55,6 +55,8 @@ func resourceAuthenticator() *schema.Resource {
 					"provider_auth_port",
 					"provider_hostname",
 					"provider_shared_secret",
+					"provider_shared_secret_wo",
+					"provider_shared_secret_wo_version",
 					"provider_user_name_template",
 					// duo
 					"provider_host",
@@ -100,6 +102,22 @@ func resourceAuthenticator() *schema.Resource {
 				Optional:      true,
 				Description:   "An authentication key that must be defined when the RADIUS server is configured, and must be the same on both the RADIUS client and server.",
 				RequiredWith:  []string{"provider_hostname"},
+				ConflictsWith: []string{"provider_json", "provider_shared_secret_wo"},
+			},
+			"provider_shared_secret_wo": {
+				Type:          schema.TypeString,
+				Sensitive:     true,
+				Optional:      true,
+				WriteOnly:     true,
+				Description:   "Write-only variant of `provider_shared_secret`. It is never stored in the state, change `provider_shared_secret_wo_version` to update it. Requires Terraform 1.11 or later.",
+				RequiredWith:  []string{"provider_hostname", "provider_shared_secret_wo_version"},
+				ConflictsWith: []string{"provider_json"},
+			},
+			"provider_shared_secret_wo_version": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Description:   "Version of `provider_shared_secret_wo`, a change of the version updates the shared secret.",
+				RequiredWith:  []string{"provider_shared_secret_wo"},
 				ConflictsWith: []string{"provider_json"},
 			},
 			"provider_user_name_template": {
@@ -270,7 +288,7 @@ func buildAuthenticator(d *schema.ResourceData) (*sdk.Authenticator, error) {
 				HostName:     d.Get("provider_hostname").(string),
 				AuthPortPtr:  int64Ptr(d.Get("provider_auth_port").(int)),
 				InstanceId:   d.Get("provider_instance_id").(string),
-				SharedSecret: d.Get("provider_shared_secret").(string),
+				SharedSecret: getSecret(d, "provider_shared_secret"),
 				UserNameTemplate: &sdk.AuthenticatorProviderConfigurationUserNamePlate{
 					Template: "",
 				},
@@ -316,7 +334,7 @@ func validateAuthenticator(d *schema.ResourceData) error {
 	if typ == "security_key" {
 		h := d.Get("provider_hostname").(string)
 		_, pok := d.GetOk("provider_auth_port")
-		s := d.Get("provider_shared_secret").(string)
+		s := getSecret(d, "provider_shared_secret")
 		templ := d.Get("provider_user_name_template").(string)
 		if h == "" || s == "" || templ == "" || !pok {
 			return fmt.Errorf("for authenticator type '%s' fields 'provider_hostname', "+
@@ -35,10 +35,25 @@ func resourceCaptcha() *schema.Resource {
 				Description: "Site key issued from the CAPTCHA vendor to render a CAPTCHA on a page",
 			},
 			"secret_key": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Sensitive:   true,
-				Description: "Secret key issued from the CAPTCHA vendor to perform server-side validation for a CAPTCHA token",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"secret_key", "secret_key_wo"},
+				Description:  "Secret key issued from the CAPTCHA vendor to perform server-side validation for a CAPTCHA token",
+			},
+			"secret_key_wo": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				WriteOnly:    true,
+				RequiredWith: []string{"secret_key_wo_version"},
+				Description:  "Write-only secret key issued from the CAPTCHA vendor. It is never stored in the state, change `secret_key_wo_version` to update it. Requires Terraform 1.11 or later.",
+			},
+			"secret_key_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"secret_key_wo"},
+				Description:  "Version of `secret_key_wo`, a change of the version updates the secret key.",
 			},
 		},
 	}
@@ -105,7 +120,7 @@ func buildCaptcha(d *schema.ResourceData) sdk.Captcha {
 	return sdk.Captcha{
 		Name:      d.Get("name").(string),
 		SiteKey:   d.Get("site_key").(string),
-		SecretKey: d.Get("secret_key").(string),
+		SecretKey: getSecret(d, "secret_key"),
 		Type:      d.Get("type").(string),
 	}
 }
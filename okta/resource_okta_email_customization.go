@@ -16,6 +16,7 @@ func resourceEmailCustomization() *schema.Resource {
 		DeleteContext: resourceEmailCustomizationDelete,
 		Importer:      createNestedResourceImporter([]string{"id", "brand_id", "template_name"}),
 		Schema:        emailCustomizationResourceSchema,
+		CustomizeDiff: emailCustomizationDiff,
 	}
 }
 
@@ -0,0 +1,273 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/okta-sdk-golang/v3/okta"
+)
+
+func resourceEmailCustomizations() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceEmailCustomizationsCreate,
+		ReadContext:   resourceEmailCustomizationsRead,
+		UpdateContext: resourceEmailCustomizationsUpdate,
+		DeleteContext: resourceEmailCustomizationsDelete,
+		Importer:      createNestedResourceImporter([]string{"brand_id", "template_name"}),
+		CustomizeDiff: emailCustomizationsDiff,
+		Description:   "Manages all the customizations, one per language, of an email template belonging to a brand in an Okta organization.",
+		Schema: map[string]*schema.Schema{
+			"brand_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Brand ID",
+			},
+			"template_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Template Name",
+			},
+			"default_language": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The language of the default customization, sent to users whose locale has no customization. Must be the language of a `customization`.",
+			},
+			"customization": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "The customizations of the email template, one per language",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"language": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The language of the customization",
+						},
+						"subject": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The subject of the customization",
+						},
+						"body": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The body of the customization",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// emailCustomizationsDiff checks the languages of the customizations are
+// unique and include the default language, and checks the subjects and
+// bodies of the customizations like okta_email_customization does.
+func emailCustomizationsDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("customization") {
+		return nil
+	}
+	languages := make(map[string]bool)
+	for _, v := range d.Get("customization").(*schema.Set).List() {
+		c := v.(map[string]interface{})
+		language := c["language"].(string)
+		if languages[language] {
+			return fmt.Errorf("customization language %q is set more than once", language)
+		}
+		languages[language] = true
+	}
+	if d.NewValueKnown("default_language") && !languages[d.Get("default_language").(string)] {
+		return fmt.Errorf("default_language %q is not the language of a customization", d.Get("default_language").(string))
+	}
+	if !d.HasChanges("template_name", "customization") || !d.NewValueKnown("template_name") {
+		return nil
+	}
+	templateName := d.Get("template_name").(string)
+	for _, v := range d.Get("customization").(*schema.Set).List() {
+		c := v.(map[string]interface{})
+		for _, k := range []string{"subject", "body"} {
+			attr := fmt.Sprintf("customization %q %s", c["language"].(string), k)
+			warnings, err := checkEmailTemplate(ctx, m, attr, templateName, c[k].(string), k == "body")
+			logEmailTemplateWarnings(warnings)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resourceEmailCustomizationsCreate fails when the email template is already
+// customized rather than deleting the customizations that aren't configured,
+// they have to be imported first. The ID is set before the customizations are
+// created so the ones created before a failure are kept in the state.
+func resourceEmailCustomizationsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	brandID := d.Get("brand_id").(string)
+	templateName := d.Get("template_name").(string)
+	existing, err := collectEmailCustomizations(ctx, getOktaV3ClientFromMetadata(m), brandID, templateName)
+	if err != nil {
+		return diag.Errorf("failed to list email customizations: %v", err)
+	}
+	if len(existing) > 0 {
+		languages := make([]string, len(existing))
+		for i, customization := range existing {
+			languages[i] = customization.GetLanguage()
+		}
+		sort.Strings(languages)
+		return diag.Errorf("the %q email template of brand %q is already customized in %s, import the customizations with the ID '%s/%s' before applying",
+			templateName, brandID, strings.Join(languages, ", "), brandID, templateName)
+	}
+	d.SetId(fmt.Sprintf("%s/%s", brandID, templateName))
+	if err := syncEmailCustomizations(ctx, d, m); err != nil {
+		return append(diag.Errorf("failed to create email customizations: %v", err), resourceEmailCustomizationsRead(ctx, d, m)...)
+	}
+	return resourceEmailCustomizationsRead(ctx, d, m)
+}
+
+func resourceEmailCustomizationsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	customizations, err := collectEmailCustomizations(ctx, getOktaV3ClientFromMetadata(m), d.Get("brand_id").(string), d.Get("template_name").(string))
+	if err != nil {
+		return diag.Errorf("failed to list email customizations: %v", err)
+	}
+	if len(customizations) == 0 {
+		d.SetId("")
+		return nil
+	}
+	arr := make([]interface{}, len(customizations))
+	for i, customization := range customizations {
+		arr[i] = map[string]interface{}{
+			"language": customization.GetLanguage(),
+			"subject":  customization.GetSubject(),
+			"body":     customization.GetBody(),
+		}
+		if customization.GetIsDefault() {
+			_ = d.Set("default_language", customization.GetLanguage())
+		}
+	}
+	err = d.Set("customization", arr)
+	if err != nil {
+		return diag.Errorf("failed to set email customizations: %v", err)
+	}
+	return nil
+}
+
+func resourceEmailCustomizationsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := syncEmailCustomizations(ctx, d, m); err != nil {
+		return append(diag.Errorf("failed to update email customizations: %v", err), resourceEmailCustomizationsRead(ctx, d, m)...)
+	}
+	return resourceEmailCustomizationsRead(ctx, d, m)
+}
+
+func resourceEmailCustomizationsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// The API doesn't allow deleting the default customization while other
+	// customizations exist, delete them all at once.
+	resp, err := getOktaV3ClientFromMetadata(m).CustomizationAPI.DeleteAllCustomizations(ctx, d.Get("brand_id").(string), d.Get("template_name").(string)).Execute()
+	if err := v3suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to delete email customizations: %v", err)
+	}
+	return nil
+}
+
+// syncEmailCustomizations creates, updates and deletes the customizations of
+// the email template to match the configuration. The API returns 409 Conflict
+// when a customization is created as the default while a default exists, when
+// the default is updated to not be the default and when the default is
+// deleted, so the default language is made the default first, then the other
+// languages are created or updated and last the languages no longer
+// configured are deleted.
+func syncEmailCustomizations(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	client := getOktaV3ClientFromMetadata(m)
+	brandID := d.Get("brand_id").(string)
+	templateName := d.Get("template_name").(string)
+	defaultLanguage := d.Get("default_language").(string)
+
+	existing, err := collectEmailCustomizations(ctx, client, brandID, templateName)
+	if err != nil {
+		return err
+	}
+	current := make(map[string]*okta.EmailCustomization, len(existing))
+	for i := range existing {
+		current[existing[i].GetLanguage()] = &existing[i]
+	}
+	desired := make(map[string]okta.EmailCustomization)
+	var languages []string
+	for _, v := range d.Get("customization").(*schema.Set).List() {
+		c := v.(map[string]interface{})
+		language := c["language"].(string)
+		desired[language] = okta.EmailCustomization{
+			Language: language,
+			Subject:  c["subject"].(string),
+			Body:     c["body"].(string),
+		}
+		if language != defaultLanguage {
+			languages = append(languages, language)
+		}
+	}
+	if _, ok := desired[defaultLanguage]; !ok {
+		return fmt.Errorf("default_language %q is not the language of a customization", defaultLanguage)
+	}
+	sort.Strings(languages)
+
+	put := func(language string, isDefault bool) error {
+		want := desired[language]
+		want.IsDefault = boolPtr(isDefault)
+		c, ok := current[language]
+		if !ok {
+			// only the first customization of a template can be created as
+			// the default, others are made the default once created
+			want.IsDefault = boolPtr(isDefault && len(current) == 0)
+			created, _, err := client.CustomizationAPI.CreateEmailCustomization(ctx, brandID, templateName).Instance(want).Execute()
+			if err != nil {
+				return fmt.Errorf("failed to create the %q customization: %v", language, err)
+			}
+			c = created
+			current[language] = c
+			want.IsDefault = boolPtr(isDefault)
+		}
+		if c.GetSubject() == want.Subject && c.GetBody() == want.Body && c.GetIsDefault() == isDefault {
+			return nil
+		}
+		updated, _, err := client.CustomizationAPI.ReplaceEmailCustomization(ctx, brandID, templateName, c.GetId()).Instance(want).Execute()
+		if err != nil {
+			return fmt.Errorf("failed to update the %q customization: %v", language, err)
+		}
+		current[language] = updated
+		return nil
+	}
+
+	if err := put(defaultLanguage, true); err != nil {
+		return err
+	}
+	// making a customization the default unsets the previous default
+	for language, c := range current {
+		if language != defaultLanguage {
+			c.IsDefault = boolPtr(false)
+		}
+	}
+	for _, language := range languages {
+		if err := put(language, false); err != nil {
+			return err
+		}
+	}
+	var removed []string
+	for language := range current {
+		if _, ok := desired[language]; !ok {
+			removed = append(removed, language)
+		}
+	}
+	sort.Strings(removed)
+	for _, language := range removed {
+		_, err := client.CustomizationAPI.DeleteEmailCustomization(ctx, brandID, templateName, current[language].GetId()).Execute()
+		if err != nil {
+			return fmt.Errorf("failed to delete the %q customization: %v", language, err)
+		}
+	}
+	return nil
+}
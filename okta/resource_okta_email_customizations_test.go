@@ -0,0 +1,218 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/okta-sdk-golang/v3/okta"
+	"github.com/okta/terraform-provider-okta/okta/internal/fakeokta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccResourceOktaEmailCustomizations_crud(t *testing.T) {
+	mgr := newFixtureManager("resources", emailCustomizations, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	updatedConfig := mgr.GetFixtures("updated.tf", t)
+	resourceName := fmt.Sprintf("%s.test", emailCustomizations)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "template_name", "ForgotPassword"),
+					resource.TestCheckResourceAttr(resourceName, "default_language", "en"),
+					resource.TestCheckResourceAttr(resourceName, "customization.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "customization.*", map[string]string{
+						"language": "es",
+						"subject":  "Has olvidado tu contraseña",
+					}),
+				),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "default_language", "fr"),
+					resource.TestCheckResourceAttr(resourceName, "customization.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "customization.*", map[string]string{
+						"language": "fr",
+						"subject":  "Mot de passe oublié",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "customization.*", map[string]string{
+						"language": "en",
+						"subject":  "Forgot your password?",
+					}),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// seedEmailCustomizations creates customizations of the ForgotPassword
+// template of the default brand, the first one is the default.
+func seedEmailCustomizations(t *testing.T, server *fakeokta.Server, config *Config, languages ...string) {
+	t.Helper()
+	for _, language := range languages {
+		_, _, err := getOktaV3ClientFromMetadata(config).CustomizationAPI.CreateEmailCustomization(context.TODO(), server.DefaultBrandID(), "ForgotPassword").Instance(okta.EmailCustomization{
+			Language: language,
+			Subject:  "Subject " + language,
+			Body:     "Body " + language + " ${resetPasswordLink}",
+		}).Execute()
+		require.NoError(t, err)
+	}
+}
+
+func TestSyncEmailCustomizations(t *testing.T) {
+	customization := func(language string) map[string]interface{} {
+		return map[string]interface{}{
+			"language": language,
+			"subject":  "Subject " + language,
+			"body":     "Body " + language + " ${resetPasswordLink}",
+		}
+	}
+	// fakeokta answers 409 Conflict like Okta when a customization is created
+	// as the default while a default exists, the default is updated to not be
+	// the default or the default is deleted, so a sync in the wrong order
+	// fails
+	tests := []struct {
+		name            string
+		existing        []string
+		defaultLanguage string
+		customizations  []interface{}
+		languages       []string
+		requests        int
+	}{
+		{
+			name:            "create",
+			defaultLanguage: "en",
+			customizations:  []interface{}{customization("fr"), customization("en")},
+			languages:       []string{"en", "fr"},
+			requests:        3,
+		},
+		{
+			name:            "new default language",
+			existing:        []string{"en"},
+			defaultLanguage: "es",
+			customizations:  []interface{}{customization("en"), customization("es")},
+			languages:       []string{"en", "es"},
+			requests:        3,
+		},
+		{
+			name:            "change default and remove the previous default",
+			existing:        []string{"en", "de", "fr"},
+			defaultLanguage: "fr",
+			customizations: []interface{}{
+				customization("de"),
+				map[string]interface{}{"language": "fr", "subject": "Mot de passe oublié", "body": "${resetPasswordLink}"},
+				customization("es"),
+			},
+			languages: []string{"de", "es", "fr"},
+			requests:  4,
+		},
+		{
+			name:            "no changes",
+			existing:        []string{"en", "de"},
+			defaultLanguage: "en",
+			customizations:  []interface{}{customization("de"), customization("en")},
+			languages:       []string{"de", "en"},
+			requests:        1,
+		},
+	}
+	for _, test := range tests {
+		server, config := newFakeOktaConfig(t)
+		seedEmailCustomizations(t, server, config, test.existing...)
+		d := schema.TestResourceDataRaw(t, resourceEmailCustomizations().Schema, map[string]interface{}{
+			"brand_id":         server.DefaultBrandID(),
+			"template_name":    "ForgotPassword",
+			"default_language": test.defaultLanguage,
+			"customization":    test.customizations,
+		})
+		requests := server.Requests()
+		require.NoError(t, syncEmailCustomizations(context.TODO(), d, config), test.name)
+		require.Equal(t, test.requests, server.Requests()-requests, test.name)
+
+		customizations, err := collectEmailCustomizations(context.TODO(), getOktaV3ClientFromMetadata(config), server.DefaultBrandID(), "ForgotPassword")
+		require.NoError(t, err, test.name)
+		var languages []string
+		for _, c := range customizations {
+			languages = append(languages, c.GetLanguage())
+			require.Equal(t, c.GetLanguage() == test.defaultLanguage, c.GetIsDefault(), test.name)
+		}
+		sort.Strings(languages)
+		require.Equal(t, test.languages, languages, test.name)
+
+		diags := resourceEmailCustomizationsRead(context.TODO(), d, config)
+		require.False(t, diags.HasError(), test.name)
+		require.Equal(t, test.defaultLanguage, d.Get("default_language"), test.name)
+		require.Equal(t, len(test.customizations), d.Get("customization").(*schema.Set).Len(), test.name)
+	}
+}
+
+func TestEmailCustomizationsCreateCustomizedTemplate(t *testing.T) {
+	server, config := newFakeOktaConfig(t)
+	seedEmailCustomizations(t, server, config, "en", "de")
+	brandID := server.DefaultBrandID()
+
+	d := schema.TestResourceDataRaw(t, resourceEmailCustomizations().Schema, map[string]interface{}{
+		"brand_id":         brandID,
+		"template_name":    "ForgotPassword",
+		"default_language": "en",
+		"customization": []interface{}{map[string]interface{}{
+			"language": "en",
+			"subject":  "Subject",
+			"body":     "${resetPasswordLink}",
+		}},
+	})
+	// the customizations that aren't configured are left as they are
+	requests := server.Requests()
+	diags := resourceEmailCustomizationsCreate(context.TODO(), d, config)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Summary, fmt.Sprintf("already customized in de, en, import the customizations with the ID '%s/ForgotPassword'", brandID))
+	require.Equal(t, 1, server.Requests()-requests)
+	customizations, err := collectEmailCustomizations(context.TODO(), getOktaV3ClientFromMetadata(config), brandID, "ForgotPassword")
+	require.NoError(t, err)
+	require.Len(t, customizations, 2)
+	require.Equal(t, "", d.Id())
+}
+
+func TestEmailCustomizationsCreatePartialFailure(t *testing.T) {
+	server, config := newFakeOktaConfig(t)
+	brandID := server.DefaultBrandID()
+	d := schema.TestResourceDataRaw(t, resourceEmailCustomizations().Schema, map[string]interface{}{
+		"brand_id":         brandID,
+		"template_name":    "ForgotPassword",
+		"default_language": "en",
+		"customization": []interface{}{
+			map[string]interface{}{"language": "en", "subject": "Subject en", "body": "${resetPasswordLink}"},
+			map[string]interface{}{"language": "de", "subject": "Subject de", "body": "${resetPasswordLink}"},
+			// fakeokta rejects the third create like Okta, the subject is blank
+			map[string]interface{}{"language": "fr", "subject": "", "body": "${resetPasswordLink}"},
+		},
+	})
+	diags := resourceEmailCustomizationsCreate(context.TODO(), d, config)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Summary, `failed to create the "fr" customization`)
+
+	// the customizations created before the failure are in the state, the
+	// next apply updates them rather than failing as already customized
+	require.Equal(t, brandID+"/ForgotPassword", d.Id())
+	require.Equal(t, "en", d.Get("default_language"))
+	var languages []string
+	for _, v := range d.Get("customization").(*schema.Set).List() {
+		languages = append(languages, v.(map[string]interface{})["language"].(string))
+	}
+	sort.Strings(languages)
+	require.Equal(t, []string{"de", "en"}, languages)
+}
@@ -2,6 +2,7 @@ package okta
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -59,8 +60,14 @@ func resourceGroupMembershipsCreate(ctx context.Context, d *schema.ResourceData,
 		d.SetId(groupId)
 		return nil
 	}
-	err := addGroupMembers(ctx, client, groupId, users)
+	added, err := addGroupMembers(ctx, client, groupId, users, m.(*Config).parallelism)
 	if err != nil {
+		if len(added) == 0 {
+			return diag.FromErr(err)
+		}
+		// record the memberships that were actually made
+		d.SetId(groupId)
+		_ = d.Set("users", convertStringSliceToSet(added))
 		return diag.FromErr(err)
 	}
 	boc := newExponentialBackOffWithContext(ctx, 10*time.Second)
@@ -124,8 +131,11 @@ func resourceGroupMembershipsDelete(ctx context.Context, d *schema.ResourceData,
 	groupId := d.Get("group_id").(string)
 	users := convertInterfaceToStringSetNullable(d.Get("users"))
 	client := getOktaClientFromMetadata(m)
-	err := removeGroupMembers(ctx, client, groupId, users)
+	removed, err := removeGroupMembers(ctx, client, groupId, users, m.(*Config).parallelism)
 	if err != nil {
+		// record the memberships that remain
+		remaining := d.Get("users").(*schema.Set).Difference(convertStringSliceToSet(removed))
+		_ = d.Set("users", remaining)
 		return diag.FromErr(err)
 	}
 	return nil
@@ -143,14 +153,14 @@ func resourceGroupMembershipsUpdate(ctx context.Context, d *schema.ResourceData,
 	usersToAdd := convertInterfaceArrToStringArr(newSet.Difference(oldSet).List())
 	usersToRemove := convertInterfaceArrToStringArr(oldSet.Difference(newSet).List())
 
-	err := addGroupMembers(ctx, client, groupId, usersToAdd)
-	if err != nil {
-		diag.FromErr(err)
-	}
-
-	err = removeGroupMembers(ctx, client, groupId, usersToRemove)
-	if err != nil {
-		diag.FromErr(err)
+	parallelism := m.(*Config).parallelism
+	added, addErr := addGroupMembers(ctx, client, groupId, usersToAdd, parallelism)
+	removed, removeErr := removeGroupMembers(ctx, client, groupId, usersToRemove, parallelism)
+	if err := errors.Join(addErr, removeErr); err != nil {
+		// record the memberships that were actually changed
+		users := oldSet.Difference(convertStringSliceToSet(removed)).Union(convertStringSliceToSet(added))
+		_ = d.Set("users", users)
+		return diag.FromErr(err)
 	}
 
 	return nil
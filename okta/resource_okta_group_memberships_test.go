@@ -1,11 +1,15 @@
 package okta
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccResourceOktaGroupMemberships_crud(t *testing.T) {
@@ -306,3 +310,51 @@ resource "okta_user" "test-%03d" {
 }
 `, i, i, i, i)
 }
+
+func TestAddRemoveGroupMembersAggregatesErrors(t *testing.T) {
+	server, config := newFakeOktaConfig(t)
+	ctx := context.TODO()
+	client := config.oktaSDKClientV2
+
+	group, _, err := client.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: "Engineering"}})
+	require.NoError(t, err)
+	var users []string
+	for i := 1; i <= 4; i++ {
+		login := fmt.Sprintf("user%d@example.com", i)
+		user, _, err := client.User.CreateUser(ctx, sdk.CreateUserRequest{Profile: &sdk.UserProfile{
+			"login":     login,
+			"email":     login,
+			"firstName": "User",
+			"lastName":  fmt.Sprint(i),
+		}}, nil)
+		require.NoError(t, err)
+		users = append(users, user.Id)
+	}
+	members := func() []string {
+		var ids []string
+		groupUsers, _, err := client.Group.ListGroupUsers(ctx, group.Id, nil)
+		require.NoError(t, err)
+		for _, user := range groupUsers {
+			ids = append(ids, user.Id)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	// a user that doesn't exist fails without stopping the others
+	requests := server.Requests()
+	added, err := addGroupMembers(ctx, client, group.Id, []string{users[0], users[1], "00ubad", users[2], users[3]}, 4)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "00ubad")
+	require.Equal(t, 5, server.Requests()-requests, "expected one add request per user")
+	sort.Strings(added)
+	require.Equal(t, users, added)
+	require.Equal(t, users, members())
+
+	// users that are gone count as removed
+	removed, err := removeGroupMembers(ctx, client, group.Id, []string{users[0], "00ugone", users[1]}, 2)
+	require.NoError(t, err)
+	sort.Strings(removed)
+	require.Equal(t, []string{users[0], users[1], "00ugone"}, removed)
+	require.Equal(t, users[2:], members())
+}
@@ -41,8 +41,9 @@ func resourceGroupRule() *schema.Resource {
 				Optional: true,
 			},
 			"expression_value": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: isOktaExpression,
 			},
 			"status": statusSchema,
 			"remove_assigned_users": {
@@ -57,9 +58,12 @@ func resourceGroupRule() *schema.Resource {
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 		},
-		CustomizeDiff: customdiff.ForceNewIf("status", func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
-			return statusIsInvalidDiffFn(d.Get("status").(string))
-		}),
+		CustomizeDiff: customdiff.All(
+			customdiff.ForceNewIf("status", func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
+				return statusIsInvalidDiffFn(d.Get("status").(string))
+			}),
+			expressionDiff("expression_value"),
+		),
 	}
 }
 
@@ -49,10 +49,25 @@ func resourceIdpOidc() *schema.Resource {
 				Description: "Unique identifier (opens new window)issued by the AS for the Okta IdP instance",
 			},
 			"client_secret": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Sensitive:   true,
-				Description: "Client secret issued (opens new window)by the AS for the Okta IdP instance",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"client_secret", "client_secret_wo"},
+				Description:  "Client secret issued (opens new window)by the AS for the Okta IdP instance",
+			},
+			"client_secret_wo": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				WriteOnly:    true,
+				RequiredWith: []string{"client_secret_wo_version"},
+				Description:  "Write-only client secret issued by the AS for the Okta IdP instance. It is never stored in the state, change `client_secret_wo_version` to update it. Requires Terraform 1.11 or later.",
+			},
+			"client_secret_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"client_secret_wo"},
+				Description:  "Version of `client_secret_wo`, a change of the version updates the client secret.",
 			},
 			"pkce_required": {
 				Type:        schema.TypeBool,
@@ -121,7 +136,9 @@ func resourceIdpRead(ctx context.Context, d *schema.ResourceData, m interface{})
 	_ = d.Set("subject_match_type", idp.Policy.Subject.MatchType)
 	_ = d.Set("username_template", idp.Policy.Subject.UserNameTemplate.Template)
 	_ = d.Set("issuer_url", idp.Protocol.Issuer.Url)
-	_ = d.Set("client_secret", idp.Protocol.Credentials.Client.ClientSecret)
+	if _, ok := d.GetOk("client_secret_wo_version"); !ok {
+		_ = d.Set("client_secret", idp.Protocol.Credentials.Client.ClientSecret)
+	}
 	_ = d.Set("client_id", idp.Protocol.Credentials.Client.ClientId)
 	if idp.Protocol.Credentials.Client.PKCERequired != nil {
 		_ = d.Set("pkce_required", idp.Protocol.Credentials.Client.PKCERequired)
@@ -138,7 +155,8 @@ func resourceIdpRead(ctx context.Context, d *schema.ResourceData, m interface{})
 	if idp.IssuerMode != "" {
 		_ = d.Set("issuer_mode", idp.IssuerMode)
 	}
-	mapping, _, err := getProfileMappingBySourceID(ctx, idp.Id, "", m)
+	mapping, resp, err := getProfileMappingBySourceID(ctx, idp.Id, "", m)
+	diags, err := suppressErrorOnPermission(ctx, "reading resource okta_idp_oidc.user_type_id", m, resp, err)
 	if err != nil {
 		return diag.Errorf("failed to get identity provider profile mapping: %v", err)
 	}
@@ -158,7 +176,7 @@ func resourceIdpRead(ctx context.Context, d *schema.ResourceData, m interface{})
 	if err != nil {
 		return diag.Errorf("failed to set OIDC identity provider properties: %v", err)
 	}
-	return nil
+	return diags
 }
 
 func resourceIdpUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -184,7 +202,7 @@ func buildIdPOidc(d *schema.ResourceData) (sdk.IdentityProvider, error) {
 	}
 	client := &sdk.IdentityProviderCredentialsClient{
 		ClientId:     d.Get("client_id").(string),
-		ClientSecret: d.Get("client_secret").(string),
+		ClientSecret: getSecret(d, "client_secret"),
 	}
 	pkceVal := d.GetRawConfig().GetAttr("pkce_required")
 	if !pkceVal.IsNull() {
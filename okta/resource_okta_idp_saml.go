@@ -148,7 +148,8 @@ func resourceIdpSamlRead(ctx context.Context, d *schema.ResourceData, m interfac
 		_ = d.Set("status", idp.Status)
 	}
 	mapping, resp, err := getProfileMappingBySourceID(ctx, idp.Id, "", m)
-	if err := suppressErrorOn401("resource okta_idp_saml.user_type_id", m, resp, err); err != nil {
+	diags, err := suppressErrorOnPermission(ctx, "reading resource okta_idp_saml.user_type_id", m, resp, err)
+	if err != nil {
 		return diag.Errorf("failed to get SAML identity provider profile mapping: %v", err)
 	}
 	if mapping != nil {
@@ -167,7 +168,7 @@ func resourceIdpSamlRead(ctx context.Context, d *schema.ResourceData, m interfac
 	if err != nil {
 		return diag.Errorf("failed to set SAML identity provider properties: %v", err)
 	}
-	return nil
+	return diags
 }
 
 func resourceIdpSamlUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
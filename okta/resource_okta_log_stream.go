@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -13,8 +15,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
-	"github.com/okta/okta-sdk-golang/v3/okta"
 )
 
 const (
@@ -31,26 +31,15 @@ var (
 	splunkHostRegex                    = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*\.splunkcloud(\.gc\.com|\.fed\.com|\.com|\.mil)$`)
 )
 
+var (
+	_ resource.ResourceWithImportState    = &logStreamResource{}
+	_ resource.ResourceWithValidateConfig = &logStreamResource{}
+)
+
 type logStreamResource struct {
 	*Config
 }
 
-type logStreamModel struct {
-	ID       types.String `tfsdk:"id"`
-	Name     types.String `tfsdk:"name"`
-	Type     types.String `tfsdk:"type"`
-	Status   types.String `tfsdk:"status"`
-	Settings types.Object `tfsdk:"settings"`
-}
-type logStreamSettingsModel struct {
-	AccountID       types.String `tfsdk:"account_id"`
-	EventSourceName types.String `tfsdk:"event_source_name"`
-	Region          types.String `tfsdk:"region"`
-	Edition         types.String `tfsdk:"edition"`
-	Host            types.String `tfsdk:"host"`
-	Token           types.String `tfsdk:"token"`
-}
-
 func NewLogStreamResource() resource.Resource {
 	return &logStreamResource{}
 }
@@ -60,6 +49,19 @@ func (r *logStreamResource) Metadata(_ context.Context, req resource.MetadataReq
 }
 
 func (r *logStreamResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	blocks := make(map[string]schema.Block)
+	for _, typeName := range sortedLogStreamTypes() {
+		blocks[typeName] = schema.SingleNestedBlock{
+			Description: fmt.Sprintf("Settings of %s log streams, required when type is '%s'", logStreamTypes[typeName].title, typeName),
+			Attributes:  logStreamSettingsAttributes(typeName),
+		}
+	}
+	blocks[logStreamLegacySettings] = schema.SingleNestedBlock{
+		Description:        "Settings of the log stream, use the block named after the type of the log stream instead",
+		DeprecationMessage: "Use the block named after the type of the log stream instead, e.g. aws_eventbridge or splunk_cloud_logstreaming",
+		Attributes:         logStreamSettingsAttributes(logStreamLegacySettings),
+	}
+
 	resp.Schema = schema.Schema{
 		Description: "Manages log streams",
 		Attributes: map[string]schema.Attribute{
@@ -75,22 +77,23 @@ func (r *logStreamResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Required:    true,
 			},
 			"type": schema.StringAttribute{
-				Description: "Streaming provider used - 'aws_eventbridge' or 'splunk_cloud_logstreaming'",
+				Description: "Streaming provider used - " + logStreamTypesDescription(),
 				Required:    true,
 				PlanModifiers: []planmodifier.String{
 					// force new
 					stringplanmodifier.RequiresReplace(),
 				},
 				Validators: []validator.String{
-					stringvalidator.OneOf([]string{
-						logStreamTypeEventBridge,
-						logStreamTypeSplunk,
-					}...),
+					stringvalidator.OneOf(sortedLogStreamTypes()...),
 				},
 			},
 			"status": schema.StringAttribute{
-				Description: "Stream status",
+				Description: "Stream status, the log stream is activated or deactivated in place",
 				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 				Validators: []validator.String{
 					stringvalidator.OneOf([]string{
 						statusActive,
@@ -99,77 +102,61 @@ func (r *logStreamResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				},
 			},
 		},
-		Blocks: map[string]schema.Block{
-			"settings": schema.SingleNestedBlock{
-				Attributes: map[string]schema.Attribute{
-					"account_id": schema.StringAttribute{
-						Description: "AWS account ID. Required only for 'aws_eventbridge' type",
-						Optional:    true,
-						PlanModifiers: []planmodifier.String{
-							stringplanmodifier.RequiresReplace(),
-						},
-						Validators: []validator.String{
-							stringvalidator.LengthBetween(12, 12),
-						},
-					},
-					"event_source_name": schema.StringAttribute{
-						Description: "An alphanumeric name (no spaces) to identify this event source in AWS EventBridge. Required only for 'aws_eventbridge' type",
-						Optional:    true,
-						PlanModifiers: []planmodifier.String{
-							// force new
-							stringplanmodifier.RequiresReplace(),
-						},
-						Validators: []validator.String{
-							stringvalidator.RegexMatches(awsEventBridgeEventSourceNameRegex, "Event Source must have an alphanumeric name (no spaces) shorter than 76 characters"),
-						},
-					},
-					"region": schema.StringAttribute{
-						Description: "The destination AWS region where event source is located. Required only for 'aws_eventbridge' type",
-						Optional:    true,
-						PlanModifiers: []planmodifier.String{
-							// force new
-							stringplanmodifier.RequiresReplace(),
-						},
-					},
-					"edition": schema.StringAttribute{
-						Description: "Edition of the Splunk Cloud instance. Could be one of: 'aws', 'aws_govcloud', 'gcp'. Required only for 'splunk_cloud_logstreaming' type",
-						Optional:    true,
-						Validators: []validator.String{
-							stringvalidator.OneOf([]string{
-								logStreamSplunkEditionAws,
-								logStreamSplunkEditionAwsGovCloud,
-								logStreamSplunkEditionGcp,
-							}...),
-						},
-					},
-					"host": schema.StringAttribute{
-						Description: "The domain name for Splunk Cloud instance. Don't include http or https in the string. For example: 'acme.splunkcloud.com'. Required only for 'splunk_cloud_logstreaming' type",
-						Optional:    true,
-						Validators: []validator.String{
-							stringvalidator.RegexMatches(
-								splunkHostRegex,
-								"Splunk host must match the pattern: `^(?!(?:http-inputs-))([a-z0-9]+(-[a-z0-9]+)*){1,100}\\\\.splunkcloud(gc\\\\.com|fed\\\\.com|\\\\.com|\\\\.mil)$`",
-							),
-						},
-					},
-					"token": schema.StringAttribute{
-						Description: "The HEC token for your Splunk Cloud HTTP Event Collector. Required only for 'splunk_cloud_logstreaming' type",
-						Optional:    true,
-						Sensitive:   true,
-						PlanModifiers: []planmodifier.String{
-							// force new
-							stringplanmodifier.RequiresReplace(),
-						},
-						Validators: []validator.String{
-							stringvalidator.RegexMatches(
-								splunkTokenRegex,
-								"Splunk token must match the pattern: `(?i)^[0-9a-f]{8}-[0-9a-f]{4}-[1-5][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`",
-							),
-						},
-					},
-				},
-			},
-		},
+		Blocks: blocks,
+	}
+}
+
+// logStreamSettingsAttributes returns the attributes of a settings block.
+func logStreamSettingsAttributes(block string) map[string]schema.Attribute {
+	attrs := make(map[string]schema.Attribute)
+	for name, setting := range logStreamBlockSettings(block) {
+		description := setting.description
+		if block == logStreamLegacySettings {
+			typeName, _ := logStreamLegacySetting(name)
+			description = fmt.Sprintf("%s. Required only for '%s' type", description, typeName)
+		}
+		attribute := schema.StringAttribute{
+			Description: description,
+			Optional:    true,
+			Sensitive:   setting.secret,
+			Validators:  setting.validators,
+		}
+		if setting.forceNew {
+			attribute.PlanModifiers = []planmodifier.String{
+				stringplanmodifier.RequiresReplaceIf(
+					logStreamSettingRequiresReplace(name, setting),
+					"Changing the setting replaces the log stream",
+					"Changing the setting replaces the log stream",
+				),
+			}
+		}
+		attrs[name] = attribute
+	}
+	return attrs
+}
+
+// logStreamSettingRequiresReplace replaces the log stream when the value of
+// the setting changes, wherever it was set before, so settings can be moved
+// from the deprecated settings block to the block of the type in place. An
+// imported log stream has no secret settings as Okta doesn't return them,
+// they are set from the configuration without replacing the log stream.
+func logStreamSettingRequiresReplace(name string, setting logStreamSetting) stringplanmodifier.RequiresReplaceIfFunc {
+	return func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+		previous := types.StringNull()
+		for _, block := range logStreamBlocks() {
+			var v types.String
+			if diags := req.State.GetAttribute(ctx, path.Root(block).AtName(name), &v); diags.HasError() {
+				continue
+			}
+			if !v.IsNull() {
+				previous = v
+				break
+			}
+		}
+		if previous.IsNull() && setting.secret {
+			return
+		}
+		resp.RequiresReplace = !req.PlanValue.Equal(previous)
 	}
 }
 
@@ -177,23 +164,22 @@ func (r *logStreamResource) Configure(_ context.Context, req resource.ConfigureR
 	r.Config = resourceConfiguration(req, resp)
 }
 
-func (r *logStreamResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var state logStreamModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &state)...)
+func (r *logStreamResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	data, diags := getLogStreamModel(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	logStreamCreateRequest := r.oktaSDKClientV3.LogStreamAPI.CreateLogStream(ctx)
-	logStreamCreateRequestBody := buildLogStreamCreateBody(ctx, &state)
-	if logStreamCreateRequestBody == nil {
-		resp.Diagnostics.AddError(
-			"failed to build log stream create request",
-			fmt.Sprintf("unknown type %q", state.Type.ValueString()),
-		)
+	resp.Diagnostics.Append(validateLogStream(data)...)
+}
+
+func (r *logStreamResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	state, diags := getLogStreamModel(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	logStreamCreateRequest = logStreamCreateRequest.Instance(*logStreamCreateRequestBody)
-	logStreamResp, _, err := logStreamCreateRequest.Execute()
+	logStream, err := createLogStream(ctx, r.Config, buildLogStream(state, false))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"failed to create log stream",
@@ -201,12 +187,10 @@ func (r *logStreamResource) Create(ctx context.Context, req resource.CreateReque
 		)
 		return
 	}
-	logStream, err := normalizeLogSteamResponse(logStreamResp)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"failed to normalize log stream",
-			err.Error(),
-		)
+	applyLogStreamToModel(logStream, state)
+	// save the log stream so it isn't lost if setting its status fails
+	resp.Diagnostics.Append(setLogStreamModel(ctx, &resp.State, state)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -214,47 +198,23 @@ func (r *logStreamResource) Create(ctx context.Context, req resource.CreateReque
 	// stream when it is created. Therefore, we need to compare the operator's
 	// intentions in the plan with the API result. See Create Log Stream:
 	// https://developer.okta.com/docs/api/openapi/okta-management/management/tag/LogStream/#tag/LogStream/operation/createLogStream
-	planStatus := state.Status.ValueString()
-	if planStatus != logStream.Status {
-		if planStatus == statusActive {
-			_, _, err = r.oktaSDKClientV3.LogStreamAPI.ActivateLogStream(ctx, logStream.Id).Execute()
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"failed to activate log stream",
-					err.Error(),
-				)
-				return
-			}
-			logStream.Status = statusActive
-		}
-		if planStatus == statusInactive {
-			_, _, err = r.oktaSDKClientV3.LogStreamAPI.DeactivateLogStream(ctx, logStream.Id).Execute()
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"failed to deactivate log stream",
-					err.Error(),
-				)
-				return
-			}
-			logStream.Status = statusInactive
-		}
-	}
-
-	applyLogStreamToState(ctx, logStream, &state)
+	var planStatus types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("status"), &planStatus)...)
+	resp.Diagnostics.Append(r.setStatus(ctx, planStatus, state)...)
 
 	// need to set the "new" state of the log stream model, TF runtime does
 	// change detection there
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setLogStreamModel(ctx, &resp.State, state)...)
 	// don't need to check for error, we are returning already
 }
 
 func (r *logStreamResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var data logStreamModel
-	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	data, diags := getLogStreamModel(ctx, req.State)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	logStreamResp, _, err := r.oktaSDKClientV3.LogStreamAPI.GetLogStream(ctx, data.ID.ValueString()).Execute()
+	logStream, err := getLogStream(ctx, r.Config, data.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"failed to get log stream",
@@ -262,259 +222,121 @@ func (r *logStreamResource) Read(ctx context.Context, req resource.ReadRequest,
 		)
 		return
 	}
-	logStream, err := normalizeLogSteamResponse(logStreamResp)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"failed to format log stream",
-			err.Error(),
-		)
-		return
-	}
-	applyLogStreamToState(ctx, logStream, &data)
+	applyLogStreamToModel(logStream, data)
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(setLogStreamModel(ctx, &resp.State, data)...)
 }
+
 func (r *logStreamResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var state logStreamModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &state)...)
+	state, diags := getLogStreamModel(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	prior, diags := getLogStreamModel(ctx, req.State)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	logStreamReplaceRequest := r.oktaSDKClientV3.LogStreamAPI.ReplaceLogStream(ctx, state.ID.ValueString())
-	logStreamReplaceBody := buildLogStreamReplaceBody(ctx, &state)
-	if logStreamReplaceBody == nil {
-		resp.Diagnostics.AddError(
-			"failed to build log stream replace request",
-			fmt.Sprintf("unknown type %q", state.Type.ValueString()),
-		)
-		return
-	}
-	logStreamReplaceRequest = logStreamReplaceRequest.Instance(*logStreamReplaceBody)
-	logStreamResp, _, err := logStreamReplaceRequest.Execute()
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"failed to replace log stream",
-			err.Error(),
-		)
-		return
-	}
-	logStream, err := normalizeLogSteamResponse(logStreamResp)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"failed to normalize log stream",
-			err.Error(),
-		)
-		return
-	}
 
-	// NOTE: Okta API doesn't allow directly setting the status on the log
-	// stream when it is created. Therefore, we need to compare the operator's
-	// intentions in the plan with the API result. See Create Log Stream:
-	// https://developer.okta.com/docs/api/openapi/okta-management/management/tag/LogStream/#tag/LogStream/operation/createLogStream
-	planStatus := state.Status.ValueString()
-	if planStatus != logStream.Status {
-		if planStatus == statusActive {
-			_, _, err = r.oktaSDKClientV3.LogStreamAPI.ActivateLogStream(ctx, logStream.Id).Execute()
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"failed to activate log stream",
-					err.Error(),
-				)
-				return
-			}
-			logStream.Status = statusActive
-		}
-		if planStatus == statusInactive {
-			_, _, err = r.oktaSDKClientV3.LogStreamAPI.DeactivateLogStream(ctx, logStream.Id).Execute()
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"failed to deactivate log stream",
-					err.Error(),
-				)
-				return
-			}
-			logStream.Status = statusInactive
+	// only the status changing, the log stream is activated or deactivated
+	// without replacing it
+	body := buildLogStream(state, true)
+	priorBody := buildLogStream(prior, true)
+	logStream := &apiLogStream{
+		ID:       prior.ID.ValueString(),
+		Name:     prior.Name.ValueString(),
+		Type:     prior.Type.ValueString(),
+		Status:   prior.Status.ValueString(),
+		Settings: priorBody.Settings,
+	}
+	if body.Name != priorBody.Name || !logStreamSettingsEqual(body.Settings, priorBody.Settings) {
+		var err error
+		logStream, err = replaceLogStream(ctx, r.Config, state.ID.ValueString(), body)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"failed to replace log stream",
+				err.Error(),
+			)
+			return
 		}
 	}
+	applyLogStreamToModel(logStream, state)
 
-	applyLogStreamToState(ctx, logStream, &state)
+	var planStatus types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("status"), &planStatus)...)
+	resp.Diagnostics.Append(r.setStatus(ctx, planStatus, state)...)
 
 	// need to set the "new" state of the log stream model, TF runtime does
 	// change detection there
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setLogStreamModel(ctx, &resp.State, state)...)
 	// don't need to check for error, we are returning already
-
 }
 
-func (r *logStreamResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data logStreamModel
-	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	_, _, err := r.oktaSDKClientV3.LogStreamAPI.DeactivateLogStream(ctx, data.ID.ValueString()).Execute()
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"failed to deactivate log stream",
-			err.Error(),
-		)
-		return
+// setStatus activates or deactivates the log stream when its status differs
+// from the planned status, an unknown status is left as it is.
+func (r *logStreamResource) setStatus(ctx context.Context, planStatus types.String, state *logStreamModel) (diags diag.Diagnostics) {
+	status := planStatus.ValueString()
+	if planStatus.IsUnknown() || planStatus.IsNull() || status == state.Status.ValueString() {
+		return nil
 	}
-	_, err = r.oktaSDKClientV3.LogStreamAPI.DeleteLogStream(ctx, data.ID.ValueString()).Execute()
+	_, err := setLogStreamStatus(ctx, r.Config, state.ID.ValueString(), status)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"failed to delete log stream",
+		action := "activate"
+		if status == statusInactive {
+			action = "deactivate"
+		}
+		diags.AddError(
+			fmt.Sprintf("failed to %s log stream", action),
 			err.Error(),
 		)
-		return
-	}
-}
-
-func (r *logStreamResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
-}
-
-func applyLogStreamToState(ctx context.Context, ls *providerLogStream, m *logStreamModel) {
-	m.ID = types.StringValue(ls.Id)
-	m.Name = types.StringValue(ls.Name)
-	m.Status = types.StringValue(ls.Status)
-	m.Type = types.StringValue(ls.Type)
-
-	settings := &logStreamSettingsModel{}
-	m.Settings.As(ctx, settings, basetypes.ObjectAsOptions{})
-
-	if ls.Settings.AccountID != "" {
-		settings.AccountID = types.StringValue(ls.Settings.AccountID)
-	}
-	if ls.Settings.EventSourceName != "" {
-		settings.EventSourceName = types.StringValue(ls.Settings.EventSourceName)
-	}
-	if ls.Settings.Region != "" {
-		settings.Region = types.StringValue(ls.Settings.Region)
-	}
-	if ls.Settings.Edition != "" {
-		settings.Edition = types.StringValue(ls.Settings.Edition)
-	}
-	if ls.Settings.Host != "" {
-		settings.Host = types.StringValue(ls.Settings.Host)
-	}
-	if ls.Settings.Token != "" {
-		settings.Token = types.StringValue(ls.Settings.Token)
+		return diags
 	}
+	state.Status = types.StringValue(status)
+	return nil
 }
 
-func buildLogStreamCreateBody(ctx context.Context, m *logStreamModel) *okta.ListLogStreams200ResponseInner {
-	_type := m.Type.ValueString()
-	ls := okta.LogStream{
-		Id:     m.ID.ValueString(),
-		Name:   m.Name.ValueString(),
-		Status: m.Status.ValueString(),
-		Type:   okta.LogStreamType(m.Type.ValueString()),
+func logStreamSettingsEqual(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
 	}
-
-	settings := &logStreamSettingsModel{}
-	m.Settings.As(ctx, settings, basetypes.ObjectAsOptions{})
-
-	switch _type {
-	case logStreamTypeEventBridge:
-		var settingAws okta.LogStreamSettingsAws
-		settingAws.AccountId = settings.AccountID.ValueString()
-		settingAws.EventSourceName = settings.EventSourceName.ValueString()
-		settingAws.Region = okta.AwsRegion(settings.Region.ValueString())
-		return &okta.ListLogStreams200ResponseInner{
-			LogStreamAws: &okta.LogStreamAws{
-				LogStream: ls,
-				Settings:  settingAws,
-			},
-		}
-	case logStreamTypeSplunk:
-		var settingSplunk okta.LogStreamSettingsSplunk
-		settingSplunk.Edition = okta.SplunkEdition(settings.Edition.ValueString())
-		settingSplunk.Host = settings.Host.ValueString()
-		settingSplunk.Token = settings.Token.ValueString()
-		return &okta.ListLogStreams200ResponseInner{
-			LogStreamSplunk: &okta.LogStreamSplunk{
-				LogStream: ls,
-				Settings:  settingSplunk,
-			},
+	for k, v := range a {
+		if fmt.Sprint(v) != fmt.Sprint(b[k]) {
+			return false
 		}
 	}
-	return nil
+	return true
 }
 
-func buildLogStreamReplaceBody(ctx context.Context, m *logStreamModel) *okta.ReplaceLogStreamRequest {
-	_type := m.Type.ValueString()
-	ls := okta.LogStreamPutSchema{
-		Name: m.Name.ValueString(),
-		Type: okta.LogStreamType(m.Type.ValueString()),
+func (r *logStreamResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var id types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	settings := &logStreamSettingsModel{}
-	m.Settings.As(ctx, settings, basetypes.ObjectAsOptions{})
-
-	switch _type {
-	case logStreamTypeEventBridge:
-		var settingAws okta.LogStreamSettingsAws
-		settingAws.AccountId = settings.AccountID.ValueString()
-		settingAws.EventSourceName = settings.EventSourceName.ValueString()
-		settingAws.Region = okta.AwsRegion(settings.Region.ValueString())
-		return &okta.ReplaceLogStreamRequest{
-			LogStreamAwsPutSchema: &okta.LogStreamAwsPutSchema{
-				LogStreamPutSchema: ls,
-				Settings:           settingAws,
-			},
-		}
-	case logStreamTypeSplunk:
-		var settingSplunk okta.LogStreamSettingsSplunkPut
-		settingSplunk.Edition = okta.SplunkEdition(settings.Edition.ValueString())
-		settingSplunk.Host = settings.Host.ValueString()
-		return &okta.ReplaceLogStreamRequest{
-			LogStreamSplunkPutSchema: &okta.LogStreamSplunkPutSchema{
-				LogStreamPutSchema: ls,
-				Settings:           settingSplunk,
-			},
-		}
+	err := deleteLogStream(ctx, r.Config, id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"failed to delete log stream",
+			err.Error(),
+		)
+		return
 	}
-	return nil
 }
 
-type providerLogStream struct {
-	Id       string
-	Name     string
-	Status   string
-	Type     string
-	Settings struct {
-		AccountID       string
-		EventSourceName string
-		Region          string
-		Edition         string
-		Host            string
-		Token           string
+// ImportState imports a log stream by ID, or by name when the import ID is
+// written "name:<name>".
+func (r *logStreamResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	name, ok := strings.CutPrefix(req.ID, "name:")
+	if !ok {
+		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+		return
 	}
-}
-
-func normalizeLogSteamResponse(resp *okta.ListLogStreams200ResponseInner) (*providerLogStream, error) {
-	ls := providerLogStream{}
-	if resp.LogStreamAws != nil {
-		ls.Id = resp.LogStreamAws.Id
-		ls.Name = resp.LogStreamAws.Name
-		ls.Status = resp.LogStreamAws.Status
-		ls.Type = string(resp.LogStreamAws.Type)
-		ls.Settings.AccountID = resp.LogStreamAws.Settings.AccountId
-		ls.Settings.EventSourceName = resp.LogStreamAws.Settings.EventSourceName
-		ls.Settings.Region = string(resp.LogStreamAws.Settings.Region)
-	} else if resp.LogStreamSplunk != nil {
-		ls.Id = resp.LogStreamSplunk.Id
-		ls.Name = resp.LogStreamSplunk.Name
-		ls.Status = resp.LogStreamSplunk.Status
-		ls.Type = string(resp.LogStreamSplunk.Type)
-		ls.Settings.Edition = string(resp.LogStreamSplunk.Settings.Edition)
-		ls.Settings.Host = resp.LogStreamSplunk.Settings.Host
-		ls.Settings.Token = resp.LogStreamSplunk.Settings.Token
-	} else {
-		return nil, fmt.Errorf("log stream is type other than aws or splunk, this is a provider bug")
+	logStream, err := findLogStreamByName(ctx, r.Config, name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"failed to import log stream",
+			err.Error(),
+		)
+		return
 	}
-
-	return &ls, nil
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), logStream.ID)...)
 }
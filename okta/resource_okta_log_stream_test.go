@@ -26,9 +26,12 @@ func TestAccResourceOktaLogStream_crud(t *testing.T) {
 		//   Step 1:
 		//     AWS log stream is created in an active status and Splunk log stream is created in an inactive status
 		//   Step 2:
-		//     Names and status are toggled
+		//     Names and status are toggled, the Splunk settings move from the
+		//     deprecated settings block to the splunk_cloud_logstreaming block
 		//   Step 3:
 		//     Import check
+		//   Step 4:
+		//     Import by name check
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -53,6 +56,8 @@ func TestAccResourceOktaLogStream_crud(t *testing.T) {
 					resource.TestCheckResourceAttr(splunkResourceName, "name", buildResourceName(mgr.Seed)+" Splunk Updated"),
 					resource.TestCheckResourceAttr(splunkResourceName, "type", "splunk_cloud_logstreaming"),
 					resource.TestCheckResourceAttr(splunkResourceName, "status", "ACTIVE"),
+					resource.TestCheckResourceAttr(splunkResourceName, "splunk_cloud_logstreaming.host", "acme.splunkcloud.com"),
+					resource.TestCheckResourceAttr(awsEventBridgeResourceName, "aws_eventbridge.region", "eu-west-3"),
 				),
 			},
 			{
@@ -80,6 +85,19 @@ func TestAccResourceOktaLogStream_crud(t *testing.T) {
 					return nil
 				},
 			},
+			{
+				ResourceName: awsEventBridgeResourceName,
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources[awsEventBridgeResourceName]
+					if !ok {
+						return "", fmt.Errorf("failed to find %s", awsEventBridgeResourceName)
+					}
+
+					return "name:" + rs.Primary.Attributes["name"], nil
+				},
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -151,27 +151,43 @@ func resourceOrgSettingsCreate(ctx context.Context, d *schema.ResourceData, m in
 }
 
 func resourceOrgSettingsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	settings, _, err := getOktaClientFromMetadata(m).OrgSetting.GetOrgSettings(ctx)
+	settings, resp, err := getOktaClientFromMetadata(m).OrgSetting.GetOrgSettings(ctx)
+	warnings, err := suppressErrorOnPermission(ctx, "reading resource okta_org_configuration", m, resp, err)
 	if err != nil {
 		return diag.Errorf("failed to get org settings: %v", err)
 	}
+	if warnings != nil {
+		return warnings
+	}
 	setOrgSettings(d, settings)
-	comm, _, err := getOktaClientFromMetadata(m).OrgSetting.GetOktaCommunicationSettings(ctx)
+	comm, resp, err := getOktaClientFromMetadata(m).OrgSetting.GetOktaCommunicationSettings(ctx)
+	diags, err := suppressErrorOnPermission(ctx, "reading resource okta_org_configuration.opt_out_communication_emails", m, resp, err)
 	if err != nil {
 		return diag.Errorf("failed to get org communication settings: %v", err)
 	}
-	_ = d.Set("opt_out_communication_emails", comm.OptOutEmailUsers)
-	billingContact, _, err := getOktaClientFromMetadata(m).OrgSetting.GetOrgContactUser(ctx, "BILLING")
+	warnings = append(warnings, diags...)
+	if comm != nil {
+		_ = d.Set("opt_out_communication_emails", comm.OptOutEmailUsers)
+	}
+	billingContact, resp, err := getOktaClientFromMetadata(m).OrgSetting.GetOrgContactUser(ctx, "BILLING")
+	diags, err = suppressErrorOnPermission(ctx, "reading resource okta_org_configuration.billing_contact_user", m, resp, err)
 	if err != nil {
 		return diag.Errorf("failed to get billing contact user: %v", err)
 	}
-	_ = d.Set("billing_contact_user", billingContact.UserId)
-	technicalContact, _, err := getOktaClientFromMetadata(m).OrgSetting.GetOrgContactUser(ctx, "TECHNICAL")
+	warnings = append(warnings, diags...)
+	if billingContact != nil {
+		_ = d.Set("billing_contact_user", billingContact.UserId)
+	}
+	technicalContact, resp, err := getOktaClientFromMetadata(m).OrgSetting.GetOrgContactUser(ctx, "TECHNICAL")
+	diags, err = suppressErrorOnPermission(ctx, "reading resource okta_org_configuration.technical_contact_user", m, resp, err)
 	if err != nil {
 		return diag.Errorf("failed to get technical contact user: %v", err)
 	}
-	_ = d.Set("technical_contact_user", technicalContact.UserId)
-	return nil
+	warnings = append(warnings, diags...)
+	if technicalContact != nil {
+		_ = d.Set("technical_contact_user", technicalContact.UserId)
+	}
+	return warnings
 }
 
 func resourceOrgSettingsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
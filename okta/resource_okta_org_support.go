@@ -57,10 +57,14 @@ func resourceOrgSupportCreate(ctx context.Context, d *schema.ResourceData, m int
 }
 
 func resourceOrgSupportRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	support, _, err := getOktaClientFromMetadata(m).OrgSetting.GetOrgOktaSupportSettings(ctx)
+	support, resp, err := getOktaClientFromMetadata(m).OrgSetting.GetOrgOktaSupportSettings(ctx)
+	warnings, err := suppressErrorOnPermission(ctx, "reading resource okta_org_support", m, resp, err)
 	if err != nil {
 		return diag.Errorf("failed to get org support settings: %v", err)
 	}
+	if warnings != nil {
+		return warnings
+	}
 	if support.Expiration != nil {
 		_ = d.Set("expiration", support.Expiration.String())
 	}
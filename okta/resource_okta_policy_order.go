@@ -0,0 +1,187 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+// orderedPolicyTypes are the types of policies with priorities
+var orderedPolicyTypes = []string{
+	sdk.SignOnPolicyType,
+	sdk.PasswordPolicyType,
+	sdk.MfaPolicyType,
+	sdk.IdpDiscoveryType,
+	sdk.ProfileEnrollmentPolicyType,
+}
+
+func resourcePolicyOrder() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePolicyOrderCreate,
+		ReadContext:   resourcePolicyOrderRead,
+		UpdateContext: resourcePolicyOrderUpdate,
+		DeleteContext: resourcePolicyOrderDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				if !contains(orderedPolicyTypes, d.Id()) {
+					return nil, fmt.Errorf("invalid policy order specifier. Expecting one of the policy types %v", orderedPolicyTypes)
+				}
+				_ = d.Set("type", d.Id())
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: stringInSlice(orderedPolicyTypes),
+				Description:      fmt.Sprintf("Type of the policies: %v", orderedPolicyTypes),
+			},
+			"policy_ids": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of all the non-default policies of the type in priority order, the first policy has the highest priority. The default policy stays the last one",
+			},
+		},
+	}
+}
+
+func resourcePolicyOrderCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	policyType := d.Get("type").(string)
+	logger(m).Info("ordering policies", "type", policyType)
+	if err := applyPolicyOrder(ctx, m, policyType, convertInterfaceToStringArr(d.Get("policy_ids"))); err != nil {
+		return diag.Errorf("failed to order policies: %v", err)
+	}
+	d.SetId(policyType)
+	return resourcePolicyOrderRead(ctx, d, m)
+}
+
+func resourcePolicyOrderRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	policies, err := listPoliciesByPriority(ctx, m, d.Get("type").(string))
+	if err != nil {
+		return diag.Errorf("failed to list policies: %v", err)
+	}
+	// the resource owns the complete ordering, a policy added or reordered
+	// outside of terraform is a drift
+	var ids []string
+	for _, policy := range policies {
+		if !jsonIsSystem(policy) {
+			ids = append(ids, getMapString(policy, "id"))
+		}
+	}
+	_ = d.Set("policy_ids", convertStringSliceToInterfaceSlice(ids))
+	return nil
+}
+
+func resourcePolicyOrderUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	policyType := d.Get("type").(string)
+	logger(m).Info("ordering policies", "type", policyType)
+	if err := applyPolicyOrder(ctx, m, policyType, convertInterfaceToStringArr(d.Get("policy_ids"))); err != nil {
+		return diag.Errorf("failed to order policies: %v", err)
+	}
+	return resourcePolicyOrderRead(ctx, d, m)
+}
+
+// resourcePolicyOrderDelete only removes the resource from the state, the
+// policies keep their order.
+func resourcePolicyOrderDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return nil
+}
+
+// applyPolicyOrder reconciles the priorities of the policies of the type in
+// one pass with the minimal number of priority updates. Every non-default
+// policy must be listed, the default policy is kept last.
+func applyPolicyOrder(ctx context.Context, m interface{}, policyType string, policyIDs []string) error {
+	policies, err := listPoliciesByPriority(ctx, m, policyType)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]map[string]interface{}, len(policies))
+	current := make([]string, len(policies))
+	for i, policy := range policies {
+		current[i] = getMapString(policy, "id")
+		byID[current[i]] = policy
+	}
+
+	target := make([]string, 0, len(policies))
+	for _, id := range policyIDs {
+		policy, ok := byID[id]
+		switch {
+		case !ok:
+			return fmt.Errorf("policy '%s' isn't a %s policy", id, policyType)
+		case jsonIsSystem(policy):
+			return fmt.Errorf("policy '%s' is the default %s policy, it's always the last one", id, policyType)
+		case contains(target, id):
+			return fmt.Errorf("policy '%s' is listed more than once", id)
+		}
+		target = append(target, id)
+	}
+	var missing []string
+	for _, id := range current {
+		if !contains(target, id) && !jsonIsSystem(byID[id]) {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("'policy_ids' should list all the non-default %s policies, missing: %s", policyType, strings.Join(missing, ", "))
+	}
+	for _, id := range current {
+		if jsonIsSystem(byID[id]) {
+			target = append(target, id)
+		}
+	}
+
+	for _, move := range priorityMoves(current, target) {
+		logger(m).Debug("setting policy priority", "policy_id", move.ID, "priority", move.Priority)
+		if err := updatePolicyPriority(ctx, m, byID[move.ID], move.Priority); err != nil {
+			return fmt.Errorf("failed to set the priority of policy '%s': %v", move.ID, err)
+		}
+	}
+	return nil
+}
+
+// listPoliciesByPriority lists the policies of the type as raw JSON objects,
+// so they can be updated whatever their type, ordered by priority.
+func listPoliciesByPriority(ctx context.Context, m interface{}, policyType string) ([]map[string]interface{}, error) {
+	re := getRequestExecutor(m)
+	req, err := re.WithAccept("application/json").WithContentType("application/json").
+		NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/policies?type=%s&limit=%d", url.QueryEscape(policyType), defaultPaginationLimit), nil)
+	if err != nil {
+		return nil, err
+	}
+	var policies []map[string]interface{}
+	resp, err := re.Do(ctx, req, &policies)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var additionalPolicies []map[string]interface{}
+		resp, err = resp.Next(ctx, &additionalPolicies)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, additionalPolicies...)
+	}
+	sortByPriority(policies)
+	return policies, nil
+}
+
+func updatePolicyPriority(ctx context.Context, m interface{}, policy map[string]interface{}, priority int) error {
+	re := getRequestExecutor(m)
+	req, err := re.WithAccept("application/json").WithContentType("application/json").
+		NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/policies/%s", getMapString(policy, "id")), priorityUpdateBody(policy, priority))
+	if err != nil {
+		return err
+	}
+	_, err = re.Do(ctx, req, nil)
+	return err
+}
@@ -0,0 +1,74 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyOrder(t *testing.T) {
+	server, config := newFakeOktaConfig(t)
+	ctx := context.TODO()
+	createPolicy := func(name string) string {
+		return createFakeOktaObject(t, config, "/api/v1/policies", map[string]interface{}{
+			"type": sdk.PasswordPolicyType,
+			"name": name,
+		})
+	}
+	var policyIDs []string
+	for i := 1; i <= 3; i++ {
+		policyIDs = append(policyIDs, createPolicy(fmt.Sprintf("Policy %d", i)))
+	}
+	order := func() []string {
+		policies, err := listPoliciesByPriority(ctx, config, sdk.PasswordPolicyType)
+		require.NoError(t, err)
+		var names []string
+		for _, policy := range policies {
+			names = append(names, getMapString(policy, "name"))
+		}
+		return names
+	}
+
+	r := resourcePolicyOrder()
+	d := r.TestResourceData()
+	_ = d.Set("type", sdk.PasswordPolicyType)
+	_ = d.Set("policy_ids", []interface{}{policyIDs[2], policyIDs[0], policyIDs[1]})
+	requests := server.Requests()
+	diags := r.CreateContext(ctx, d, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	// listing the policies, one priority update and reading the policies
+	require.Equal(t, 3, server.Requests()-requests)
+	require.Equal(t, []string{"Policy 3", "Policy 1", "Policy 2", "Default Policy"}, order())
+	require.Equal(t, sdk.PasswordPolicyType, d.Id())
+
+	// a policy added for a new business unit is inserted with a single update
+	policyIDs = append(policyIDs, createPolicy("Policy 4"))
+	diags = r.ReadContext(ctx, d, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, []string{policyIDs[2], policyIDs[0], policyIDs[1], policyIDs[3]}, convertInterfaceToStringArr(d.Get("policy_ids")))
+	_ = d.Set("policy_ids", []interface{}{policyIDs[2], policyIDs[3], policyIDs[0], policyIDs[1]})
+	requests = server.Requests()
+	diags = r.UpdateContext(ctx, d, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, 3, server.Requests()-requests)
+	require.Equal(t, []string{"Policy 3", "Policy 4", "Policy 1", "Policy 2", "Default Policy"}, order())
+
+	// a policy that isn't listed isn't silently ordered
+	_ = d.Set("policy_ids", []interface{}{policyIDs[2], policyIDs[0], policyIDs[1]})
+	requests = server.Requests()
+	diags = r.UpdateContext(ctx, d, config)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Summary, "missing: "+policyIDs[3])
+	require.Equal(t, 1, server.Requests()-requests)
+
+	// the default policy is pinned last
+	policies, err := listPoliciesByPriority(ctx, config, sdk.PasswordPolicyType)
+	require.NoError(t, err)
+	_ = d.Set("policy_ids", []interface{}{getMapString(policies[4], "id"), policyIDs[0]})
+	diags = r.UpdateContext(ctx, d, config)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Summary, "it's always the last one")
+}
@@ -0,0 +1,193 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func resourcePolicyRuleOrder() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePolicyRuleOrderCreate,
+		ReadContext:   resourcePolicyRuleOrderRead,
+		UpdateContext: resourcePolicyRuleOrderUpdate,
+		DeleteContext: resourcePolicyRuleOrderDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				parts := strings.Split(d.Id(), "/")
+				switch len(parts) {
+				case 1:
+					_ = d.Set("policy_id", parts[0])
+				case 2:
+					_ = d.Set("auth_server_id", parts[0])
+					_ = d.Set("policy_id", parts[1])
+				default:
+					return nil, fmt.Errorf("invalid policy rule order specifier. Expecting {policyID} or {authServerID}/{policyID}")
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: map[string]*schema.Schema{
+			"policy_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the policy",
+			},
+			"auth_server_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "ID of the authorization server, when the policy is an authorization server policy",
+			},
+			"rule_ids": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the policy rules in priority order, the first rule has the highest priority. The rules of the policy that aren't in the list keep their order after the listed rules",
+			},
+		},
+	}
+}
+
+func resourcePolicyRuleOrderCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	policyID := d.Get("policy_id").(string)
+	authServerID := d.Get("auth_server_id").(string)
+	logger(m).Info("ordering policy rules", "policy_id", policyID)
+	if err := applyPolicyRuleOrder(ctx, m, authServerID, policyID, convertInterfaceToStringArr(d.Get("rule_ids"))); err != nil {
+		return diag.Errorf("failed to order policy rules: %v", err)
+	}
+	if authServerID != "" {
+		d.SetId(fmt.Sprintf("%s/%s", authServerID, policyID))
+	} else {
+		d.SetId(policyID)
+	}
+	return resourcePolicyRuleOrderRead(ctx, d, m)
+}
+
+func resourcePolicyRuleOrderRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	rules, resp, err := listPolicyRulesByPriority(ctx, m, d.Get("auth_server_id").(string), d.Get("policy_id").(string))
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to list policy rules: %v", err)
+	}
+	if rules == nil {
+		d.SetId("")
+		return nil
+	}
+	// The order of the rules is the order of the first rules of the policy,
+	// so a rule reordered in the admin console above or between the ordered
+	// rules is a drift as well.
+	var ids []string
+	for _, rule := range rules {
+		if !jsonIsSystem(rule) {
+			ids = append(ids, getMapString(rule, "id"))
+		}
+	}
+	if n := len(d.Get("rule_ids").([]interface{})); n > 0 && n < len(ids) {
+		ids = ids[:n]
+	}
+	_ = d.Set("rule_ids", convertStringSliceToInterfaceSlice(ids))
+	return nil
+}
+
+func resourcePolicyRuleOrderUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	policyID := d.Get("policy_id").(string)
+	logger(m).Info("ordering policy rules", "policy_id", policyID)
+	if err := applyPolicyRuleOrder(ctx, m, d.Get("auth_server_id").(string), policyID, convertInterfaceToStringArr(d.Get("rule_ids"))); err != nil {
+		return diag.Errorf("failed to order policy rules: %v", err)
+	}
+	return resourcePolicyRuleOrderRead(ctx, d, m)
+}
+
+// resourcePolicyRuleOrderDelete only removes the resource from the state, the
+// rules keep their order.
+func resourcePolicyRuleOrderDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return nil
+}
+
+// applyPolicyRuleOrder gives the rules the highest priorities in the order of
+// ruleIDs with the minimal number of priority updates.
+func applyPolicyRuleOrder(ctx context.Context, m interface{}, authServerID, policyID string, ruleIDs []string) error {
+	rules, _, err := listPolicyRulesByPriority(ctx, m, authServerID, policyID)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]map[string]interface{}, len(rules))
+	current := make([]string, len(rules))
+	for i, rule := range rules {
+		current[i] = getMapString(rule, "id")
+		byID[current[i]] = rule
+	}
+
+	target := make([]string, 0, len(rules))
+	for _, id := range ruleIDs {
+		rule, ok := byID[id]
+		switch {
+		case !ok:
+			return fmt.Errorf("rule '%s' doesn't belong to the policy '%s'", id, policyID)
+		case jsonIsSystem(rule):
+			return fmt.Errorf("rule '%s' is the default rule of the policy, its priority can't be changed", id)
+		case contains(target, id):
+			return fmt.Errorf("rule '%s' is listed more than once", id)
+		}
+		target = append(target, id)
+	}
+	// the other rules keep their order after the listed rules, and the
+	// default rule stays the last one
+	for _, system := range []bool{false, true} {
+		for _, id := range current {
+			if !contains(target, id) && jsonIsSystem(byID[id]) == system {
+				target = append(target, id)
+			}
+		}
+	}
+
+	for _, move := range priorityMoves(current, target) {
+		logger(m).Debug("setting policy rule priority", "rule_id", move.ID, "priority", move.Priority)
+		if err := updatePolicyRulePriority(ctx, m, authServerID, policyID, byID[move.ID], move.Priority); err != nil {
+			return fmt.Errorf("failed to set the priority of rule '%s': %v", move.ID, err)
+		}
+	}
+	return nil
+}
+
+func policyRulesURL(authServerID, policyID string) string {
+	if authServerID != "" {
+		return fmt.Sprintf("/api/v1/authorizationServers/%s/policies/%s/rules", authServerID, policyID)
+	}
+	return fmt.Sprintf("/api/v1/policies/%s/rules", policyID)
+}
+
+// listPolicyRulesByPriority lists the rules of the policy as raw JSON objects,
+// so they can be updated whatever their type, ordered by priority.
+func listPolicyRulesByPriority(ctx context.Context, m interface{}, authServerID, policyID string) ([]map[string]interface{}, *sdk.Response, error) {
+	re := getRequestExecutor(m)
+	req, err := re.WithAccept("application/json").WithContentType("application/json").NewRequest(http.MethodGet, policyRulesURL(authServerID, policyID), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rules []map[string]interface{}
+	resp, err := re.Do(ctx, req, &rules)
+	if err != nil {
+		return nil, resp, err
+	}
+	sortByPriority(rules)
+	return rules, resp, nil
+}
+
+func updatePolicyRulePriority(ctx context.Context, m interface{}, authServerID, policyID string, rule map[string]interface{}, priority int) error {
+	re := getRequestExecutor(m)
+	url := fmt.Sprintf("%s/%s", policyRulesURL(authServerID, policyID), getMapString(rule, "id"))
+	req, err := re.WithAccept("application/json").WithContentType("application/json").NewRequest(http.MethodPut, url, priorityUpdateBody(rule, priority))
+	if err != nil {
+		return err
+	}
+	_, err = re.Do(ctx, req, nil)
+	return err
+}
@@ -0,0 +1,137 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccResourceOktaPolicyRuleOrder(t *testing.T) {
+	mgr := newFixtureManager("resources", policyRuleOrder, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	updatedConfig := mgr.GetFixtures("basic_updated.tf", t)
+	resourceName := fmt.Sprintf("%s.test", policyRuleOrder)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "rule_ids.0", "okta_policy_rule_signon.test.2", "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "rule_ids.1", "okta_policy_rule_signon.test.0", "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "rule_ids.2", "okta_policy_rule_signon.test.1", "id"),
+				),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "rule_ids.0", "okta_policy_rule_signon.test.1", "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "rule_ids.1", "okta_policy_rule_signon.test.2", "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "rule_ids.2", "okta_policy_rule_signon.test.0", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestPriorityMoves(t *testing.T) {
+	tests := []struct {
+		current, target []string
+		moves           int
+	}{
+		{[]string{"a", "b", "c", "d"}, []string{"a", "b", "c", "d"}, 0},
+		{[]string{"a", "b", "c", "d"}, []string{"d", "a", "b", "c"}, 1},
+		{[]string{"a", "b", "c", "d"}, []string{"b", "c", "d", "a"}, 1},
+		{[]string{"a", "b", "c", "d"}, []string{"d", "c", "b", "a"}, 3},
+		{[]string{"a", "b", "c", "d", "e"}, []string{"a", "d", "b", "c", "e"}, 1},
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		n := 1 + r.Intn(12)
+		current := make([]string, n)
+		for j := range current {
+			current[j] = fmt.Sprintf("r%d", j)
+		}
+		target := append([]string(nil), current...)
+		r.Shuffle(n, func(a, b int) { target[a], target[b] = target[b], target[a] })
+		tests = append(tests, struct {
+			current, target []string
+			moves           int
+		}{current, target, -1})
+	}
+
+	for _, tt := range tests {
+		moves := priorityMoves(tt.current, tt.target)
+		if tt.moves >= 0 && len(moves) != tt.moves {
+			t.Errorf("%v to %v: expected %d moves, got %+v", tt.current, tt.target, tt.moves, moves)
+		}
+		// apply the moves the way Okta does
+		order := append([]string(nil), tt.current...)
+		for _, move := range moves {
+			order = remove(order, move.ID)
+			order = append(order[:move.Priority-1], append([]string{move.ID}, order[move.Priority-1:]...)...)
+		}
+		require.Equal(t, tt.target, order, "moves %+v", moves)
+	}
+}
+
+func TestPolicyRuleOrder(t *testing.T) {
+	server, config := newFakeOktaConfig(t)
+	ctx := context.TODO()
+	policies, _, err := config.oktaSDKClientV2.Policy.ListPolicies(ctx, &query.Params{Type: sdk.PasswordPolicyType})
+	require.NoError(t, err)
+	policyID := policies[0].(*sdk.Policy).Id
+	var ruleIDs []string
+	for i := 1; i <= 4; i++ {
+		ruleIDs = append(ruleIDs, createFakeOktaObject(t, config, "/api/v1/policies/"+policyID+"/rules", map[string]interface{}{
+			"name": fmt.Sprintf("Rule %d", i),
+		}))
+	}
+	order := func() ([]string, []map[string]interface{}) {
+		rules, _, err := listPolicyRulesByPriority(ctx, config, "", policyID)
+		require.NoError(t, err)
+		var ids []string
+		for _, rule := range rules {
+			ids = append(ids, getMapString(rule, "name"))
+		}
+		return ids, rules
+	}
+
+	r := resourcePolicyRuleOrder()
+	d := r.TestResourceData()
+	_ = d.Set("policy_id", policyID)
+	_ = d.Set("rule_ids", []interface{}{ruleIDs[2], ruleIDs[0]})
+	requests := server.Requests()
+	diags := r.CreateContext(ctx, d, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	// listing the rules, one priority update and reading the rules
+	require.Equal(t, 3, server.Requests()-requests)
+	names, rules := order()
+	require.Equal(t, []string{"Rule 3", "Rule 1", "Rule 2", "Rule 4", "Default Rule"}, names)
+	require.Equal(t, []interface{}{ruleIDs[2], ruleIDs[0]}, d.Get("rule_ids"))
+
+	// reordering in the admin console is reported as a drift
+	require.NoError(t, updatePolicyRulePriority(ctx, config, "", policyID, rules[3], 2))
+	diags = r.ReadContext(ctx, d, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, []interface{}{ruleIDs[2], ruleIDs[3]}, d.Get("rule_ids"))
+
+	_, rules = order()
+	_ = d.Set("rule_ids", []interface{}{getMapString(rules[4], "id")})
+	diags = r.UpdateContext(ctx, d, config)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Summary, "is the default rule of the policy")
+	_ = d.Set("rule_ids", []interface{}{"0pr9"})
+	diags = r.UpdateContext(ctx, d, config)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Summary, "doesn't belong to the policy")
+}
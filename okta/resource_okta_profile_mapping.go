@@ -66,9 +66,30 @@ func resourceProfileMapping() *schema.Resource {
 				Default:     false,
 			},
 		},
+		CustomizeDiff: resourceProfileMappingCustomizeDiff,
 	}
 }
 
+// resourceProfileMappingCustomizeDiff checks the properties referenced by the
+// mapping expressions, appuser properties are properties of the source app.
+func resourceProfileMappingCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("mappings") || !d.NewValueKnown("mappings") {
+		return nil
+	}
+	sourceID := d.Get("source_id").(string)
+	if !d.NewValueKnown("source_id") {
+		sourceID = ""
+	}
+	for _, v := range d.Get("mappings").(*schema.Set).List() {
+		mapping := v.(map[string]interface{})
+		k := fmt.Sprintf("mappings %q expression", mapping["id"].(string))
+		if err := checkExpression(ctx, m, k, mapping["expression"].(string), sourceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 var mappingResource = &schema.Resource{
 	Schema: map[string]*schema.Schema{
 		"id": {
@@ -77,8 +98,9 @@ var mappingResource = &schema.Resource{
 			Description: "The mapping property key.",
 		},
 		"expression": {
-			Type:     schema.TypeString,
-			Required: true,
+			Type:             schema.TypeString,
+			Required:         true,
+			ValidateDiagFunc: isOktaExpression,
 		},
 		"push_status": {
 			Type:     schema.TypeString,
@@ -52,19 +52,26 @@ func resourceRateLimitingCreate(ctx context.Context, d *schema.ResourceData, m i
 }
 
 func resourceRateLimitingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	rl, _, err := getAPISupplementFromMetadata(m).GetClientBasedRateLimiting(ctx)
-	if err != nil || rl.GranularModeSettings == nil {
+	rl, resp, err := getAPISupplementFromMetadata(m).GetClientBasedRateLimiting(ctx)
+	warnings, err := suppressErrorOnPermission(ctx, "reading resource okta_rate_limiting", m, resp, err)
+	if err != nil || (warnings == nil && rl.GranularModeSettings == nil) {
 		return diag.Errorf("failed to get client-based rate limiting: %v", err)
 	}
+	if warnings != nil {
+		return warnings
+	}
 	_ = d.Set("login", rl.GranularModeSettings.LoginPage)
 	_ = d.Set("authorize", rl.GranularModeSettings.OAuth2Authorize)
-	comm, _, err := getAPISupplementFromMetadata(m).GetRateLimitingCommunications(ctx)
+	comm, resp, err := getAPISupplementFromMetadata(m).GetRateLimitingCommunications(ctx)
+	warnings, err = suppressErrorOnPermission(ctx, "reading resource okta_rate_limiting.communications_enabled", m, resp, err)
 	if err != nil {
 		return diag.Errorf("failed to get rate limiting communications: %v", err)
 	}
-	_ = d.Set("communications_enabled", *comm.RateLimitNotification)
+	if comm != nil && comm.RateLimitNotification != nil {
+		_ = d.Set("communications_enabled", *comm.RateLimitNotification)
+	}
 	d.SetId("rate_limiting")
-	return nil
+	return warnings
 }
 
 func resourceRateLimitingUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
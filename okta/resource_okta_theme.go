@@ -1,11 +1,16 @@
 package okta
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/okta/okta-sdk-golang/v3/okta"
@@ -20,7 +25,8 @@ func resourceTheme() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceThemeImportStateContext,
 		},
-		Schema: themeResourceSchema,
+		CustomizeDiff: themeAssetsDiff,
+		Schema:        themeResourceSchema,
 	}
 }
 
@@ -50,6 +56,7 @@ func resourceThemeCreate(ctx context.Context, d *schema.ResourceData, m interfac
 	}
 
 	d.SetId(theme.GetId())
+	setThemeAssetHashes(ctx, d, m, theme)
 	rawMap := flattenTheme(brandID, theme)
 	err = setNonPrimitives(d, rawMap)
 	if err != nil {
@@ -73,6 +80,7 @@ func resourceThemeRead(ctx context.Context, d *schema.ResourceData, m interface{
 		return diag.Errorf("failed to get theme: %v", err)
 	}
 
+	setThemeAssetHashes(ctx, d, m, theme)
 	rawMap := flattenTheme(brandID, theme)
 	err = setNonPrimitives(d, rawMap)
 	if err != nil {
@@ -93,22 +101,10 @@ func resourceThemeUpdate(ctx context.Context, d *schema.ResourceData, m interfac
 
 	// peform delete/upload on the logo/favicon/background_image first so any
 	// errors there will interrupt apply on the theme itself
-	if d.HasChange("logo") {
-		err := handleThemeLogo(ctx, d, m, brandID, d.Id())
+	for _, asset := range themeAssets {
+		err := handleThemeAsset(ctx, d, m, asset, brandID, d.Id())
 		if err != nil {
-			return diag.Errorf("failed to handle logo for theme: %v", err)
-		}
-	}
-	if d.HasChange("favicon") {
-		err := handleThemeFavicon(ctx, d, m, brandID, d.Id())
-		if err != nil {
-			return diag.Errorf("failed to handle favicon for theme: %v", err)
-		}
-	}
-	if d.HasChange("background_image") {
-		err := handleThemeBackgroundImage(ctx, d, m, brandID, d.Id())
-		if err != nil {
-			return diag.Errorf("failed to handle background_image for theme: %v", err)
+			return diag.Errorf("failed to handle %s for theme: %v", asset.name, err)
 		}
 	}
 
@@ -159,6 +155,7 @@ func resourceThemeUpdate(ctx context.Context, d *schema.ResourceData, m interfac
 		return diag.Errorf("failed to update theme: %v", err)
 	}
 
+	setThemeAssetHashes(ctx, d, m, themeResp)
 	rawMap := flattenTheme(brandID, themeResp)
 	err = setNonPrimitives(d, rawMap)
 	if err != nil {
@@ -188,6 +185,7 @@ func resourceThemeImportStateContext(ctx context.Context, d *schema.ResourceData
 	}
 
 	d.SetId(theme.GetId())
+	setThemeAssetHashes(ctx, d, m, theme)
 	rawMap := flattenTheme(brandID, theme)
 	err = setNonPrimitives(d, rawMap)
 	if err != nil {
@@ -197,47 +195,202 @@ func resourceThemeImportStateContext(ctx context.Context, d *schema.ResourceData
 	return []*schema.ResourceData{d}, nil
 }
 
-func handleThemeLogo(ctx context.Context, d *schema.ResourceData, m interface{}, brandID, themeID string) error {
-	_, newPath := d.GetChange("logo")
-	if newPath == "" {
-		_, err := getOktaV3ClientFromMetadata(m).CustomizationAPI.DeleteBrandThemeLogo(ctx, brandID, themeID).Execute()
-		return err
+// themeAsset is an image of a theme uploaded from the file at the path of the
+// attribute named after it or from the base64 content of its "_content"
+// attribute.
+type themeAsset struct {
+	name   string
+	url    func(theme *okta.ThemeResponse) string
+	upload func(ctx context.Context, client *okta.APIClient, brandID, themeID string, file *os.File) error
+	remove func(ctx context.Context, client *okta.APIClient, brandID, themeID string) error
+}
+
+var themeAssets = []themeAsset{
+	{
+		name: "logo",
+		url:  (*okta.ThemeResponse).GetLogo,
+		upload: func(ctx context.Context, client *okta.APIClient, brandID, themeID string, file *os.File) error {
+			_, _, err := client.CustomizationAPI.UploadBrandThemeLogo(ctx, brandID, themeID).File(file).Execute()
+			return err
+		},
+		remove: func(ctx context.Context, client *okta.APIClient, brandID, themeID string) error {
+			_, err := client.CustomizationAPI.DeleteBrandThemeLogo(ctx, brandID, themeID).Execute()
+			return err
+		},
+	},
+	{
+		name: "favicon",
+		url:  (*okta.ThemeResponse).GetFavicon,
+		upload: func(ctx context.Context, client *okta.APIClient, brandID, themeID string, file *os.File) error {
+			_, _, err := client.CustomizationAPI.UploadBrandThemeFavicon(ctx, brandID, themeID).File(file).Execute()
+			return err
+		},
+		remove: func(ctx context.Context, client *okta.APIClient, brandID, themeID string) error {
+			_, err := client.CustomizationAPI.DeleteBrandThemeFavicon(ctx, brandID, themeID).Execute()
+			return err
+		},
+	},
+	{
+		name: "background_image",
+		url:  (*okta.ThemeResponse).GetBackgroundImage,
+		upload: func(ctx context.Context, client *okta.APIClient, brandID, themeID string, file *os.File) error {
+			_, _, err := client.CustomizationAPI.UploadBrandThemeBackgroundImage(ctx, brandID, themeID).File(file).Execute()
+			return err
+		},
+		remove: func(ctx context.Context, client *okta.APIClient, brandID, themeID string) error {
+			_, err := client.CustomizationAPI.DeleteBrandThemeBackgroundImage(ctx, brandID, themeID).Execute()
+			return err
+		},
+	},
+}
+
+// themeAssetContent returns the content of the asset set in the
+// configuration, read from the file at its path or decoded from its base64
+// content. It is nil when the asset isn't set or is set to "".
+func themeAssetContent(raw cty.Value, name string) ([]byte, error) {
+	if raw.IsNull() || !raw.IsKnown() {
+		return nil, nil
 	}
-	fo, err := os.Open(newPath.(string))
-	if err != nil {
-		return err
+	if v := raw.GetAttr(name); v.IsKnown() && !v.IsNull() && v.AsString() != "" {
+		return os.ReadFile(v.AsString())
 	}
-	defer fo.Close()
-	_, _, err = getOktaV3ClientFromMetadata(m).CustomizationAPI.UploadBrandThemeLogo(ctx, brandID, themeID).File(fo).Execute()
-	return err
+	if v := raw.GetAttr(name + "_content"); v.IsKnown() && !v.IsNull() && v.AsString() != "" {
+		return base64.StdEncoding.DecodeString(v.AsString())
+	}
+	return nil, nil
+}
+
+// themeAssetsDiff plans the upload of the assets whose content in Okta,
+// according to the hash of the asset read from Okta, differs from the
+// configured content.
+func themeAssetsDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	for _, asset := range themeAssets {
+		content, err := themeAssetContent(d.GetRawConfig(), asset.name)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", asset.name, err)
+		}
+		remoteHash := d.Get(asset.name + "_hash").(string)
+		if content == nil || remoteHash == "" && d.Get(asset.name+"_url").(string) != "" {
+			continue
+		}
+		if remoteHash != computeContentHash(content) {
+			logger(m).Info("theme asset changed outside of Terraform", "asset", asset.name, "id", d.Id())
+			if err := d.SetNewComputed(asset.name + "_hash"); err != nil {
+				return err
+			}
+			if err := d.SetNewComputed(asset.name + "_url"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
-func handleThemeFavicon(ctx context.Context, d *schema.ResourceData, m interface{}, brandID, themeID string) error {
-	_, newPath := d.GetChange("favicon")
-	if newPath == "" {
-		_, err := getOktaV3ClientFromMetadata(m).CustomizationAPI.DeleteBrandThemeFavicon(ctx, brandID, themeID).Execute()
+// handleThemeAsset uploads the configured asset when it changed, in the
+// configuration or in Okta, and deletes it, reverting the theme to Okta's
+// default, when it is set to "" or removed from the configuration.
+func handleThemeAsset(ctx context.Context, d *schema.ResourceData, m interface{}, asset themeAsset, brandID, themeID string) error {
+	content, err := themeAssetContent(d.GetRawConfig(), asset.name)
+	if err != nil {
 		return err
 	}
-	fo, err := os.Open(newPath.(string))
+	configChanged := d.HasChanges(asset.name, asset.name+"_content")
+	client := getOktaV3ClientFromMetadata(m)
+	if content == nil {
+		if !configChanged {
+			return nil
+		}
+		return asset.remove(ctx, client, brandID, themeID)
+	}
+	oldHash, _ := d.GetChange(asset.name + "_hash")
+	if !configChanged && oldHash.(string) == computeContentHash(content) {
+		return nil
+	}
+
+	// the API takes a file, base64 content is uploaded from a temporary file
+	// with the extension of its type
+	if path := d.GetRawConfig().GetAttr(asset.name); !path.IsNull() && path.AsString() != "" {
+		fo, err := os.Open(path.AsString())
+		if err != nil {
+			return err
+		}
+		defer fo.Close()
+		return asset.upload(ctx, client, brandID, themeID, fo)
+	}
+	fo, err := os.CreateTemp("", fmt.Sprintf("okta-theme-%s-*%s", asset.name, imageExtension(content)))
 	if err != nil {
 		return err
 	}
+	defer os.Remove(fo.Name())
 	defer fo.Close()
-	_, _, err = getOktaV3ClientFromMetadata(m).CustomizationAPI.UploadBrandThemeFavicon(ctx, brandID, themeID).File(fo).Execute()
-	return err
+	if _, err := fo.Write(content); err != nil {
+		return err
+	}
+	if _, err := fo.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return asset.upload(ctx, client, brandID, themeID, fo)
 }
 
-func handleThemeBackgroundImage(ctx context.Context, d *schema.ResourceData, m interface{}, brandID, themeID string) error {
-	_, newPath := d.GetChange("background_image")
-	if newPath == "" {
-		_, err := getOktaV3ClientFromMetadata(m).CustomizationAPI.DeleteBrandThemeBackgroundImage(ctx, brandID, themeID).Execute()
-		return err
+// imageExtension returns the file extension of the type of an image.
+func imageExtension(content []byte) string {
+	if bytes.Contains(content[:min(len(content), 512)], []byte("<svg")) {
+		return ".svg"
+	}
+	switch http.DetectContentType(content) {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "image/x-icon":
+		return ".ico"
+	}
+	return ""
+}
+
+// setThemeAssetHashes sets the hashes of the theme's assets. An asset is
+// downloaded only when its URL changed, Okta serves uploaded assets from new
+// URLs.
+func setThemeAssetHashes(ctx context.Context, d *schema.ResourceData, m interface{}, theme *okta.ThemeResponse) {
+	for _, asset := range themeAssets {
+		url := asset.url(theme)
+		if url == "" {
+			_ = d.Set(asset.name+"_hash", "")
+			continue
+		}
+		if url == d.Get(asset.name+"_url").(string) && d.Get(asset.name+"_hash").(string) != "" {
+			continue
+		}
+		hash, err := downloadHash(ctx, getOktaV3ClientFromMetadata(m).GetConfig().HTTPClient, url)
+		if err != nil {
+			logger(m).Warn("unable to download the theme asset, changes made outside of Terraform won't be detected", "asset", asset.name, "url", url, "error", err)
+		}
+		_ = d.Set(asset.name+"_hash", hash)
+	}
+}
+
+// downloadHash returns the hash of the content at the URL.
+func downloadHash(ctx context.Context, client *http.Client, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
 	}
-	fo, err := os.Open(newPath.(string))
+	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer fo.Close()
-	_, _, err = getOktaV3ClientFromMetadata(m).CustomizationAPI.UploadBrandThemeBackgroundImage(ctx, brandID, themeID).File(fo).Execute()
-	return err
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return computeContentHash(content), nil
 }
@@ -1,12 +1,20 @@
 package okta
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccResourceOktaTheme_import_update(t *testing.T) {
@@ -130,3 +138,102 @@ func TestAccResourceOktaTheme_import_update(t *testing.T) {
 		},
 	})
 }
+
+// themeRawConfig returns the configuration of an okta_theme as Terraform
+// sends it to the provider.
+func themeRawConfig(attrs map[string]interface{}) cty.Value {
+	vals := make(map[string]cty.Value)
+	for name, ty := range resourceTheme().CoreConfigSchema().ImpliedType().AttributeTypes() {
+		vals[name] = cty.NullVal(ty)
+		if v, ok := attrs[name]; ok {
+			vals[name] = cty.StringVal(v.(string))
+		}
+	}
+	return cty.ObjectVal(vals)
+}
+
+func TestResourceThemeAssets(t *testing.T) {
+	server, config := newFakeOktaConfig(t)
+	ctx := context.TODO()
+	client := getOktaV3ClientFromMetadata(config)
+	brandID := server.DefaultBrandID()
+	themes, _, err := client.CustomizationAPI.ListBrandThemes(ctx, brandID).Execute()
+	require.NoError(t, err)
+	themeID := themes[0].GetId()
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+	pngContent := base64.StdEncoding.EncodeToString(png)
+	adminLogo := append([]byte("\x89PNG\r\n\x1a\n"), []byte("logo uploaded in the admin console")...)
+	file, err := os.CreateTemp(t.TempDir(), "logo-*.png")
+	require.NoError(t, err)
+	_, err = file.Write(adminLogo)
+	require.NoError(t, err)
+	_, err = file.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	_, _, err = client.CustomizationAPI.UploadBrandThemeLogo(ctx, brandID, themeID).File(file).Execute()
+	require.NoError(t, err)
+	r := resourceTheme()
+
+	// the hashes of the logo and favicon are read from Okta
+	cfg := map[string]interface{}{
+		"brand_id":                               brandID,
+		"theme_id":                               themeID,
+		"logo_content":                           pngContent,
+		"primary_color_hex":                      "#1662dd",
+		"secondary_color_hex":                    "#ebebed",
+		"sign_in_page_touch_point_variant":       "OKTA_DEFAULT",
+		"end_user_dashboard_touch_point_variant": "OKTA_DEFAULT",
+		"error_page_touch_point_variant":         "OKTA_DEFAULT",
+		"email_template_touch_point_variant":     "OKTA_DEFAULT",
+	}
+	d := r.Data(&terraform.InstanceState{
+		ID:         themeID,
+		Attributes: map[string]string{"brand_id": brandID, "theme_id": themeID, "logo_content": computeContentHash(png)},
+		RawConfig:  themeRawConfig(cfg),
+	})
+	requests := server.Requests()
+	require.False(t, resourceThemeRead(ctx, d, config).HasError())
+	require.Equal(t, computeContentHash(adminLogo), d.Get("logo_hash"))
+	require.NotEmpty(t, d.Get("favicon_hash"))
+	require.Equal(t, "", d.Get("background_image_hash"))
+	require.Equal(t, 3, server.Requests()-requests, "expected the theme, the logo and the favicon to be read")
+
+	// the assets aren't downloaded again while their URLs don't change
+	requests = server.Requests()
+	require.False(t, resourceThemeRead(ctx, d, config).HasError())
+	require.Equal(t, 1, server.Requests()-requests)
+
+	// the logo changed outside of Terraform is uploaded again
+	state := d.State()
+	state.RawConfig = themeRawConfig(cfg)
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(cfg), config)
+	require.NoError(t, err)
+	require.True(t, diff.Attributes["logo_hash"].NewComputed)
+	requests = server.Requests()
+	state, diags := r.Apply(ctx, state, diff, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, 3, server.Requests()-requests, "expected the logo to be uploaded, the theme replaced and the new logo read")
+	require.True(t, strings.HasSuffix(state.Attributes["logo_url"], ".png"))
+	require.Equal(t, computeContentHash(png), state.Attributes["logo_hash"])
+	logoHash, err := downloadHash(ctx, http.DefaultClient, state.Attributes["logo_url"])
+	require.NoError(t, err)
+	require.Equal(t, computeContentHash(png), logoHash)
+
+	// no changes once the logo in Okta matches the configuration
+	state.RawConfig = themeRawConfig(cfg)
+	diff, err = r.Diff(ctx, state, terraform.NewResourceConfigRaw(cfg), config)
+	require.NoError(t, err)
+	require.Nil(t, diff)
+
+	// setting the logo to "" reverts the theme to Okta's default logo
+	cfg["logo_content"] = ""
+	state.RawConfig = themeRawConfig(cfg)
+	diff, err = r.Diff(ctx, state, terraform.NewResourceConfigRaw(cfg), config)
+	require.NoError(t, err)
+	state, diags = r.Apply(ctx, state, diff, config)
+	require.False(t, diags.HasError(), "%v", diags)
+	theme, _, err := client.CustomizationAPI.GetBrandTheme(ctx, brandID, themeID).Execute()
+	require.NoError(t, err)
+	require.Equal(t, theme.GetLogo(), state.Attributes["logo_url"])
+	require.Equal(t, computeContentHash([]byte("fake okta default logo")), state.Attributes["logo_hash"])
+}
@@ -79,10 +79,14 @@ func resourceThreatInsightSettingsCreate(ctx context.Context, d *schema.Resource
 }
 
 func resourceThreatInsightSettingsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	conf, _, err := getOktaClientFromMetadata(m).ThreatInsightConfiguration.GetCurrentConfiguration(ctx)
+	conf, resp, err := getOktaClientFromMetadata(m).ThreatInsightConfiguration.GetCurrentConfiguration(ctx)
+	warnings, err := suppressErrorOnPermission(ctx, "reading resource okta_threat_insight_settings", m, resp, err)
 	if err != nil {
 		return diag.Errorf("failed to get threat insight configuration: %v", err)
 	}
+	if warnings != nil {
+		return warnings
+	}
 	d.SetId("threat_insight_settings")
 	_ = d.Set("action", conf.Action)
 	_ = d.Set("network_excludes", convertStringSliceToInterfaceSlice(conf.ExcludeZones))
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/okta/terraform-provider-okta/sdk"
 	"github.com/okta/terraform-provider-okta/sdk/query"
@@ -229,8 +231,12 @@ func resourceUser() *schema.Resource {
 				Description: "The status of the User in Okta - remove to set user back to active/provisioned",
 				Default:     statusActive,
 				// ignore diff changing to ACTIVE if state is set to PROVISIONED or PASSWORD_EXPIRED
-				// since this is a similar status in Okta terms
+				// since this is a similar status in Okta terms, or if the user is suspended during
+				// its suspend window
 				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					if old == userStatusSuspended && new == statusActive {
+						return suspendWindowActive(d.Get("suspend_window"), time.Now())
+					}
 					return old == userStatusProvisioned && new == statusActive || old == userStatusPasswordExpired && new == statusActive
 				},
 			},
@@ -274,9 +280,56 @@ func resourceUser() *schema.Resource {
 				Type:         schema.TypeBool,
 				Optional:     true,
 				Default:      false,
-				Description:  "If set to `true`, the user will have to change the password at the next login. This property will be used when user is being created and works only when `password` field is set",
+				Description:  "If set to `true`, the user will have to change the password at the next login. This property will be used when user is being created and works only when `password` field is set and the user is `ACTIVE` once created",
 				RequiredWith: []string{"password"},
 			},
+			"expire_password_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Arbitrary value, the user's password is expired whenever it's set or changed to a non-empty value, so the user will have to change the password at the next login",
+			},
+			"password_expired_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the user's password was last expired by the provider, RFC3339 timestamp",
+			},
+			"reset_factors_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Arbitrary value, all the user's enrolled factors are reset whenever it's set or changed to a non-empty value",
+			},
+			"factors_reset_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the user's factors were last reset by the provider, RFC3339 timestamp",
+			},
+			"unlocked_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the LOCKED_OUT user was last unlocked by the provider, RFC3339 timestamp",
+			},
+			"suspend_window": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "The user is suspended from the start until the end of the window, and unsuspended afterwards. The window is enforced when the resource is applied",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"start": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: stringIsRFC3339,
+							Description:      "Start of the suspension, RFC3339 timestamp",
+						},
+						"end": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: stringIsRFC3339,
+							Description:      "End of the suspension, RFC3339 timestamp. The user stays suspended when it's not set",
+						},
+					},
+				},
+			},
 			"password_inline_hook": {
 				Type:          schema.TypeString,
 				Optional:      true,
@@ -349,47 +402,89 @@ func resourceUser() *schema.Resource {
 			},
 		},
 
-		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, v interface{}) error {
-			filteredCustomAttributes := convertInterfaceToStringSet(d.Get("custom_profile_attributes_to_ignore"))
-			if len(filteredCustomAttributes) == 0 {
-				return nil
-			}
+		CustomizeDiff: customdiff.All(
+			userCustomProfileAttributesDiff,
+			userLifecycleDiff,
+		),
+	}
+}
 
-			oldAttrs, newAttrs := d.GetChange("custom_profile_attributes")
-			var oldAttrsMap map[string]interface{}
-			_ = json.Unmarshal([]byte(oldAttrs.(string)), &oldAttrsMap)
-			var newAttrsMap map[string]interface{}
-			_ = json.Unmarshal([]byte(newAttrs.(string)), &newAttrsMap)
-
-			if d.Id() == "" {
-				// This is a new user resource. In this case, we only have new values. We'll filter any
-				// values for newly created resources as this is a rare case. If one specifies
-				// `custom_profile_attributes_to_filter` and then additionally includes those fields
-				// as specified in the initial resource creation, we'll simply ignore them.
-
-				for k := range newAttrsMap {
-					if contains(filteredCustomAttributes, k) {
-						delete(newAttrsMap, k)
-					}
-				}
-			} else {
-				// We are updating. We've already done a read from the server so the old value will now contain
-				// correct values. Thus, we update `custom_profile_attributes` with the filtered attributes
-				// from the current old value.
-
-				for k, v := range oldAttrsMap {
-					if contains(filteredCustomAttributes, k) {
-						newAttrsMap[k] = v
-					}
-				}
+func userCustomProfileAttributesDiff(ctx context.Context, d *schema.ResourceDiff, v interface{}) error {
+	filteredCustomAttributes := convertInterfaceToStringSet(d.Get("custom_profile_attributes_to_ignore"))
+	if len(filteredCustomAttributes) == 0 {
+		return nil
+	}
+
+	oldAttrs, newAttrs := d.GetChange("custom_profile_attributes")
+	var oldAttrsMap map[string]interface{}
+	_ = json.Unmarshal([]byte(oldAttrs.(string)), &oldAttrsMap)
+	var newAttrsMap map[string]interface{}
+	_ = json.Unmarshal([]byte(newAttrs.(string)), &newAttrsMap)
+
+	if d.Id() == "" {
+		// This is a new user resource. In this case, we only have new values. We'll filter any
+		// values for newly created resources as this is a rare case. If one specifies
+		// `custom_profile_attributes_to_filter` and then additionally includes those fields
+		// as specified in the initial resource creation, we'll simply ignore them.
+
+		for k := range newAttrsMap {
+			if contains(filteredCustomAttributes, k) {
+				delete(newAttrsMap, k)
+			}
+		}
+	} else {
+		// We are updating. We've already done a read from the server so the old value will now contain
+		// correct values. Thus, we update `custom_profile_attributes` with the filtered attributes
+		// from the current old value.
+
+		for k, v := range oldAttrsMap {
+			if contains(filteredCustomAttributes, k) {
+				newAttrsMap[k] = v
 			}
+		}
+	}
 
-			customProfileAttributes, _ := json.Marshal(newAttrsMap)
-			d.SetNew("custom_profile_attributes", string(customProfileAttributes))
+	customProfileAttributes, _ := json.Marshal(newAttrsMap)
+	d.SetNew("custom_profile_attributes", string(customProfileAttributes))
 
-			return nil
-		},
+	return nil
+}
+
+// userLifecycleDiff plans the lifecycle operations of the user: the
+// timestamps of the operations triggered by the apply are unknown, and the
+// user is suspended when its suspend window is active.
+func userLifecycleDiff(ctx context.Context, d *schema.ResourceDiff, v interface{}) error {
+	if err := validateSuspendWindow(d.Get("suspend_window")); err != nil {
+		return err
+	}
+	if d.Id() == "" {
+		expire := d.Get("expire_password_trigger").(string) != "" || d.Get("expire_password_on_create").(bool)
+		if expire && passwordExpirableOnCreate(d.Get("status").(string), d.Get("suspend_window"), time.Now()) {
+			_ = d.SetNewComputed("password_expired_at")
+		}
+		if d.Get("reset_factors_trigger").(string) != "" {
+			_ = d.SetNewComputed("factors_reset_at")
+		}
+		return nil
 	}
+	if d.HasChange("expire_password_trigger") && d.Get("expire_password_trigger").(string) != "" {
+		_ = d.SetNewComputed("password_expired_at")
+	}
+	if d.HasChange("reset_factors_trigger") && d.Get("reset_factors_trigger").(string) != "" {
+		_ = d.SetNewComputed("factors_reset_at")
+	}
+	oldStatus, newStatus := d.GetChange("status")
+	if oldStatus.(string) == userStatusLockedOut && newStatus.(string) == statusActive {
+		_ = d.SetNewComputed("unlocked_at")
+	}
+	// only an ACTIVE user, or a user being activated, is suspended during its
+	// suspend window
+	rawStatus := d.Get("raw_status").(string)
+	activated := d.HasChange("status") && newStatus.(string) == statusActive
+	if suspendWindowActive(d.Get("suspend_window"), time.Now()) && (rawStatus == statusActive || activated) {
+		return d.SetNew("raw_status", userStatusSuspended)
+	}
+	return nil
 }
 
 func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -445,12 +540,33 @@ func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface
 		}
 	}
 
+	if suspendWindowActive(d.Get("suspend_window"), time.Now()) && d.Get("status").(string) == statusActive {
+		err := updateUserStatus(m, ctx, user.Id, userStatusSuspended, client)
+		if err != nil {
+			return diag.Errorf("failed to suspend user: %v", err)
+		}
+	}
+
 	expire, ok := d.GetOk("expire_password_on_create")
-	if ok && expire.(bool) {
+	expirePassword := (ok && expire.(bool)) || d.Get("expire_password_trigger").(string) != ""
+	if expirePassword && !passwordExpirableOnCreate(d.Get("status").(string), d.Get("suspend_window"), time.Now()) {
+		logger(m).Warn("the password of a user that isn't ACTIVE once created can't be expired", "id", user.Id, "status", d.Get("status").(string))
+		expirePassword = false
+	}
+	if expirePassword {
 		_, _, err = getOktaClientFromMetadata(m).User.ExpirePassword(ctx, user.Id)
 		if err != nil {
 			return diag.Errorf("failed to expire user's password: %v", err)
 		}
+		_ = d.Set("password_expired_at", lifecycleTimestamp())
+	}
+
+	if d.Get("reset_factors_trigger").(string) != "" {
+		_, err = client.User.ResetFactors(ctx, user.Id)
+		if err != nil {
+			return diag.Errorf("failed to reset user's factors: %v", err)
+		}
+		_ = d.Set("factors_reset_at", lifecycleTimestamp())
 	}
 
 	return resourceUserRead(ctx, d, m)
@@ -513,11 +629,23 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface
 	// run the update status func first so a user that was previously deprovisioned
 	// can be updated further if it's status changed in it's terraform configs
 	if statusChange {
+		oldStatus, _ := d.GetChange("status")
 		err := updateUserStatus(m, ctx, d.Id(), status, client)
 		if err != nil {
 			return diag.Errorf("failed to update user status: %v", err)
 		}
 		_ = d.Set("status", status)
+		if oldStatus.(string) == userStatusLockedOut && status == statusActive {
+			_ = d.Set("unlocked_at", lifecycleTimestamp())
+		}
+	}
+
+	// the user's suspend window started, the end of the window is a status change back to ACTIVE
+	if d.HasChange("raw_status") && d.Get("raw_status").(string) == userStatusSuspended && status == statusActive {
+		err := updateUserStatus(m, ctx, d.Id(), userStatusSuspended, client)
+		if err != nil {
+			return diag.Errorf("failed to suspend user: %v", err)
+		}
 	}
 
 	if status == userStatusDeprovisioned && userChange {
@@ -606,6 +734,23 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface
 		}
 	}
 
+	// expire the password after it's changed, otherwise the new password would reset the expiration
+	if d.HasChange("expire_password_trigger") && d.Get("expire_password_trigger").(string) != "" {
+		_, _, err := client.User.ExpirePassword(ctx, d.Id())
+		if err != nil {
+			return diag.Errorf("failed to expire user's password: %v", err)
+		}
+		_ = d.Set("password_expired_at", lifecycleTimestamp())
+	}
+
+	if d.HasChange("reset_factors_trigger") && d.Get("reset_factors_trigger").(string) != "" {
+		_, err := client.User.ResetFactors(ctx, d.Id())
+		if err != nil {
+			return diag.Errorf("failed to reset user's factors: %v", err)
+		}
+		_ = d.Set("factors_reset_at", lifecycleTimestamp())
+	}
+
 	filteredCustomAttributes := convertInterfaceToStringSet(d.Get("custom_profile_attributes_to_ignore"))
 
 	return resourceUserReadFilterCustomAttributes(ctx, d, m, filteredCustomAttributes)
@@ -59,11 +59,11 @@ func resourceUserAdminRolesCreate(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceUserAdminRolesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	err := setAdminRoles(ctx, d, m)
+	diags, err := setAdminRoles(ctx, d, m)
 	if err != nil {
 		return diag.Errorf("failed to set read user's roles: %v", err)
 	}
-	return nil
+	return diags
 }
 
 func resourceUserAdminRolesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
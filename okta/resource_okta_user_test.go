@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -435,3 +436,89 @@ resource "okta_user" "test" {
 		},
 	})
 }
+
+func TestAccResourceOktaUser_lifecycle(t *testing.T) {
+	mgr := newFixtureManager("resources", user, t.Name())
+	config := mgr.GetFixtures("lifecycle.tf", t)
+	updated := mgr.GetFixtures("lifecycle_updated.tf", t)
+	resourceName := fmt.Sprintf("%s.test", user)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		CheckDestroy:      checkUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "raw_status", userStatusPasswordExpired),
+					resource.TestCheckResourceAttrSet(resourceName, "password_expired_at"),
+					resource.TestCheckNoResourceAttr(resourceName, "factors_reset_at"),
+				),
+			},
+			{
+				Config: updated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "status", userStatusSuspended),
+					resource.TestCheckResourceAttr(resourceName, "raw_status", userStatusSuspended),
+					resource.TestCheckResourceAttrSet(resourceName, "factors_reset_at"),
+				),
+			},
+			{
+				// re-applying the same triggers and window doesn't plan any change
+				Config:   updated,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestSuspendWindowActive(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	window := func(start, end string) interface{} {
+		return []interface{}{map[string]interface{}{"start": start, "end": end}}
+	}
+	tests := []struct {
+		name   string
+		window interface{}
+		active bool
+	}{
+		{"no window", []interface{}{}, false},
+		{"not started", window("2023-06-02T00:00:00Z", ""), false},
+		{"started without end", window("2023-06-01T00:00:00Z", ""), true},
+		{"within", window("2023-06-01T00:00:00Z", "2023-06-01T13:00:00+00:00"), true},
+		{"ended", window("2023-05-01T00:00:00Z", "2023-06-01T12:00:00Z"), false},
+		{"offset", window("2023-06-01T13:30:00+02:00", ""), true},
+	}
+	for _, tt := range tests {
+		if active := suspendWindowActive(tt.window, now); active != tt.active {
+			t.Errorf("%s: expected active %t, got %t", tt.name, tt.active, active)
+		}
+	}
+	if err := validateSuspendWindow(window("2023-06-02T00:00:00Z", "2023-06-01T00:00:00Z")); err == nil {
+		t.Error("expected error for a window ending before its start")
+	}
+}
+
+func TestPasswordExpirableOnCreate(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	active := []interface{}{map[string]interface{}{"start": "2023-06-01T00:00:00Z", "end": ""}}
+	tests := []struct {
+		name      string
+		status    string
+		window    interface{}
+		expirable bool
+	}{
+		{"active", statusActive, []interface{}{}, true},
+		{"staged", userStatusStaged, []interface{}{}, false},
+		{"suspended", userStatusSuspended, []interface{}{}, false},
+		{"deprovisioned", userStatusDeprovisioned, []interface{}{}, false},
+		{"active within suspend window", statusActive, active, false},
+	}
+	for _, tt := range tests {
+		if expirable := passwordExpirableOnCreate(tt.status, tt.window, now); expirable != tt.expirable {
+			t.Errorf("%s: expected expirable %t, got %t", tt.name, tt.expirable, expirable)
+		}
+	}
+}
@@ -8,27 +8,41 @@ import (
 )
 
 func syncSamlIndexEndpointBinding(d *schema.ResourceData, services []saml.IndexedEndpoint) {
-	// Always grab the last one just for simplicity. Should never have duplicates.
-	for _, service := range services {
-		switch service.Binding {
-		case postBinding:
-			_ = d.Set("http_post_binding", service.Location)
-		case redirectBinding:
-			_ = d.Set("http_redirect_binding", service.Location)
-		}
-	}
+	syncSamlEndpointBinding(d, samlEndpoints(services))
 }
 
 func syncSamlEndpointBinding(d *schema.ResourceData, services []saml.Endpoint) {
+	post, redirect := samlEndpointLocations(services)
+	if post != "" {
+		_ = d.Set("http_post_binding", post)
+	}
+	if redirect != "" {
+		_ = d.Set("http_redirect_binding", redirect)
+	}
+}
+
+// samlEndpointLocations returns the locations of the HTTP-POST and
+// HTTP-Redirect bindings of the services.
+func samlEndpointLocations(services []saml.Endpoint) (post, redirect string) {
 	// Always grab the last one just for simplicity. Should never have duplicates.
 	for _, service := range services {
 		switch service.Binding {
 		case postBinding:
-			_ = d.Set("http_post_binding", service.Location)
+			post = service.Location
 		case redirectBinding:
-			_ = d.Set("http_redirect_binding", service.Location)
+			redirect = service.Location
 		}
 	}
+	return
+}
+
+// samlEndpoints drops the indexes of the services.
+func samlEndpoints(services []saml.IndexedEndpoint) []saml.Endpoint {
+	endpoints := make([]saml.Endpoint, len(services))
+	for i := range services {
+		endpoints[i] = saml.Endpoint{Binding: services[i].Binding, Location: services[i].Location}
+	}
+	return endpoints
 }
 
 func getExternalID(url, pattern string) string {
@@ -38,8 +52,13 @@ func getExternalID(url, pattern string) string {
 	return strings.ReplaceAll(url, pur, "")
 }
 
+// syncSamlCertificates sets the certificates of the key descriptors whose use
+// is signing or encryption, a descriptor without use is ignored.
 func syncSamlCertificates(d *schema.ResourceData, descriptors []saml.KeyDescriptor) {
 	for _, desc := range descriptors {
+		if len(desc.KeyInfo.X509Data.X509Certificates) == 0 {
+			continue
+		}
 		switch desc.Use {
 		case "encryption":
 			_ = d.Set("encryption_certificate", desc.KeyInfo.X509Data.X509Certificates[0].Data)
@@ -48,3 +67,29 @@ func syncSamlCertificates(d *schema.ResourceData, descriptors []saml.KeyDescript
 		}
 	}
 }
+
+// samlCertificates returns the signing and encryption certificates of the
+// key descriptors for the parse_saml_metadata function, a descriptor without
+// use holds a certificate for both.
+func samlCertificates(descriptors []saml.KeyDescriptor) (signing, encryption string) {
+	for _, desc := range descriptors {
+		if len(desc.KeyInfo.X509Data.X509Certificates) == 0 {
+			continue
+		}
+		cert := desc.KeyInfo.X509Data.X509Certificates[0].Data
+		switch desc.Use {
+		case "encryption":
+			encryption = cert
+		case "signing":
+			signing = cert
+		case "":
+			if encryption == "" {
+				encryption = cert
+			}
+			if signing == "" {
+				signing = cert
+			}
+		}
+	}
+	return
+}
@@ -5,10 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
 	"reflect"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/okta/terraform-provider-okta/sdk"
 	"github.com/okta/terraform-provider-okta/sdk/query"
@@ -251,30 +251,29 @@ func listUserRoles(ctx context.Context, c *sdk.Client, userID string) (userOnlyR
 	return
 }
 
-func getRoles(ctx context.Context, id string, c *sdk.Client) ([]interface{}, error) {
+func getRoles(ctx context.Context, id string, c *sdk.Client) ([]interface{}, *sdk.Response, error) {
 	roleTypes := make([]interface{}, 0)
 	roles, resp, err := listUserRoles(ctx, c, id)
 	if err != nil {
-		if resp != nil && resp.StatusCode == http.StatusForbidden {
-			// no-op
-		} else {
-			return nil, err
-		}
-	} else {
-		for _, role := range roles {
-			roleTypes = append(roleTypes, role.Type)
-		}
+		return nil, resp, err
 	}
-	return roleTypes, err
+	for _, role := range roles {
+		roleTypes = append(roleTypes, role.Type)
+	}
+	return roleTypes, resp, nil
 }
 
-func setRoles(ctx context.Context, d *schema.ResourceData, m interface{}) error {
-	roleTypes, err := getRoles(ctx, d.Id(), getOktaClientFromMetadata(m))
+func setRoles(ctx context.Context, d *schema.ResourceData, m interface{}) (diag.Diagnostics, error) {
+	roleTypes, resp, err := getRoles(ctx, d.Id(), getOktaClientFromMetadata(m))
+	warnings, err := suppressErrorOnPermission(ctx, "setting roles", m, resp, err)
 	if err != nil {
-		return fmt.Errorf("failed to get roles: %v", err)
+		return nil, fmt.Errorf("failed to get roles: %v", err)
+	}
+	if warnings != nil {
+		return warnings, nil
 	}
 	// set the custom_profile_attributes values
-	return setNonPrimitives(d, map[string]interface{}{
+	return nil, setNonPrimitives(d, map[string]interface{}{
 		"roles": schema.NewSet(schema.HashString, roleTypes),
 	})
 }
@@ -319,14 +318,18 @@ func getAdminRoles(ctx context.Context, id string, c *sdk.Client) ([]interface{}
 	return roleTypes, resp, err
 }
 
-func setAdminRoles(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+func setAdminRoles(ctx context.Context, d *schema.ResourceData, m interface{}) (diag.Diagnostics, error) {
 	roleTypes, resp, err := getAdminRoles(ctx, d.Id(), getOktaClientFromMetadata(m))
-	if err := suppressErrorOn403("setting admin roles", m, resp, err); err != nil {
-		return fmt.Errorf("failed to get admin roles: %v", err)
+	warnings, err := suppressErrorOnPermission(ctx, "setting admin roles", m, resp, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin roles: %v", err)
+	}
+	if warnings != nil {
+		return warnings, nil
 	}
 
 	// set the custom_profile_attributes values
-	return setNonPrimitives(d, map[string]interface{}{
+	return nil, setNonPrimitives(d, map[string]interface{}{
 		"admin_roles": schema.NewSet(schema.HashString, roleTypes),
 	})
 }
@@ -442,6 +445,56 @@ func updateUserStatus(m interface{}, ctx context.Context, uid, desiredStatus str
 	return waitForStatusTransition(m, ctx, uid, c)
 }
 
+// suspendWindow returns the start and end of the user's suspend window, end is
+// zero when the window doesn't end.
+func suspendWindow(raw interface{}) (start, end time.Time, ok bool) {
+	windows, _ := raw.([]interface{})
+	if len(windows) == 0 || windows[0] == nil {
+		return
+	}
+	window := windows[0].(map[string]interface{})
+	start, err := time.Parse(time.RFC3339, window["start"].(string))
+	if err != nil {
+		return
+	}
+	if v, _ := window["end"].(string); v != "" {
+		end, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return
+		}
+	}
+	return start, end, true
+}
+
+func validateSuspendWindow(raw interface{}) error {
+	start, end, ok := suspendWindow(raw)
+	if ok && !end.IsZero() && !end.After(start) {
+		return fmt.Errorf("'suspend_window' end should be after its start")
+	}
+	return nil
+}
+
+// passwordExpirableOnCreate returns true when the password of a user created
+// with the status can be expired, Okta only expires the password of an ACTIVE
+// user, which isn't the case of a user suspended by its suspend window.
+func passwordExpirableOnCreate(status string, window interface{}, now time.Time) bool {
+	return status == statusActive && !suspendWindowActive(window, now)
+}
+
+// suspendWindowActive returns true when now is within the user's suspend window.
+func suspendWindowActive(raw interface{}, now time.Time) bool {
+	start, end, ok := suspendWindow(raw)
+	if !ok {
+		return false
+	}
+	return !now.Before(start) && (end.IsZero() || now.Before(end))
+}
+
+// lifecycleTimestamp is the time a lifecycle operation ran, as recorded in the state.
+func lifecycleTimestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
 // need to wait for user.TransitioningToStatus field to be empty before allowing Terraform to continue
 // so the proper current status gets set in the state during the Read operation after a Status update
 func waitForStatusTransition(m interface{}, ctx context.Context, u string, c *sdk.Client) error {
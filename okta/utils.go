@@ -17,11 +17,14 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/okta/okta-sdk-golang/v3/okta"
+	"github.com/okta/terraform-provider-okta/okta/internal/permissions"
 	"github.com/okta/terraform-provider-okta/sdk"
 )
 
@@ -343,24 +346,86 @@ func v3suppressErrorOn404(resp *okta.APIResponse, err error) error {
 	return v3responseErr(resp, err)
 }
 
-// Useful shortcut for suppressing errors from Okta's SDK when a Org does not
-// have permission to access a feature.
-func suppressErrorOn401(what string, meta interface{}, resp *sdk.Response, err error) error {
-	if resp != nil && resp.StatusCode == http.StatusUnauthorized {
-		logger(meta).Warn(fmt.Sprintf("Suppressing %q on %q", "401 Unauthorized", what))
-		return nil
-	}
-	return responseErr(resp, err)
-}
-
-// Useful shortcut for suppressing errors from Okta's SDK when a Org does not
-// have permission to access a feature.
-func suppressErrorOn403(what string, meta interface{}, resp *sdk.Response, err error) error {
-	if resp != nil && resp.StatusCode == http.StatusForbidden {
-		logger(meta).Warn(fmt.Sprintf("Suppressing %q on %q", "403 Forbidden", what))
-		return nil
+// suppressErrorOnPermission is a shortcut for degrading an error from Okta's
+// SDK to a warning when the API responds 401 Unauthorized or 403 Forbidden on
+// a registered endpoint class (see internal/permissions) that the provider's
+// api_token_role isn't known to have access to, or that requires an org
+// feature. The caller leaves the attributes depending on the request unset.
+// Any other error is returned as is.
+func suppressErrorOnPermission(ctx context.Context, what string, meta interface{}, resp *sdk.Response, err error) (diag.Diagnostics, error) {
+	if err == nil {
+		return nil, nil
+	}
+	if resp == nil || resp.Response == nil || resp.Request == nil ||
+		(resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden) {
+		return nil, responseErr(resp, err)
+	}
+	class := permissions.Lookup(resp.Request.URL.Path)
+	if class == nil {
+		return nil, responseErr(resp, err)
+	}
+	role := meta.(*Config).APITokenRole(ctx)
+	if class.Permits(role) && !class.Feature {
+		return nil, responseErr(resp, err)
+	}
+
+	logger(meta).Warn(fmt.Sprintf("Suppressing %q on %q", resp.Status, what))
+	detail := fmt.Sprintf("The Okta API responded %q to \"%s %s\" while %s. The %s endpoints are available to the %s roles",
+		resp.Status, resp.Request.Method, resp.Request.URL.Path, what, class.Name, class.RolesDescription())
+	if class.Feature {
+		detail += " in orgs with the feature enabled"
+	}
+	if role == permissions.Unknown {
+		detail += ", the provider's api_token_role is unknown."
+	} else {
+		detail += fmt.Sprintf(", the provider's api_token_role is %q.", role)
+	}
+	detail += " Attributes depending on this request are left unset."
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Insufficient permissions %s", what),
+			Detail:   detail,
+		},
+	}, nil
+}
+
+// concurrentlyEach calls fn for each of the ids with at most parallelism calls
+// in flight. API requests made by fn still pass through the provider's http
+// transport so they remain subject to the max_api_capacity rate limit
+// governor. A failing call doesn't stop the others; the ids fn succeeded for
+// are returned along with the errors of those it failed for joined together.
+func concurrentlyEach(ids []string, parallelism int, fn func(id string) error) ([]string, error) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	var (
+		wg        sync.WaitGroup
+		lock      sync.Mutex
+		succeeded []string
+		errs      []error
+	)
+	sem := make(chan struct{}, parallelism)
+	for _, id := range ids {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(id string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			err := fn(id)
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			succeeded = append(succeeded, id)
+		}(id)
 	}
-	return responseErr(resp, err)
+	wg.Wait()
+	return succeeded, errors.Join(errs...)
 }
 
 func getOktaClientFromMetadata(meta interface{}) *sdk.Client {
@@ -540,6 +605,22 @@ func localFileStateFunc(val interface{}) string {
 	return computeFileHash(filePath)
 }
 
+// base64ContentStateFunc - helper for schema.Schema StateFunc storing the hash
+// of base64 encoded content, comparable to the hash of localFileStateFunc.
+func base64ContentStateFunc(val interface{}) string {
+	content, err := base64.StdEncoding.DecodeString(val.(string))
+	if err != nil || len(content) == 0 {
+		return ""
+	}
+	return computeContentHash(content)
+}
+
+// computeContentHash - equivalent to computeFileHash for content in memory
+func computeContentHash(content []byte) string {
+	h := sha256.Sum256(content)
+	return hex.EncodeToString(h[:])
+}
+
 // computeFileHash - equivalent to  `shasum -a 256 filepath`
 func computeFileHash(filename string) string {
 	file, err := os.Open(filename)
@@ -628,3 +709,18 @@ func noChangeInObjectFromUnmarshaledJSON(k, oldJSON, newJSON string, d *schema.R
 
 	return reflect.DeepEqual(oldObj, newObj)
 }
+
+// getSecret returns the value of the write-only variant "<key>_wo" of a secret
+// attribute when it is set in the configuration, otherwise the value of the
+// secret attribute itself. Write-only values are never persisted to the state,
+// so they can only be read from the raw configuration during create and update.
+func getSecret(d *schema.ResourceData, key string) string {
+	raw := d.GetRawConfig()
+	if !raw.IsNull() && raw.Type().HasAttribute(key+"_wo") {
+		v := raw.GetAttr(key + "_wo")
+		if v.IsKnown() && !v.IsNull() {
+			return v.AsString()
+		}
+	}
+	return d.Get(key).(string)
+}
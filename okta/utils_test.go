@@ -1,11 +1,19 @@
 package okta
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net/http"
 	"reflect"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/okta/terraform-provider-okta/okta/internal/fakeokta"
+	"github.com/okta/terraform-provider-okta/okta/internal/permissions"
 	"github.com/okta/terraform-provider-okta/sdk"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -366,3 +374,109 @@ func TestNoChangeInObjectUnmarshaledFromJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestSuppressErrorOnPermission(t *testing.T) {
+	_, config := newFakeOktaConfig(t, fakeokta.WithAdminRole(string(permissions.ReadOnlyAdmin)))
+	request := func(config *Config, method, path string, body interface{}) (*sdk.Response, error) {
+		re := config.oktaSDKsupplementClient.RequestExecutor
+		req, err := re.NewRequest(method, path, body)
+		require.NoError(t, err)
+		return re.Do(context.TODO(), req, nil)
+	}
+	get := func(path string) (*sdk.Response, error) {
+		return request(config, http.MethodGet, path, nil)
+	}
+
+	examples := map[string]string{
+		"user admin roles":        "/api/v1/users/00u1/roles",
+		"profile mappings":        "/api/v1/mappings",
+		"org settings":            "/api/v1/org/privacy/oktaSupport",
+		"rate limit settings":     "/api/v1/internal/rateLimits/clientRateLimitMode",
+		"threat insight settings": "/api/v1/threats/configuration",
+	}
+	for _, class := range permissions.Classes() {
+		path, ok := examples[class.Name]
+		if !ok {
+			t.Errorf("endpoint class %q needs an example path in this test", class.Name)
+			continue
+		}
+		resp, err := get(path)
+		require.Error(t, err)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		// unknown role and a role without access warn
+		for _, role := range []string{"", string(permissions.ReadOnlyAdmin)} {
+			config.apiTokenRole = role
+			diags, err := suppressErrorOnPermission(context.TODO(), "testing", config, resp, err)
+			require.NoError(t, err, "class %q, role %q", class.Name, role)
+			require.Len(t, diags, 1, "class %q, role %q", class.Name, role)
+			assert.Equal(t, diag.Warning, diags[0].Severity)
+			assert.Contains(t, diags[0].Detail, path)
+		}
+
+		// a role with access errors unless the class also requires a feature
+		config.apiTokenRole = string(class.Roles[0])
+		diags, suppressErr := suppressErrorOnPermission(context.TODO(), "testing", config, resp, err)
+		if class.Feature {
+			require.NoError(t, suppressErr, "class %q", class.Name)
+			require.Len(t, diags, 1, "class %q", class.Name)
+		} else {
+			require.Error(t, suppressErr, "class %q", class.Name)
+			require.Nil(t, diags, "class %q", class.Name)
+		}
+	}
+
+	config.apiTokenRole = ""
+	// 403 on an endpoint that isn't registered is an error
+	resp, err := request(config, http.MethodPost, "/api/v1/groups", map[string]interface{}{
+		"profile": map[string]interface{}{"name": "Engineering"},
+	})
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	require.Error(t, err)
+	diags, err := suppressErrorOnPermission(context.TODO(), "testing", config, resp, err)
+	require.Error(t, err)
+	require.Nil(t, diags)
+
+	// other errors on registered endpoints are errors
+	_, superAdminConfig := newFakeOktaConfig(t)
+	resp, err = request(superAdminConfig, http.MethodGet, "/api/v1/users/notfound/roles", nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.Error(t, err)
+	diags, err = suppressErrorOnPermission(context.TODO(), "testing", config, resp, err)
+	require.Error(t, err)
+	require.Nil(t, diags)
+
+	// no error, no warning
+	diags, err = suppressErrorOnPermission(context.TODO(), "testing", config, nil, nil)
+	require.NoError(t, err)
+	require.Nil(t, diags)
+}
+
+func TestConcurrentlyEach(t *testing.T) {
+	ids := []string{"1", "2", "3", "4", "5", "6", "7", "8"}
+	var inFlight, maxInFlight int32
+	succeeded, err := concurrentlyEach(ids, 3, func(id string) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			prev := atomic.LoadInt32(&maxInFlight)
+			if n <= prev || atomic.CompareAndSwapInt32(&maxInFlight, prev, n) {
+				break
+			}
+		}
+		if id == "3" || id == "6" {
+			return fmt.Errorf("failed %s", id)
+		}
+		return nil
+	})
+	sort.Strings(succeeded)
+	require.Equal(t, []string{"1", "2", "4", "5", "7", "8"}, succeeded)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed 3")
+	assert.Contains(t, err.Error(), "failed 6")
+	assert.LessOrEqual(t, maxInFlight, int32(3))
+
+	succeeded, err = concurrentlyEach(nil, 0, func(id string) error { return nil })
+	require.NoError(t, err)
+	require.Empty(t, succeeded)
+}
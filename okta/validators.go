@@ -1,12 +1,16 @@
 package okta
 
 import (
+	"encoding/base64"
 	"os"
+	"regexp"
+	"time"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
 )
 
 func intBetween(min, max int) schema.SchemaValidateDiagFunc {
@@ -35,6 +39,21 @@ func intAtMost(max int) schema.SchemaValidateDiagFunc {
 	}
 }
 
+func stringInSlice(valid []string) schema.SchemaValidateDiagFunc {
+	return func(i interface{}, k cty.Path) diag.Diagnostics {
+		v, ok := i.(string)
+		if !ok {
+			return diag.Errorf("expected type of %s to be string", k)
+		}
+		for _, str := range valid {
+			if v == str {
+				return nil
+			}
+		}
+		return diag.Errorf("expected %s to be one of %q, got %s", k, valid, v)
+	}
+}
+
 func logoFileIsValid() schema.SchemaValidateDiagFunc {
 	return func(i interface{}, k cty.Path) diag.Diagnostics {
 		v, ok := i.(string)
@@ -52,6 +71,23 @@ func logoFileIsValid() schema.SchemaValidateDiagFunc {
 	}
 }
 
+func base64ImageIsValid() schema.SchemaValidateDiagFunc {
+	return func(i interface{}, k cty.Path) diag.Diagnostics {
+		v, ok := i.(string)
+		if !ok {
+			return diag.Errorf("expected type of %v to be string", k)
+		}
+		content, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return diag.Errorf("invalid base64 encoded content: %v", err)
+		}
+		if len(content) > 1<<20 { // should be less than 1 MB in size.
+			return diag.Errorf("content should be less than 1 MB in size")
+		}
+		return nil
+	}
+}
+
 func stringIsJSON(i interface{}, k cty.Path) diag.Diagnostics {
 	v, ok := i.(string)
 	if !ok {
@@ -65,3 +101,47 @@ func stringIsJSON(i interface{}, k cty.Path) diag.Diagnostics {
 	}
 	return nil
 }
+
+func stringIsRegexp(i interface{}, k cty.Path) diag.Diagnostics {
+	v, ok := i.(string)
+	if !ok {
+		return diag.Errorf("expected type of %s to be string", k)
+	}
+	if _, err := regexp.Compile(v); err != nil {
+		return diag.Errorf("%q contains an invalid regular expression: %s", k, err)
+	}
+	return nil
+}
+
+func isOktaExpression(i interface{}, k cty.Path) diag.Diagnostics {
+	v, ok := i.(string)
+	if !ok {
+		return diag.Errorf("expected type of %s to be string", k)
+	}
+	if _, err := expression.Validate(v); err != nil {
+		return diag.Errorf("expected %s to be a valid Okta Expression Language expression: %v", k, err)
+	}
+	return nil
+}
+
+func stringIsRFC3339(i interface{}, k cty.Path) diag.Diagnostics {
+	v, ok := i.(string)
+	if !ok {
+		return diag.Errorf("expected type of %s to be string", k)
+	}
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return diag.Errorf("expected %s to be a RFC3339 timestamp, e.g. 2006-01-02T15:04:05Z: %v", k, err)
+	}
+	return nil
+}
+
+func stringIsDuration(i interface{}, k cty.Path) diag.Diagnostics {
+	v, ok := i.(string)
+	if !ok {
+		return diag.Errorf("expected type of %s to be string", k)
+	}
+	if d, err := time.ParseDuration(v); err != nil || d < 0 {
+		return diag.Errorf("expected %s to be a positive duration, e.g. 24h or 30m", k)
+	}
+	return nil
+}